@@ -1,16 +1,17 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"syscall"
+	"time"
 
 	"tenazas/internal/cli"
-	"tenazas/internal/client"
 	_ "tenazas/internal/client" // register client implementations
 	"tenazas/internal/config"
 	"tenazas/internal/engine"
@@ -18,10 +19,13 @@ import (
 	"tenazas/internal/formatter"
 	"tenazas/internal/heartbeat"
 	"tenazas/internal/logs"
+	"tenazas/internal/metrics"
 	"tenazas/internal/models"
 	"tenazas/internal/onboard"
 	"tenazas/internal/registry"
 	"tenazas/internal/session"
+	"tenazas/internal/skill"
+	"tenazas/internal/storage"
 	"tenazas/internal/task"
 	"tenazas/internal/telegram"
 )
@@ -29,12 +33,17 @@ import (
 func main() {
 	resume := flag.Bool("resume", false, "Resume a previous session")
 	daemon := flag.Bool("daemon", false, "Run as a background daemon (Telegram bot and Heartbeat runner)")
+	observe := flag.String("observe", "", "Watch a session read-only: replay and stream it, but refuse prompts and changes")
+	plain := flag.Bool("plain", false, "Line-based CLI without the full-screen TUI: prompts and responses print as a plain log")
 	flag.Parse()
 
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	if err := storage.CheckWritable(cfg.StorageDir); err != nil {
+		log.Printf("⚠️ WARNING: storage dir %s is not writable (%v); sessions, audit logs and tasks will not be saved", cfg.StorageDir, err)
+	}
 
 	// Handle subcommands that don't need full initialization
 	if flag.Arg(0) == "onboard" {
@@ -44,45 +53,65 @@ func main() {
 		return
 	}
 
+	if flag.Arg(0) == "stop-all" {
+		if err := heartbeat.Pause(cfg.StorageDir, "tenazas stop-all"); err != nil {
+			log.Fatalf("Stop-all failed: %v", err)
+		}
+		fmt.Println("Heartbeats paused; a running daemon stops its sessions within a few seconds. Resume with: tenazas resume-all")
+		return
+	}
+	if flag.Arg(0) == "resume-all" {
+		if err := heartbeat.Resume(cfg.StorageDir); err != nil {
+			log.Fatalf("Resume-all failed: %v", err)
+		}
+		fmt.Println("Heartbeats resumed.")
+		return
+	}
+
 	sm := session.NewManager(cfg.StorageDir)
+	sm.Storage.IncludePaths = cfg.InstructionPaths
 
 	if flag.Arg(0) == "logs" {
 		logs.HandleCommand(sm, flag.Args()[1:])
 		return
 	}
+	if flag.Arg(0) == "skills" {
+		cwd, _ := os.Getwd()
+		os.Exit(skill.HandleCommand(sm.Storage, cwd, flag.Args()[1:], os.Stdout))
+	}
 
 	reg, err := registry.NewRegistry(cfg.StorageDir)
 	if err != nil {
 		log.Fatalf("Failed to init registry: %v", err)
 	}
 
-	logPath := filepath.Join(cfg.StorageDir, "tenazas.log")
-	clients := make(map[string]client.Client)
-	for name, cc := range cfg.Clients {
-		c, cerr := client.NewClient(name, cc.BinPath, logPath)
-		if cerr != nil {
-			log.Printf("Warning: could not init client %q: %v", name, cerr)
-			continue
-		}
-		if len(cc.Models) > 0 {
-			c.SetModels(cc.Models)
-		}
-		clients[name] = c
+	eng, err := engine.NewFromConfig(sm, cfg)
+	if err != nil {
+		log.Printf("Warning: %v", err)
 	}
-	eng := engine.NewEngine(sm, clients, cfg.DefaultClient, cfg.MaxLoops)
 
 	if flag.Arg(0) == "work" {
-		task.HandleWorkCommand(cfg.StorageDir, flag.Args()[1:])
+		task.HandleWorkCommand(cfg.StorageDir, taskSettings(cfg), flag.Args()[1:])
 		return
 	}
 
+	if flag.Arg(0) == "prompt" {
+		handleSignals()
+		os.Exit(handlePromptCommand(sm, eng, cfg, flag.Args()[1:]))
+	}
+
 	if flag.Arg(0) == "run" {
-		if flag.Arg(1) == "" {
-			fmt.Println("Usage: tenazas run <skillname>")
+		opts := parseRunArgs(flag.Args()[1:])
+		if (opts.skill == "") == (opts.prompt == "") || (opts.prompt != "" && (opts.trace || opts.output != "text")) ||
+			(opts.output != "text" && opts.output != "json") {
+			fmt.Println("Usage: tenazas run <skillname> [--trace] [--output text|json] | tenazas run --prompt <text>")
 			os.Exit(1)
 		}
 		handleSignals()
-		os.Exit(handleRunCommand(sm, eng, cfg, flag.Arg(1)))
+		if opts.prompt != "" {
+			os.Exit(handleRunPrompt(sm, eng, cfg, opts.prompt))
+		}
+		os.Exit(handleRunCommand(sm, eng, cfg, opts, os.Stdout, os.Stderr))
 	}
 
 	if *daemon {
@@ -91,7 +120,16 @@ func main() {
 			tg = setupTelegram(cfg, sm, reg, eng)
 		}
 		hb := heartbeat.NewRunner(cfg.StorageDir, sm, eng, tg)
+		hb.SkipDirty = cfg.HeartbeatSkipDirty
+		hb.LabelSkills = task.NewLabelSkills(cfg.LabelSkills)
+		if tg != nil {
+			tg.Stopper = hb
+		}
 		go hb.CheckAndRun()
+		go hb.WatchStopRequests()
+		if cfg.MetricsAddr != "" {
+			go serveMetrics(cfg, sm, eng)
+		}
 		fmt.Println("Daemon started. Press Ctrl+C to stop.")
 		handleSignals()
 		select {} // block forever
@@ -99,16 +137,66 @@ func main() {
 
 	handleSignals()
 
-	clientModels := make(map[string]map[string]string)
+	c := cli.NewCLI(sm, reg, eng, cfg.DefaultClient, cfg.DefaultModelTier, clientModels(cfg))
+	c.TimeFormat = timeFormat(cfg)
+	c.ShortIDLength = cfg.SessionIDLength
+	c.Tasks = taskSettings(cfg)
+	c.TranscriptEnabled = cfg.Transcript
+	c.DefaultApprovalMode = defaultApprovalMode(cfg)
+	c.Plain = *plain
+	if flag.Arg(0) == "attach" {
+		if flag.Arg(1) == "" {
+			fmt.Println("Usage: tenazas attach <session-id>")
+			os.Exit(1)
+		}
+		c.AttachID = flag.Arg(1)
+	}
+	if *observe != "" {
+		c.AttachID = *observe
+		c.ReadOnly = true
+	}
+	if err := c.Run(*resume); err != nil {
+		fmt.Printf("CLI Error: %v\n", err)
+	}
+}
+
+// defaultApprovalMode resolves cfg.DefaultApprovalMode, warning on an
+// unknown value (new sessions then start in plan) and on yolo.
+func defaultApprovalMode(cfg *config.Config) string {
+	if cfg.DefaultApprovalMode == "" {
+		return ""
+	}
+	mode, ok := models.ParseApprovalMode(cfg.DefaultApprovalMode)
+	if !ok {
+		log.Printf("Warning: unknown default_approval_mode %q; new sessions start in plan mode", cfg.DefaultApprovalMode)
+		return ""
+	}
+	if mode == models.ApprovalModeYolo {
+		log.Printf("WARNING: default_approval_mode is yolo; new sessions edit files and run commands without asking")
+	}
+	return mode
+}
+
+// clientModels collects the tier→model mapping of each configured client.
+func clientModels(cfg *config.Config) map[string]map[string]string {
+	m := make(map[string]map[string]string)
 	for name, cc := range cfg.Clients {
 		if len(cc.Models) > 0 {
-			clientModels[name] = cc.Models
+			m[name] = cc.Models
 		}
 	}
+	return m
+}
 
-	c := cli.NewCLI(sm, reg, eng, cfg.DefaultClient, cfg.DefaultModelTier, clientModels)
-	if err := c.Run(*resume); err != nil {
-		fmt.Printf("CLI Error: %v\n", err)
+func timeFormat(cfg *config.Config) formatter.TimeFormat {
+	return formatter.TimeFormat{Layout: cfg.TimestampLayout, UTC: cfg.TimestampUTC()}
+}
+
+// taskSettings collects the priority labels and label routing of cfg.
+func taskSettings(cfg *config.Config) task.Settings {
+	return task.Settings{
+		Priorities:  task.NewPriorityLevels(cfg.PriorityLabels),
+		LabelSkills: task.NewLabelSkills(cfg.LabelSkills),
 	}
 }
 
@@ -119,19 +207,48 @@ func setupTelegram(cfg *config.Config, sm *session.Manager, reg *registry.Regist
 	}
 
 	tg := &telegram.Telegram{
-		Token:          cfg.Channel.Token,
-		AllowedIDs:     cfg.Channel.AllowedUserIDs,
-		UpdateInterval: cfg.Channel.UpdateInterval,
-		Sm:             sm,
-		Reg:            reg,
-		Engine:         eng,
-		DefaultClient:  cfg.DefaultClient,
+		Token:               cfg.Channel.Token,
+		AllowedIDs:          cfg.Channel.AllowedUserIDs,
+		AllowedUsernames:    cfg.Channel.AllowedUsernames,
+		UpdateInterval:      cfg.Channel.UpdateInterval,
+		Sm:                  sm,
+		Reg:                 reg,
+		Engine:              eng,
+		DefaultClient:       cfg.DefaultClient,
+		ClientModels:        clientModels(cfg),
+		TimeFormat:          timeFormat(cfg),
+		ShortIDLength:       cfg.SessionIDLength,
+		DefaultApprovalMode: defaultApprovalMode(cfg),
+		StatusDebounce:      time.Duration(cfg.Channel.StatusDebounce) * time.Millisecond,
+		ActionKeyboard:      cfg.Channel.ActionKeyboard,
+		EscalationChatIDs:   cfg.Channel.EscalationChatIDs,
+		MaxMessageChars:     cfg.Channel.MaxMessageChars,
+		TruncationMarker:    cfg.Channel.TruncationMarker,
+		PreviewChars:        cfg.Channel.PreviewChars,
+		StreamMinDelta:      cfg.Channel.StreamMinDelta,
+		StreamFinalOnly:     cfg.Channel.StreamFinalOnly,
+	}
+	if len(cfg.Channel.EscalationChatIDs) > 0 {
+		eng.EscalateAfter = time.Duration(cfg.Channel.EscalateAfterSec) * time.Second
+	}
+	for _, problem := range telegram.ValidateActionKeyboard(cfg.Channel.ActionKeyboard) {
+		fmt.Println("Warning: channel.action_keyboard:", problem)
 	}
 	go tg.Poll()
 	fmt.Println("Telegram bot started.")
 	return tg
 }
 
+// serveMetrics exposes Prometheus metrics on cfg.MetricsAddr; a failure to
+// listen is reported but does not stop the daemon.
+func serveMetrics(cfg *config.Config, sm *session.Manager, eng *engine.Engine) {
+	c := &metrics.Collector{Sm: sm, Engine: eng, StorageDir: cfg.StorageDir}
+	fmt.Printf("Metrics at http://%s/metrics\n", cfg.MetricsAddr)
+	if err := metrics.Serve(cfg.MetricsAddr, c); err != nil {
+		fmt.Println("Warning: metrics endpoint stopped:", err)
+	}
+}
+
 func resumeBackgroundSessions(sm *session.Manager, eng *engine.Engine, storageDir string) {
 	go func() {
 		page := 0
@@ -142,7 +259,7 @@ func resumeBackgroundSessions(sm *session.Manager, eng *engine.Engine, storageDi
 			}
 			for _, s := range sessions {
 				if isResumable(&s) {
-					if sk, err := sm.LoadSkill(s.SkillName); err == nil {
+					if sk, err := sm.LoadSkillFor(s.CWD, s.SkillName); err == nil {
 						fmt.Printf("Resuming task: %s (Skill: %s)\n", s.ID, s.SkillName)
 						go eng.Run(sk, &s)
 					}
@@ -170,12 +287,119 @@ func handleSignals() {
 	}()
 }
 
-func handleRunCommand(sm *session.Manager, eng *engine.Engine, cfg *config.Config, skillName string) int {
+// handlePromptCommand runs a one-shot prompt from --prompt or stdin.
+func handlePromptCommand(sm *session.Manager, eng *engine.Engine, cfg *config.Config, args []string) int {
+	opts := promptOptions(cfg)
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--prompt", "-p":
+			if i+1 < len(args) {
+				i++
+				opts.Prompt = args[i]
+			}
+		case "--session":
+			if i+1 < len(args) {
+				i++
+				opts.SessionID = args[i]
+			}
+		case "--plain":
+			opts.Plain = true
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown argument %q\nUsage: tenazas prompt [--prompt <text>] [--session <id>] [--plain]\n", args[i])
+			return 2
+		}
+	}
+
+	var in io.Reader
+	if opts.Prompt == "" {
+		if isTerminal(os.Stdin) {
+			fmt.Fprintln(os.Stderr, "Usage: tenazas prompt [--prompt <text>] [--session <id>] [--plain]  (or pipe the prompt on stdin)")
+			return 2
+		}
+		in = os.Stdin
+	}
+	return cli.RunPrompt(sm, eng, opts, in, os.Stdout, os.Stderr)
+}
+
+// promptOptions returns the one-shot prompt settings cfg implies for the
+// current directory.
+func promptOptions(cfg *config.Config) cli.PromptOptions {
+	cwd, _ := os.Getwd()
+	return cli.PromptOptions{
+		CWD:        cwd,
+		Client:     cfg.DefaultClient,
+		ModelTier:  cfg.DefaultModelTier,
+		Plain:      !isTerminal(os.Stdout),
+		Transcript: cfg.Transcript,
+	}
+}
+
+// handleRunPrompt implements "run --prompt": the prompt runs in a new yolo
+// session, like a skill run, and its response streams to stdout as
+// "tenazas prompt" would. The exit code is 0 only when the agent completed
+// a response.
+func handleRunPrompt(sm *session.Manager, eng *engine.Engine, cfg *config.Config, prompt string) int {
+	opts := promptOptions(cfg)
+	opts.Prompt = prompt
+	opts.Yolo = true
+	return cli.RunPrompt(sm, eng, opts, nil, os.Stdout, os.Stderr)
+}
+
+// isTerminal reports whether f is attached to a character device (a TTY).
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// runOptions holds the arguments of the "run" subcommand.
+type runOptions struct {
+	skill  string
+	prompt string // run this prompt instead of a skill
+	output string // "text" streams the run; "json" prints its audit entries at the end
+	trace  bool
+}
+
+// parseRunArgs accepts flags before or after the skill name.
+func parseRunArgs(args []string) runOptions {
+	opts := runOptions{output: "text"}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case (a == "--prompt" || a == "-p") && i+1 < len(args):
+			i++
+			opts.prompt = args[i]
+		case a == "--output" && i+1 < len(args):
+			i++
+			opts.output = args[i]
+		case a == "--trace" || a == "-trace":
+			opts.trace = true
+		case opts.skill == "":
+			opts.skill = a
+		}
+	}
+	return opts
+}
+
+// handleRunCommand runs a skill in a new yolo session, streaming it to
+// stdout, and returns the exit code. With --output json nothing is streamed:
+// the session's audit entries are written to stdout at the end, and errors
+// go to stderr so stdout stays valid JSON.
+func handleRunCommand(sm *session.Manager, eng *engine.Engine, cfg *config.Config, opts runOptions, stdout, stderr io.Writer) int {
+	skillName := opts.skill
+	jsonOutput := opts.output == "json"
+	errOut := stdout
+	if jsonOutput {
+		errOut = stderr
+	}
 	cwd, _ := os.Getwd()
+	if err := eng.CheckCWD(cfg.DefaultClient, cwd); err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
 
 	sess, err := sm.Create(cwd, "run: "+skillName)
 	if err != nil {
-		fmt.Printf("Failed to create session: %v\n", err)
+		fmt.Fprintf(errOut, "Failed to create session: %v\n", err)
 		return 1
 	}
 	sess.Client = cfg.DefaultClient
@@ -186,20 +410,39 @@ func handleRunCommand(sm *session.Manager, eng *engine.Engine, cfg *config.Confi
 	}
 	sm.Save(sess)
 
-	sk, err := sm.LoadSkill(skillName)
+	sk, err := sm.LoadSkillFor(sess.CWD, skillName)
 	if err != nil {
-		fmt.Printf("Failed to load skill %q: %v\n", skillName, err)
+		fmt.Fprintf(errOut, "Failed to load skill %q: %v\n", skillName, err)
+		if hint := skill.NotFoundHint(cfg.StorageDir, sess.CWD, skillName); hint != "" {
+			fmt.Fprintln(errOut, "Hint:", hint)
+		}
 		return 1
 	}
 
+	live := stdout
+	if jsonOutput {
+		live = io.Discard
+	}
+	out := live
+	if cfg.Transcript {
+		if tf, err := cli.OpenTranscriptFile(sm, sess); err == nil {
+			defer tf.Close()
+			out = io.MultiWriter(live, &formatter.PlainWriter{W: tf})
+		}
+	}
+
 	// Stream events to stdout.
 	eventCh := events.GlobalBus.Subscribe()
-	f := &formatter.AnsiFormatter{}
+	f := &formatter.AnsiFormatter{Time: timeFormat(cfg)}
 	done := make(chan struct{})
 
 	go func() {
 		defer close(done)
 		for e := range eventCh {
+			if p, ok := e.Payload.(events.StorageErrorPayload); ok {
+				fmt.Fprintln(stderr, "⚠️ WARNING:", p.Message())
+				continue
+			}
 			if e.SessionID != sess.ID || e.Type != events.EventAudit {
 				continue
 			}
@@ -209,16 +452,22 @@ func handleRunCommand(sm *session.Manager, eng *engine.Engine, cfg *config.Confi
 			}
 			switch audit.Type {
 			case events.AuditLLMChunk:
-				fmt.Print(audit.Content)
+				fmt.Fprint(out, audit.Content)
 			case events.AuditLLMResponse:
-				fmt.Println()
-			case events.AuditCmdResult, events.AuditStatus, events.AuditInfo, events.AuditIntervention:
-				fmt.Println(f.Format(audit))
+				fmt.Fprintln(out)
+			case events.AuditCmdResult, events.AuditStatus, events.AuditInfo, events.AuditIntervention, events.AuditResume:
+				fmt.Fprintln(out, f.Format(audit))
 			}
 		}
 	}()
 
+	if opts.trace {
+		eng.TraceNextRun(sess.ID)
+	}
 	eng.Run(sk, sess)
+	if opts.trace {
+		fmt.Fprintln(live, "Trace written to", sm.ArtifactPath(sess, engine.TraceFileName))
+	}
 
 	events.GlobalBus.Unsubscribe(eventCh)
 	<-done
@@ -228,8 +477,29 @@ func handleRunCommand(sm *session.Manager, eng *engine.Engine, cfg *config.Confi
 		sess = updated
 	}
 
+	if jsonOutput {
+		if err := writeAuditJSON(stdout, sm, sess); err != nil {
+			fmt.Fprintf(stderr, "Failed to read the audit log: %v\n", err)
+			return 1
+		}
+	}
 	if sess.Status == models.StatusCompleted {
 		return 0
 	}
 	return 1
 }
+
+// writeAuditJSON writes every audit entry of sess to w as one JSON array,
+// for "run --output json".
+func writeAuditJSON(w io.Writer, sm *session.Manager, sess *models.Session) error {
+	entries, err := sm.FilterAudit(sess, nil)
+	if err != nil {
+		return err
+	}
+	if entries == nil {
+		entries = []events.AuditEntry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
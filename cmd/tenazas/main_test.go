@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tenazas/internal/client"
+	"tenazas/internal/config"
+	"tenazas/internal/engine"
+	"tenazas/internal/events"
+	"tenazas/internal/session"
+)
+
+// newRunEnv returns a session manager and engine over a fresh storage dir
+// holding one skill, "check", that runs a shell command and ends.
+func newRunEnv(t *testing.T) (*session.Manager, *engine.Engine, *config.Config) {
+	t.Helper()
+	storageDir := t.TempDir()
+	skillsDir := filepath.Join(storageDir, "skills")
+	os.MkdirAll(skillsDir, 0755)
+	data := `{"skill_name": "check", "initial_state": "run", "states": {
+		"run": {"type": "tool", "command": "echo checked", "next": "end"},
+		"end": {"type": "end"}}}`
+	os.WriteFile(filepath.Join(skillsDir, "check.json"), []byte(data), 0644)
+
+	sm := session.NewManager(storageDir)
+	sm.ToggleSkill("check", true)
+	c, _ := client.NewClient("gemini", "gemini", filepath.Join(storageDir, "tenazas.log"))
+	eng := engine.NewEngine(sm, map[string]client.Client{"gemini": c}, "gemini", 5)
+	return sm, eng, &config.Config{StorageDir: storageDir, DefaultClient: "gemini"}
+}
+
+func TestRunOutputJSON(t *testing.T) {
+	sm, eng, cfg := newRunEnv(t)
+
+	var stdout, stderr bytes.Buffer
+	code := handleRunCommand(sm, eng, cfg, runOptions{skill: "check", output: "json"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr %q", code, stderr.String())
+	}
+	var entries []events.AuditEntry
+	if err := json.Unmarshal(stdout.Bytes(), &entries); err != nil {
+		t.Fatalf("stdout is not a JSON array: %v\n%s", err, stdout.String())
+	}
+	var sawCmd bool
+	for _, e := range entries {
+		sawCmd = sawCmd || (e.Type == events.AuditCmdResult && strings.Contains(e.Content, "checked"))
+	}
+	if !sawCmd {
+		t.Errorf("expected the tool's command result among the entries, got %+v", entries)
+	}
+}
+
+func TestRunOutputJSONErrorsGoToStderr(t *testing.T) {
+	sm, eng, cfg := newRunEnv(t)
+
+	var stdout, stderr bytes.Buffer
+	code := handleRunCommand(sm, eng, cfg, runOptions{skill: "missing", output: "json"}, &stdout, &stderr)
+	if code == 0 || stdout.Len() != 0 {
+		t.Errorf("want a non-zero exit and empty stdout, got %d %q", code, stdout.String())
+	}
+	if !strings.Contains(stderr.String(), `Failed to load skill "missing"`) {
+		t.Errorf("expected the failure on stderr, got %q", stderr.String())
+	}
+
+	// Text mode keeps printing everything to stdout.
+	stdout.Reset()
+	stderr.Reset()
+	handleRunCommand(sm, eng, cfg, runOptions{skill: "missing", output: "text"}, &stdout, &stderr)
+	if !strings.Contains(stdout.String(), `Failed to load skill "missing"`) || stderr.Len() != 0 {
+		t.Errorf("text mode should report on stdout, got %q / %q", stdout.String(), stderr.String())
+	}
+}
+
+func TestParseRunArgs(t *testing.T) {
+	opts := parseRunArgs([]string{"--output", "json", "deploy", "--trace"})
+	if opts.skill != "deploy" || opts.output != "json" || !opts.trace {
+		t.Errorf("parseRunArgs = %+v", opts)
+	}
+	if opts := parseRunArgs([]string{"--prompt", "fix the build"}); opts.prompt != "fix the build" || opts.output != "text" {
+		t.Errorf("parseRunArgs(--prompt) = %+v", opts)
+	}
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// attachPollInterval is how often an attached session's audit log is checked
+// for entries written by another process (e.g. the daemon).
+const attachPollInterval = 500 * time.Millisecond
+
+// handleAttach implements "/attach <id>": focus a session that may be running
+// elsewhere and stream its activity, without starting a second engine run.
+func (c *CLI) handleAttach(args []string) {
+	if len(args) == 0 {
+		c.write("Usage: /attach <session-id>\n")
+		return
+	}
+	target, err := c.findSession(args[0])
+	if err != nil {
+		c.writef("Error: %v\n", err)
+		return
+	}
+	if cur := c.currentSession(); cur == nil || cur.ID != target.ID {
+		c.switchSession(target)
+	}
+	c.followSession(target)
+}
+
+// followSession tails sess's audit log from its current end and republishes
+// new entries on the event bus, so runs owned by another process render like
+// local ones. Sessions this process is running need no follower: their
+// entries already reach the bus.
+func (c *CLI) followSession(sess *models.Session) {
+	c.stopFollow()
+	if c.Engine != nil && c.Engine.IsRunning(sess.ID) {
+		return
+	}
+	path := c.Sm.AuditPath(sess)
+	var offset int64
+	if fi, err := os.Stat(path); err == nil {
+		offset = fi.Size()
+	}
+	stop := make(chan struct{})
+	c.mu.Lock()
+	c.followStop = stop
+	poll := c.followPoll
+	c.mu.Unlock()
+	if poll <= 0 {
+		poll = attachPollInterval
+	}
+
+	if sess.Status == models.StatusRunning || sess.Status == models.StatusIntervention {
+		c.writef("%sAttached to %s (%s). Prompts and interventions must be sent from the process running it.\n", Margin, c.shortSessionID(sess.ID), sess.Status)
+	} else {
+		c.writef("%sAttached to %s (%s). Waiting for activity...\n", Margin, c.shortSessionID(sess.ID), sess.Status)
+	}
+	go c.followAudit(sess.ID, path, offset, poll, stop)
+}
+
+// followAudit polls path for entries appended after offset until stop is
+// closed, or until this process starts running the session itself.
+func (c *CLI) followAudit(sessionID, path string, offset int64, poll time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		if c.Engine != nil && c.Engine.IsRunning(sessionID) {
+			return
+		}
+		entries, next := readAuditFrom(path, offset)
+		offset = next
+		for _, entry := range entries {
+			events.GlobalBus.Publish(events.Event{Type: events.EventAudit, SessionID: sessionID, Payload: entry})
+		}
+	}
+}
+
+// readAuditFrom decodes the complete audit lines after offset and returns
+// them with the offset just past the last one. A partially written trailing
+// line is left for the next read.
+func readAuditFrom(path string, offset int64) ([]events.AuditEntry, int64) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, offset
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset
+	}
+
+	var entries []events.AuditEntry
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			break
+		}
+		offset += int64(len(line))
+		var entry events.AuditEntry
+		if json.Unmarshal(line, &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, offset
+}
+
+// attachedAllowed reports whether the command in parts may run while attached
+// to a session another process runs: prompts and commands that drive the
+// run would act on this process's engine, which is not running it.
+func attachedAllowed(parts []string) bool {
+	cmd, ok := lookupCommand(parts[0])
+	return ok && !cmd.drivesRun
+}
+
+// runElsewhere reports whether sess is attached (followed) and being run by
+// another process, so local prompts and interventions would not reach it.
+func (c *CLI) runElsewhere(sess *models.Session) bool {
+	c.mu.Lock()
+	following := c.followStop != nil
+	c.mu.Unlock()
+	if !following || sess == nil || c.Sm == nil || (c.Engine != nil && c.Engine.IsRunning(sess.ID)) {
+		return false
+	}
+	stored, err := c.Sm.Load(sess.ID)
+	return err == nil && (stored.Status == models.StatusRunning || stored.Status == models.StatusIntervention)
+}
+
+// refuseAttached explains why input was ignored while attached.
+func (c *CLI) refuseAttached(sess *models.Session, cmd string) {
+	what := "Prompts are"
+	if cmd != "" && cmd[0] == '/' {
+		what = cmd + " is"
+	}
+	c.writef("Attached: %s is run by another process. %s disabled here; send them from that process, or wait for the run to finish.\n", c.shortSessionID(sess.ID), what)
+}
+
+// stopFollow stops the audit follower, if one is running.
+func (c *CLI) stopFollow() {
+	c.mu.Lock()
+	stop := c.followStop
+	c.followStop = nil
+	c.mu.Unlock()
+	if stop != nil {
+		close(stop)
+	}
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/client"
+	"tenazas/internal/engine"
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+func TestAttachReplaysHistoryAndStreamsExternalEntries(t *testing.T) {
+	c, _, second := setupSessionsTest(t)
+	c.Engine = engine.NewEngine(c.Sm, map[string]client.Client{}, "gemini", 5)
+	c.followPoll = 10 * time.Millisecond
+	defer c.stopFollow()
+
+	second.Status = models.StatusRunning
+	second.SkillName = "nightly"
+	c.Sm.Save(second)
+	c.Sm.AppendAudit(second, events.AuditEntry{Type: events.AuditInfo, Content: "earlier daemon output"})
+
+	c.handleCommand(c.sess, "/attach bbbb")
+
+	if cur := c.currentSession(); cur == nil || cur.ID != second.ID {
+		t.Fatalf("expected CLI to focus %s, got %+v", second.ID, cur)
+	}
+	if !strings.Contains(c.output(), "earlier daemon output") {
+		t.Fatalf("expected history to be replayed, got:\n%s", c.output())
+	}
+
+	// Simulate the daemon appending to the audit log from another process:
+	// the entry reaches the file but not this process's event bus.
+	data, _ := json.Marshal(events.AuditEntry{Type: events.AuditInfo, Content: "live daemon output", Timestamp: time.Now()})
+	f, err := os.OpenFile(c.Sm.AuditPath(second), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("open audit: %v", err)
+	}
+	f.Write(append(data, '\n'))
+	f.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && !strings.Contains(c.output(), "live daemon output") {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !strings.Contains(c.output(), "live daemon output") {
+		t.Fatalf("expected the appended entry to stream, got:\n%s", c.output())
+	}
+
+	if c.Engine.IsRunning(second.ID) {
+		t.Error("attach must not start an engine run")
+	}
+	if reloaded, _ := c.Sm.Load(second.ID); reloaded.Status != models.StatusRunning {
+		t.Errorf("expected session status to be left alone, got %q", reloaded.Status)
+	}
+}
+
+func TestReadAuditFromLeavesPartialLine(t *testing.T) {
+	path := t.TempDir() + "/audit.jsonl"
+	first, _ := json.Marshal(events.AuditEntry{Type: events.AuditInfo, Content: "one"})
+	os.WriteFile(path, append(append(first, '\n'), []byte(`{"type":"info","con`)...), 0644)
+
+	entries, offset := readAuditFrom(path, 0)
+	if len(entries) != 1 || entries[0].Content != "one" {
+		t.Fatalf("expected one complete entry, got %+v", entries)
+	}
+	if offset != int64(len(first)+1) {
+		t.Errorf("expected offset %d, got %d", len(first)+1, offset)
+	}
+}
+
+func TestAttachRefusesPromptsAndInterventions(t *testing.T) {
+	c, _, second := setupSessionsTest(t)
+	c.Engine = engine.NewEngine(c.Sm, map[string]client.Client{}, "gemini", 5)
+	c.followPoll = time.Hour
+	defer c.stopFollow()
+
+	second.Status = models.StatusIntervention
+	c.Sm.Save(second)
+	c.handleCommand(c.sess, "/attach bbbb")
+
+	for _, input := range []string{"/intervene retry", "fix it please", "/run nightly"} {
+		before := len(c.output())
+		c.handleCommand(c.sess, input)
+		if got := c.output()[before:]; !strings.Contains(got, "run by another process") {
+			t.Errorf("expected %q to be refused while attached, got %q", input, got)
+		}
+	}
+	if c.Engine.IsRunning(second.ID) {
+		t.Error("a refused prompt must not start a local run")
+	}
+
+	before := len(c.output())
+	c.handleCommand(c.sess, "/status")
+	if got := c.output()[before:]; strings.Contains(got, "run by another process") {
+		t.Errorf("expected /status to work while attached, got %q", got)
+	}
+
+	// Once the other process is done the session is free to drive here.
+	c.Sm.Update(second, func(s *models.Session) { s.Status = models.StatusCompleted })
+	if c.runElsewhere(c.sess) {
+		t.Error("a finished session should no longer count as run elsewhere")
+	}
+}
+
+func (c *CLI) output() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Out.(*bytes.Buffer).String()
+}
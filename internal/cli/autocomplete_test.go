@@ -8,7 +8,9 @@ import (
 	"strings"
 	"testing"
 
+	"tenazas/internal/models"
 	"tenazas/internal/session"
+	"tenazas/internal/skill"
 )
 
 func TestRenderLine(t *testing.T) {
@@ -70,13 +72,14 @@ func TestGetCompletions(t *testing.T) {
 		input    string
 		expected []string
 	}{
-		{"/", []string{"/run", "/last", "/intervene", "/skills", "/mode", "/tier", "/budget", "/tasks", "/task", "/help"}},
-		{"/r", []string{"/run"}},
+		{"/", []string{"/run", "/last", "/search", "/replay", "/intervene", "/nudge", "/cancel", "/skills", "/commands", "/run-chain", "/mode", "/tier", "/budget", "/persona", "/tasks", "/task", "/commit", "/wrap", "/prefs", "/queue", "/meta", "/undo", "/status", "/sessions", "/switch", "/attach", "/redraw", "/help"}},
+		{"/r", []string{"/run", "/replay", "/run-chain", "/redraw"}},
 		{"/l", []string{"/last"}},
 		{"/i", []string{"/intervene"}},
-		{"/s", []string{"/skills"}},
-		{"/m", []string{"/mode"}},
+		{"/s", []string{"/search", "/skills", "/status", "/sessions", "/switch"}},
+		{"/m", []string{"/mode", "/meta"}},
 		{"/t", []string{"/tier", "/tasks", "/task"}},
+		{"/a", []string{"/attach"}},
 		{"/b", []string{"/budget"}},
 		{"/h", []string{"/help"}},
 		{"/notfound", []string{}},
@@ -133,8 +136,11 @@ func TestGetDimmedSuggestion(t *testing.T) {
 		input    string
 		expected string
 	}{
-		{"/r", "un"},
-		{"/ru", "n"},
+		{"/r", ""},  // /run, /replay and /redraw
+		{"/re", ""}, // /replay and /redraw
+		{"/red", "raw"},
+		{"/rep", "lay"},
+		{"/run-", "chain"},
 		{"/run", ""},
 		{"/", ""}, // More than one match
 		{"/l", "ast"},
@@ -236,3 +242,20 @@ func TestInputBufferManipulation(t *testing.T) {
 		t.Errorf("expected cursorPos 0, got %d", cli.cursorPos)
 	}
 }
+
+func TestGetSkillCompletionsIncludesProjectSkills(t *testing.T) {
+	tmpDir := t.TempDir()
+	cwd := t.TempDir()
+	cli := &CLI{Sm: session.NewManager(tmpDir), sess: &models.Session{CWD: cwd}}
+
+	for _, dir := range []string{filepath.Join(tmpDir, "skills", "deploy"), filepath.Join(skill.ProjectDir(cwd), "lint")} {
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "skill.json"), []byte("{}"), 0644)
+	}
+
+	got := cli.getCompletions("/run ")
+	want := []string{"/run deploy", "/run lint"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getCompletions(%q) = %v; want %v", "/run ", got, want)
+	}
+}
@@ -7,12 +7,12 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
-	"sort"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
@@ -24,6 +24,7 @@ import (
 	"tenazas/internal/registry"
 	"tenazas/internal/session"
 	"tenazas/internal/skill"
+	"tenazas/internal/task"
 )
 
 // permissionState holds the pending permission request and the response channel.
@@ -33,42 +34,65 @@ type permissionState struct {
 }
 
 type CLI struct {
-	Sm            *session.Manager
-	Reg           *registry.Registry
-	Engine        *engine.Engine
-	DefaultClient    string
-	DefaultModelTier string
-	ClientModels     map[string]map[string]string // clientName → tier → model name
-	In            io.Reader
-	Out           io.Writer
-	sess          *models.Session
-	input         []rune
-	cursorPos     int
-	completions   []string
-	completionIdx int
-	inRawMode     bool
-	oldTermState  interface{}
-	mu            sync.Mutex
-	IsImmersive   bool
-	drawer        []string
-	lastTabTime   time.Time
-	isThinking    bool
-	pulseFrame    int
-	lastThought   string
-	skillCount    int
-	lastRows         int // tracks terminal rows for resize cleanup
-	gitBranch        string
-	lastRenderLines  int // tracks how many terminal rows the last input render occupied
-	promptLines      int // current number of wrapped prompt lines (for footer positioning)
-	lastEscTime      time.Time
-	isStreaming       bool // true while engine is producing output; keeps cursor in scroll region
-	currentTask      string // current intent/task from the LLM (e.g. report_intent)
-	permPending      *permissionState // non-nil when waiting for user permission decision
+	Sm                  *session.Manager
+	Reg                 *registry.Registry
+	Engine              *engine.Engine
+	DefaultClient       string
+	DefaultModelTier    string
+	ClientModels        map[string]map[string]string // clientName → tier → model name
+	TimeFormat          formatter.TimeFormat         // timestamp layout/timezone for audit output
+	ShortIDLength       int                          // session ID characters shown in listings; 0 means formatter.DefaultShortIDLength
+	Tasks               task.Settings                // priority labels and label routing for /task
+	TranscriptEnabled   bool                         // mirror session output to <session-id>.transcript.txt
+	DefaultApprovalMode string                       // models.ApprovalMode* for new sessions; empty means plan
+	AttachID            string                       // session to attach to instead of starting one (tenazas attach)
+	ReadOnly            bool                         // observer mode: refuse prompts and commands that change the session
+	Plain               bool                         // line mode: no raw input, scroll region, footer or drawer (tenazas --plain)
+	In                  io.Reader
+	Out                 io.Writer
+	sess                *models.Session
+	input               []rune
+	cursorPos           int
+	completions         []string
+	completionIdx       int
+	inRawMode           bool
+	oldTermState        interface{}
+	mu                  sync.Mutex
+	IsImmersive         bool
+	drawer              []string
+	lastTabTime         time.Time
+	isThinking          bool
+	pulseFrame          int
+	lastThought         string
+	skillCount          int
+	lastRows            int // tracks terminal rows for resize cleanup
+	gitBranch           string
+	lastRenderLines     int // tracks how many terminal rows the last input render occupied
+	promptLines         int // current number of wrapped prompt lines (for footer positioning)
+	lastEscTime         time.Time
+	lastCtrlCTime       time.Time
+	isStreaming         bool                    // true while engine is producing output; keeps cursor in scroll region
+	streamRow           int                     // scroll-region row the streaming cursor was last anchored to
+	currentTask         string                  // current intent/task from the LLM (e.g. report_intent)
+	permPending         *permissionState        // non-nil when waiting for user permission decision
+	picker              *taskPicker             // open /task pick list; nil when closed
+	render              renderScheduler         // batches prompt/footer/drawer redraws
+	transcript          io.Writer               // plain-text mirror of session output; nil when disabled
+	outCol              int                     // output column after the last reflowed write
+	outIndent           int                     // leading spaces of the output line in progress; see reflowText
+	instanceID          string                  // registry instance bound to the focused session
+	eventCh             chan events.Event       // subscription feeding the active listenOn
+	transcriptClose     func()                  // closes the open transcript; nil when none
+	followStop          chan struct{}           // stops the audit follower of an attached session; nil when none
+	followPoll          time.Duration           // audit follower poll interval; zero means attachPollInterval
+	termSize            func() (rows, cols int) // terminal size source; nil queries the real terminal
+	prefs               uiPrefs                 // UI preferences restored from PrefsFileName
+	undoStack           []sessionSettings       // settings before recent undoable commands, newest last
 }
 
 func (c *CLI) refreshSkillCount() {
 	if c.Sm != nil {
-		skills, _ := skill.List(c.Sm.StoragePath)
+		skills, _ := skill.ListAll(c.Sm.StoragePath, sessionCWD(c.currentSession()))
 		c.mu.Lock()
 		c.skillCount = len(skills)
 		c.mu.Unlock()
@@ -99,7 +123,7 @@ func (c *CLI) refreshGitBranch() {
 }
 
 func NewCLI(sm *session.Manager, reg *registry.Registry, eng *engine.Engine, defaultClient, defaultModelTier string, clientModels map[string]map[string]string) *CLI {
-	return &CLI{
+	c := &CLI{
 		Sm:               sm,
 		Reg:              reg,
 		Engine:           eng,
@@ -109,6 +133,11 @@ func NewCLI(sm *session.Manager, reg *registry.Registry, eng *engine.Engine, def
 		In:               os.Stdin,
 		Out:              os.Stdout,
 	}
+	if sm != nil {
+		c.prefs = loadPrefs(sm.StoragePath)
+		c.IsImmersive = c.prefs.Immersive
+	}
+	return c
 }
 
 const (
@@ -120,6 +149,7 @@ const (
 	escBlueWhite     = "\x1b[44;37m"
 	escCyan          = "\x1b[36m"
 	escBoldCyan      = "\x1b[1;36m"
+	escBoldRed       = "\x1b[1;31m"
 	escGreen         = "\x1b[32m"
 	escGray          = "\x1b[90m"
 	escDim           = "\x1b[2m"
@@ -135,11 +165,14 @@ const (
 const (
 	DrawerHeight      = 8
 	DoubleTabInterval = 300 * time.Millisecond
-	PromptNormal      = "› "
-	PromptPulse       = "› "
-	PromptOffset      = 2
-	Margin            = "  "
-	MarginWidth       = 2
+	// DoubleCtrlCInterval is how quickly a second Ctrl-C must follow the
+	// first to exit while a session waits on an intervention.
+	DoubleCtrlCInterval = time.Second
+	PromptNormal        = "› "
+	PromptPulse         = "› "
+	PromptOffset        = 2
+	Margin              = "  "
+	MarginWidth         = 2
 )
 
 func (c *CLI) getPrompt() string {
@@ -190,29 +223,58 @@ func (c *CLI) writeAtomic(sb *strings.Builder, fn func(*strings.Builder)) {
 // writeInScrollRegion writes content into the scroll region.
 // During streaming the cursor is already there, so a plain write suffices.
 // Otherwise it saves the cursor, jumps to the scroll-region bottom,
-// writes, and restores.
+// writes, and restores. The check and the write share one critical section
+// so a resize redraw can't land between them.
 func (c *CLI) writeInScrollRegion(content string) {
-	c.mu.Lock()
-	inRaw := c.inRawMode
-	streaming := c.isStreaming
-	c.mu.Unlock()
-	if !inRaw {
-		c.write(content)
+	c.writeTranscript(content)
+	if c.Out == nil {
 		return
 	}
-	if streaming {
-		c.write(content)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.inRawMode || c.isStreaming {
+		c.writeLocked(content)
 		return
 	}
 	rows, _ := c.getTermSize()
-	scrollBottom := rows - 6
+	c.writeLocked(escSaveCursor + fmt.Sprintf(escMoveTo, c.scrollBottomLocked(rows)) + content + escRestoreCursor)
+}
+
+// scrollBottomLocked is the last row of the scroll region for a terminal of
+// the given height.
+func (c *CLI) scrollBottomLocked(rows int) int {
+	bottom := rows - 6
 	if c.IsImmersive {
-		scrollBottom = rows - DrawerHeight - 6
+		bottom = rows - DrawerHeight - 6
+	}
+	if bottom < 1 {
+		bottom = 1
 	}
-	if scrollBottom < 1 {
-		scrollBottom = 1
+	return bottom
+}
+
+// startStreaming marks the session as streaming and, on the first chunk,
+// moves the cursor into the scroll region for the chunks that follow.
+func (c *CLI) startStreaming() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.isStreaming {
+		return
+	}
+	c.isStreaming = true
+	if !c.Plain && c.Out != nil {
+		var sb strings.Builder
+		c.anchorStreamLocked(&sb)
+		c.writeLocked(sb.String())
 	}
-	c.write(escSaveCursor + fmt.Sprintf(escMoveTo, scrollBottom) + content + escRestoreCursor)
+}
+
+// anchorStreamLocked moves the cursor to the bottom of the current scroll
+// region, where streamed chunks are written.
+func (c *CLI) anchorStreamLocked(sb *strings.Builder) {
+	rows, _ := c.getTermSize()
+	c.streamRow = c.scrollBottomLocked(rows)
+	fmt.Fprintf(sb, escMoveTo, c.streamRow)
 }
 
 func (c *CLI) drawBrandingAtomic(sb *strings.Builder) {
@@ -260,32 +322,57 @@ func (c *CLI) Run(resume bool) error {
 	}
 	c.sess = sess
 
-	instanceID := fmt.Sprintf("cli-%d", os.Getpid())
-	c.Reg.Set(instanceID, sess.ID)
-	c.Reg.SetVerbosity(instanceID, "HIGH")
-
-	c.writeEscape(EscClear)
-	c.setupTerminal()
-	c.refreshSkillCount()
-	c.refreshGitBranch()
-	defer c.writeEscape("\x1b[r")
+	if c.TranscriptEnabled {
+		closeTranscript, err := c.openTranscript(sess)
+		if err != nil {
+			c.write(fmt.Sprintln("Warning: could not open transcript:", err))
+		}
+		c.transcriptClose = closeTranscript
+		defer c.closeTranscript()
+	}
 
-	c.drawBranding()
+	c.instanceID = fmt.Sprintf("cli-%d", os.Getpid())
+	c.Reg.Set(c.instanceID, sess.ID)
+	c.Reg.SetVerbosity(c.instanceID, c.prefs.verbosity())
 
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGWINCH)
-	go func() {
-		for range sigChan {
-			c.mu.Lock()
-			c.redrawScreenLocked()
-			c.mu.Unlock()
-		}
-	}()
+	if c.Plain {
+		c.Out = &formatter.PlainWriter{W: c.Out}
+		c.refreshSkillCount()
+		c.refreshGitBranch()
+		c.writePlainHeader(sess)
+	} else {
+		c.writeEscape(EscClear)
+		c.setupTerminal()
+		c.refreshSkillCount()
+		c.refreshGitBranch()
+		defer c.writeEscape("\x1b[r")
+
+		c.drawBranding()
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGWINCH)
+		go func() {
+			for range sigChan {
+				c.mu.Lock()
+				c.redrawScreenLocked()
+				c.mu.Unlock()
+			}
+		}()
+	}
 
 	c.write(Margin + "Commands: /run <skill>, /last <N>, /intervene <action>, /mode, /tier, /budget, /help\n")
+	attaching := c.AttachID != ""
+	if !resume && !attaching {
+		c.warnDefaultYolo()
+	}
+	if c.ReadOnly {
+		c.write(Margin + escDim + "Observing read-only: prompts and commands that change the session are disabled." + escReset + "\n")
+	}
 
-	if resume {
+	if resume || attaching {
 		c.replayHistory(sess)
+	}
+	if resume {
 		if sess.SkillName != "" {
 			c.resumeSkill(sess)
 		}
@@ -302,8 +389,17 @@ func (c *CLI) Run(resume bool) error {
 		return resp
 	}
 
-	go c.listenEvents(sess.ID)
-	go c.pulseLoop()
+	if !c.Plain {
+		stopRender := make(chan struct{})
+		defer close(stopRender)
+		go c.renderLoop(stopRender)
+		go c.pulseLoop()
+	}
+	c.attachEvents(sess.ID)
+	if attaching {
+		c.followSession(sess)
+		defer c.stopFollow()
+	}
 
 	return c.repl(sess)
 }
@@ -322,9 +418,9 @@ func (c *CLI) pulseLoop() {
 		c.pulseFrame++
 		c.mu.Unlock()
 		if hasTask && sess != nil {
-			c.drawFooter(sess)
+			c.requestRender(renderFooter)
 		} else {
-			c.renderLine()
+			c.requestRender(renderPrompt)
 		}
 	}
 }
@@ -392,6 +488,7 @@ func (c *CLI) selectSession() (*models.Session, error) {
 			escDim, "#", "Modified", "Created", "Client", "ID", "Summary", escReset)
 		fmt.Fprintf(&sb, "  %s%s%s\n", escDim, strings.Repeat("─", 86), escReset)
 
+		ids := sessionIDs(sessions)
 		for i, s := range sessions {
 			num := page*pageSize + i + 1
 			cursor := "  "
@@ -409,7 +506,7 @@ func (c *CLI) selectSession() (*models.Session, error) {
 				clientName = c.DefaultClient
 			}
 
-			shortID := s.ID[:8]
+			shortID := formatter.ShortID(s.ID, ids, c.ShortIDLength)
 
 			summary := s.Summary
 			if summary == "" && s.Title != "" {
@@ -482,6 +579,12 @@ func (c *CLI) selectSession() (*models.Session, error) {
 }
 
 func (c *CLI) initializeSession(resume bool) (*models.Session, error) {
+	if c.AttachID != "" {
+		return c.findSession(c.AttachID)
+	}
+	if resume && c.Plain {
+		return c.latestSession()
+	}
 	if resume {
 		return c.selectSession()
 	}
@@ -496,19 +599,33 @@ func (c *CLI) initializeSession(resume bool) (*models.Session, error) {
 		CreatedAt:    now,
 		LastUpdated:  now,
 		RoleCache:    make(map[string]string),
-		ApprovalMode: models.ApprovalModePlan,
+		ApprovalMode: c.Sm.ProjectApprovalMode(cwd),
+		Wrap:         c.prefs.Wrap,
+	}
+	if c.DefaultApprovalMode != "" {
+		sess.ApprovalMode = c.DefaultApprovalMode
+		sess.Yolo = c.DefaultApprovalMode == models.ApprovalModeYolo
 	}
 	c.Sm.Save(sess)
 	return sess, nil
 }
 
+// warnDefaultYolo prints a banner when new sessions start in yolo mode, so
+// nobody runs unattended edits by accident.
+func (c *CLI) warnDefaultYolo() {
+	if c.DefaultApprovalMode != models.ApprovalModeYolo {
+		return
+	}
+	c.write(Margin + escBoldRed + "⚠ YOLO MODE: default_approval_mode is yolo — the agent will edit files and run commands without asking." + escReset + "\n")
+	c.write(Margin + "Use /mode plan or shift+tab to switch this session.\n")
+}
+
 func (c *CLI) resumeSkill(sess *models.Session) {
-	sk, err := c.Sm.LoadSkill(sess.SkillName)
+	sk, err := c.Sm.LoadSkillFor(sess.CWD, sess.SkillName)
 	if err == nil {
-		if sess.Status != models.StatusRunning && sess.Status != models.StatusIntervention {
+		if status := c.snapshot(sess).Status; status != models.StatusRunning && status != models.StatusIntervention {
 			c.write(fmt.Sprintf("Resuming task: %s (Skill: %s)\n", sess.ID, sess.SkillName))
-			sess.Status = models.StatusRunning
-			c.Sm.Save(sess)
+			c.updateSession(sess, func(s *models.Session) { s.Status = models.StatusRunning })
 		}
 		go c.Engine.Run(sk, sess)
 	}
@@ -520,22 +637,30 @@ func (c *CLI) setThinking(thinking bool) {
 	c.isThinking = thinking
 	c.mu.Unlock()
 	if changed {
-		c.renderLine()
+		c.requestRender(renderPrompt)
 	}
 }
 
 func (c *CLI) listenEvents(sessionID string) {
-	eventCh := events.GlobalBus.Subscribe()
-	f := &formatter.AnsiFormatter{}
+	c.listenOn(events.GlobalBus.Subscribe(), sessionID)
+}
+
+// listenOn renders sessionID's events from eventCh until it is closed.
+func (c *CLI) listenOn(eventCh chan events.Event, sessionID string) {
+	f := &formatter.AnsiFormatter{Time: c.TimeFormat}
 
 	for e := range eventCh {
+		if p, ok := e.Payload.(events.StorageErrorPayload); ok {
+			c.writeInScrollRegion(fmt.Sprintf("\n%s%s⚠ %s%s\n", Margin, escBoldRed, p.Message(), escReset))
+			continue
+		}
 		if e.SessionID == sessionID && e.Type == events.EventAudit {
 			audit, ok := e.Payload.(events.AuditEntry)
 			if !ok {
 				continue
 			}
 
-			if audit.Type == events.AuditLLMPrompt {
+			if audit.Type == events.AuditLLMPrompt || audit.Type == events.AuditResume {
 				c.mu.Lock()
 				c.lastThought = ""
 				c.currentTask = ""
@@ -543,22 +668,7 @@ func (c *CLI) listenEvents(sessionID string) {
 				c.mu.Unlock()
 				c.setThinking(true)
 			} else if audit.Type == events.AuditLLMChunk || audit.Type == events.AuditLLMThought {
-				c.mu.Lock()
-				wasStreaming := c.isStreaming
-				c.isStreaming = true
-				c.mu.Unlock()
-				if !wasStreaming {
-					// Move cursor into scroll region before first chunk
-					rows, _ := c.getTermSize()
-					scrollEnd := rows - 6
-					if c.IsImmersive {
-						scrollEnd = rows - DrawerHeight - 6
-					}
-					if scrollEnd < 1 {
-						scrollEnd = 1
-					}
-					c.write(fmt.Sprintf(escMoveTo, scrollEnd))
-				}
+				c.startStreaming()
 				c.setThinking(false)
 			}
 
@@ -573,16 +683,13 @@ func (c *CLI) listenEvents(sessionID string) {
 			if audit.Type == events.AuditIntent {
 				c.mu.Lock()
 				c.currentTask = audit.Content
-				sess := c.sess
 				c.mu.Unlock()
-				if sess != nil {
-					c.drawFooter(sess)
-				}
+				c.requestRender(renderFooter)
 				continue
 			}
 
 			if audit.Type == events.AuditLLMChunk {
-				c.writeInScrollRegion(audit.Content)
+				c.writeInScrollRegion(c.reflowOutput(audit.Content))
 				continue
 			}
 
@@ -599,16 +706,19 @@ func (c *CLI) listenEvents(sessionID string) {
 				c.isStreaming = false
 				isImm := c.IsImmersive
 				c.mu.Unlock()
+				if !c.showsAudit(audit.Type) {
+					continue
+				}
 				formatted := f.Format(audit)
 				if isImm {
 					c.addThought(formatted)
 					continue
 				}
-				c.writeInScrollRegion(fmt.Sprintf("\n%s%s\n", Margin, formatted))
+				c.writeInScrollRegion(c.reflowOutput(fmt.Sprintf("\n%s%s\n", Margin, formatted)))
 				continue
 			}
 
-			c.writeInScrollRegion(fmt.Sprintf("\n%s%s\n", Margin, f.Format(audit)))
+			c.writeInScrollRegion(c.reflowOutput(fmt.Sprintf("\n%s%s\n", Margin, f.Format(audit))))
 			if audit.Type == events.AuditIntervention {
 				c.writeInScrollRegion(fmt.Sprintf("\n%sType `/intervene <retry|proceed_to_fail|abort>`\n", Margin))
 			}
@@ -632,40 +742,24 @@ func (c *CLI) getCompletions(line string) []string {
 		return []string{}
 	}
 
-	commands := []string{"/run", "/last", "/intervene", "/skills", "/mode", "/tier", "/budget", "/tasks", "/task", "/help"}
-
-	if strings.HasPrefix(line, "/task ") {
-		prefix := strings.TrimPrefix(line, "/task ")
-		subs := []string{"show", "next", "complete", "add", "unblock"}
-		matches := []string{}
-		for _, s := range subs {
-			if strings.HasPrefix(s, prefix) {
-				matches = append(matches, "/task "+s)
-			}
-		}
-		return matches
-	}
-
-	if strings.HasPrefix(line, "/run ") {
-		prefix := strings.TrimPrefix(line, "/run ")
-		skills, err := skill.List(c.Sm.StoragePath)
-		if err != nil {
-			return []string{}
+	matches := []string{}
+	if name, prefix, ok := strings.Cut(line, " "); ok {
+		cmd, found := lookupCommand(name)
+		if !found || cmd.args == nil {
+			return matches
 		}
-		matches := []string{}
-		for _, s := range skills {
-			if strings.HasPrefix(s, prefix) {
-				matches = append(matches, "/run "+s)
+		prefix = strings.ToLower(prefix)
+		for _, arg := range cmd.args(c) {
+			if strings.HasPrefix(strings.ToLower(arg), prefix) {
+				matches = append(matches, name+" "+arg)
 			}
 		}
-		sort.Strings(matches)
 		return matches
 	}
 
-	matches := []string{}
 	for _, cmd := range commands {
-		if strings.HasPrefix(cmd, line) {
-			matches = append(matches, cmd)
+		if strings.HasPrefix(cmd.name, line) {
+			matches = append(matches, cmd.name)
 		}
 	}
 	return matches
@@ -683,6 +777,9 @@ func (c *CLI) getDimmedSuggestion(line string) string {
 }
 
 func (c *CLI) redrawScreenLocked() {
+	if c.Plain {
+		return
+	}
 	var sb strings.Builder
 	sb.WriteString(EscClear)
 	c.setupTerminalAtomic(&sb)
@@ -691,34 +788,56 @@ func (c *CLI) redrawScreenLocked() {
 		c.replayHistoryAtomic(&sb, c.sess)
 	}
 	c.redrawAllAtomic(&sb)
+	if c.isStreaming {
+		// Setting the scroll region homed the cursor and any position saved
+		// before the resize may be off-screen, so re-anchor the stream.
+		c.outCol, c.outIndent = 0, 0
+		c.anchorStreamLocked(&sb)
+	}
 	c.writeLocked(sb.String())
 }
 
-func (c *CLI) toggleImmersive() {
+// redraw re-queries the terminal size and rebuilds the scroll region, footer,
+// drawer and prompt from scratch, for terminals that miss SIGWINCH.
+func (c *CLI) redraw() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.IsImmersive = !c.IsImmersive
 	c.redrawScreenLocked()
 }
 
+func (c *CLI) toggleImmersive() {
+	c.mu.Lock()
+	c.setImmersiveLocked(!c.IsImmersive)
+	c.redrawScreenLocked()
+	c.mu.Unlock()
+	c.savePrefs()
+}
+
 func (c *CLI) handleTab() {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.handleTabLocked(c.sess)
+	toggled := c.handleTabLocked(c.sess)
+	c.mu.Unlock()
+	if toggled {
+		c.savePrefs()
+	}
 }
 
-func (c *CLI) handleTabLocked(sess *models.Session) {
+// handleTabLocked cycles completions, or toggles immersive mode on a double
+// tab. It reports whether it toggled, so the caller saves the preferences
+// once it releases c.mu.
+func (c *CLI) handleTabLocked(sess *models.Session) bool {
 	now := time.Now()
 	if !c.lastTabTime.IsZero() && now.Sub(c.lastTabTime) < DoubleTabInterval {
 		if c.shouldToggleImmersiveLocked(now) {
-			c.IsImmersive = !c.IsImmersive
+			c.setImmersiveLocked(!c.IsImmersive)
 			c.lastTabTime = time.Time{}
 			c.redrawScreenLocked()
-			return
+			return true
 		}
 	}
 	c.lastTabTime = now
 	c.cycleCompletionsLocked()
+	return false
 }
 
 func (c *CLI) shouldToggleImmersiveLocked(now time.Time) bool {
@@ -957,63 +1076,59 @@ func (c *CLI) renderLine() {
 func (c *CLI) handleCommand(sess *models.Session, text string) {
 	parts := strings.Fields(text)
 	if len(parts) == 0 {
+		c.write("Nothing to send. Type a prompt, or /help for commands.\n")
 		return
 	}
 	cmd := parts[0]
+	if c.ReadOnly && !readOnlyAllowed(parts) {
+		c.refuseReadOnly(cmd)
+		return
+	}
+	if !attachedAllowed(parts) && c.runElsewhere(sess) {
+		c.refuseAttached(sess, cmd)
+		return
+	}
 
-	switch cmd {
-	case "/run":
-		if len(parts) > 1 {
-			c.handleRun(sess, parts[1])
-		}
-	case "/last":
-		n := 5
-		if len(parts) > 1 {
-			fmt.Sscanf(parts[1], "%d", &n)
-		}
-		c.handleLast(sess, n)
-	case "/intervene":
-		if len(parts) > 1 {
-			c.Engine.ResolveIntervention(sess.ID, parts[1])
-		}
-	case "/skills":
-		c.handleSkills(parts[1:])
-	case "/mode":
-		c.handleMode(sess, parts[1:])
-	case "/tier":
-		c.handleTier(sess, parts[1:])
-	case "/budget":
-		c.handleBudget(sess, parts[1:])
-	case "/tasks":
-		c.handleTasks()
-	case "/task":
-		c.handleTask(sess, parts[1:])
-	case "/help":
-		c.handleHelp()
-	default:
-		go c.Engine.ExecutePrompt(sess, text)
+	if command, ok := lookupCommand(cmd); ok {
+		if command.undoable {
+			c.runUndoable(command, sess, parts[1:])
+		} else {
+			command.run(c, sess, parts[1:])
+		}
+		return
 	}
+	go c.Engine.ExecutePrompt(sess, text)
 }
 
 func (c *CLI) repl(sess *models.Session) error {
-	fd := int(syscall.Stdin)
-	if oldState, err := enableRawMode(fd); err == nil {
-		c.inRawMode = true
-		c.oldTermState = oldState
-		defer restoreTerminal(fd, oldState)
-		return c.replRaw(sess)
+	if !c.Plain {
+		fd := int(syscall.Stdin)
+		if oldState, err := enableRawMode(fd); err == nil {
+			c.inRawMode = true
+			c.oldTermState = oldState
+			defer restoreTerminal(fd, oldState)
+			return c.replRaw(sess)
+		}
 	}
+	return c.replLines()
+}
 
+// replLines reads input a line at a time: the --plain mode, and the
+// fallback when the terminal cannot enter raw mode.
+func (c *CLI) replLines() error {
 	scanner := bufio.NewScanner(c.In)
 	for {
-		c.write("\n" + Margin + PromptNormal)
+		if !c.Plain {
+			c.write("\n" + Margin + PromptNormal)
+		}
 		if !scanner.Scan() {
 			return io.EOF
 		}
-		text := scanner.Text()
-		if text != "" {
-			c.handleCommand(sess, text)
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || c.answerPermissionLine(text) {
+			continue
 		}
+		c.handleCommand(c.currentSession(), text)
 	}
 }
 
@@ -1028,7 +1143,11 @@ func (c *CLI) replRaw(sess *models.Session) error {
 		}
 
 		c.mu.Lock()
-		// Ctrl+C always exits, even during permission prompts
+		if c.sess != nil {
+			sess = c.sess // follow /switch
+		}
+		// Ctrl+C exits, even during permission prompts; only a blocked
+		// session keeps the CLI alive on the first press
 		if r == '\x03' {
 			// If permission pending, reject it before exiting
 			if c.permPending != nil {
@@ -1038,10 +1157,13 @@ func (c *CLI) replRaw(sess *models.Session) error {
 				c.writePermissionFeedback(perm.req.Title, "cancelled")
 				optID := findOptionByKind(perm.req.Options, "reject_once")
 				perm.resp <- client.PermissionResponse{OptionID: optID}
-			} else {
-				c.mu.Unlock()
+				return nil
 			}
-			return nil
+			c.mu.Unlock()
+			if c.handleCtrlC(sess) {
+				return nil
+			}
+			continue
 		}
 		// If a permission prompt is pending, route the keypress to the permission handler
 		if c.permPending != nil {
@@ -1053,6 +1175,15 @@ func (c *CLI) replRaw(sess *models.Session) error {
 			c.mu.Unlock()
 			continue
 		}
+		// An open task picker takes every key until it closes
+		if c.picker != nil {
+			c.mu.Unlock()
+			if r == '\x1b' {
+				time.Sleep(50 * time.Millisecond)
+			}
+			c.handlePickerKey(decodePickerKey(r, reader))
+			continue
+		}
 		if r != '\t' {
 			c.lastTabTime = time.Time{}
 		}
@@ -1083,7 +1214,13 @@ func (c *CLI) replRaw(sess *models.Session) error {
 				c.handleCommand(sess, line)
 			}
 		case '\t':
-			c.handleTabLocked(sess)
+			toggled := c.handleTabLocked(sess)
+			c.mu.Unlock()
+			if toggled {
+				c.savePrefs()
+			}
+		case '\x0c': // Ctrl-L
+			c.redrawScreenLocked()
 			c.mu.Unlock()
 		case '\x7f', '\x08':
 			c.handleBackspaceLocked()
@@ -1110,9 +1247,10 @@ func (c *CLI) handleSkills(args []string) {
 	c.Sm.RefreshSkillRegistry()
 	defer c.refreshSkillCount()
 
+	cwd := sessionCWD(c.currentSession())
 	if len(args) >= 2 && args[0] == "toggle" {
 		name := args[1]
-		active, _ := c.Sm.GetActiveSkills()
+		active, _ := c.Sm.ActiveSkillsFor(cwd)
 		enabled := false
 		for _, s := range active {
 			if s == name {
@@ -1124,38 +1262,170 @@ func (c *CLI) handleSkills(args []string) {
 		return
 	}
 
-	all, _ := skill.List(c.Sm.StoragePath)
-	active, _ := c.Sm.GetActiveSkills()
+	all, _ := skill.ListAll(c.Sm.StoragePath, cwd)
+	switch {
+	case len(args) >= 2 && args[0] == "--tag":
+		all = skill.FilterByTag(all, args[1])
+	case len(args) >= 2 && args[0] == "search":
+		all = searchSkills(all, strings.Join(args[1:], " "))
+	}
+	if len(args) >= 2 && len(all) == 0 {
+		c.write("No skills match.\n")
+		return
+	}
+	active, _ := c.Sm.ActiveSkillsFor(cwd)
 
 	activeMap := make(map[string]bool)
 	for _, s := range active {
 		activeMap[s] = true
 	}
 
-	c.write("STATUS  NAME\n")
+	c.write("STATUS  SOURCE   NAME\n")
 	for _, s := range all {
 		status := "[ ]"
-		if activeMap[s] {
+		if activeMap[s.Name] {
 			status = "[X]"
 		}
-		c.write(fmt.Sprintf("%-7s %s\n", status, s))
+		line := fmt.Sprintf("%-7s %-8s %s", status, s.Source, s.Name)
+		if len(s.Tags) > 0 {
+			line += "  [" + strings.Join(s.Tags, ", ") + "]"
+		}
+		c.write(line + "\n")
 	}
 }
 
-func (c *CLI) handleRun(sess *models.Session, skillName string) {
-	sk, err := c.Sm.LoadSkill(skillName)
+// searchSkills keeps the skills whose name or one of whose tags contains
+// query, ignoring case.
+func searchSkills(entries []skill.Entry, query string) []skill.Entry {
+	q := strings.ToLower(query)
+	var out []skill.Entry
+	for _, e := range entries {
+		match := strings.Contains(strings.ToLower(e.Name), q)
+		for _, t := range e.Tags {
+			match = match || strings.Contains(strings.ToLower(t), q)
+		}
+		if match {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// sessionCWD returns the directory whose .tenazas/skills apply to sess.
+func sessionCWD(sess *models.Session) string {
+	if sess == nil {
+		return ""
+	}
+	return sess.CWD
+}
+
+// handleRunArgs parses "/run <skill> [--trace] [--from-checkpoint]".
+func (c *CLI) handleRunArgs(sess *models.Session, args []string) {
+	skillName, trace, fromCheckpoint := "", false, false
+	for _, a := range args {
+		if a == "--trace" {
+			trace = true
+		} else if a == "--from-checkpoint" {
+			fromCheckpoint = true
+		} else if skillName == "" {
+			skillName = a
+		}
+	}
+	if skillName == "" {
+		c.write("Usage: /run <skill> [--trace] [--from-checkpoint]\n")
+		return
+	}
+	if trace {
+		c.Engine.TraceNextRun(sess.ID)
+	}
+	c.handleRun(sess, skillName, fromCheckpoint)
+}
+
+func (c *CLI) handleRun(sess *models.Session, skillName string, fromCheckpoint bool) {
+	sk, err := c.Sm.LoadSkillFor(sess.CWD, skillName)
 	if err != nil {
-		c.write(fmt.Sprintln("Skill error:", err))
+		msg := fmt.Sprintf("Skill error: %v", err)
+		if hint := skill.NotFoundHint(c.Sm.StoragePath, sess.CWD, skillName); hint != "" {
+			msg += " (" + hint + ")"
+		}
+		c.write(msg + "\n")
 		return
 	}
-	sess.SkillName = skillName
-	c.Sm.Save(sess)
+	if c.Engine.IsRunning(sess.ID) {
+		if fromCheckpoint {
+			c.write("Session busy: --from-checkpoint needs the current work to finish first.\n")
+			return
+		}
+		c.writef("Session busy: %s will run when the current work finishes.\n", skillName)
+		go c.Engine.RunChain(sess, []*models.SkillGraph{sk}, false)
+		return
+	}
+	if fromCheckpoint {
+		if sess.SkillName != skillName {
+			c.writef("No checkpoint for %s in this session (last skill: %q).\n", skillName, sess.SkillName)
+			return
+		}
+		node, err := c.Engine.ResetToCheckpoint(sk, sess)
+		if err != nil {
+			c.writef("Checkpoint error: %v\n", err)
+			return
+		}
+		c.writef("Resuming %s from checkpoint at %s.\n", skillName, node)
+	}
+	c.updateSession(sess, func(s *models.Session) { s.SkillName = skillName })
 	go c.Engine.Run(sk, sess)
 }
 
+// handleRunChain implements "/run-chain <skill>... [--continue-on-error]":
+// the skills run in order on the session, each after the previous ends.
+func (c *CLI) handleRunChain(sess *models.Session, args []string) {
+	continueOnError := false
+	var skills []*models.SkillGraph
+	for _, a := range args {
+		if a == "--continue-on-error" {
+			continueOnError = true
+			continue
+		}
+		sk, err := c.Sm.LoadSkillFor(sess.CWD, a)
+		if err != nil {
+			c.writef("Skill error: %v\n", err)
+			return
+		}
+		skills = append(skills, sk)
+	}
+	if len(skills) == 0 {
+		c.write("Usage: /run-chain <skill> [skill...] [--continue-on-error]\n")
+		return
+	}
+	names := make([]string, len(skills))
+	for i, sk := range skills {
+		names[i] = sk.Name
+	}
+	c.writef("Running chain: %s\n", strings.Join(names, " → "))
+	go c.Engine.RunChain(sess, skills, continueOnError)
+}
+
+// handleCancelCall implements "/cancel [role|node]": it aborts the LLM call
+// in flight, if it matches, without cancelling the session.
+func (c *CLI) handleCancelCall(sess *models.Session, args []string) {
+	target := ""
+	if len(args) > 0 {
+		target = args[0]
+	}
+	if !c.Engine.CancelCall(sess.ID, target) {
+		if target == "" {
+			c.write("No LLM call in flight.\n")
+		} else {
+			c.writef("No LLM call in flight for %s.\n", target)
+		}
+		return
+	}
+	c.write("Call cancelled; the state will retry it.\n")
+}
+
 func (c *CLI) handleLast(sess *models.Session, n int) {
 	logs, _ := c.Sm.GetLastAudit(sess, n)
-	f := &formatter.AnsiFormatter{}
+	f := &formatter.AnsiFormatter{Time: c.TimeFormat}
 	var output strings.Builder
 	for _, l := range logs {
 		fmt.Fprintln(&output, f.Format(l))
@@ -1178,7 +1448,7 @@ func (c *CLI) replayHistoryAtomic(sb *strings.Builder, sess *models.Session) {
 	if err != nil || len(logs) == 0 {
 		return
 	}
-	f := &formatter.AnsiFormatter{}
+	f := &formatter.AnsiFormatter{Time: c.TimeFormat}
 	fmt.Fprintf(sb, "\n%s%s── Session History ──%s\n\n", Margin, escDim, escReset)
 	for _, entry := range logs {
 		switch entry.Type {
@@ -1233,11 +1503,20 @@ func FormatFooterLine1(d FooterData, cols int) string {
 	}
 	right := strings.Join(rightParts, " · ")
 
-	gap := cols - len(left) - len(right)
-	if gap < 1 {
-		gap = 1
+	return footerRow(left, right, cols)
+}
+
+// footerRow lays left and right out on one cols-wide row. On narrow
+// terminals right is dropped first, then left is cut short.
+func footerRow(left, right string, cols int) string {
+	lw, rw := utf8.RuneCountInString(left), utf8.RuneCountInString(right)
+	if lw+1+rw <= cols {
+		return left + strings.Repeat(" ", cols-lw-rw) + right
+	}
+	if lw > cols && cols > 3 {
+		return string([]rune(left)[:cols-3]) + "..."
 	}
-	return left + strings.Repeat(" ", gap) + right
+	return left
 }
 
 // ModeColor returns the ANSI color escape for the current approval mode.
@@ -1265,36 +1544,40 @@ func FormatFooterLine2(d FooterData, cols int) string {
 	left := "shift+tab " + displayMode + " · ctrl+s run skill"
 	right := fmt.Sprintf("Skills: %d", d.SkillCount)
 
-	gap := cols - len(left) - len(right)
-	if gap < 1 {
-		gap = 1
-	}
-	return left + strings.Repeat(" ", gap) + right
+	return footerRow(left, right, cols)
 }
 
 func (c *CLI) handleMode(sess *models.Session, args []string) {
 	if len(args) == 0 {
-		c.write(fmt.Sprintf("Current mode: %s (Yolo: %v)\n", sess.ApprovalMode, sess.Yolo))
+		c.write(fmt.Sprintf("Current mode: %s (Yolo: %v)\nUsage: /mode <%s>\n", sess.ApprovalMode, sess.Yolo, strings.Join(models.ApprovalModeNames, "|")))
 		return
 	}
 	c.setApprovalMode(sess, args[0])
 }
 
+// tierLabel describes the session's model tier. A session without one uses
+// the configured default_model_tier, or else the client's own default model.
+func (c *CLI) tierLabel(sess *models.Session) string {
+	switch {
+	case sess.ModelTier != "":
+		return sess.ModelTier
+	case c.DefaultModelTier != "":
+		return c.DefaultModelTier + " (default)"
+	default:
+		return "client default"
+	}
+}
+
 func (c *CLI) handleTier(sess *models.Session, args []string) {
 	if len(args) == 0 {
-		tier := sess.ModelTier
-		if tier == "" {
-			tier = "high (default)"
-		}
-		c.write(fmt.Sprintf("Tier: %s\nUsage: /tier <high|medium|low>\n", tier))
+		c.write(fmt.Sprintf("Tier: %s\nUsage: /tier <high|medium|low>\n", c.tierLabel(sess)))
 		return
 	}
 	tier := strings.ToLower(args[0])
 	switch tier {
 	case "high", "medium", "low":
 		c.mu.Lock()
-		sess.ModelTier = tier
-		c.persistSession(sess)
+		c.updateSession(sess, func(s *models.Session) { s.ModelTier = tier })
 		c.drawFooterLocked(sess)
 		c.mu.Unlock()
 		c.write(fmt.Sprintf("Model tier set to %s.\n", tier))
@@ -1318,8 +1601,7 @@ func (c *CLI) handleBudget(sess *models.Session, args []string) {
 		return
 	}
 	c.mu.Lock()
-	sess.MaxBudgetUSD = amount
-	c.persistSession(sess)
+	c.updateSession(sess, func(s *models.Session) { s.MaxBudgetUSD = amount })
 	c.drawFooterLocked(sess)
 	c.mu.Unlock()
 	if amount <= 0 {
@@ -1329,30 +1611,147 @@ func (c *CLI) handleBudget(sess *models.Session, args []string) {
 	}
 }
 
-func (c *CLI) persistSession(sess *models.Session) {
+func (c *CLI) handleStatus(sess *models.Session) {
+	sess = c.snapshot(sess)
+	var output strings.Builder
+	title := sess.Title
+	if title == "" {
+		title = "(untitled)"
+	}
+	clientName := sess.Client
+	if clientName == "" {
+		clientName = c.DefaultClient
+	}
+	mode := sess.ApprovalMode
+	if sess.Yolo {
+		mode = models.ApprovalModeYolo
+	}
+	tier := c.tierLabel(sess)
+	budget := "unlimited"
+	if sess.MaxBudgetUSD > 0 {
+		budget = fmt.Sprintf("$%.2f", sess.MaxBudgetUSD)
+	}
+	status := sess.Status
+	if status == "" {
+		status = models.StatusIdle
+	}
+	fmt.Fprintf(&output, "Session: %s (%s)\n", title, sess.ID)
+	fmt.Fprintf(&output, "  Status:  %s\n", status)
+	if sess.SkillName != "" {
+		fmt.Fprintf(&output, "  Skill:   %s @ %s\n", sess.SkillName, sess.ActiveNode)
+	}
+	fmt.Fprintf(&output, "  Client:  %s\n", clientName)
+	fmt.Fprintf(&output, "  Mode:    %s\n", mode)
+	fmt.Fprintf(&output, "  Tier:    %s\n", tier)
+	fmt.Fprintf(&output, "  Budget:  %s\n", budget)
+	fmt.Fprintf(&output, "  Wrap:    %s\n", wrapLabel(sess))
+	fmt.Fprintf(&output, "  Prompts: %s\n", c.promptModeLabel(sess))
+	if sess.SystemPrompt != "" {
+		fmt.Fprintf(&output, "  Persona: %d chars (/persona to show)\n", len(sess.SystemPrompt))
+	}
+	if c.Engine != nil {
+		if queued, continueOnError := c.Engine.QueuedSkills(sess.ID); len(queued) > 0 {
+			onError := "stop on failure"
+			if continueOnError {
+				onError = "continue on error"
+			}
+			fmt.Fprintf(&output, "  Queue:   %s (%s)\n", strings.Join(queued, " → "), onError)
+		}
+	}
+	c.write(output.String())
+}
+
+// handleAgentCommands implements "/commands": the commands the session's
+// agent advertises. They are not Tenazas commands, so typing one sends it to
+// the agent as a prompt.
+func (c *CLI) handleAgentCommands(sess *models.Session) {
+	clientName := sess.Client
+	if clientName == "" {
+		clientName = c.DefaultClient
+	}
+	var names []string
+	if c.Engine != nil {
+		names = c.Engine.AgentCommands(sess)
+	}
+	if len(names) == 0 {
+		c.write(fmt.Sprintf("No agent commands advertised by %s (ACP agents list theirs after the first prompt).\n", clientName))
+		return
+	}
+	var output strings.Builder
+	fmt.Fprintf(&output, "Agent commands (%s):\n", clientName)
+	for _, name := range names {
+		fmt.Fprintf(&output, "  /%s\n", name)
+	}
+	c.write(output.String())
+}
+
+// handleQueue implements "/queue <on|off>": whether prompts sent while one is
+// running wait their turn or interrupt it.
+func (c *CLI) handleQueue(sess *models.Session, args []string) {
+	if len(args) == 0 {
+		c.write(fmt.Sprintf("Prompts: %s\nUsage: /queue <on|off>\n", c.promptModeLabel(sess)))
+		return
+	}
+	var mode string
+	switch strings.ToLower(args[0]) {
+	case "on":
+		mode = models.PromptModeQueue
+	case "off":
+		mode = models.PromptModeInterrupt
+	default:
+		c.write("Invalid value. Use: /queue <on|off>\n")
+		return
+	}
+	c.mu.Lock()
+	c.updateSession(sess, func(s *models.Session) { s.PromptMode = mode })
+	c.mu.Unlock()
+	c.write(fmt.Sprintf("Prompts: %s.\n", c.promptModeLabel(sess)))
+}
+
+func (c *CLI) promptModeLabel(sess *models.Session) string {
+	mode := sess.PromptMode
+	if mode == "" && c.Engine != nil {
+		mode = c.Engine.PromptMode
+	}
+	if mode == "" {
+		mode = models.PromptModeInterrupt
+	}
+	if mode == models.PromptModeQueue && c.Engine != nil {
+		if n := c.Engine.QueuedPrompts(sess.ID); n > 0 {
+			return fmt.Sprintf("%s (%d waiting)", mode, n)
+		}
+	}
+	return mode
+}
+
+// updateSession applies fn to sess and persists it under the session lock,
+// since the engine may be saving the same session concurrently.
+func (c *CLI) updateSession(sess *models.Session, fn func(*models.Session)) {
 	if c.Sm != nil {
-		c.Sm.Save(sess)
+		c.Sm.Update(sess, fn)
+		return
 	}
+	fn(sess)
+}
+
+// snapshot returns a copy of sess to read from, since the engine may be
+// updating the same session concurrently.
+func (c *CLI) snapshot(sess *models.Session) *models.Session {
+	if c.Sm != nil {
+		return c.Sm.Snapshot(sess)
+	}
+	return sess
 }
 
 func (c *CLI) handleHelp() {
 	var output strings.Builder
 	fmt.Fprintln(&output, "Commands:")
-	fmt.Fprintln(&output, "  /run <skill>         Run a specific skill")
-	fmt.Fprintln(&output, "  /last <N>            Show last N audit logs")
-	fmt.Fprintln(&output, "  /intervene <action>  Resolve an intervention")
-	fmt.Fprintln(&output, "  /skills              List or toggle skills")
-	fmt.Fprintln(&output, "  /mode <mode>         Switch approval mode (plan, auto_edit, yolo)")
-	fmt.Fprintln(&output, "  /tier <tier>         Switch model tier (high, medium, low)")
-	fmt.Fprintln(&output, "  /budget <amount>     Set session budget cap (0 = unlimited)")
-	fmt.Fprintln(&output, "  /tasks                List all tasks for this session")
-	fmt.Fprintln(&output, "  /task show <id>       Show task details")
-	fmt.Fprintln(&output, "  /task next            Pick up the next ready task")
-	fmt.Fprintln(&output, "  /task complete        Mark the active task as done")
-	fmt.Fprintln(&output, "  /task add <t> <desc>  Create a new task")
-	fmt.Fprintln(&output, "  /task unblock <id>    Unblock a blocked task")
-	fmt.Fprintln(&output, "  /help                Show this help")
-	fmt.Fprintln(&output, "\nModes: plan, auto_edit, yolo")
+	for _, cmd := range commands {
+		for _, row := range cmd.help {
+			fmt.Fprintf(&output, "  %-23s %s\n", row[0], row[1])
+		}
+	}
+	fmt.Fprintln(&output, "\nModes: "+strings.Join(models.ApprovalModeNames, ", "))
 	c.write(output.String())
 }
 
@@ -1360,6 +1759,7 @@ func (c *CLI) drawFooterAtomic(sb *strings.Builder, sess *models.Session) {
 	if sess == nil {
 		return
 	}
+	sess = c.snapshot(sess)
 	rows, cols := c.getTermSize()
 
 	clientName := sess.Client
@@ -1473,10 +1873,49 @@ func (c *CLI) handleEscape(reader *bufio.Reader, sess *models.Session) {
 			c.cursorPos--
 		}
 	case 'Z':
+		if c.ReadOnly {
+			c.writeLocked("\nRead-only: the approval mode can't be changed while observing.\n")
+			return
+		}
 		c.cycleModeLocked(sess)
 	}
 }
 
+// ctrlCAction is what a Ctrl-C outside a permission prompt does.
+type ctrlCAction int
+
+const (
+	ctrlCExit  ctrlCAction = iota // leave the CLI
+	ctrlCAbort                    // resolve the pending intervention as abort
+)
+
+// ctrlCActionFor decides what a Ctrl-C pressed at now does. It aborts the
+// intervention a session is blocked on, unless it follows the previous
+// Ctrl-C at last within DoubleCtrlCInterval; otherwise it exits.
+func ctrlCActionFor(blocked bool, last, now time.Time) ctrlCAction {
+	if blocked && (last.IsZero() || now.Sub(last) >= DoubleCtrlCInterval) {
+		return ctrlCAbort
+	}
+	return ctrlCExit
+}
+
+// handleCtrlC reacts to a Ctrl-C and reports whether the CLI should exit.
+func (c *CLI) handleCtrlC(sess *models.Session) bool {
+	blocked := !c.ReadOnly && sess != nil && c.Engine != nil && c.Engine.AwaitingIntervention(sess.ID)
+	c.mu.Lock()
+	now := time.Now()
+	action := ctrlCActionFor(blocked, c.lastCtrlCTime, now)
+	c.lastCtrlCTime = now
+	c.mu.Unlock()
+
+	if action == ctrlCExit {
+		return true
+	}
+	c.Engine.ResolveIntervention(sess.ID, "abort")
+	c.write(fmt.Sprintf("\n%s%s● Intervention aborted%s (Ctrl-C again to exit)\n", Margin, "\x1b[33m", escReset))
+	return false
+}
+
 func (c *CLI) handleBareEscape(sess *models.Session) {
 	c.mu.Lock()
 	isRunning := c.isThinking
@@ -1486,7 +1925,7 @@ func (c *CLI) handleBareEscape(sess *models.Session) {
 	c.lastEscTime = now
 	c.mu.Unlock()
 
-	if isRunning {
+	if isRunning && !c.ReadOnly {
 		// Single escape while LLM is working → cancel
 		c.Engine.CancelSession(sess.ID)
 		c.setThinking(false)
@@ -1519,20 +1958,20 @@ func (c *CLI) setApprovalMode(sess *models.Session, mode string) {
 	c.setApprovalModeLocked(sess, mode)
 }
 
-func (c *CLI) setApprovalModeLocked(sess *models.Session, mode string) {
-	mode = strings.ToUpper(mode)
-	switch mode {
-	case models.ApprovalModeYolo:
-		sess.Yolo = true
-		sess.ApprovalMode = models.ApprovalModeYolo
-	case models.ApprovalModePlan, models.ApprovalModeAutoEdit:
-		sess.Yolo = false
-		sess.ApprovalMode = mode
-	default:
-		c.writeLocked(fmt.Sprintf("Invalid mode: %s. Use plan, auto_edit, or yolo.\n", mode))
+func (c *CLI) setApprovalModeLocked(sess *models.Session, name string) {
+	mode, ok := models.ParseApprovalMode(name)
+	if !ok {
+		c.writeLocked(fmt.Sprintf("Invalid mode: %s. Valid modes: %s (aliases: auto, edit).\n", name, strings.Join(models.ApprovalModeNames, ", ")))
 		return
 	}
-	c.persistSession(sess)
+	c.updateSession(sess, func(s *models.Session) {
+		s.Yolo = mode == models.ApprovalModeYolo
+		s.ApprovalMode = mode
+	})
+	c.Sm.RememberApprovalMode(sess.CWD, mode)
+	if c.Plain {
+		c.writeLocked(fmt.Sprintf("Mode set to %s.\n", mode))
+	}
 	c.drawFooterLocked(sess)
 }
 
@@ -1560,12 +1999,18 @@ func (c *CLI) drawFooter(sess *models.Session) {
 }
 
 func (c *CLI) drawFooterLocked(sess *models.Session) {
+	if c.Plain {
+		return
+	}
 	var sb strings.Builder
 	c.drawFooterAtomic(&sb, sess)
 	c.writeLocked(sb.String())
 }
 
 func (c *CLI) getTermSize() (int, int) {
+	if c.termSize != nil {
+		return c.termSize()
+	}
 	rows, cols, err := getTerminalSize()
 	if err != nil {
 		return 24, 80
@@ -1623,20 +2068,18 @@ func (c *CLI) addThought(text string) {
 	c.mu.Unlock()
 
 	if isImm {
-		var sb strings.Builder
-		c.writeAtomic(&sb, func(sb *strings.Builder) {
-			lines := strings.Split(text, "\n")
-			for _, l := range lines {
-				if l == "" {
-					continue
-				}
-				c.drawer = append(c.drawer, l)
-			}
-			if len(c.drawer) > DrawerHeight {
-				c.drawer = c.drawer[len(c.drawer)-DrawerHeight:]
+		c.mu.Lock()
+		for _, l := range strings.Split(text, "\n") {
+			if l == "" {
+				continue
 			}
-			c.redrawAllAtomic(sb)
-		})
+			c.drawer = append(c.drawer, l)
+		}
+		if len(c.drawer) > DrawerHeight {
+			c.drawer = c.drawer[len(c.drawer)-DrawerHeight:]
+		}
+		c.mu.Unlock()
+		c.requestRender(renderFooter | renderDrawer | renderPrompt)
 	} else {
 		c.writeInScrollRegion(escDim + text + escReset)
 	}
@@ -1689,6 +2132,10 @@ func (c *CLI) writeEscape(seq string) {
 // renderPermission replaces the prompt sandwich area with a focused
 // permission panel showing the tool request and keybinding options.
 func (c *CLI) renderPermission(req client.PermissionRequest) {
+	if c.Plain {
+		c.writePlainPermission(req)
+		return
+	}
 	var sb strings.Builder
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -1747,18 +2194,7 @@ func (c *CLI) renderPermission(req client.PermissionRequest) {
 	sb.WriteString(escClearLine)
 	var opts strings.Builder
 	for _, opt := range req.Options {
-		key := ""
-		switch opt.Kind {
-		case "allow_once":
-			key = "y"
-		case "allow_always":
-			key = "a"
-		case "reject_once":
-			key = "n"
-		case "reject_always":
-			key = "N"
-		}
-		if key != "" {
+		if key := permissionKey(opt.Kind); key != "" {
 			fmt.Fprintf(&opts, "[%s%s%s] %s   ", kindColor, key, escReset, opt.Name)
 		}
 	}
@@ -1807,6 +2243,22 @@ func (c *CLI) handlePermissionKeyLocked(r rune) bool {
 	return true
 }
 
+// permissionKey returns the key answering a permission option of kind, or
+// "" for kinds without one.
+func permissionKey(kind string) string {
+	switch kind {
+	case "allow_once":
+		return "y"
+	case "allow_always":
+		return "a"
+	case "reject_once":
+		return "n"
+	case "reject_always":
+		return "N"
+	}
+	return ""
+}
+
 func findOptionByKind(opts []client.PermissionOption, kind string) string {
 	for _, o := range opts {
 		if o.Kind == kind {
@@ -1844,6 +2296,9 @@ func (c *CLI) writePermissionFeedback(title, kind string) {
 
 // redrawFooterAndPrompt restores the normal footer and prompt sandwich area.
 func (c *CLI) redrawFooterAndPrompt() {
+	if c.Plain {
+		return
+	}
 	var sb strings.Builder
 	c.mu.Lock()
 	c.redrawAllAtomic(&sb)
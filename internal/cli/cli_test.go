@@ -60,3 +60,66 @@ func TestCLIBasic(t *testing.T) {
 		t.Errorf("expected output to contain 'Test log entry', got %s", output)
 	}
 }
+
+func TestListenOnShowsStorageWarning(t *testing.T) {
+	var out bytes.Buffer
+	cli := &CLI{Out: &out}
+	ch := make(chan events.Event, 1)
+	ch <- events.Event{Type: events.EventStorageError, SessionID: "other", Payload: events.StorageErrorPayload{Op: "audit", Err: "read-only file system"}}
+	close(ch)
+
+	cli.listenOn(ch, "sess")
+
+	if !strings.Contains(out.String(), "Could not save audit to storage: read-only file system") {
+		t.Errorf("expected the storage warning whatever the session, got %q", out.String())
+	}
+}
+
+// TestSettingsChangeDuringRun changes and reads a session from the CLI while
+// the engine runs it. Run with -race to check neither side reads a field
+// the other is writing.
+func TestSettingsChangeDuringRun(t *testing.T) {
+	sm, eng, cwd := newPromptEngine(t, `#!/bin/bash
+sleep 0.2
+echo '{"type": "init", "session_id": "sid-1"}'
+echo '{"type": "message", "content": "done"}'
+`)
+	sess, err := sm.Create(cwd, "race")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := &models.SkillGraph{
+		Name:         "slow",
+		InitialState: "work",
+		States: map[string]models.StateDef{
+			"work": {Type: "action_loop", SessionRole: "worker", Instruction: "work", Next: "done"},
+			"done": {Type: "end"},
+		},
+	}
+	var out bytes.Buffer
+	c := &CLI{Sm: sm, Engine: eng, Out: &out}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		eng.Run(sk, sess)
+	}()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+			for _, cmd := range []string{"/mode yolo", "/budget 2", "/meta set ticket T-1", "/status", "/meta list", "/mode plan"} {
+				c.handleCommand(sess, cmd)
+			}
+		}
+	}
+
+	loaded, err := sm.Load(sess.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Status != models.StatusCompleted || loaded.ApprovalMode != models.ApprovalModePlan || loaded.Metadata["ticket"] != "T-1" {
+		t.Errorf("expected the run and the settings to both be kept, got %+v", loaded)
+	}
+}
@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+
+	"tenazas/internal/models"
+	"tenazas/internal/skill"
+)
+
+// command is one slash command. handleCommand, getCompletions and handleHelp
+// all read the commands table, so a command only needs adding here.
+type command struct {
+	name string
+	// help rows: usage and description. The first row describes the
+	// command itself; more rows list subcommands or extra flags.
+	help [][2]string
+	run  func(c *CLI, sess *models.Session, args []string)
+	// args returns the argument completions for the command, or is nil.
+	args func(c *CLI) []string
+	// readOnly reports whether the call may run in observer mode; nil
+	// means never.
+	readOnly func(args []string) bool
+	// undoable commands only change session settings; /undo restores the
+	// settings from before the latest one that changed anything.
+	undoable bool
+	// drivesRun commands act on this process's engine run, so they are
+	// refused while attached to a session another process is running.
+	drivesRun bool
+}
+
+// commands lists the slash commands in completion and help order. It is
+// filled in init because several handlers refer back to the table.
+var commands []command
+
+func init() {
+	commands = []command{
+		{
+			name: "/run",
+			help: [][2]string{
+				{"/run <skill> [--trace]", "Run a skill (optionally writing a run trace)"},
+				{"     [--from-checkpoint]", "Retry after the last state that succeeded"},
+			},
+			run:       func(c *CLI, sess *models.Session, args []string) { c.handleRunArgs(sess, args) },
+			args:      (*CLI).skillNames,
+			drivesRun: true,
+		},
+		{
+			name: "/last",
+			help: [][2]string{
+				{"/last <N>", "Show last N audit logs"},
+				{"     [--since <dur>]", "Only entries from the last <dur> (e.g. 10m); all of them unless N is given"},
+				{"     [--type <type>]", "Only one kind: responses, prompts, commands or an audit type"},
+			},
+			run:      func(c *CLI, sess *models.Session, args []string) { c.handleLastArgs(sess, args) },
+			readOnly: always,
+		},
+		{
+			name:     "/search",
+			help:     [][2]string{{"/search <query>", "Find audit entries of this session containing <query>"}},
+			run:      func(c *CLI, sess *models.Session, args []string) { c.handleSearch(sess, args) },
+			readOnly: always,
+		},
+		{
+			name:     "/replay",
+			help:     [][2]string{{"/replay [N]", "Print the Nth-from-last response again (1 = latest); nothing is sent to the model"}},
+			run:      func(c *CLI, sess *models.Session, args []string) { c.handleReplay(sess, args) },
+			readOnly: always,
+		},
+		{
+			name: "/intervene",
+			help: [][2]string{{"/intervene <action>", "Resolve an intervention"}},
+			run: func(c *CLI, sess *models.Session, args []string) {
+				if len(args) > 0 {
+					c.Engine.ResolveIntervention(sess.ID, args[0])
+				}
+			},
+			args:      func(*CLI) []string { return []string{"retry", "proceed_to_fail", "abort"} },
+			drivesRun: true,
+		},
+		{
+			name:     "/nudge",
+			help:     [][2]string{{"/nudge", "Show the pending intervention question again"}},
+			run:      func(c *CLI, sess *models.Session, _ []string) { c.handleNudge(sess) },
+			readOnly: always,
+		},
+		{
+			name:      "/cancel",
+			help:      [][2]string{{"/cancel [role|node]", "Abort the in-flight LLM call; the state retries it"}},
+			run:       func(c *CLI, sess *models.Session, args []string) { c.handleCancelCall(sess, args) },
+			drivesRun: true,
+		},
+		{
+			name: "/skills",
+			help: [][2]string{
+				{"/skills", "List or toggle skills"},
+				{"/skills --tag <tag>", "Only skills tagged <tag>"},
+				{"/skills search <q>", "Skills whose name or a tag contains <q>"},
+			},
+			run: func(c *CLI, _ *models.Session, args []string) { c.handleSkills(args) },
+		},
+		{
+			name:     "/commands",
+			help:     [][2]string{{"/commands", "List commands the agent advertises (send them as prompts)"}},
+			run:      func(c *CLI, sess *models.Session, _ []string) { c.handleAgentCommands(sess) },
+			readOnly: always,
+		},
+		{
+			name:      "/run-chain",
+			help:      [][2]string{{"/run-chain <skill>...", "Run skills one after another (--continue-on-error to go on past failures)"}},
+			run:       func(c *CLI, sess *models.Session, args []string) { c.handleRunChain(sess, args) },
+			args:      (*CLI).skillNames,
+			drivesRun: true,
+		},
+		{
+			name:     "/mode",
+			help:     [][2]string{{"/mode <mode>", "Switch approval mode (plan, auto_edit, yolo; auto or edit = auto_edit)"}},
+			run:      func(c *CLI, sess *models.Session, args []string) { c.handleMode(sess, args) },
+			args:     func(*CLI) []string { return models.ApprovalModeNames },
+			undoable: true,
+		},
+		{
+			name:     "/tier",
+			help:     [][2]string{{"/tier <tier>", "Switch model tier (high, medium, low)"}},
+			run:      func(c *CLI, sess *models.Session, args []string) { c.handleTier(sess, args) },
+			args:     func(*CLI) []string { return []string{"high", "medium", "low"} },
+			undoable: true,
+		},
+		{
+			name:     "/budget",
+			help:     [][2]string{{"/budget <amount>", "Set session budget cap (0 = unlimited)"}},
+			run:      func(c *CLI, sess *models.Session, args []string) { c.handleBudget(sess, args) },
+			undoable: true,
+		},
+		{
+			name: "/persona",
+			help: [][2]string{
+				{"/persona [text|@file]", "Show or set the session system prompt"},
+				{"/persona clear", "Remove it"},
+			},
+			run:      func(c *CLI, sess *models.Session, args []string) { c.handlePersona(sess, args) },
+			readOnly: func(args []string) bool { return len(args) == 0 },
+			undoable: true,
+		},
+		{
+			name:     "/tasks",
+			help:     [][2]string{{"/tasks", "List all tasks for this session"}},
+			run:      func(c *CLI, _ *models.Session, _ []string) { c.handleTasks() },
+			readOnly: always,
+		},
+		{
+			name: "/task",
+			help: [][2]string{
+				{"/task show <id> [--log] [--deps]", "Show task details (its log, blocker tree)"},
+				{"/task next", "Pick up the next ready task"},
+				{"/task pick [id]", "Choose a ready task to start"},
+				{"/task complete", "Mark the active task as done"},
+				{"/task add <t> <desc>", "Create a new task (--priority, --labels)"},
+				{"/task unblock <id>", "Unblock a blocked task"},
+			},
+			run:  func(c *CLI, sess *models.Session, args []string) { c.handleTask(sess, args) },
+			args: func(*CLI) []string { return []string{"show", "next", "pick", "complete", "add", "unblock"} },
+			readOnly: func(args []string) bool {
+				return len(args) > 0 && args[0] == "show"
+			},
+		},
+		{
+			name: "/commit",
+			help: [][2]string{{"/commit [message]", "Stage and commit all changes, naming the active task"}},
+			run:  func(c *CLI, sess *models.Session, args []string) { c.handleCommit(sess, args) },
+		},
+		{
+			name:     "/wrap",
+			help:     [][2]string{{"/wrap <on|off>", "Reflow output to terminal width or pass it through raw"}},
+			run:      func(c *CLI, sess *models.Session, args []string) { c.handleWrap(sess, args) },
+			args:     onOff,
+			undoable: true,
+		},
+		{
+			name: "/prefs",
+			help: [][2]string{
+				{"/prefs", "Show the UI preferences restored on every launch"},
+				{"/prefs verbosity <level>", "Save the verbosity: high shows all, medium hides tool output, low also info lines"},
+				{"/prefs reset", "Forget saved preferences and return to the defaults"},
+			},
+			run:      func(c *CLI, _ *models.Session, args []string) { c.handlePrefs(args) },
+			args:     func(*CLI) []string { return []string{"reset", "verbosity"} },
+			readOnly: always,
+		},
+		{
+			name:     "/queue",
+			help:     [][2]string{{"/queue <on|off>", "Queue new prompts behind the running one instead of interrupting it"}},
+			run:      func(c *CLI, sess *models.Session, args []string) { c.handleQueue(sess, args) },
+			args:     onOff,
+			undoable: true,
+		},
+		{
+			name: "/meta",
+			help: [][2]string{{"/meta set <k> <v>", "Tag the session with metadata (also: get <k>, unset <k>, list)"}},
+			run:  func(c *CLI, sess *models.Session, args []string) { c.handleMeta(sess, args) },
+			args: func(*CLI) []string { return []string{"set", "get", "unset", "list"} },
+			readOnly: func(args []string) bool {
+				return len(args) == 0 || strings.EqualFold(args[0], "get") || strings.EqualFold(args[0], "list")
+			},
+			undoable: true,
+		},
+		{
+			name: "/undo",
+			help: [][2]string{{"/undo", "Revert the last settings change (/mode, /tier, /budget, /persona, /wrap, /queue, /meta)"}},
+			run:  func(c *CLI, sess *models.Session, _ []string) { c.handleUndo(sess) },
+		},
+		{
+			name:     "/status",
+			help:     [][2]string{{"/status", "Show the current session settings"}},
+			run:      func(c *CLI, sess *models.Session, _ []string) { c.handleStatus(sess) },
+			readOnly: always,
+		},
+		{
+			name:     "/sessions",
+			help:     [][2]string{{"/sessions [page|q]", "List active sessions, or search them by title, skill or metadata"}},
+			run:      func(c *CLI, _ *models.Session, args []string) { c.handleSessions(args) },
+			readOnly: always,
+		},
+		{
+			name:     "/switch",
+			help:     [][2]string{{"/switch <id>", "Focus another session (ID or unique prefix)"}},
+			run:      func(c *CLI, _ *models.Session, args []string) { c.handleSwitch(args) },
+			readOnly: always,
+		},
+		{
+			name:     "/attach",
+			help:     [][2]string{{"/attach <id>", "Watch a session run by another process (e.g. the daemon)"}},
+			run:      func(c *CLI, _ *models.Session, args []string) { c.handleAttach(args) },
+			readOnly: always,
+		},
+		{
+			name:     "/redraw",
+			help:     [][2]string{{"/redraw", "Redraw the screen after a resize (also Ctrl-L)"}},
+			run:      func(c *CLI, _ *models.Session, _ []string) { c.redraw() },
+			readOnly: always,
+		},
+		{
+			name:     "/help",
+			help:     [][2]string{{"/help", "Show this help"}},
+			run:      func(c *CLI, _ *models.Session, _ []string) { c.handleHelp() },
+			readOnly: always,
+		},
+	}
+}
+
+func always([]string) bool { return true }
+
+func onOff(*CLI) []string { return []string{"on", "off"} }
+
+// lookupCommand finds a command by its exact name (e.g. "/run").
+func lookupCommand(name string) (*command, bool) {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i], true
+		}
+	}
+	return nil, false
+}
+
+// skillNames lists the skills available to the focused session, sorted. It
+// runs under c.mu from getCompletions, so it reads c.sess directly.
+func (c *CLI) skillNames() []string {
+	if c.Sm == nil {
+		return nil
+	}
+	entries, err := skill.ListAll(c.Sm.StoragePath, sessionCWD(c.sess))
+	if err != nil {
+		return nil
+	}
+	names := skill.Names(entries)
+	sort.Strings(names)
+	return names
+}
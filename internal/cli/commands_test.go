@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/skill"
+)
+
+func TestEveryCommandIsCompleted(t *testing.T) {
+	cli := &CLI{}
+	all := cli.getCompletions("/")
+	if len(all) != len(commands) {
+		t.Fatalf("expected %d completions for \"/\", got %v", len(commands), all)
+	}
+	for i, cmd := range commands {
+		if all[i] != cmd.name {
+			t.Errorf("completion %d = %q, want %q", i, all[i], cmd.name)
+		}
+		if got := cli.getCompletions(cmd.name); !strings.Contains(strings.Join(got, " ")+" ", cmd.name+" ") {
+			t.Errorf("getCompletions(%q) = %v, want it included", cmd.name, got)
+		}
+	}
+}
+
+func TestEveryCommandIsInHelp(t *testing.T) {
+	var out bytes.Buffer
+	cli := &CLI{Out: &out}
+	cli.handleHelp()
+
+	lines := strings.Split(out.String(), "\n")
+	for _, cmd := range commands {
+		if len(cmd.help) == 0 {
+			t.Errorf("%s has no help text", cmd.name)
+			continue
+		}
+		found := false
+		for _, line := range lines {
+			if fields := strings.Fields(line); len(fields) > 0 && fields[0] == cmd.name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("help has no line for %s:\n%s", cmd.name, out.String())
+		}
+	}
+}
+
+func TestCommandArgCompletions(t *testing.T) {
+	cli := &CLI{}
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"/tier m", []string{"/tier medium"}},
+		{"/queue o", []string{"/queue on", "/queue off"}},
+		{"/intervene a", []string{"/intervene abort"}},
+		{"/mode Y", []string{"/mode yolo"}},
+		{"/budget ", []string{}},
+		{"/nope ", []string{}},
+	}
+	for _, tt := range tests {
+		got := cli.getCompletions(tt.line)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("getCompletions(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestCommandNamesAreUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for _, cmd := range commands {
+		if seen[cmd.name] {
+			t.Errorf("duplicate command %s", cmd.name)
+		}
+		seen[cmd.name] = true
+		if cmd.run == nil {
+			t.Errorf("%s has no handler", cmd.name)
+		}
+	}
+}
+
+func TestCancelWithoutCallInFlight(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+
+	cli.handleCommand(sess, "/cancel reviewer")
+
+	if out := cli.output(); !strings.Contains(out, "No LLM call in flight for reviewer") {
+		t.Errorf("expected a no-call notice, got %q", out)
+	}
+}
+
+func TestAgentCommandsWhenNoneAdvertised(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+
+	cli.handleCommand(sess, "/commands")
+
+	if out := cli.output(); !strings.Contains(out, "No agent commands advertised by gemini") {
+		t.Errorf("expected a none-advertised notice, got %q", out)
+	}
+}
+
+func TestReplayShowsLatestResponse(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+	for _, e := range []events.AuditEntry{
+		{Type: events.AuditLLMPrompt, Content: "first question"},
+		{Type: events.AuditLLMChunk, Content: "older "},
+		{Type: events.AuditLLMResponse, Content: "older answer"},
+		{Type: events.AuditLLMPrompt, Content: "second question"},
+		{Type: events.AuditLLMChunk, Content: "latest "},
+		{Type: events.AuditLLMChunk, Content: "answer"},
+	} {
+		cli.Sm.AppendAudit(sess, e)
+	}
+
+	cli.handleCommand(sess, "/replay 1")
+	if out := cli.output(); !strings.Contains(out, "latest answer") || strings.Contains(out, "older answer") {
+		t.Errorf("/replay 1 should print the latest (chunk-rebuilt) response only, got %q", out)
+	}
+
+	cli.Out.(*bytes.Buffer).Reset()
+	cli.handleCommand(sess, "/replay 2")
+	if out := cli.output(); !strings.Contains(out, "older answer") {
+		t.Errorf("/replay 2 should print the previous response, got %q", out)
+	}
+}
+
+func TestReplayOutOfRange(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+
+	cli.handleCommand(sess, "/replay")
+	if out := cli.output(); !strings.Contains(out, "No responses to replay yet") {
+		t.Errorf("expected an empty-history notice, got %q", out)
+	}
+
+	cli.Sm.AppendAudit(sess, events.AuditEntry{Type: events.AuditLLMResponse, Content: "only answer"})
+	cli.Out.(*bytes.Buffer).Reset()
+	cli.handleCommand(sess, "/replay 3")
+	if out := cli.output(); !strings.Contains(out, "Only 1 response(s)") || strings.Contains(out, "only answer") {
+		t.Errorf("expected an out-of-range notice, got %q", out)
+	}
+}
+
+func TestPersonaSetFromFileAndClear(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+	if err := os.WriteFile(filepath.Join(sess.CWD, "persona.md"), []byte("You review Go code.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cli.handleCommand(sess, "/persona @persona.md")
+	loaded, _ := cli.Sm.Load(sess.ID)
+	if loaded.SystemPrompt != "You review Go code." {
+		t.Fatalf("SystemPrompt = %q, want the file content", loaded.SystemPrompt)
+	}
+
+	cli.handleCommand(sess, "/persona clear")
+	loaded, _ = cli.Sm.Load(sess.ID)
+	if loaded.SystemPrompt != "" {
+		t.Errorf("SystemPrompt = %q after clear", loaded.SystemPrompt)
+	}
+}
+
+func seedMixedAudit(cli *CLI, sess *models.Session) {
+	now := time.Now()
+	for _, e := range []events.AuditEntry{
+		{Type: events.AuditStatus, Content: "old status", Timestamp: now.Add(-2 * time.Hour)},
+		{Type: events.AuditLLMResponse, Content: "old response", Timestamp: now.Add(-2 * time.Hour)},
+		{Type: events.AuditCmdResult, Content: "old command", Timestamp: now.Add(-90 * time.Minute)},
+		{Type: events.AuditStatus, Content: "recent status", Timestamp: now.Add(-5 * time.Minute)},
+		{Type: events.AuditLLMResponse, Content: "recent response", Timestamp: now.Add(-4 * time.Minute)},
+		{Type: events.AuditCmdResult, Content: "recent command", Timestamp: now.Add(-time.Minute)},
+	} {
+		cli.Sm.AppendAudit(sess, e)
+	}
+}
+
+func TestLastSinceAndType(t *testing.T) {
+	all := []string{"old status", "old response", "old command", "recent status", "recent response", "recent command"}
+	tests := []struct {
+		args string
+		want []string
+	}{
+		{"--since 10m", []string{"recent status", "recent response", "recent command"}},
+		{"--type responses", []string{"old response", "recent response"}},
+		{"--type cmd_result --since 1h", []string{"recent command"}},
+		{"1 --type status", []string{"recent status"}},
+		{"2 --since 3h", []string{"recent response", "recent command"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			cli, sess, _ := setupTaskTest(t)
+			seedMixedAudit(cli, sess)
+
+			cli.handleCommand(sess, "/last "+tt.args)
+			out := cli.output()
+			for _, content := range all {
+				want := false
+				for _, w := range tt.want {
+					want = want || w == content
+				}
+				if got := strings.Contains(out, content); got != want {
+					t.Errorf("/last %s: shows %q = %v, want %v\n%s", tt.args, content, got, want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestLastRejectsBadArgs(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+
+	cli.handleCommand(sess, "/last --since soon")
+	if out := cli.output(); !strings.Contains(out, "invalid --since") {
+		t.Errorf("expected an invalid duration message, got %q", out)
+	}
+}
+
+func TestSkillsFilterByTag(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+	dir := skill.ProjectDir(sess.CWD)
+	os.MkdirAll(dir, 0755)
+	for name, tags := range map[string]string{"ship": `["deploy"]`, "rollback": `["deploy", "ops"]`, "lint": `[]`} {
+		data := `{"skill_name": "` + name + `", "initial_state": "s", "tags": ` + tags + `, "states": {"s": {"type": "end"}}}`
+		os.WriteFile(filepath.Join(dir, name+".json"), []byte(data), 0644)
+	}
+
+	cli.handleCommand(sess, "/skills --tag deploy")
+	out := cli.output()
+	if !strings.Contains(out, "ship  [deploy]") || !strings.Contains(out, "rollback  [deploy, ops]") || strings.Contains(out, "lint") {
+		t.Errorf("expected only the deploy skills with their tags, got:\n%s", out)
+	}
+
+	cli.Out.(*bytes.Buffer).Reset()
+	cli.handleCommand(sess, "/skills search OPS")
+	if out := cli.output(); !strings.Contains(out, "rollback") || strings.Contains(out, "ship") {
+		t.Errorf("search should match tags too, got:\n%s", out)
+	}
+
+	cli.Out.(*bytes.Buffer).Reset()
+	cli.handleCommand(sess, "/skills --tag nothing")
+	if out := cli.output(); !strings.Contains(out, "No skills match.") {
+		t.Errorf("expected a no-match notice, got %q", out)
+	}
+}
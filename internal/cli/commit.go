@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"tenazas/internal/models"
+	"tenazas/internal/storage"
+	"tenazas/internal/task"
+)
+
+// commitTrailer names the session that produced a commit, so the change can
+// be traced back to its audit log.
+const commitTrailer = "Tenazas-Session"
+
+// handleCommit implements "/commit [message]": it stages everything in the
+// session's CWD and commits it, naming the active task in the subject.
+func (c *CLI) handleCommit(sess *models.Session, args []string) {
+	if sess == nil {
+		c.write("Error: no active session. Start or resume a session first.\n")
+		return
+	}
+	cwd := sess.CWD
+	if code, _ := c.Engine.RunShell("git rev-parse --is-inside-work-tree", cwd); code != 0 {
+		c.writef("Not a git repository: %s\n", cwd)
+		return
+	}
+	if _, out := c.Engine.RunShell("git status --porcelain", cwd); strings.TrimSpace(out) == "" {
+		c.writef("Nothing to commit in %s\n", cwd)
+		return
+	}
+
+	msg := commitMessage(c.activeTask(sess), strings.Join(args, " "), sess.ID)
+	if msg == "" {
+		c.write("Usage: /commit <message> (no active task to name the commit after)\n")
+		return
+	}
+	code, out := c.Engine.RunShell("git add -A && git commit -q "+commitArgs(msg), cwd)
+	if code != 0 {
+		c.writef("git commit failed (exit %d):\n%s\n", code, strings.TrimSpace(out))
+		return
+	}
+	_, head := c.Engine.RunShell("git log -1 --format='%h %s'", cwd)
+	c.writef("Committed %s\n", strings.TrimSpace(head))
+	c.refreshGitBranch()
+}
+
+// activeTask returns the task the session is tied to: its TaskID when set
+// (heartbeat sessions), otherwise the task in progress for its CWD.
+func (c *CLI) activeTask(sess *models.Session) *task.Task {
+	if c.Sm == nil {
+		return nil
+	}
+	tasks, err := task.ListTasks(filepath.Join(c.Sm.StoragePath, "tasks", storage.Slugify(sess.CWD)))
+	if err != nil {
+		return nil
+	}
+	if sess.TaskID != "" {
+		for _, t := range tasks {
+			if t.ID == sess.TaskID {
+				return t
+			}
+		}
+	}
+	return findInProgress(tasks)
+}
+
+// commitMessage builds "<task ID>: <title>" with note as the body, or just
+// note when there is no task, then appends the session trailer. It returns
+// "" when there is nothing to say.
+func commitMessage(t *task.Task, note, sessionID string) string {
+	note = strings.TrimSpace(note)
+	var paragraphs []string
+	switch {
+	case t != nil:
+		paragraphs = append(paragraphs, fmt.Sprintf("%s: %s", t.ID, t.Title))
+		if note != "" {
+			paragraphs = append(paragraphs, note)
+		}
+	case note != "":
+		paragraphs = append(paragraphs, note)
+	default:
+		return ""
+	}
+	paragraphs = append(paragraphs, commitTrailer+": "+sessionID)
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// commitArgs passes msg to git commit as one single-quoted -m argument.
+func commitArgs(msg string) string {
+	return "-m '" + strings.ReplaceAll(msg, "'", `'\''`) + "'"
+}
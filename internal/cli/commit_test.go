@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tenazas/internal/storage"
+	"tenazas/internal/task"
+)
+
+// setupCommitRepo points the test session at a fresh git repository and
+// returns it with the tasks directory for that CWD.
+func setupCommitRepo(t *testing.T) (*CLI, string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	cli, sess, _ := setupTaskTest(t)
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	sess.CWD = repo
+	tasksDir := filepath.Join(cli.Sm.StoragePath, "tasks", storage.Slugify(repo))
+	os.MkdirAll(tasksDir, 0755)
+	return cli, repo, tasksDir
+}
+
+func lastCommitMessage(t *testing.T, repo string) string {
+	t.Helper()
+	cmd := exec.Command("git", "log", "-1", "--format=%B")
+	cmd.Dir = repo
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git log: %v", err)
+	}
+	return string(out)
+}
+
+func TestCommitNamesActiveTask(t *testing.T) {
+	cli, repo, tasksDir := setupCommitRepo(t)
+	createTestTask(t, tasksDir, "TSK-000012", "Fix login bug", task.StatusInProgress, 1)
+	os.WriteFile(filepath.Join(repo, "login.go"), []byte("package login\n"), 0644)
+
+	cli.handleCommit(cli.sess, []string{"handle", "empty", "passwords"})
+
+	msg := lastCommitMessage(t, repo)
+	if !strings.HasPrefix(msg, "TSK-000012: Fix login bug\n\nhandle empty passwords\n") {
+		t.Errorf("unexpected commit message %q", msg)
+	}
+	if !strings.Contains(msg, commitTrailer+": "+cli.sess.ID) {
+		t.Errorf("expected the session trailer in %q", msg)
+	}
+	if out := cli.output(); !strings.Contains(out, "Committed") {
+		t.Errorf("expected a confirmation, got %q", out)
+	}
+}
+
+func TestCommitPrefersSessionTask(t *testing.T) {
+	cli, repo, tasksDir := setupCommitRepo(t)
+	createTestTask(t, tasksDir, "TSK-000001", "Other work", task.StatusInProgress, 1)
+	createTestTask(t, tasksDir, "TSK-000002", "Heartbeat's task", task.StatusTodo, 1)
+	cli.sess.TaskID = "TSK-000002"
+	os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a"), 0644)
+
+	cli.handleCommit(cli.sess, nil)
+
+	if msg := lastCommitMessage(t, repo); !strings.HasPrefix(msg, "TSK-000002: Heartbeat's task\n") {
+		t.Errorf("unexpected commit message %q", msg)
+	}
+}
+
+func TestCommitWithoutTaskNeedsMessage(t *testing.T) {
+	cli, repo, _ := setupCommitRepo(t)
+	os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a"), 0644)
+
+	cli.handleCommit(cli.sess, nil)
+	if out := cli.output(); !strings.Contains(out, "Usage: /commit") {
+		t.Errorf("expected usage without a task or message, got %q", out)
+	}
+
+	cli.handleCommit(cli.sess, []string{"it's", "done"})
+	if msg := lastCommitMessage(t, repo); !strings.HasPrefix(msg, "it's done\n") {
+		t.Errorf("unexpected commit message %q", msg)
+	}
+}
+
+func TestCommitNoChangesOrNoRepo(t *testing.T) {
+	cli, repo, _ := setupCommitRepo(t)
+	cli.handleCommit(cli.sess, []string{"msg"})
+	if out := cli.output(); !strings.Contains(out, "Nothing to commit in "+repo) {
+		t.Errorf("expected a no-changes message, got %q", out)
+	}
+
+	cli.sess.CWD = t.TempDir()
+	cli.handleCommit(cli.sess, []string{"msg"})
+	if out := cli.output(); !strings.Contains(out, "Not a git repository") {
+		t.Errorf("expected a no-repo message, got %q", out)
+	}
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/client"
+	"tenazas/internal/engine"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestCtrlCActionFor(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		blocked bool
+		last    time.Time
+		want    ctrlCAction
+	}{
+		{"idle session exits", false, time.Time{}, ctrlCExit},
+		{"blocked session aborts", true, time.Time{}, ctrlCAbort},
+		{"double press exits", true, now.Add(-DoubleCtrlCInterval / 2), ctrlCExit},
+		{"slow second press aborts again", true, now.Add(-2 * DoubleCtrlCInterval), ctrlCAbort},
+	}
+	for _, tt := range tests {
+		if got := ctrlCActionFor(tt.blocked, tt.last, now); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCtrlCAbortsIntervention(t *testing.T) {
+	tmpDir := t.TempDir()
+	sm := session.NewManager(tmpDir)
+	c, _ := client.NewClient("gemini", "gemini", filepath.Join(tmpDir, "tenazas.log"))
+	eng := engine.NewEngine(sm, map[string]client.Client{"gemini": c}, "gemini", 5)
+	sess := &models.Session{
+		ID:         "sess-ctrlc",
+		CWD:        tmpDir,
+		Status:     models.StatusIntervention,
+		ActiveNode: "wait",
+		RoleCache:  map[string]string{},
+	}
+	sm.Save(sess)
+	skill := &models.SkillGraph{Name: "s", InitialState: "wait", States: map[string]models.StateDef{
+		"wait": {Type: "tool", Command: "true", Next: "end"},
+		"end":  {Type: "end"},
+	}}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		eng.Run(skill, sess)
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for !eng.AwaitingIntervention(sess.ID) {
+		if time.Now().After(deadline) {
+			t.Fatal("run never blocked on the intervention")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	var out bytes.Buffer
+	cli := NewCLI(sm, nil, eng, "gemini", "", nil)
+	cli.Out = &out
+	cli.sess = sess
+	cli.In = strings.NewReader("\x03")
+	if err := cli.replRaw(sess); err != io.EOF {
+		t.Fatalf("Ctrl-C at an intervention should keep the CLI reading, got %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("intervention was not resolved")
+	}
+	if reloaded, _ := sm.Load(sess.ID); reloaded.Status != models.StatusFailed {
+		t.Errorf("status = %s, want %s after abort", reloaded.Status, models.StatusFailed)
+	}
+	if !strings.Contains(out.String(), "Intervention aborted") {
+		t.Errorf("expected an abort notice, got %q", out.String())
+	}
+
+	// With nothing blocked any more, the next Ctrl-C exits.
+	cli.In = strings.NewReader("\x03")
+	if err := cli.replRaw(sess); err != nil {
+		t.Errorf("Ctrl-C without an intervention should exit cleanly, got %v", err)
+	}
+}
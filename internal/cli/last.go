@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/formatter"
+	"tenazas/internal/models"
+)
+
+const defaultLastCount = 5
+
+// lastTypeAliases maps friendly /last --type names to audit types.
+var lastTypeAliases = map[string]string{
+	"prompt":    events.AuditLLMPrompt,
+	"prompts":   events.AuditLLMPrompt,
+	"response":  events.AuditLLMResponse,
+	"responses": events.AuditLLMResponse,
+	"command":   events.AuditCmdResult,
+	"commands":  events.AuditCmdResult,
+	"cmd":       events.AuditCmdResult,
+}
+
+// lastQuery is a parsed "/last [N] [--since <dur>] [--type <type>]". A zero
+// count means every matching entry (only allowed with --since).
+type lastQuery struct {
+	count int
+	since time.Duration
+	kind  string
+}
+
+func parseLastQuery(args []string) (lastQuery, error) {
+	q := lastQuery{}
+	counted := false
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; a {
+		case "--since", "--type":
+			if i+1 >= len(args) {
+				return q, fmt.Errorf("%s requires a value", a)
+			}
+			i++
+			if a == "--since" {
+				d, err := time.ParseDuration(args[i])
+				if err != nil || d <= 0 {
+					return q, fmt.Errorf("invalid --since %q (use e.g. 10m, 2h)", args[i])
+				}
+				q.since = d
+			} else {
+				q.kind = strings.ToLower(args[i])
+				if t, ok := lastTypeAliases[q.kind]; ok {
+					q.kind = t
+				}
+			}
+		default:
+			n, err := strconv.Atoi(a)
+			if err != nil || n <= 0 {
+				return q, fmt.Errorf("invalid count %q", a)
+			}
+			q.count, counted = n, true
+		}
+	}
+	if !counted && q.since == 0 {
+		q.count = defaultLastCount
+	}
+	return q, nil
+}
+
+// handleLastArgs implements "/last [N] [--since <dur>] [--type <type>]".
+// Without filters it reads just the tail of the audit log; with them it walks
+// the whole log and keeps the last N matches (all of them for --since alone).
+func (c *CLI) handleLastArgs(sess *models.Session, args []string) {
+	q, err := parseLastQuery(args)
+	if err != nil {
+		c.write(fmt.Sprintf("%v\nUsage: /last [N] [--since <dur>] [--type <type>]\n", err))
+		return
+	}
+	if q.since == 0 && q.kind == "" {
+		c.handleLast(sess, q.count)
+		return
+	}
+
+	cutoff := time.Now().Add(-q.since)
+	logs, err := c.Sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		if q.kind != "" && e.Type != q.kind {
+			return false
+		}
+		return q.since == 0 || !e.Timestamp.Before(cutoff)
+	})
+	if err != nil {
+		c.write(fmt.Sprintf("Could not read the audit log: %v\n", err))
+		return
+	}
+	if q.count > 0 && len(logs) > q.count {
+		logs = logs[len(logs)-q.count:]
+	}
+	if len(logs) == 0 {
+		c.write("No matching audit entries.\n")
+		return
+	}
+
+	f := &formatter.AnsiFormatter{Time: c.TimeFormat}
+	var output strings.Builder
+	for _, l := range logs {
+		fmt.Fprintln(&output, f.Format(l))
+	}
+	c.write(output.String())
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"tenazas/internal/formatter"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+var moveToPattern = regexp.MustCompile(`\x1b\[(\d+);1H`)
+
+// screenRows replays the absolute row moves in out and returns the visible
+// text written on each row. Moves followed by no text (cursor placement)
+// leave the row as it was.
+func screenRows(out string) map[int]string {
+	screen := map[int]string{}
+	locs := moveToPattern.FindAllStringSubmatchIndex(out, -1)
+	for i, loc := range locs {
+		row, _ := strconv.Atoi(out[loc[2]:loc[3]])
+		end := len(out)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		if text := formatter.StripANSI(out[loc[1]:end]); text != "" {
+			screen[row] = text
+		}
+	}
+	return screen
+}
+
+func newLayoutCLI(rows, cols int) *CLI {
+	return &CLI{
+		termSize: func() (int, int) { return rows, cols },
+		sess:     &models.Session{ID: "layout", CWD: "/work/proj", ApprovalMode: models.ApprovalModePlan},
+	}
+}
+
+var layoutSizes = []struct{ rows, cols int }{{24, 80}, {10, 40}, {50, 200}}
+
+func TestFooterLayoutAtSizes(t *testing.T) {
+	for _, size := range layoutSizes {
+		t.Run(fmt.Sprintf("%dx%d", size.rows, size.cols), func(t *testing.T) {
+			c := newLayoutCLI(size.rows, size.cols)
+			var sb strings.Builder
+			c.drawFooterAtomic(&sb, c.sess)
+			screen := screenRows(sb.String())
+
+			separator := strings.Repeat("─", size.cols)
+			if screen[size.rows-3] != separator || screen[size.rows-1] != separator {
+				t.Errorf("expected separators on rows %d and %d, got %q / %q", size.rows-3, size.rows-1, screen[size.rows-3], screen[size.rows-1])
+			}
+			line1, line2 := screen[size.rows-4], screen[size.rows]
+			if !strings.HasPrefix(line1, "/work/proj") {
+				t.Errorf("expected the CWD on row %d, got %q", size.rows-4, line1)
+			}
+			if !strings.Contains(line2, "PLAN") {
+				t.Errorf("expected the mode on row %d, got %q", size.rows, line2)
+			}
+			for row, text := range map[int]string{size.rows - 4: line1, size.rows: line2} {
+				if n := utf8.RuneCountInString(text); n != size.cols {
+					t.Errorf("row %d is %d columns wide, want %d: %q", row, n, size.cols, text)
+				}
+			}
+		})
+	}
+}
+
+func TestScrollRegionAtSizes(t *testing.T) {
+	for _, size := range layoutSizes {
+		c := newLayoutCLI(size.rows, size.cols)
+		var sb strings.Builder
+		c.setupTerminalAtomic(&sb)
+		if want := fmt.Sprintf(escScrollRegion, 1, size.rows-6); !strings.HasPrefix(sb.String(), want) {
+			t.Errorf("%dx%d: expected scroll region %q, got %q", size.rows, size.cols, want, sb.String())
+		}
+
+		c.IsImmersive = true
+		sb.Reset()
+		c.setupTerminalAtomic(&sb)
+		if want := fmt.Sprintf(escScrollRegion, 1, size.rows-DrawerHeight-6); !strings.Contains(sb.String(), want) {
+			t.Errorf("%dx%d immersive: expected scroll region %q, got %q", size.rows, size.cols, want, sb.String())
+		}
+	}
+}
+
+func TestPromptWrapsAtSizes(t *testing.T) {
+	for _, size := range layoutSizes {
+		t.Run(fmt.Sprintf("%dx%d", size.rows, size.cols), func(t *testing.T) {
+			c := newLayoutCLI(size.rows, size.cols)
+			width := size.cols - MarginWidth - PromptOffset
+			input := strings.Repeat("a", width) + strings.Repeat("b", width) + "cc"
+			c.input = []rune(input)
+			c.cursorPos = len(c.input)
+
+			var sb strings.Builder
+			c.renderLineAtomic(&sb)
+			screen := screenRows(sb.String())
+
+			// Three lines, growing upward so the last sits on rows-2.
+			promptRow := size.rows - 2
+			want := map[int]string{
+				promptRow - 2: Margin + PromptNormal + strings.Repeat("a", width),
+				promptRow - 1: strings.Repeat(" ", MarginWidth+PromptOffset) + strings.Repeat("b", width),
+				promptRow:     strings.Repeat(" ", MarginWidth+PromptOffset) + "cc",
+			}
+			for row, text := range want {
+				if screen[row] != text {
+					t.Errorf("row %d = %q, want %q", row, screen[row], text)
+				}
+			}
+			if c.promptLines != 3 {
+				t.Errorf("promptLines = %d, want 3", c.promptLines)
+			}
+			if cursor := fmt.Sprintf(escMoveTo+escCR+escMoveRight, promptRow, 2+MarginWidth+PromptOffset); !strings.HasSuffix(sb.String(), cursor) {
+				t.Errorf("expected the cursor after \"cc\" (%q), got %q", cursor, sb.String())
+			}
+
+			// The footer shifts up by the two extra prompt lines.
+			sb.Reset()
+			c.drawFooterAtomic(&sb, c.sess)
+			footer := screenRows(sb.String())
+			if !strings.HasPrefix(footer[size.rows-6], "/work/proj") {
+				t.Errorf("expected footer line 1 on row %d, got %v", size.rows-6, footer)
+			}
+			if footer[size.rows-5] != strings.Repeat("─", size.cols) {
+				t.Errorf("expected the top separator on row %d, got %q", size.rows-5, footer[size.rows-5])
+			}
+		})
+	}
+}
+
+func TestResizeDuringStreamingReanchorsCursor(t *testing.T) {
+	rows, cols := 24, 80
+	var out bytes.Buffer
+	c := &CLI{
+		Sm:        session.NewManager(t.TempDir()),
+		Out:       &out,
+		inRawMode: true,
+		termSize:  func() (int, int) { return rows, cols },
+		sess:      &models.Session{ID: "resize", CWD: "/work/proj", ApprovalMode: models.ApprovalModePlan},
+	}
+
+	c.startStreaming()
+	if c.streamRow != 18 || out.String() != fmt.Sprintf(escMoveTo, 18) {
+		t.Fatalf("first chunk should anchor at row 18, got row %d and %q", c.streamRow, out.String())
+	}
+	c.writeInScrollRegion("partial ")
+
+	// The terminal shrinks mid-stream: the stream must follow the new
+	// scroll region instead of restoring a cursor saved for the old one.
+	rows = 12
+	out.Reset()
+	c.redraw()
+	anchor := fmt.Sprintf(escMoveTo, 6)
+	if c.streamRow != 6 || !strings.HasSuffix(out.String(), anchor) {
+		t.Errorf("resize should re-anchor the stream at row 6, got row %d and %q", c.streamRow, out.String())
+	}
+	if c.outCol != 0 {
+		t.Errorf("outCol = %d after re-anchoring, want 0", c.outCol)
+	}
+
+	out.Reset()
+	c.writeInScrollRegion("rest")
+	if out.String() != "rest" {
+		t.Errorf("chunks after the resize should be written in place, got %q", out.String())
+	}
+}
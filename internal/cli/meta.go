@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"tenazas/internal/models"
+)
+
+const metaUsage = "Usage: /meta <set <key> <value>|get <key>|unset <key>|list>\n"
+
+// handleMeta implements "/meta": free-form key/value tags persisted with the
+// session so integrations can find it again (ticket IDs, PR numbers).
+func (c *CLI) handleMeta(sess *models.Session, args []string) {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+	switch strings.ToLower(args[0]) {
+	case "set":
+		if len(args) < 3 {
+			c.write(metaUsage)
+			return
+		}
+		key, value := args[1], strings.Join(args[2:], " ")
+		c.mu.Lock()
+		c.updateSession(sess, func(s *models.Session) {
+			if s.Metadata == nil {
+				s.Metadata = make(map[string]string)
+			}
+			s.Metadata[key] = value
+		})
+		c.mu.Unlock()
+		c.writef("%s = %s\n", key, value)
+	case "get":
+		if len(args) != 2 {
+			c.write(metaUsage)
+			return
+		}
+		c.mu.Lock()
+		value, ok := c.snapshot(sess).Metadata[args[1]]
+		c.mu.Unlock()
+		if !ok {
+			c.writef("No metadata for %q.\n", args[1])
+			return
+		}
+		c.writef("%s\n", value)
+	case "unset":
+		if len(args) != 2 {
+			c.write(metaUsage)
+			return
+		}
+		c.mu.Lock()
+		c.updateSession(sess, func(s *models.Session) {
+			delete(s.Metadata, args[1])
+			if len(s.Metadata) == 0 {
+				s.Metadata = nil
+			}
+		})
+		c.mu.Unlock()
+		c.writef("Removed %q.\n", args[1])
+	case "list":
+		c.mu.Lock()
+		meta := c.snapshot(sess).Metadata
+		keys := make([]string, 0, len(meta))
+		for k := range meta {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var sb strings.Builder
+		for _, k := range keys {
+			fmt.Fprintf(&sb, "  %s = %s\n", k, meta[k])
+		}
+		c.mu.Unlock()
+		if len(keys) == 0 {
+			c.write("No metadata. " + metaUsage)
+			return
+		}
+		c.write(sb.String())
+	default:
+		c.write(metaUsage)
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHandleMetaSetGetPersists(t *testing.T) {
+	c, sess, _ := setupSessionsTest(t)
+
+	c.handleMeta(sess, []string{"set", "ticket", "ENG-42"})
+	c.handleMeta(sess, []string{"set", "pr", "https://example.com/pr/7"})
+
+	loaded, err := c.Sm.Load(sess.ID)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Metadata["ticket"] != "ENG-42" || loaded.Metadata["pr"] != "https://example.com/pr/7" {
+		t.Fatalf("metadata not persisted: %v", loaded.Metadata)
+	}
+
+	c.Out = &bytes.Buffer{}
+	c.handleMeta(sess, []string{"get", "ticket"})
+	if out := c.Out.(*bytes.Buffer).String(); strings.TrimSpace(out) != "ENG-42" {
+		t.Errorf("get ticket = %q, want ENG-42", out)
+	}
+
+	c.handleMeta(sess, []string{"unset", "ticket"})
+	c.handleMeta(sess, []string{"unset", "pr"})
+	loaded, _ = c.Sm.Load(sess.ID)
+	if loaded.Metadata != nil {
+		t.Errorf("expected metadata cleared, got %v", loaded.Metadata)
+	}
+}
+
+func TestHandleSessionsSearchesMetadata(t *testing.T) {
+	c, _, second := setupSessionsTest(t)
+
+	c.handleMeta(second, []string{"set", "ticket", "ENG-1234"})
+	c.Out = &bytes.Buffer{}
+	c.handleSessions([]string{"eng-1234"})
+
+	out := c.Out.(*bytes.Buffer).String()
+	if !strings.Contains(out, "bbbbbbbb") {
+		t.Errorf("expected tagged session in results:\n%s", out)
+	}
+	if strings.Contains(out, "aaaaaaaa") {
+		t.Errorf("untagged session should not match:\n%s", out)
+	}
+}
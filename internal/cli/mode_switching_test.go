@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -133,3 +134,117 @@ func TestFooterUpdateOnModeSwitch(t *testing.T) {
 		t.Errorf("expected footer to contain AUTO_EDIT, got %s", output)
 	}
 }
+
+func TestInitializeSessionUsesDefaultApprovalMode(t *testing.T) {
+	sm := session.NewManager(t.TempDir())
+
+	cli := NewCLI(sm, nil, nil, "gemini", "", nil)
+	sess, err := cli.initializeSession(false)
+	if err != nil {
+		t.Fatalf("initializeSession: %v", err)
+	}
+	if sess.ApprovalMode != models.ApprovalModePlan || sess.Yolo {
+		t.Errorf("expected PLAN by default, got %s (Yolo: %v)", sess.ApprovalMode, sess.Yolo)
+	}
+
+	cli.DefaultApprovalMode = models.ApprovalModeAutoEdit
+	sess, _ = cli.initializeSession(false)
+	loaded, _ := sm.Load(sess.ID)
+	if loaded.ApprovalMode != models.ApprovalModeAutoEdit || loaded.Yolo {
+		t.Errorf("expected persisted AUTO_EDIT, got %s (Yolo: %v)", loaded.ApprovalMode, loaded.Yolo)
+	}
+}
+
+func TestInitializeSessionRestoresProjectMode(t *testing.T) {
+	sm := session.NewManager(t.TempDir())
+	cli := NewCLI(sm, nil, nil, "gemini", "", nil)
+	cli.Out = io.Discard
+
+	first, _ := cli.initializeSession(false)
+	cli.setApprovalMode(first, "auto_edit")
+
+	next, _ := cli.initializeSession(false)
+	if next.ApprovalMode != models.ApprovalModeAutoEdit {
+		t.Errorf("expected the project's AUTO_EDIT to be restored, got %s", next.ApprovalMode)
+	}
+
+	other, _ := sm.Create(t.TempDir(), "other project")
+	if other.ApprovalMode != models.ApprovalModePlan {
+		t.Errorf("expected another project to start in PLAN, got %s", other.ApprovalMode)
+	}
+
+	// The configured default still wins.
+	cli.DefaultApprovalMode = models.ApprovalModePlan
+	if sess, _ := cli.initializeSession(false); sess.ApprovalMode != models.ApprovalModePlan {
+		t.Errorf("expected default_approval_mode to override the project, got %s", sess.ApprovalMode)
+	}
+}
+
+func TestDefaultYoloWarns(t *testing.T) {
+	var out bytes.Buffer
+	cli := NewCLI(session.NewManager(t.TempDir()), nil, nil, "gemini", "", nil)
+	cli.Out = &out
+
+	cli.warnDefaultYolo()
+	if out.Len() != 0 {
+		t.Errorf("expected no warning for the plan default, got %q", out.String())
+	}
+
+	cli.DefaultApprovalMode = models.ApprovalModeYolo
+	sess, _ := cli.initializeSession(false)
+	if !sess.Yolo || sess.ApprovalMode != models.ApprovalModeYolo {
+		t.Errorf("expected a yolo session, got %s (Yolo: %v)", sess.ApprovalMode, sess.Yolo)
+	}
+	cli.warnDefaultYolo()
+	if !strings.Contains(out.String(), "YOLO MODE") {
+		t.Errorf("expected a yolo warning, got %q", out.String())
+	}
+}
+
+func TestParseApprovalMode(t *testing.T) {
+	for in, want := range map[string]string{"plan": models.ApprovalModePlan, "auto_edit": models.ApprovalModeAutoEdit, " YOLO ": models.ApprovalModeYolo, "auto": models.ApprovalModeAutoEdit, "Edit": models.ApprovalModeAutoEdit, "auto-edit": models.ApprovalModeAutoEdit} {
+		if got, ok := models.ParseApprovalMode(in); !ok || got != want {
+			t.Errorf("ParseApprovalMode(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	if _, ok := models.ParseApprovalMode("reckless"); ok {
+		t.Error("expected an unknown mode to be rejected")
+	}
+}
+
+func TestModeCompletions(t *testing.T) {
+	cli := &CLI{}
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"/mode ", []string{"/mode plan", "/mode auto_edit", "/mode yolo"}},
+		{"/mode a", []string{"/mode auto_edit"}},
+		{"/mode Y", []string{"/mode yolo"}},
+		{"/mode x", []string{}},
+	}
+	for _, tt := range tests {
+		if got := cli.getCompletions(tt.input); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("getCompletions(%q) = %v; want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestModeAliasesAndInvalidMode(t *testing.T) {
+	var out bytes.Buffer
+	sess := &models.Session{ID: "alias", ApprovalMode: models.ApprovalModePlan}
+	cli := &CLI{Out: &out}
+
+	cli.handleMode(sess, []string{"auto"})
+	if sess.ApprovalMode != models.ApprovalModeAutoEdit || sess.Yolo {
+		t.Errorf("expected /mode auto to select AUTO_EDIT, got %s (yolo=%v)", sess.ApprovalMode, sess.Yolo)
+	}
+
+	cli.handleMode(sess, []string{"reckless"})
+	if sess.ApprovalMode != models.ApprovalModeAutoEdit {
+		t.Errorf("an invalid mode must not change the session, got %s", sess.ApprovalMode)
+	}
+	if msg := out.String(); !strings.Contains(msg, "Invalid mode: reckless") || !strings.Contains(msg, "plan, auto_edit, yolo") {
+		t.Errorf("expected the valid modes to be listed, got %q", msg)
+	}
+}
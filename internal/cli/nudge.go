@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"tenazas/internal/models"
+)
+
+// handleNudge implements "/nudge": it re-publishes the pending intervention
+// of sess and prints the question again, without advancing the run.
+func (c *CLI) handleNudge(sess *models.Session) {
+	if sess == nil {
+		c.write("Error: no active session. Start or resume a session first.\n")
+		return
+	}
+	details, err := c.Engine.Nudge(sess.ID)
+	if err != nil {
+		c.writef("Nothing to nudge: %v\n", err)
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\n%s%s● Intervention Required at %s%s\n", Margin, escBoldRed, details["node"], escReset)
+	for _, field := range []struct{ key, label string }{
+		{"category", "Category"},
+		{"instruction", "Instruction"},
+		{"reason", "Reason"},
+	} {
+		if v := details[field.key]; v != "" {
+			fmt.Fprintf(&sb, "%s  %s: %s\n", Margin, field.label, v)
+		}
+	}
+	if out := details["last_output"]; out != "" {
+		fmt.Fprintf(&sb, "%s  Last output:\n%s%s%s\n", Margin, escDim, out, escReset)
+	}
+	fmt.Fprintf(&sb, "\n%sType `/intervene <retry|proceed_to_fail|abort>`\n", Margin)
+	c.write(sb.String())
+}
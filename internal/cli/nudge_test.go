@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+func TestNudgeReprintsIntervention(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+	cli.Sm.Update(sess, func(s *models.Session) {
+		s.Status = models.StatusIntervention
+		s.ActiveNode = "verify"
+		s.PendingFeedback = "tests failed"
+	})
+	cli.Sm.AppendAudit(sess, events.AuditEntry{Type: events.AuditCmdResult, Content: "FAIL: TestLogin", ExitCode: 1})
+
+	cli.handleCommand(sess, "/nudge")
+
+	out := cli.output()
+	for _, want := range []string{"Intervention Required at verify", "Reason: tests failed", "FAIL: TestLogin", "/intervene <retry|proceed_to_fail|abort>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got %q", want, out)
+		}
+	}
+}
+
+func TestNudgeWithoutIntervention(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+
+	cli.handleCommand(sess, "/nudge")
+
+	out := cli.output()
+	if !strings.Contains(out, "Nothing to nudge") || strings.Contains(out, "Intervention Required") {
+		t.Errorf("expected a no-op notice, got %q", out)
+	}
+}
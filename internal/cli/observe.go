@@ -0,0 +1,21 @@
+package cli
+
+// readOnlyAllowed reports whether the command in parts is safe to run while
+// observing (--observe): only commands that navigate or display are, and
+// plain prompts never are.
+func readOnlyAllowed(parts []string) bool {
+	if len(parts) == 0 {
+		return true
+	}
+	cmd, ok := lookupCommand(parts[0])
+	return ok && cmd.readOnly != nil && cmd.readOnly(parts[1:])
+}
+
+// refuseReadOnly explains why input was ignored in observer mode.
+func (c *CLI) refuseReadOnly(cmd string) {
+	if cmd == "" || cmd[0] != '/' {
+		c.write("Read-only: prompts are disabled while observing. Restart without --observe to interact.\n")
+		return
+	}
+	c.writef("Read-only: %s is disabled while observing. Use /last, /status, /tasks, /sessions or /help.\n", cmd)
+}
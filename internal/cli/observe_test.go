@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"tenazas/internal/models"
+)
+
+func TestReadOnlyRejectsMutatingCommands(t *testing.T) {
+	c, sess, _ := setupSessionsTest(t)
+	c.ReadOnly = true
+	sess.ApprovalMode = models.ApprovalModePlan
+	c.Sm.Save(sess)
+
+	// Engine is nil: reaching ExecutePrompt or a skill run would panic.
+	for _, input := range []string{"fix the bug", "/mode yolo", "/meta set ticket 42", "/run deploy", "/task next", "/budget 5", "/intervene abort"} {
+		c.Out = &bytes.Buffer{}
+		c.handleCommand(sess, input)
+		if out := c.output(); !strings.Contains(out, "Read-only") {
+			t.Errorf("%q: expected a read-only notice, got %q", input, out)
+		}
+	}
+
+	loaded, _ := c.Sm.Load(sess.ID)
+	if loaded.ApprovalMode != models.ApprovalModePlan || loaded.Yolo || loaded.MaxBudgetUSD != 0 || loaded.Metadata["ticket"] != "" {
+		t.Errorf("session was changed while observing: %+v", loaded)
+	}
+}
+
+func TestReadOnlyAllowsDisplayCommands(t *testing.T) {
+	c, sess, _ := setupSessionsTest(t)
+	c.ReadOnly = true
+
+	for _, input := range []string{"/status", "/last 3", "/meta list", "/meta GET ticket", "/sessions", "/help"} {
+		c.Out = &bytes.Buffer{}
+		c.handleCommand(sess, input)
+		if out := c.output(); strings.Contains(out, "Read-only") {
+			t.Errorf("%q: expected the command to run, got %q", input, out)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tenazas/internal/models"
+)
+
+// handlePersona implements "/persona [text|@file|clear]": show or set the
+// session's system prompt, which is sent with every prompt to the agent.
+func (c *CLI) handlePersona(sess *models.Session, args []string) {
+	if len(args) == 0 {
+		if sess.SystemPrompt == "" {
+			c.write("No persona set. Use /persona <text> or /persona @file.\n")
+		} else {
+			c.write("Persona:\n" + sess.SystemPrompt + "\n")
+		}
+		return
+	}
+
+	text := strings.Join(args, " ")
+	switch {
+	case len(args) == 1 && args[0] == "clear":
+		text = ""
+	case len(args) == 1 && strings.HasPrefix(args[0], "@"):
+		path := args[0][1:]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(sess.CWD, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			c.write(fmt.Sprintf("Could not read persona file: %v\n", err))
+			return
+		}
+		text = strings.TrimSpace(string(data))
+		if text == "" {
+			c.write(fmt.Sprintf("Persona file %s is empty.\n", args[0][1:]))
+			return
+		}
+	}
+
+	c.updateSession(sess, func(s *models.Session) { s.SystemPrompt = text })
+	if text == "" {
+		c.write("Persona cleared.\n")
+	} else {
+		c.write(fmt.Sprintf("Persona set (%d chars).\n", len(text)))
+	}
+}
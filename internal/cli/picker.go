@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"tenazas/internal/models"
+	"tenazas/internal/task"
+)
+
+// pickerMaxRows bounds how many tasks the picker lists at once.
+const pickerMaxRows = 10
+
+// pickerKey is a keypress as the task picker understands it.
+type pickerKey int
+
+const (
+	pickerNone pickerKey = iota
+	pickerUp
+	pickerDown
+	pickerSelect
+	pickerCancel
+)
+
+// taskPicker is an open "/task pick" list. While it is set on the CLI,
+// replRaw routes keypresses to it instead of the input line, the way it
+// does for permission prompts.
+type taskPicker struct {
+	tasks    []*task.Task
+	index    int
+	maxRows  int
+	row      int // terminal row of the first line drawn
+	sess     *models.Session
+	tasksDir string
+	skills   task.LabelSkills // routes each shown task to its skill hint
+}
+
+// decodePickerKey maps r to a picker key, reading the rest of an escape
+// sequence from reader when r starts one. A bare Escape cancels.
+func decodePickerKey(r rune, reader *bufio.Reader) pickerKey {
+	switch r {
+	case '\r', '\n':
+		return pickerSelect
+	case 'k':
+		return pickerUp
+	case 'j':
+		return pickerDown
+	case 'q':
+		return pickerCancel
+	case '\x1b':
+		if reader.Buffered() == 0 {
+			return pickerCancel
+		}
+		if r2, _, _ := reader.ReadRune(); r2 != '[' && r2 != 'O' {
+			return pickerNone
+		}
+		switch r3, _, _ := reader.ReadRune(); r3 {
+		case 'A':
+			return pickerUp
+		case 'B':
+			return pickerDown
+		}
+	}
+	return pickerNone
+}
+
+// handle applies k to the selection. It returns done once the picker should
+// close, with the chosen task on select and nil on cancel.
+func (p *taskPicker) handle(k pickerKey) (chosen *task.Task, done bool) {
+	switch k {
+	case pickerUp:
+		if p.index > 0 {
+			p.index--
+		}
+	case pickerDown:
+		if p.index < len(p.tasks)-1 {
+			p.index++
+		}
+	case pickerSelect:
+		return p.tasks[p.index], true
+	case pickerCancel:
+		return nil, true
+	}
+	return nil, false
+}
+
+// lines renders the visible tasks, scrolled to keep the selection in view,
+// followed by a key hint. The count never changes while the picker is open.
+func (p *taskPicker) lines() []string {
+	start := 0
+	if p.index >= p.maxRows {
+		start = p.index - p.maxRows + 1
+	}
+	end := start + p.maxRows
+	if end > len(p.tasks) {
+		end = len(p.tasks)
+	}
+	var out []string
+	for i := start; i < end; i++ {
+		t := p.tasks[i]
+		cursor := "  "
+		if i == p.index {
+			cursor = escBoldCyan + "❯ " + escReset
+		}
+		line := fmt.Sprintf("%s%s%s  %s", Margin, cursor, t.ID, t.Title)
+		if skill := p.skills.SkillFor(t); skill != "" {
+			line += escDim + "  (" + skill + ")" + escReset
+		}
+		out = append(out, line)
+	}
+	return append(out, fmt.Sprintf("%s%s↑↓ navigate · Enter start · Esc cancel (%d ready)%s",
+		Margin, escDim, len(p.tasks), escReset))
+}
+
+// handleTaskPick implements "/task pick [id]". With an ID it starts that
+// task; otherwise it opens the picker, or lists the ready tasks when the
+// terminal is not in raw mode.
+func (c *CLI) handleTaskPick(tasksDir string, sess *models.Session, args []string) {
+	if len(args) > 0 {
+		c.startPickedTask(tasksDir, sess, task.NormalizeTaskIDIn(tasksDir, args[0]))
+		return
+	}
+	tasks, ok := c.loadTasks(tasksDir)
+	if !ok {
+		return
+	}
+	if active := findInProgress(tasks); active != nil {
+		c.writeAlreadyInProgress(active)
+		return
+	}
+	ready := task.ReadyTasks(tasks)
+	if len(ready) == 0 {
+		c.write("No tasks ready to start.\n")
+		return
+	}
+	if !c.inRawMode {
+		for i, t := range ready {
+			c.writef("%3d. %s  %s\n", i+1, t.ID, t.Title)
+		}
+		c.write("Start one with /task pick <id>.\n")
+		return
+	}
+	c.openTaskPicker(&taskPicker{tasks: ready, sess: sess, tasksDir: tasksDir, skills: c.Tasks.LabelSkills})
+}
+
+// openTaskPicker scrolls the output region up to make room for p and draws
+// it at the bottom of the region.
+func (c *CLI) openTaskPicker(p *taskPicker) {
+	rows, _ := c.getTermSize()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	bottom := c.scrollBottomLocked(rows)
+	p.maxRows = pickerMaxRows
+	if p.maxRows > bottom-1 {
+		p.maxRows = bottom - 1
+	}
+	if p.maxRows < 1 {
+		p.maxRows = 1
+	}
+	n := len(p.lines())
+	c.writeLocked(fmt.Sprintf(escMoveTo, bottom) + strings.Repeat("\n", n))
+	p.row = bottom - n + 1
+	if p.row < 1 {
+		p.row = 1
+	}
+	c.picker = p
+	c.drawPickerLocked()
+}
+
+func (c *CLI) drawPickerLocked() {
+	var sb strings.Builder
+	sb.WriteString(escHideCursor)
+	for i, line := range c.picker.lines() {
+		fmt.Fprintf(&sb, escMoveTo, c.picker.row+i)
+		sb.WriteString(escClearLine + line)
+	}
+	sb.WriteString(escShowCursor)
+	c.writeLocked(sb.String())
+}
+
+// handlePickerKey feeds k to the open picker. Closing it moves output back
+// below the list; a selection starts the task.
+func (c *CLI) handlePickerKey(k pickerKey) {
+	rows, _ := c.getTermSize()
+	c.mu.Lock()
+	p := c.picker
+	if p == nil {
+		c.mu.Unlock()
+		return
+	}
+	chosen, done := p.handle(k)
+	if !done {
+		c.drawPickerLocked()
+		c.mu.Unlock()
+		return
+	}
+	c.picker = nil
+	c.writeLocked(fmt.Sprintf(escMoveTo, c.scrollBottomLocked(rows)) + "\n")
+	c.mu.Unlock()
+
+	if chosen == nil {
+		c.write("Cancelled.\n")
+		return
+	}
+	c.startPickedTask(p.tasksDir, p.sess, chosen.ID)
+}
+
+// startPickedTask claims the task with the given ID if it is still ready,
+// then runs its skill when it routes to one.
+func (c *CLI) startPickedTask(tasksDir string, sess *models.Session, id string) {
+	tasks, ok := c.loadTasks(tasksDir)
+	if !ok {
+		return
+	}
+	if active := findInProgress(tasks); active != nil {
+		c.writeAlreadyInProgress(active)
+		return
+	}
+	var picked *task.Task
+	for _, t := range task.ReadyTasks(tasks) {
+		if t.ID == id {
+			picked = t
+			break
+		}
+	}
+	if picked == nil {
+		c.writef("Task %s is not ready to start.\n", id)
+		return
+	}
+	if !c.claimTask(sess, picked) {
+		return
+	}
+	if skill := c.Tasks.LabelSkills.SkillFor(picked); skill != "" {
+		c.handleRun(sess, skill, false)
+	}
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tenazas/internal/task"
+)
+
+// pickerKeys decodes simulated terminal input into picker keys.
+func pickerKeys(input string) []pickerKey {
+	reader := bufio.NewReader(strings.NewReader(input))
+	var keys []pickerKey
+	for {
+		r, _, err := reader.ReadRune()
+		if err != nil {
+			return keys
+		}
+		keys = append(keys, decodePickerKey(r, reader))
+	}
+}
+
+func TestDecodePickerKey(t *testing.T) {
+	got := pickerKeys("\x1b[B\x1b[Ajk\r\x1bOBx")
+	want := []pickerKey{pickerDown, pickerUp, pickerDown, pickerUp, pickerSelect, pickerDown, pickerNone}
+	if len(got) != len(want) {
+		t.Fatalf("keys = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("key %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+	// A lone Escape with nothing buffered behind it cancels.
+	if k := pickerKeys("\x1b"); len(k) != 1 || k[0] != pickerCancel {
+		t.Errorf("bare Escape = %v, want cancel", k)
+	}
+}
+
+func TestTaskPickerNavigation(t *testing.T) {
+	p := &taskPicker{maxRows: 2, tasks: []*task.Task{
+		{ID: "TSK-000001", Title: "one"},
+		{ID: "TSK-000002", Title: "two"},
+		{ID: "TSK-000003", Title: "three"},
+	}}
+	for _, k := range pickerKeys("\x1b[A\x1b[B\x1b[B\x1b[B") {
+		if _, done := p.handle(k); done {
+			t.Fatal("navigation closed the picker")
+		}
+	}
+	if p.index != 2 {
+		t.Errorf("index = %d, want 2 (clamped at both ends)", p.index)
+	}
+	lines := p.lines()
+	if len(lines) != 3 || strings.Contains(lines[0], "TSK-000001") || !strings.Contains(lines[1], "❯ "+escReset+"TSK-000003") {
+		t.Errorf("window should scroll to keep the selection visible, got %q", lines)
+	}
+
+	chosen, done := p.handle(pickerUp)
+	if chosen, done = p.handle(pickerSelect); !done || chosen == nil || chosen.ID != "TSK-000002" {
+		t.Errorf("select = %v, %v, want TSK-000002", chosen, done)
+	}
+	if chosen, done = p.handle(pickerCancel); !done || chosen != nil {
+		t.Errorf("cancel = %v, %v, want nil, true", chosen, done)
+	}
+}
+
+func TestTaskPickClaimsSelection(t *testing.T) {
+	cli, sess, tasksDir := setupTaskTest(t)
+	cli.inRawMode = true
+	cli.termSize = func() (int, int) { return 40, 100 }
+	createTestTask(t, tasksDir, "TSK-000001", "High", task.StatusTodo, 2)
+	createTestTask(t, tasksDir, "TSK-000002", "Low", task.StatusTodo, 1)
+	createTestTask(t, tasksDir, "TSK-000003", "Finished", task.StatusDone, 0)
+
+	cli.handleCommand(sess, "/task pick")
+	if cli.picker == nil || len(cli.picker.tasks) != 2 {
+		t.Fatalf("expected a picker over the two ready tasks, got %+v", cli.picker)
+	}
+	for _, k := range pickerKeys("\x1b[B\r") {
+		cli.handlePickerKey(k)
+	}
+	if cli.picker != nil {
+		t.Error("picker should close after a selection")
+	}
+	got, err := task.ReadTask(filepath.Join(tasksDir, "TSK-000002.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != task.StatusInProgress || got.OwnerSessionID != sess.ID {
+		t.Errorf("picked task = %s owned by %q, want in-progress for the session", got.Status, got.OwnerSessionID)
+	}
+	if out := cli.Out.(*bytes.Buffer).String(); !strings.Contains(out, "Started: TSK-000002 — Low") {
+		t.Errorf("expected a Started line, got: %s", out)
+	}
+}
+
+func TestTaskPickCancel(t *testing.T) {
+	cli, sess, tasksDir := setupTaskTest(t)
+	cli.inRawMode = true
+	cli.termSize = func() (int, int) { return 40, 100 }
+	createTestTask(t, tasksDir, "TSK-000001", "Only", task.StatusTodo, 0)
+
+	cli.handleCommand(sess, "/task pick")
+	cli.handlePickerKey(pickerCancel)
+	if cli.picker != nil || !strings.Contains(cli.Out.(*bytes.Buffer).String(), "Cancelled.") {
+		t.Error("Escape should close the picker and say so")
+	}
+	if got, _ := task.ReadTask(filepath.Join(tasksDir, "TSK-000001.md")); got.Status != task.StatusTodo {
+		t.Errorf("cancelled pick changed the task to %s", got.Status)
+	}
+}
+
+func TestTaskPickWithoutRawMode(t *testing.T) {
+	cli, sess, tasksDir := setupTaskTest(t)
+	createTestTask(t, tasksDir, "TSK-000001", "Ready", task.StatusTodo, 0)
+	createTestTask(t, tasksDir, "TSK-000002", "Done", task.StatusDone, 0)
+
+	cli.handleCommand(sess, "/task pick")
+	out := cli.Out.(*bytes.Buffer)
+	if cli.picker != nil || !strings.Contains(out.String(), "1. TSK-000001  Ready") {
+		t.Fatalf("expected a numbered list without raw mode, got: %s", out)
+	}
+
+	out.Reset()
+	cli.handleCommand(sess, "/task pick TSK-000002")
+	if !strings.Contains(out.String(), "not ready to start") {
+		t.Errorf("done task should not be pickable, got: %s", out)
+	}
+	out.Reset()
+	cli.handleCommand(sess, "/task pick 1")
+	if !strings.Contains(out.String(), "Started: TSK-000001") {
+		t.Errorf("expected the task to start by ID, got: %s", out)
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"tenazas/internal/client"
+	"tenazas/internal/models"
+)
+
+// Plain mode (tenazas --plain) drops the full-screen TUI: there is no raw
+// input, scroll region, footer, drawer or pulse. Input is read a line at a
+// time by replLines and the session prints as a plain log, like the run
+// subcommand. Commands work as in the TUI; those that only redraw the
+// screen are no-ops.
+
+// writePlainHeader stands in for the banner in plain mode.
+func (c *CLI) writePlainHeader(sess *models.Session) {
+	clientName := sess.Client
+	if clientName == "" {
+		clientName = c.DefaultClient
+	}
+	c.writef("Tenazas session %s in %s (client: %s, mode: %s)\n", sess.ID, sess.CWD, clientName, sess.ApprovalMode)
+}
+
+// latestSession picks the most recently updated session for --resume in
+// plain mode, where the interactive picker is unavailable.
+func (c *CLI) latestSession() (*models.Session, error) {
+	sessions, _, err := c.Sm.ListActive(0, 1)
+	if err != nil {
+		return nil, fmt.Errorf("could not list sessions: %v", err)
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("no sessions to resume")
+	}
+	return c.Sm.Load(sessions[0].ID)
+}
+
+// writePlainPermission prints a permission request as lines; the answer is
+// the next input line holding one of the listed keys.
+func (c *CLI) writePlainPermission(req client.PermissionRequest) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\nPermission requested (%s): %s\n", req.Kind, req.Title)
+	if req.Command != "" {
+		fmt.Fprintf(&sb, "  %s\n", req.Command)
+	}
+	var opts []string
+	for _, opt := range req.Options {
+		if key := permissionKey(opt.Kind); key != "" {
+			opts = append(opts, fmt.Sprintf("[%s] %s", key, opt.Name))
+		}
+	}
+	fmt.Fprintf(&sb, "  %s\n", strings.Join(opts, "   "))
+	c.write(sb.String())
+}
+
+// answerPermissionLine treats a one-key line as the answer to a pending
+// permission request. It reports whether the line was consumed.
+func (c *CLI) answerPermissionLine(text string) bool {
+	r := []rune(text)
+	if len(r) != 1 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.handlePermissionKeyLocked(r[0])
+}
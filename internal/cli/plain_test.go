@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"tenazas/internal/client"
+	"tenazas/internal/models"
+)
+
+func TestPlainRunHasNoTUI(t *testing.T) {
+	cli, _, _ := setupTaskTest(t)
+	cli.sess = nil
+	cli.Plain = true
+	cli.In = strings.NewReader("/mode auto_edit\n/budget 2.5\n/tier low\n/tasks\n/status\n")
+	var out bytes.Buffer
+	cli.Out = &out
+
+	if err := cli.Run(false); err != io.EOF {
+		t.Fatalf("expected io.EOF at the end of input, got %v", err)
+	}
+
+	got := out.String()
+	if strings.Contains(got, "\x1b") {
+		t.Errorf("expected no escape sequences in plain mode, got %q", got)
+	}
+	if strings.Contains(got, "─") || strings.Contains(got, "████") {
+		t.Errorf("expected no footer or banner in plain mode, got %q", got)
+	}
+	for _, want := range []string{"Tenazas session", "Mode set to AUTO_EDIT.", "Budget set to $2.50.", "Model tier set to low.", "No tasks found", "Mode:"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output, got %q", want, got)
+		}
+	}
+
+	sess := cli.currentSession()
+	if sess.ApprovalMode != models.ApprovalModeAutoEdit || sess.MaxBudgetUSD != 2.5 || sess.ModelTier != "low" {
+		t.Errorf("expected the commands to update the session, got mode=%s budget=%v tier=%s", sess.ApprovalMode, sess.MaxBudgetUSD, sess.ModelTier)
+	}
+}
+
+func TestPlainAnswersPermissionByLine(t *testing.T) {
+	cli, _, _ := setupTaskTest(t)
+	cli.Plain = true
+	cli.In = strings.NewReader("y\n")
+
+	resp := make(chan client.PermissionResponse, 1)
+	req := client.PermissionRequest{
+		Title:   "Run tests",
+		Kind:    "execute",
+		Command: "go test ./...",
+		Options: []client.PermissionOption{
+			{OptionID: "ok", Name: "Allow once", Kind: "allow_once"},
+			{OptionID: "no", Name: "Reject", Kind: "reject_once"},
+		},
+	}
+	cli.permPending = &permissionState{req: req, resp: resp}
+	cli.renderPermission(req)
+
+	cli.replLines()
+
+	select {
+	case r := <-resp:
+		if r.OptionID != "ok" {
+			t.Errorf("expected the allow option, got %q", r.OptionID)
+		}
+	default:
+		t.Fatal("expected the line to answer the permission request")
+	}
+	out := cli.output()
+	for _, want := range []string{"Permission requested (execute): Run tests", "go test ./...", "[y] Allow once", "[n] Reject", "Allowed (once)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got %q", want, out)
+		}
+	}
+}
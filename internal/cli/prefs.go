@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tenazas/internal/events"
+	"tenazas/internal/storage"
+)
+
+// PrefsFileName is the per-user UI preferences file, kept in the storage
+// directory next to the sessions but never inside a session.
+const PrefsFileName = "ui_prefs.json"
+
+// defaultVerbosity is the registry verbosity a CLI instance starts with when
+// no preference is saved.
+const defaultVerbosity = "HIGH"
+
+// uiPrefs holds the display settings the CLI restores on every launch.
+type uiPrefs struct {
+	Immersive bool   `json:"immersive,omitempty"` // start with the footer hidden
+	Verbosity string `json:"verbosity,omitempty"` // LOW, MEDIUM or HIGH; empty means defaultVerbosity
+	Wrap      bool   `json:"wrap,omitempty"`      // new sessions start with /wrap on
+}
+
+func (p uiPrefs) verbosity() string {
+	if p.Verbosity == "" {
+		return defaultVerbosity
+	}
+	return p.Verbosity
+}
+
+func (p uiPrefs) wrapLabel() string {
+	if p.Wrap {
+		return "on"
+	}
+	return "off"
+}
+
+// loadPrefs reads the preferences file from storageDir. A missing or
+// unreadable file yields the defaults.
+func loadPrefs(storageDir string) uiPrefs {
+	var p uiPrefs
+	if storageDir == "" {
+		return p
+	}
+	if err := storage.NewStorage(storageDir).ReadJSON(PrefsFileName, &p); err != nil {
+		return uiPrefs{}
+	}
+	return p
+}
+
+// savePrefs writes c.prefs to the storage directory. It holds c.mu only to
+// copy them, so the file is never written with the lock held; callers must
+// not hold it.
+func (c *CLI) savePrefs() error {
+	if c.Sm == nil || c.Sm.StoragePath == "" {
+		return nil
+	}
+	c.mu.Lock()
+	p := c.prefs
+	c.mu.Unlock()
+	return storage.NewStorage(c.Sm.StoragePath).WriteJSON(PrefsFileName, p)
+}
+
+// setImmersiveLocked flips immersive mode and records the choice in c.prefs.
+// Callers hold c.mu, and call savePrefs once they release it.
+func (c *CLI) setImmersiveLocked(on bool) {
+	c.IsImmersive = on
+	c.prefs.Immersive = on
+}
+
+// showsAudit reports whether the saved verbosity lets the CLI print an audit
+// line of auditType: LOW hides command results and info lines, MEDIUM hides
+// command results. The model's output, statuses and interventions always
+// show.
+func (c *CLI) showsAudit(auditType string) bool {
+	c.mu.Lock()
+	v := c.prefs.verbosity()
+	c.mu.Unlock()
+	switch auditType {
+	case events.AuditCmdResult:
+		return v == "HIGH"
+	case events.AuditInfo:
+		return v != "LOW"
+	}
+	return true
+}
+
+// handlePrefs implements "/prefs [reset|verbosity <level>]": show the saved UI
+// preferences, change the verbosity, or forget them all.
+func (c *CLI) handlePrefs(args []string) {
+	if len(args) == 0 {
+		c.mu.Lock()
+		p := c.prefs
+		c.mu.Unlock()
+		var sb strings.Builder
+		sb.WriteString("UI preferences:\n")
+		fmt.Fprintf(&sb, "  Immersive: %v\n", p.Immersive)
+		fmt.Fprintf(&sb, "  Verbosity: %s\n", p.verbosity())
+		fmt.Fprintf(&sb, "  Wrap:      %s (new sessions)\n", p.wrapLabel())
+		if c.Sm != nil {
+			fmt.Fprintf(&sb, "  File:      %s\n", filepath.Join(c.Sm.StoragePath, PrefsFileName))
+		}
+		c.write(sb.String())
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "reset":
+		c.mu.Lock()
+		c.prefs = uiPrefs{}
+		wasImmersive := c.IsImmersive
+		c.IsImmersive = false
+		var err error
+		if c.Sm != nil {
+			err = os.Remove(filepath.Join(c.Sm.StoragePath, PrefsFileName))
+			if os.IsNotExist(err) {
+				err = nil
+			}
+		}
+		if wasImmersive {
+			c.redrawScreenLocked()
+		}
+		c.mu.Unlock()
+		if err != nil {
+			c.write(fmt.Sprintf("Error resetting preferences: %v\n", err))
+			return
+		}
+		c.setVerbosity(defaultVerbosity)
+		c.write("UI preferences reset to defaults.\n")
+	case "verbosity":
+		if len(args) < 2 {
+			c.write("Usage: /prefs verbosity <low|medium|high>\n")
+			return
+		}
+		v := strings.ToUpper(args[1])
+		if v != "LOW" && v != "MEDIUM" && v != "HIGH" {
+			c.write("Invalid verbosity. Use: low, medium or high\n")
+			return
+		}
+		c.mu.Lock()
+		c.prefs.Verbosity = v
+		c.mu.Unlock()
+		if err := c.savePrefs(); err != nil {
+			c.write(fmt.Sprintf("Error saving preferences: %v\n", err))
+			return
+		}
+		c.setVerbosity(v)
+		c.write("Verbosity set to " + v + ".\n")
+	default:
+		c.write("Usage: /prefs [reset|verbosity <level>]\n")
+	}
+}
+
+// setVerbosity applies v to the registry instance of this CLI, if bound.
+func (c *CLI) setVerbosity(v string) {
+	if c.Reg != nil && c.instanceID != "" {
+		c.Reg.SetVerbosity(c.instanceID, v)
+	}
+}
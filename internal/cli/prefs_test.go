@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tenazas/internal/events"
+	"tenazas/internal/session"
+)
+
+func TestImmersivePersistsAcrossLaunches(t *testing.T) {
+	tmpDir := t.TempDir()
+	sm := session.NewManager(tmpDir)
+
+	first := NewCLI(sm, nil, nil, "gemini", "", nil)
+	first.Out = &bytes.Buffer{}
+	if first.IsImmersive {
+		t.Fatal("a fresh CLI should start with the footer shown")
+	}
+	first.toggleImmersive()
+
+	if _, err := os.Stat(filepath.Join(tmpDir, PrefsFileName)); err != nil {
+		t.Fatalf("toggling immersive should write %s: %v", PrefsFileName, err)
+	}
+
+	second := NewCLI(session.NewManager(tmpDir), nil, nil, "gemini", "", nil)
+	if !second.IsImmersive {
+		t.Error("a new CLI should restore immersive mode from the prefs file")
+	}
+}
+
+func TestPrefsVerbosityAndReset(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+
+	cli.handleCommand(sess, "/prefs verbosity low")
+	cli.handleCommand(sess, "/wrap on")
+	cli.toggleImmersive()
+
+	restored := NewCLI(cli.Sm, nil, nil, "gemini", "", nil)
+	if restored.prefs.verbosity() != "LOW" || !restored.prefs.Wrap || !restored.IsImmersive {
+		t.Fatalf("prefs not restored: %+v", restored.prefs)
+	}
+
+	cli.Out.(*bytes.Buffer).Reset()
+	cli.handleCommand(sess, "/prefs reset")
+	if out := cli.output(); !strings.Contains(out, "reset to defaults") {
+		t.Errorf("expected a reset notice, got %q", out)
+	}
+	if cli.IsImmersive {
+		t.Error("reset should leave immersive mode")
+	}
+
+	restored = NewCLI(cli.Sm, nil, nil, "gemini", "", nil)
+	if restored.prefs != (uiPrefs{}) || restored.IsImmersive {
+		t.Errorf("prefs should be back to defaults after reset, got %+v", restored.prefs)
+	}
+}
+
+func TestPrefsRejectsBadVerbosity(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+
+	cli.handleCommand(sess, "/prefs verbosity loud")
+	if out := cli.output(); !strings.Contains(out, "Invalid verbosity") {
+		t.Errorf("expected an invalid verbosity message, got %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(cli.Sm.StoragePath, PrefsFileName)); !os.IsNotExist(err) {
+		t.Errorf("a rejected value should not write the prefs file, stat err = %v", err)
+	}
+}
+
+func TestVerbosityFiltersCLIOutput(t *testing.T) {
+	for _, tc := range []struct {
+		verbosity string
+		shown     []string
+		hidden    []string
+	}{
+		{"HIGH", []string{"tool ran", "note", "finished"}, nil},
+		{"MEDIUM", []string{"note", "finished"}, []string{"tool ran"}},
+		{"LOW", []string{"finished"}, []string{"tool ran", "note"}},
+	} {
+		var out bytes.Buffer
+		cli := &CLI{Out: &out, prefs: uiPrefs{Verbosity: tc.verbosity}}
+		ch := make(chan events.Event, 3)
+		for _, a := range []events.AuditEntry{
+			{Type: events.AuditCmdResult, Content: "tool ran"},
+			{Type: events.AuditInfo, Content: "note"},
+			{Type: events.AuditStatus, Content: "finished"},
+		} {
+			ch <- events.Event{Type: events.EventAudit, SessionID: "sess", Payload: a}
+		}
+		close(ch)
+
+		cli.listenOn(ch, "sess")
+		for _, s := range tc.shown {
+			if !strings.Contains(out.String(), s) {
+				t.Errorf("%s: expected %q to be shown, got %q", tc.verbosity, s, out.String())
+			}
+		}
+		for _, s := range tc.hidden {
+			if strings.Contains(out.String(), s) {
+				t.Errorf("%s: expected %q to be hidden, got %q", tc.verbosity, s, out.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"tenazas/internal/engine"
+	"tenazas/internal/events"
+	"tenazas/internal/formatter"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+// PromptOptions configures a one-shot, non-interactive prompt run.
+type PromptOptions struct {
+	Prompt     string // prompt text; read from the input reader when empty
+	SessionID  string // existing session to continue; a new one is created when empty
+	CWD        string
+	Client     string
+	ModelTier  string
+	Plain      bool // stream only response text, without ANSI formatting
+	Transcript bool // also append the output to the session transcript file
+	Yolo       bool // auto-approve the agent's tool calls in a new session
+}
+
+// RunPrompt executes a single prompt outside the interactive TUI, streaming
+// the response to out and errors to errOut, so piped output holds only the
+// response. It returns the process exit code: 0 when the agent produced a
+// response and no error was logged, 1 otherwise.
+func RunPrompt(sm *session.Manager, eng *engine.Engine, opts PromptOptions, in io.Reader, out, errOut io.Writer) int {
+	prompt := opts.Prompt
+	if prompt == "" && in != nil {
+		data, err := io.ReadAll(in)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error reading prompt: %v\n", err)
+			return 1
+		}
+		prompt = string(data)
+	}
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		fmt.Fprintln(errOut, "Error: empty prompt. Pass --prompt or pipe text on stdin.")
+		return 1
+	}
+
+	if opts.SessionID == "" {
+		if err := eng.CheckCWD(opts.Client, opts.CWD); err != nil {
+			fmt.Fprintf(errOut, "Error: %v\n", err)
+			return 1
+		}
+	}
+	sess, err := promptSession(sm, opts)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+
+	if opts.Transcript {
+		f, err := OpenTranscriptFile(sm, sess)
+		if err != nil {
+			fmt.Fprintf(errOut, "Warning: could not open transcript: %v\n", err)
+		} else {
+			defer f.Close()
+			out = io.MultiWriter(out, &formatter.PlainWriter{W: f})
+		}
+	}
+
+	eventCh := events.GlobalBus.Subscribe()
+	done := make(chan struct{})
+	failed := false
+	go func() {
+		defer close(done)
+		failed = streamPromptEvents(eventCh, sess.ID, opts.Plain, out, errOut)
+	}()
+
+	eng.ExecutePrompt(sess, prompt)
+
+	events.GlobalBus.Unsubscribe(eventCh)
+	<-done
+
+	last, err := sm.GetLastAudit(sess, 1)
+	if failed || err != nil || len(last) == 0 || last[0].Type != events.AuditLLMResponse {
+		return 1
+	}
+	return 0
+}
+
+func promptSession(sm *session.Manager, opts PromptOptions) (*models.Session, error) {
+	if opts.SessionID != "" {
+		return sm.Load(opts.SessionID)
+	}
+	sess, err := sm.Create(opts.CWD, "prompt")
+	if err != nil {
+		return nil, err
+	}
+	sess.Client = opts.Client
+	sess.ModelTier = opts.ModelTier
+	sess.Yolo = opts.Yolo
+	return sess, sm.Save(sess)
+}
+
+// streamPromptEvents writes the session's events to out, and its errors to
+// errOut, until eventCh closes. It reports whether any error was logged.
+func streamPromptEvents(eventCh chan events.Event, sessionID string, plain bool, out, errOut io.Writer) (failed bool) {
+	f := &formatter.AnsiFormatter{}
+	for e := range eventCh {
+		if e.SessionID != sessionID || e.Type != events.EventAudit {
+			continue
+		}
+		audit, ok := e.Payload.(events.AuditEntry)
+		if !ok {
+			continue
+		}
+		if audit.Error {
+			failed = true
+			if plain {
+				// Errors still reach scripts, just without colors.
+				fmt.Fprintln(errOut, audit.Content)
+			} else {
+				fmt.Fprintln(errOut, f.Format(audit))
+			}
+			continue
+		}
+		switch audit.Type {
+		case events.AuditLLMChunk:
+			fmt.Fprint(out, audit.Content)
+		case events.AuditLLMResponse:
+			fmt.Fprintln(out)
+		case events.AuditCmdResult, events.AuditInfo:
+			if !plain {
+				fmt.Fprintln(out, f.Format(audit))
+			}
+		}
+	}
+	return failed
+}
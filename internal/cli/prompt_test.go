@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tenazas/internal/client"
+	"tenazas/internal/engine"
+	"tenazas/internal/events"
+	"tenazas/internal/session"
+)
+
+func newPromptEngine(t *testing.T, script string) (*session.Manager, *engine.Engine, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	scriptPath := filepath.Join(tmpDir, "stub.sh")
+	os.WriteFile(scriptPath, []byte(script), 0755)
+
+	sm := session.NewManager(tmpDir)
+	c, _ := client.NewClient("gemini", scriptPath, filepath.Join(tmpDir, "tenazas.log"))
+	eng := engine.NewEngine(sm, map[string]client.Client{"gemini": c}, "gemini", 5)
+	return sm, eng, tmpDir
+}
+
+func TestRunPromptFromPipe(t *testing.T) {
+	// The stub echoes back the --prompt argument as two streamed chunks.
+	sm, eng, cwd := newPromptEngine(t, `#!/bin/bash
+while [ $# -gt 0 ]; do
+  if [ "$1" = "--prompt" ]; then PROMPT="$2"; fi
+  shift
+done
+echo '{"type": "init", "session_id": "sid-1"}'
+echo "{\"type\": \"message\", \"content\": \"you said: \"}"
+echo "{\"type\": \"message\", \"content\": \"$PROMPT\"}"
+`)
+
+	var out bytes.Buffer
+	code := RunPrompt(sm, eng, PromptOptions{CWD: cwd, Client: "gemini", Plain: true}, strings.NewReader("hello world\n"), &out, &out)
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d (output %q)", code, out.String())
+	}
+	if got := out.String(); got != "you said: hello world\n" {
+		t.Errorf("unexpected plain output: %q", got)
+	}
+	if strings.Contains(out.String(), "\x1b[") {
+		t.Error("expected no ANSI escapes in plain output")
+	}
+}
+
+func TestRunPromptFailure(t *testing.T) {
+	sm, eng, cwd := newPromptEngine(t, "#!/bin/bash\nexit 3\n")
+
+	var out, errOut bytes.Buffer
+	code := RunPrompt(sm, eng, PromptOptions{Prompt: "hi", CWD: cwd, Client: "gemini", Plain: true}, nil, &out, &errOut)
+	if code != 1 {
+		t.Errorf("expected exit code 1 on client failure, got %d", code)
+	}
+	if !strings.Contains(errOut.String(), "LLM Error") || out.Len() != 0 {
+		t.Errorf("expected the error on stderr and nothing on stdout, got %q / %q", out.String(), errOut.String())
+	}
+}
+
+func TestRunPromptEmpty(t *testing.T) {
+	sm, eng, cwd := newPromptEngine(t, "#!/bin/bash\n")
+
+	var out, errOut bytes.Buffer
+	if code := RunPrompt(sm, eng, PromptOptions{CWD: cwd}, strings.NewReader("  \n"), &out, &errOut); code != 1 {
+		t.Errorf("expected exit code 1 for empty prompt, got %d", code)
+	}
+	if out.Len() != 0 || !strings.Contains(errOut.String(), "empty prompt") {
+		t.Errorf("expected the error on stderr only, got %q / %q", out.String(), errOut.String())
+	}
+}
+
+func TestRunPromptRejectedIsAnError(t *testing.T) {
+	sm, eng, cwd := newPromptEngine(t, `#!/bin/bash
+echo '{"type": "message", "content": "ok"}'
+`)
+	eng.MaxPromptChars = 5
+	eng.PromptLimitPolicy = engine.PromptLimitReject
+
+	var out, errOut bytes.Buffer
+	code := RunPrompt(sm, eng, PromptOptions{Prompt: "far too long", CWD: cwd, Client: "gemini"}, nil, &out, &errOut)
+	if code != 1 {
+		t.Errorf("expected exit code 1 for a rejected prompt, got %d", code)
+	}
+	if !strings.Contains(errOut.String(), "Prompt rejected") || strings.Contains(out.String(), "Prompt rejected") {
+		t.Errorf("expected the rejection on stderr only, got %q / %q", out.String(), errOut.String())
+	}
+}
+
+func TestStreamPromptEventsFailsOnlyOnFlaggedErrors(t *testing.T) {
+	stream := func(entries ...events.AuditEntry) (bool, string) {
+		ch := make(chan events.Event, len(entries))
+		for _, e := range entries {
+			ch <- events.Event{Type: events.EventAudit, SessionID: "s1", Payload: e}
+		}
+		close(ch)
+		var out, errOut bytes.Buffer
+		failed := streamPromptEvents(ch, "s1", true, &out, &errOut)
+		return failed, errOut.String()
+	}
+
+	// Text that merely looks like an error is not one.
+	if failed, _ := stream(events.AuditEntry{Type: events.AuditInfo, Content: "LLM Error: just quoting a log line"}); failed {
+		t.Error("expected an unflagged entry not to fail the prompt")
+	}
+	failed, errOut := stream(events.AuditEntry{Type: events.AuditInfo, Content: "quota exceeded", Error: true})
+	if !failed || !strings.Contains(errOut, "quota exceeded") {
+		t.Errorf("expected a flagged entry to fail the prompt on stderr, got %v / %q", failed, errOut)
+	}
+}
+
+func TestRunPromptYoloSession(t *testing.T) {
+	sm, eng, cwd := newPromptEngine(t, `#!/bin/bash
+echo '{"type": "message", "content": "ok"}'
+`)
+
+	var out bytes.Buffer
+	code := RunPrompt(sm, eng, PromptOptions{Prompt: "fix the build", CWD: cwd, Client: "gemini", Plain: true, Yolo: true}, nil, &out, &out)
+	if code != 0 || out.String() != "ok\n" {
+		t.Fatalf("expected exit 0 and the streamed response, got %d %q", code, out.String())
+	}
+	sess, err := sm.GetLatest()
+	if err != nil || !sess.Yolo {
+		t.Errorf("expected the new session to be yolo, got %+v (%v)", sess, err)
+	}
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestRedrawCommandRebuildsScreen(t *testing.T) {
+	var out bytes.Buffer
+	c := &CLI{Out: &out, Sm: session.NewManager(t.TempDir())}
+	c.sess = &models.Session{ID: "redraw-sess", CWD: "/tmp/redraw", ApprovalMode: models.ApprovalModePlan}
+	c.lastRows = 10 // stale size from before a missed resize
+
+	c.handleCommand(c.sess, "/redraw")
+
+	got := out.String()
+	rows, _ := c.getTermSize()
+	for _, want := range []string{EscClear, fmt.Sprintf(escScrollRegion, 1, rows-6), "shift+tab"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in redraw output, got %q", want, got)
+		}
+	}
+	if c.lastRows != rows {
+		t.Errorf("expected terminal size to be re-queried (lastRows=%d, rows=%d)", c.lastRows, rows)
+	}
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"tenazas/internal/models"
+)
+
+// reflowText soft-wraps text to width terminal columns, starting at column
+// col. Lines break at spaces when the next word would overflow, and words
+// longer than width are hard-broken. Wrapped lines repeat the leading spaces
+// of the line they continue, so output indented by Margin keeps it. ANSI
+// escape sequences are copied through without counting toward the width.
+// indent is the leading-space count of the line in progress at col. It
+// returns the wrapped text with the column and indent the output ends on,
+// so streamed chunks can be reflowed one at a time.
+func reflowText(text string, width, col, indent int) (string, int, int) {
+	if width <= 0 {
+		return text, col, indent
+	}
+	runes := []rune(text)
+	var sb strings.Builder
+	sb.Grow(len(text) + len(text)/width + 1)
+	leading := col == indent // only spaces so far on this line
+	wrap := func() {
+		if indent >= width/2 {
+			indent = 0
+		}
+		sb.WriteRune('\n')
+		sb.WriteString(strings.Repeat(" ", indent))
+		col = indent
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\x1b' {
+			end := ansiSeqEnd(runes, i)
+			sb.WriteString(string(runes[i:end]))
+			i = end - 1
+			continue
+		}
+		switch r {
+		case '\n', '\r':
+			sb.WriteRune(r)
+			col, indent, leading = 0, 0, true
+			continue
+		case ' ':
+			if leading {
+				indent++
+				break
+			}
+			wl := visibleWordLen(runes, i+1)
+			if col >= width || (col+1+wl > width && wl <= width-indent) {
+				wrap()
+				continue
+			}
+		default:
+			leading = false
+		}
+		if col >= width {
+			wrap()
+		}
+		sb.WriteRune(r)
+		col++
+	}
+	return sb.String(), col, indent
+}
+
+// ansiSeqEnd returns the index just past the escape sequence starting at i.
+func ansiSeqEnd(runes []rune, i int) int {
+	j := i + 1
+	if j < len(runes) && runes[j] == '[' {
+		for j++; j < len(runes); j++ {
+			if runes[j] >= 0x40 && runes[j] <= 0x7e {
+				return j + 1
+			}
+		}
+		return len(runes)
+	}
+	if j < len(runes) {
+		return j + 1
+	}
+	return j
+}
+
+// visibleWordLen counts the printable runes of the word starting at i.
+func visibleWordLen(runes []rune, i int) int {
+	n := 0
+	for i < len(runes) {
+		r := runes[i]
+		if r == ' ' || r == '\n' || r == '\r' {
+			break
+		}
+		if r == '\x1b' {
+			i = ansiSeqEnd(runes, i)
+			continue
+		}
+		n++
+		i++
+	}
+	return n
+}
+
+// reflowOutput applies reflowText to engine output when the session has
+// wrapping turned on, tracking the output column across calls.
+func (c *CLI) reflowOutput(s string) string {
+	_, cols := c.getTermSize()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sess == nil || !c.sess.Wrap {
+		if i := strings.LastIndexAny(s, "\r\n"); i >= 0 {
+			c.outCol = len([]rune(s[i+1:]))
+		} else {
+			c.outCol += len([]rune(s))
+		}
+		c.outIndent = 0
+		return s
+	}
+	out, col, indent := reflowText(s, cols, c.outCol, c.outIndent)
+	c.outCol, c.outIndent = col, indent
+	return out
+}
+
+func (c *CLI) handleWrap(sess *models.Session, args []string) {
+	if len(args) == 0 {
+		c.write(fmt.Sprintf("Wrap: %s\nUsage: /wrap <on|off>\n", wrapLabel(sess)))
+		return
+	}
+	var wrap bool
+	switch strings.ToLower(args[0]) {
+	case "on":
+		wrap = true
+	case "off":
+		wrap = false
+	default:
+		c.write("Invalid value. Use: /wrap <on|off>\n")
+		return
+	}
+	c.mu.Lock()
+	c.updateSession(sess, func(s *models.Session) { s.Wrap = wrap })
+	c.prefs.Wrap = wrap
+	c.mu.Unlock()
+	c.savePrefs()
+	c.write(fmt.Sprintf("Wrap %s.\n", wrapLabel(sess)))
+}
+
+func wrapLabel(sess *models.Session) string {
+	if sess.Wrap {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"tenazas/internal/models"
+)
+
+func TestReflowTextWrapsAtWordBoundaries(t *testing.T) {
+	out, col, _ := reflowText("the quick brown fox jumps", 10, 0, 0)
+	want := "the quick\nbrown fox\njumps"
+	if out != want {
+		t.Errorf("reflowText = %q, want %q", out, want)
+	}
+	if col != 5 {
+		t.Errorf("expected end column 5, got %d", col)
+	}
+}
+
+func TestReflowTextHardBreaksLongWords(t *testing.T) {
+	out, _, _ := reflowText(strings.Repeat("x", 25), 10, 0, 0)
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) > 10 {
+			t.Errorf("line exceeds width: %q", line)
+		}
+	}
+	if strings.ReplaceAll(out, "\n", "") != strings.Repeat("x", 25) {
+		t.Errorf("expected content preserved, got %q", out)
+	}
+}
+
+func TestReflowTextIgnoresANSIAndCarriesColumn(t *testing.T) {
+	colored := escCyan + "abcde" + escReset
+	out, col, _ := reflowText(colored, 10, 0, 0)
+	if out != colored || col != 5 {
+		t.Errorf("expected escape codes to not count toward width, got %q (col %d)", out, col)
+	}
+
+	// A second chunk continuing on the same line wraps relative to col.
+	out, col, _ = reflowText(" fghij", 10, col, 0)
+	if out != "\nfghij" || col != 5 {
+		t.Errorf("expected chunk to wrap onto a new line, got %q (col %d)", out, col)
+	}
+}
+
+func TestReflowTextKeepsMargin(t *testing.T) {
+	out, col, indent := reflowText("\n"+Margin+"the quick brown fox jumps", 12, 0, 0)
+	want := "\n" + Margin + "the quick\n" + Margin + "brown fox\n" + Margin + "jumps"
+	if out != want {
+		t.Errorf("reflowText = %q, want %q", out, want)
+	}
+	if col != MarginWidth+5 || indent != MarginWidth {
+		t.Errorf("expected to end at column %d with indent %d, got %d / %d", MarginWidth+5, MarginWidth, col, indent)
+	}
+
+	// A later chunk of the same line keeps wrapping under the margin.
+	out, _, _ = reflowText(" over the dog", 12, col, indent)
+	if out != " over\n"+Margin+"the dog" {
+		t.Errorf("expected the next chunk to keep the margin, got %q", out)
+	}
+}
+
+func TestWrapToggleChangesLongLineWrite(t *testing.T) {
+	var out bytes.Buffer
+	sess := &models.Session{ID: "wrap-sess"}
+	c := &CLI{Out: &out, sess: sess}
+
+	long := strings.TrimSpace(strings.Repeat("word ", 40)) // 199 chars, wider than 80 cols
+	if raw := c.reflowOutput(long + "\n"); raw != long+"\n" {
+		t.Errorf("expected raw passthrough by default, got %q", raw)
+	}
+
+	c.handleWrap(sess, []string{"on"})
+	if !sess.Wrap {
+		t.Fatal("expected /wrap on to set Wrap on the session")
+	}
+	wrapped := c.reflowOutput(long + "\n")
+	for _, line := range strings.Split(wrapped, "\n") {
+		if len(line) > 80 {
+			t.Errorf("expected wrapped line within 80 cols, got %d", len(line))
+		}
+	}
+	if strings.Count(wrapped, "\n") < 3 {
+		t.Errorf("expected long line to be reflowed, got %q", wrapped)
+	}
+
+	c.handleWrap(sess, []string{"off"})
+	if sess.Wrap {
+		t.Fatal("expected /wrap off to clear Wrap")
+	}
+	if raw := c.reflowOutput(long + "\n"); raw != long+"\n" {
+		t.Errorf("expected raw passthrough with wrap off, got %q", raw)
+	}
+}
+
+func TestHandleStatusShowsWrap(t *testing.T) {
+	var out bytes.Buffer
+	sess := &models.Session{ID: "status-sess", Title: "Demo", MaxBudgetUSD: 2.5}
+	c := &CLI{Out: &out, DefaultClient: "gemini", DefaultModelTier: "medium"}
+
+	c.handleStatus(sess)
+	got := out.String()
+	for _, want := range []string{"Demo", "status-sess", "gemini", "medium (default)", "$2.50", "Wrap:    off"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected /status output to contain %q, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// renderInterval is the minimum time between batched prompt/footer/drawer redraws.
+const renderInterval = 30 * time.Millisecond
+
+// renderTarget is a bitmask of screen areas that need redrawing.
+type renderTarget uint8
+
+const (
+	renderPrompt renderTarget = 1 << iota
+	renderFooter
+	renderDrawer
+)
+
+// renderScheduler coalesces redraw requests so that any number of marks
+// between two ticks produce at most one flush.
+type renderScheduler struct {
+	mu     sync.Mutex
+	dirty  renderTarget
+	active bool
+}
+
+func (r *renderScheduler) mark(t renderTarget) {
+	r.mu.Lock()
+	r.dirty |= t
+	r.mu.Unlock()
+}
+
+// take returns and clears the pending targets.
+func (r *renderScheduler) take() renderTarget {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.dirty
+	r.dirty = 0
+	return t
+}
+
+func (r *renderScheduler) setActive(active bool) {
+	r.mu.Lock()
+	r.active = active
+	r.mu.Unlock()
+}
+
+func (r *renderScheduler) isActive() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.active
+}
+
+// requestRender schedules a redraw for the next tick of renderLoop, or
+// redraws immediately when the loop is not running.
+func (c *CLI) requestRender(t renderTarget) {
+	if !c.render.isActive() {
+		c.flushRender(t)
+		return
+	}
+	c.render.mark(t)
+}
+
+// renderLoop flushes batched redraws at most once per renderInterval.
+func (c *CLI) renderLoop(stop <-chan struct{}) {
+	c.render.setActive(true)
+	defer c.render.setActive(false)
+	ticker := time.NewTicker(renderInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if t := c.render.take(); t != 0 {
+				c.flushRender(t)
+			}
+		}
+	}
+}
+
+func (c *CLI) flushRender(t renderTarget) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Out == nil || c.Plain {
+		return
+	}
+	var sb strings.Builder
+	if t&renderFooter != 0 && c.sess != nil {
+		c.drawFooterAtomic(&sb, c.sess)
+	}
+	if t&renderDrawer != 0 {
+		c.drawDrawerAtomic(&sb)
+	}
+	if t&renderPrompt != 0 {
+		c.renderLineAtomic(&sb)
+	}
+	c.writeLocked(sb.String())
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRenderSchedulerCoalescesMarks(t *testing.T) {
+	var r renderScheduler
+	for i := 0; i < 100; i++ {
+		r.mark(renderPrompt)
+	}
+	r.mark(renderFooter)
+
+	if got := r.take(); got != renderPrompt|renderFooter {
+		t.Errorf("expected combined prompt|footer targets, got %b", got)
+	}
+	if got := r.take(); got != 0 {
+		t.Errorf("expected nothing pending after take, got %b", got)
+	}
+}
+
+// countingWriter counts Write calls.
+type countingWriter struct {
+	mu     sync.Mutex
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	w.writes++
+	w.mu.Unlock()
+	return len(p), nil
+}
+
+func (w *countingWriter) count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.writes
+}
+
+func TestRenderLoopFlushesAtMostOncePerTick(t *testing.T) {
+	out := &countingWriter{}
+	c := &CLI{Out: out}
+
+	stop := make(chan struct{})
+	go c.renderLoop(stop)
+	defer close(stop)
+	for !c.render.isActive() {
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	for i := 0; i < 500; i++ {
+		c.requestRender(renderPrompt)
+	}
+	time.Sleep(3 * renderInterval)
+	elapsed := time.Since(start)
+
+	maxFlushes := int(elapsed/renderInterval) + 1
+	if n := out.count(); n < 1 || n > maxFlushes {
+		t.Errorf("expected between 1 and %d flushes for 500 marks, got %d", maxFlushes, n)
+	}
+}
+
+func TestRequestRenderIsImmediateWithoutLoop(t *testing.T) {
+	out := &countingWriter{}
+	c := &CLI{Out: out}
+
+	c.requestRender(renderPrompt)
+	if out.count() != 1 {
+		t.Errorf("expected an immediate flush when no render loop runs, got %d", out.count())
+	}
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// handleReplay implements "/replay [N]": print the Nth-from-last LLM
+// response again (1, the default, is the most recent). Nothing is sent to
+// the model.
+func (c *CLI) handleReplay(sess *models.Session, args []string) {
+	n := 1
+	if len(args) > 0 {
+		v, err := strconv.Atoi(args[0])
+		if err != nil || v < 1 {
+			c.write("Usage: /replay [N] (1 = most recent response)\n")
+			return
+		}
+		n = v
+	}
+
+	responses, err := c.pastResponses(sess)
+	if err != nil {
+		c.write(fmt.Sprintf("Could not read the audit log: %v\n", err))
+		return
+	}
+	if len(responses) == 0 {
+		c.write("No responses to replay yet.\n")
+		return
+	}
+	if n > len(responses) {
+		c.write(fmt.Sprintf("Only %d response(s) in this session; use /replay 1-%d.\n", len(responses), len(responses)))
+		return
+	}
+
+	c.write(fmt.Sprintf("\n%s%s── Replay %d of %d ──%s\n", Margin, escDim, n, len(responses), escReset))
+	c.writeInScrollRegion(c.reflowOutput(responses[len(responses)-n] + "\n"))
+}
+
+// pastResponses returns the session's LLM responses, oldest first. A
+// response that was streamed but never recorded whole (cancelled, failed or
+// still running) is rebuilt from its chunks.
+func (c *CLI) pastResponses(sess *models.Session) ([]string, error) {
+	var responses []string
+	pending := ""
+	err := c.Sm.IterateAudit(sess, func(e events.AuditEntry) bool {
+		switch e.Type {
+		case events.AuditLLMChunk:
+			pending += e.Content
+		case events.AuditLLMResponse:
+			text := e.Content
+			if text == "" {
+				text = pending
+			}
+			if text != "" {
+				responses = append(responses, text)
+			}
+			pending = ""
+		case events.AuditLLMPrompt:
+			if pending != "" {
+				responses = append(responses, pending)
+				pending = ""
+			}
+		}
+		return true
+	})
+	if pending != "" {
+		responses = append(responses, pending)
+	}
+	return responses, err
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"tenazas/internal/events"
+	"tenazas/internal/formatter"
+	"tenazas/internal/models"
+)
+
+// maxSearchMatches bounds how many matches /search prints, newest kept.
+const maxSearchMatches = 50
+
+// searchTimeLayout stamps /search matches when no timestamp_layout is set.
+const searchTimeLayout = "2006-01-02 15:04:05"
+
+// handleSearch implements "/search <query>": every audit entry of the
+// session whose content contains query, ignoring case, with its time and
+// type. Streamed chunks are skipped since the full response holds the same
+// text.
+func (c *CLI) handleSearch(sess *models.Session, args []string) {
+	query := strings.ToLower(strings.Join(args, " "))
+	if query == "" {
+		c.write("Usage: /search <query>\n")
+		return
+	}
+	matches, err := c.Sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return e.Type != events.AuditLLMChunk && strings.Contains(strings.ToLower(e.Content), query)
+	})
+	if err != nil {
+		c.write(fmt.Sprintf("Could not read the audit log: %v\n", err))
+		return
+	}
+	if len(matches) == 0 {
+		c.write(fmt.Sprintf("No audit entries match %q.\n", strings.Join(args, " ")))
+		return
+	}
+
+	var output strings.Builder
+	if len(matches) > maxSearchMatches {
+		fmt.Fprintf(&output, "%d matches, showing the last %d.\n", len(matches), maxSearchMatches)
+		matches = matches[len(matches)-maxSearchMatches:]
+	}
+	f := &formatter.AnsiFormatter{}
+	for _, e := range matches {
+		fmt.Fprintf(&output, "%s[%s] %s%s\n", escDim, c.TimeFormat.Format(e.Timestamp, searchTimeLayout), e.Type, escReset)
+		if e.Type == events.AuditLLMPrompt {
+			// The formatter shows prompts as "Thinking...", hiding the match.
+			fmt.Fprintf(&output, "%s› %s%s\n", escBoldCyan, e.Content, escReset)
+			continue
+		}
+		fmt.Fprintln(&output, f.Format(e))
+	}
+	c.write(output.String())
+}
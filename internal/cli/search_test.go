@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+)
+
+func TestSearchFindsMatchingEntries(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+	at := time.Date(2026, 3, 4, 10, 20, 30, 0, time.UTC)
+	cli.TimeFormat.UTC = true
+	for _, e := range []events.AuditEntry{
+		{Type: events.AuditLLMPrompt, Content: "why does the build fail?"},
+		{Type: events.AuditLLMChunk, Content: "undefined: Foo"},
+		{Type: events.AuditLLMResponse, Content: "It fails with undefined: Foo"},
+		{Type: events.AuditCmdResult, Content: "Exit Code: 0\nok", Timestamp: at},
+		{Type: events.AuditCmdResult, Content: "Exit Code: 2\nUNDEFINED: foo", ExitCode: 2, Timestamp: at},
+	} {
+		if e.Timestamp.IsZero() {
+			e.Timestamp = at
+		}
+		cli.Sm.AppendAudit(sess, e)
+	}
+
+	cli.handleCommand(sess, "/search undefined: foo")
+	out := cli.output()
+	if strings.Count(out, "[2026-03-04 10:20:30]") != 2 {
+		t.Errorf("expected two timestamped matches (chunks skipped, case ignored), got:\n%s", out)
+	}
+	for _, want := range []string{"] llm_response", "It fails with undefined: Foo", "] cmd_result", "UNDEFINED: foo"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Exit Code: 0") {
+		t.Errorf("non-matching entry printed:\n%s", out)
+	}
+
+	cli.Out.(*bytes.Buffer).Reset()
+	cli.handleCommand(sess, "/search build fail")
+	if out := cli.output(); !strings.Contains(out, "› why does the build fail?") {
+		t.Errorf("a matching prompt should be shown with its text, got:\n%s", out)
+	}
+}
+
+func TestSearchNoMatchesAndLimit(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+
+	cli.handleCommand(sess, "/search")
+	if out := cli.output(); !strings.Contains(out, "Usage: /search <query>") {
+		t.Errorf("expected usage, got %q", out)
+	}
+
+	cli.Out.(*bytes.Buffer).Reset()
+	cli.handleCommand(sess, "/search missing")
+	if out := cli.output(); !strings.Contains(out, `No audit entries match "missing"`) {
+		t.Errorf("expected a no-match notice, got %q", out)
+	}
+
+	for i := 0; i < maxSearchMatches+5; i++ {
+		cli.Sm.AppendAudit(sess, events.AuditEntry{Type: events.AuditInfo, Content: fmt.Sprintf("hit %d", i)})
+	}
+	cli.Out.(*bytes.Buffer).Reset()
+	cli.handleCommand(sess, "/search hit")
+	out := cli.output()
+	if !strings.Contains(out, fmt.Sprintf("%d matches, showing the last %d", maxSearchMatches+5, maxSearchMatches)) ||
+		strings.Contains(out, "hit 4\n") || !strings.Contains(out, fmt.Sprintf("hit %d", maxSearchMatches+4)) {
+		t.Errorf("expected only the newest %d matches, got:\n%s", maxSearchMatches, out)
+	}
+}
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tenazas/internal/events"
+	"tenazas/internal/formatter"
+	"tenazas/internal/models"
+)
+
+const sessionsPageSize = 15
+
+// handleSessions implements "/sessions [page|query]": a table of active
+// sessions, or of those matching query (title, summary, skill or metadata).
+func (c *CLI) handleSessions(args []string) {
+	page := 1
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			c.searchSessions(strings.Join(args, " "))
+			return
+		}
+		if n > 0 {
+			page = n
+		}
+	}
+	sessions, total, err := c.Sm.ListActive(page-1, sessionsPageSize)
+	if err != nil {
+		c.writef("Error listing sessions: %v\n", err)
+		return
+	}
+	if total == 0 {
+		c.write("No sessions found.\n")
+		return
+	}
+	totalPages := (total + sessionsPageSize - 1) / sessionsPageSize
+	if len(sessions) == 0 {
+		c.writef("No sessions on page %d (%d pages).\n", page, totalPages)
+		return
+	}
+
+	var sb strings.Builder
+	c.renderSessionRows(&sb, sessions)
+	if totalPages > 1 {
+		fmt.Fprintf(&sb, "Page %d/%d · /sessions <page>\n", page, totalPages)
+	}
+	sb.WriteString("Use /switch <id> to focus a session.\n")
+	c.write(sb.String())
+}
+
+// searchSessions lists the active sessions matching query.
+func (c *CLI) searchSessions(query string) {
+	matches, err := c.Sm.Search(query)
+	if err != nil {
+		c.writef("Error searching sessions: %v\n", err)
+		return
+	}
+	if len(matches) == 0 {
+		c.writef("No sessions match %q.\n", query)
+		return
+	}
+	var sb strings.Builder
+	c.renderSessionRows(&sb, matches)
+	fmt.Fprintf(&sb, "%d session(s) match %q. Use /switch <id> to focus one.\n", len(matches), query)
+	c.write(sb.String())
+}
+
+func (c *CLI) renderSessionRows(sb *strings.Builder, sessions []models.Session) {
+	current := ""
+	if cur := c.currentSession(); cur != nil {
+		current = cur.ID
+	}
+	fmt.Fprintf(sb, "  %-10s %-12s %-10s %s\n", "ID", "Status", "Updated", "Title")
+	ids := sessionIDs(sessions)
+	for _, s := range sessions {
+		marker := " "
+		if s.ID == current {
+			marker = "*"
+		}
+		status := s.Status
+		if status == "" {
+			status = models.StatusIdle
+		}
+		title := s.Title
+		if title == "" {
+			title = s.Summary
+		}
+		if title == "" {
+			title = "(untitled)"
+		}
+		if len(title) > 48 {
+			title = title[:45] + "..."
+		}
+		fmt.Fprintf(sb, "%s %-10s %-12s %-10s %s\n", marker, formatter.ShortID(s.ID, ids, c.ShortIDLength), status, timeAgo(s.LastUpdated), title)
+	}
+}
+
+// handleSwitch implements "/switch <id>", accepting a full ID or a unique prefix.
+func (c *CLI) handleSwitch(args []string) {
+	if len(args) == 0 {
+		c.write("Usage: /switch <session-id>\n")
+		return
+	}
+	target, err := c.findSession(args[0])
+	if err != nil {
+		c.writef("Error: %v\n", err)
+		return
+	}
+	if cur := c.currentSession(); cur != nil && cur.ID == target.ID {
+		c.writef("Already on session %s.\n", c.shortSessionID(target.ID))
+		return
+	}
+	c.switchSession(target)
+}
+
+// findSession resolves a full session ID, or an ID prefix matching exactly
+// one active session.
+func (c *CLI) findSession(id string) (*models.Session, error) {
+	if s, err := c.Sm.Load(id); err == nil {
+		return s, nil
+	}
+	var matches []models.Session
+	for page := 0; ; page++ {
+		sessions, total, err := c.Sm.ListActive(page, 50)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range sessions {
+			if strings.HasPrefix(s.ID, id) {
+				matches = append(matches, s)
+			}
+		}
+		if len(sessions) == 0 || (page+1)*50 >= total {
+			break
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("session %s not found", id)
+	case 1:
+		return c.Sm.Load(matches[0].ID)
+	default:
+		return nil, fmt.Errorf("session prefix %s is ambiguous (%d matches)", id, len(matches))
+	}
+}
+
+// switchSession focuses sess without restarting: it rebinds the registry
+// instance, re-attaches the event listener and transcript, and redraws.
+func (c *CLI) switchSession(sess *models.Session) {
+	c.stopFollow()
+	c.mu.Lock()
+	c.sess = sess
+	c.outCol, c.outIndent = 0, 0
+	c.isStreaming = false
+	c.currentTask = ""
+	c.lastThought = ""
+	c.mu.Unlock()
+
+	if c.Reg != nil && c.instanceID != "" {
+		c.Reg.Set(c.instanceID, sess.ID)
+	}
+	if c.TranscriptEnabled {
+		c.closeTranscript()
+		if closeFn, err := c.openTranscript(sess); err == nil {
+			c.transcriptClose = closeFn
+		}
+	}
+	c.attachEvents(sess.ID)
+	c.refreshGitBranch()
+
+	c.mu.Lock()
+	c.redrawScreenLocked()
+	c.mu.Unlock()
+	c.writef("Switched to session %s.\n", c.shortSessionID(sess.ID))
+}
+
+// attachEvents starts listening for sessionID's events, detaching the
+// previous listener if any.
+func (c *CLI) attachEvents(sessionID string) {
+	ch := events.GlobalBus.Subscribe()
+	c.mu.Lock()
+	old := c.eventCh
+	c.eventCh = ch
+	c.mu.Unlock()
+	if old != nil {
+		events.GlobalBus.Unsubscribe(old)
+	}
+	go c.listenOn(ch, sessionID)
+}
+
+// closeTranscript closes the current transcript, if one is open.
+func (c *CLI) closeTranscript() {
+	if c.transcriptClose != nil {
+		c.transcriptClose()
+		c.transcriptClose = nil
+	}
+}
+
+// currentSession returns the focused session.
+func (c *CLI) currentSession() *models.Session {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sess
+}
+
+func (c *CLI) shortSessionID(id string) string {
+	return formatter.ShortID(id, nil, c.ShortIDLength)
+}
+
+func sessionIDs(sessions []models.Session) []string {
+	ids := make([]string, len(sessions))
+	for i, s := range sessions {
+		ids[i] = s.ID
+	}
+	return ids
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/models"
+	"tenazas/internal/registry"
+	"tenazas/internal/session"
+)
+
+func setupSessionsTest(t *testing.T) (*CLI, *models.Session, *models.Session) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	sm := session.NewManager(tmpDir)
+	reg, _ := registry.NewRegistry(tmpDir)
+
+	now := time.Now()
+	first := &models.Session{ID: "aaaaaaaa-1111", CWD: tmpDir, Title: "First session", Status: models.StatusIdle, LastUpdated: now, RoleCache: map[string]string{}}
+	second := &models.Session{ID: "bbbbbbbb-2222", CWD: tmpDir, Title: "Second session", Status: models.StatusCompleted, LastUpdated: now, RoleCache: map[string]string{}}
+	sm.Save(first)
+	sm.Save(second)
+
+	c := &CLI{Sm: sm, Reg: reg, instanceID: "cli-test"}
+	c.Out = &bytes.Buffer{}
+	c.sess = first
+	reg.Set(c.instanceID, first.ID)
+	return c, first, second
+}
+
+func TestHandleSessionsListsActiveSessions(t *testing.T) {
+	c, _, _ := setupSessionsTest(t)
+
+	c.handleSessions(nil)
+
+	c.mu.Lock()
+	out := c.Out.(*bytes.Buffer).String()
+	c.mu.Unlock()
+	for _, want := range []string{"ID", "Status", "Updated", "Title", "aaaaaaaa", "First session", "bbbbbbbb", "Second session", models.StatusCompleted} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in listing:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(out, "* aaaaaaaa") {
+		t.Errorf("expected the focused session to be marked:\n%s", out)
+	}
+}
+
+func TestHandleSwitchRebindsSession(t *testing.T) {
+	c, _, second := setupSessionsTest(t)
+
+	c.handleCommand(c.sess, "/switch bbbb")
+
+	if cur := c.currentSession(); cur == nil || cur.ID != second.ID {
+		t.Fatalf("expected CLI to focus %s, got %+v", second.ID, cur)
+	}
+	if st, _ := c.Reg.Get(c.instanceID); st.SessionID != second.ID {
+		t.Errorf("expected registry instance bound to %s, got %s", second.ID, st.SessionID)
+	}
+	c.mu.Lock()
+	ch := c.eventCh
+	c.mu.Unlock()
+	if ch == nil {
+		t.Error("expected an event listener to be attached")
+	}
+}
+
+func TestHandleSwitchUnknownSession(t *testing.T) {
+	c, first, _ := setupSessionsTest(t)
+
+	c.handleSwitch([]string{"zzzz"})
+
+	if c.currentSession().ID != first.ID {
+		t.Error("expected focus to stay on the original session")
+	}
+	c.mu.Lock()
+	out := c.Out.(*bytes.Buffer).String()
+	c.mu.Unlock()
+	if !strings.Contains(out, "not found") {
+		t.Errorf("expected not-found error, got: %s", out)
+	}
+}
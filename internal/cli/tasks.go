@@ -54,6 +54,26 @@ func (c *CLI) saveTask(t *task.Task) bool {
 	return true
 }
 
+// applySkillDefaults merges the session skill's default labels and skill
+// binding into t, for tasks created or claimed while that skill is active.
+func (c *CLI) applySkillDefaults(sess *models.Session, t *task.Task) {
+	if sess == nil || sess.SkillName == "" || c.Sm == nil {
+		return
+	}
+	if sk, err := c.Sm.LoadSkillFor(sess.CWD, sess.SkillName); err == nil {
+		t.ApplySkillDefaults(sk.DefaultLabels, sk.DefaultSkill)
+	}
+}
+
+// writeAlreadyInProgress reports the task blocking a new claim, pointing at
+// "work doctor" when its owner process has died.
+func (c *CLI) writeAlreadyInProgress(active *task.Task) {
+	c.writef("Already in progress: %s — %s\n", active.ID, active.Title)
+	if len(task.OrphanedTasks([]*task.Task{active})) > 0 {
+		c.writef("Its owner (PID %d) is no longer running; reset it with: tenazas work doctor --fix\n", active.OwnerPID)
+	}
+}
+
 func findInProgress(tasks []*task.Task) *task.Task {
 	for _, t := range tasks {
 		if t.Status == task.StatusInProgress {
@@ -74,14 +94,14 @@ func (c *CLI) handleTasks() {
 		return
 	}
 	var buf bytes.Buffer
-	task.RenderList(&buf, tasks)
+	task.RenderList(&buf, tasks, c.Tasks.Priorities)
 	c.write(buf.String())
 }
 
 // handleTask dispatches "/task <subcommand> [args...]".
 func (c *CLI) handleTask(sess *models.Session, args []string) {
 	if len(args) == 0 {
-		c.write("Usage: /task <show|next|complete|add|unblock> [args...]\n")
+		c.write("Usage: /task <show|next|pick|complete|add|unblock> [args...]\n")
 		return
 	}
 	tasksDir, ok := c.resolveTasksDir()
@@ -93,10 +113,12 @@ func (c *CLI) handleTask(sess *models.Session, args []string) {
 		c.handleTaskShow(tasksDir, args[1:])
 	case "next":
 		c.handleTaskNext(tasksDir, sess)
+	case "pick":
+		c.handleTaskPick(tasksDir, sess, args[1:])
 	case "complete":
 		c.handleTaskComplete(tasksDir)
 	case "add":
-		c.handleTaskAdd(tasksDir, args[1:])
+		c.handleTaskAdd(tasksDir, sess, args[1:])
 	case "unblock":
 		c.handleTaskUnblock(tasksDir, args[1:])
 	default:
@@ -104,13 +126,25 @@ func (c *CLI) handleTask(sess *models.Session, args []string) {
 	}
 }
 
-// handleTaskShow implements "/task show <id>".
+// handleTaskShow implements "/task show <id> [--log] [--deps]".
 func (c *CLI) handleTaskShow(tasksDir string, args []string) {
-	if len(args) < 1 {
-		c.write("Usage: /task show <id>\n")
+	withLog, withDeps := false, false
+	var rest []string
+	for _, a := range args {
+		switch a {
+		case "--log":
+			withLog = true
+		case "--deps":
+			withDeps = true
+		default:
+			rest = append(rest, a)
+		}
+	}
+	if len(rest) < 1 {
+		c.write("Usage: /task show <id> [--log] [--deps]\n")
 		return
 	}
-	id := task.NormalizeTaskID(args[0])
+	id := task.NormalizeTaskIDIn(tasksDir, rest[0])
 	allTasks, ok := c.loadTasks(tasksDir)
 	if !ok {
 		return
@@ -125,7 +159,17 @@ func (c *CLI) handleTaskShow(tasksDir string, args []string) {
 		return
 	}
 	var buf bytes.Buffer
-	task.RenderShow(&buf, target, taskMap)
+	task.RenderShow(&buf, target, taskMap, c.Tasks.Priorities)
+	if withDeps {
+		task.RenderDepTree(&buf, target, taskMap, task.DepTreeDepth)
+	}
+	if withLog {
+		entries, err := task.ReadLog(tasksDir, target.ID, task.DefaultLogTail)
+		if err != nil {
+			fmt.Fprintf(&buf, "Warning: could not read task log: %v\n", err)
+		}
+		task.RenderLog(&buf, entries)
+	}
 	c.write(buf.String())
 }
 
@@ -136,7 +180,7 @@ func (c *CLI) handleTaskNext(tasksDir string, sess *models.Session) {
 		return
 	}
 	if active := findInProgress(tasks); active != nil {
-		c.writef("Already in progress: %s — %s\n", active.ID, active.Title)
+		c.writeAlreadyInProgress(active)
 		return
 	}
 	next := task.SelectNextTask(tasks)
@@ -144,17 +188,30 @@ func (c *CLI) handleTaskNext(tasksDir string, sess *models.Session) {
 		c.write("No tasks ready to start.\n")
 		return
 	}
+	if c.claimTask(sess, next) {
+		if skill := c.Tasks.LabelSkills.SkillFor(next); skill != "" {
+			c.writef("Skill for this task: %s (/run %s)\n", skill, skill)
+		}
+	}
+}
+
+// claimTask marks t in progress and owned by sess, reporting whether it was
+// saved.
+func (c *CLI) claimTask(sess *models.Session, t *task.Task) bool {
 	now := truncatedNow()
-	next.Status = task.StatusInProgress
-	next.OwnerPID = os.Getpid()
-	next.OwnerSessionID = sess.ID
-	if next.StartedAt == nil {
-		next.StartedAt = &now
+	t.SetStatus(task.StatusInProgress, now)
+	t.OwnerPID = os.Getpid()
+	t.OwnerSessionID = sess.ID
+	if t.StartedAt == nil {
+		t.StartedAt = &now
 	}
-	next.UpdatedAt = now
-	if c.saveTask(next) {
-		c.writef("Started: %s — %s\n", next.ID, next.Title)
+	c.applySkillDefaults(sess, t)
+	t.UpdatedAt = now
+	if !c.saveTask(t) {
+		return false
 	}
+	c.writef("Started: %s — %s\n", t.ID, t.Title)
+	return true
 }
 
 // handleTaskComplete implements "/task complete".
@@ -169,7 +226,7 @@ func (c *CLI) handleTaskComplete(tasksDir string) {
 		return
 	}
 	now := truncatedNow()
-	active.Status = task.StatusDone
+	active.SetStatus(task.StatusDone, now)
 	active.CompletedAt = &now
 	active.ClearOwnership()
 	active.UpdatedAt = now
@@ -178,10 +235,30 @@ func (c *CLI) handleTaskComplete(tasksDir string) {
 	}
 }
 
-// handleTaskAdd implements "/task add <title> <description>".
-func (c *CLI) handleTaskAdd(tasksDir string, args []string) {
+// handleTaskAdd implements "/task add [--priority p] [--labels a,b] <title> <description>".
+func (c *CLI) handleTaskAdd(tasksDir string, sess *models.Session, args []string) {
+	priority, args, err := task.ExtractPriorityFlag(args, c.Tasks.Priorities)
+	if err != nil {
+		c.writef("Error: %v\n", err)
+		return
+	}
+	var labels []string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--labels" && i+1 < len(args) {
+			var warnings []string
+			labels, warnings = task.NormalizeLabels(strings.Split(args[i+1], ","))
+			for _, w := range warnings {
+				c.writef("Warning: %s\n", w)
+			}
+			i++
+			continue
+		}
+		rest = append(rest, args[i])
+	}
+	args = rest
 	if len(args) < 2 {
-		c.write("Usage: /task add \"title\" \"description\"\n")
+		c.write("Usage: /task add [--priority p] [--labels a,b] \"title\" \"description\"\n")
 		return
 	}
 	id, err := task.GetNextTaskID(tasksDir)
@@ -194,11 +271,14 @@ func (c *CLI) handleTaskAdd(tasksDir string, args []string) {
 		ID:        id,
 		Title:     args[0],
 		Status:    task.StatusTodo,
+		Priority:  priority,
+		Labels:    labels,
 		CreatedAt: now,
 		UpdatedAt: now,
 		Content:   strings.Join(args[1:], " "),
 		FilePath:  filepath.Join(tasksDir, id+".md"),
 	}
+	c.applySkillDefaults(sess, t)
 	if c.saveTask(t) {
 		c.writef("Created: %s — %s\n", id, args[0])
 	}
@@ -210,7 +290,7 @@ func (c *CLI) handleTaskUnblock(tasksDir string, args []string) {
 		c.write("Usage: /task unblock <id>\n")
 		return
 	}
-	id := task.NormalizeTaskID(args[0])
+	id := task.NormalizeTaskIDIn(tasksDir, args[0])
 	t, err := task.FindTask(tasksDir, id)
 	if err != nil {
 		c.writef("Error: %v\n", err)
@@ -306,9 +306,9 @@ func TestHandleTaskCompleteEmpty(t *testing.T) {
 // --- /task add ---
 
 func TestHandleTaskAdd(t *testing.T) {
-	cli, _, tasksDir := setupTaskTest(t)
+	cli, sess, tasksDir := setupTaskTest(t)
 
-	cli.handleTaskAdd(tasksDir, []string{"Fix-login-bug", "The", "login", "page", "returns", "500"})
+	cli.handleTaskAdd(tasksDir, sess, []string{"Fix-login-bug", "The", "login", "page", "returns", "500"})
 
 	output := cli.Out.(*bytes.Buffer).String()
 	if !strings.Contains(output, "Created") {
@@ -335,10 +335,61 @@ func TestHandleTaskAdd(t *testing.T) {
 	}
 }
 
+func TestHandleTaskAddAppliesSkillDefaultLabels(t *testing.T) {
+	cli, sess, tasksDir := setupTaskTest(t)
+
+	skillDir := filepath.Join(cli.Sm.StoragePath, "skills", "bugfix")
+	os.MkdirAll(skillDir, 0755)
+	os.WriteFile(filepath.Join(skillDir, "skill.json"),
+		[]byte(`{"skill_name":"bugfix","default_labels":["bug","triage"],"default_skill":"bugfix"}`), 0644)
+	sess.SkillName = "bugfix"
+
+	cli.handleTaskAdd(tasksDir, sess, []string{"--labels", "Bug,ui", "Crash", "App crashes on start"})
+
+	tasks, err := task.ListTasks(tasksDir)
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d (%v)", len(tasks), err)
+	}
+	got := strings.Join(tasks[0].Labels, ",")
+	if got != "bug,ui,triage" {
+		t.Errorf("expected explicit labels first and no duplicates, got %q", got)
+	}
+	if tasks[0].Skill != "bugfix" {
+		t.Errorf("expected default skill binding, got %q", tasks[0].Skill)
+	}
+}
+
+func TestHandleTaskAddUsesPriorityLabels(t *testing.T) {
+	cli, sess, tasksDir := setupTaskTest(t)
+	cli.Tasks.Priorities = task.NewPriorityLevels(map[string]int{"P2": 0, "P1": 5})
+
+	cli.handleTaskAdd(tasksDir, sess, []string{"--priority", "P1", "Crash", "App crashes on start"})
+
+	tasks, err := task.ListTasks(tasksDir)
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d (%v)", len(tasks), err)
+	}
+	if tasks[0].Priority != 5 {
+		t.Errorf("expected --priority P1 to map to 5, got %d", tasks[0].Priority)
+	}
+
+	cli.Out.(*bytes.Buffer).Reset()
+	cli.handleTaskShow(tasksDir, []string{tasks[0].ID})
+	if output := cli.Out.(*bytes.Buffer).String(); !strings.Contains(output, "5 (p1)") {
+		t.Errorf("expected /task show to use the configured label, got: %s", output)
+	}
+
+	cli.Out.(*bytes.Buffer).Reset()
+	cli.handleTaskAdd(tasksDir, sess, []string{"--priority", "high", "Other", "desc"})
+	if output := cli.Out.(*bytes.Buffer).String(); !strings.Contains(output, "p2, p1") {
+		t.Errorf("expected an error listing the configured labels, got: %s", output)
+	}
+}
+
 func TestHandleTaskAddNoArgs(t *testing.T) {
-	cli, _, tasksDir := setupTaskTest(t)
+	cli, sess, tasksDir := setupTaskTest(t)
 
-	cli.handleTaskAdd(tasksDir, []string{})
+	cli.handleTaskAdd(tasksDir, sess, []string{})
 
 	output := cli.Out.(*bytes.Buffer).String()
 	if !strings.Contains(output, "Usage") {
@@ -347,9 +398,9 @@ func TestHandleTaskAddNoArgs(t *testing.T) {
 }
 
 func TestHandleTaskAddSingleArg(t *testing.T) {
-	cli, _, tasksDir := setupTaskTest(t)
+	cli, sess, tasksDir := setupTaskTest(t)
 
-	cli.handleTaskAdd(tasksDir, []string{"OnlyTitle"})
+	cli.handleTaskAdd(tasksDir, sess, []string{"OnlyTitle"})
 
 	output := cli.Out.(*bytes.Buffer).String()
 	if !strings.Contains(output, "Usage") {
@@ -549,7 +600,7 @@ func TestGetCompletionsTaskSubAll(t *testing.T) {
 
 	completions := cli.getCompletions("/task ")
 
-	expected := []string{"show", "next", "complete", "add", "unblock"}
+	expected := []string{"show", "next", "pick", "complete", "add", "unblock"}
 	for _, sub := range expected {
 		found := false
 		for _, c := range completions {
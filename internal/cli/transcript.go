@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"tenazas/internal/formatter"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+// TranscriptFileName is the per-session artifact holding the plain-text transcript.
+const TranscriptFileName = "transcript.txt"
+
+// OpenTranscriptFile opens the session's transcript file for appending.
+func OpenTranscriptFile(sm *session.Manager, sess *models.Session) (*os.File, error) {
+	path := sm.ArtifactPath(sess, TranscriptFileName)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+}
+
+// openTranscript starts mirroring session output to the session's transcript
+// file. It returns a function that closes the file.
+func (c *CLI) openTranscript(sess *models.Session) (func(), error) {
+	f, err := OpenTranscriptFile(c.Sm, sess)
+	if err != nil {
+		return func() {}, err
+	}
+	c.mu.Lock()
+	c.transcript = &formatter.PlainWriter{W: f}
+	c.mu.Unlock()
+	return func() {
+		c.mu.Lock()
+		c.transcript = nil
+		c.mu.Unlock()
+		f.Close()
+	}, nil
+}
+
+// writeTranscript appends rendered session output to the transcript, if enabled.
+func (c *CLI) writeTranscript(s string) {
+	c.mu.Lock()
+	w := c.transcript
+	c.mu.Unlock()
+	if w != nil {
+		io.WriteString(w, s)
+	}
+}
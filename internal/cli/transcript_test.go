@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestTranscriptMirrorsPlainOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	sm := session.NewManager(tmpDir)
+	sess := &models.Session{ID: "transcript-sess", CWD: tmpDir, RoleCache: map[string]string{}}
+	sm.Save(sess)
+
+	var out bytes.Buffer
+	c := &CLI{Sm: sm, Out: &out, sess: sess}
+	closeTranscript, err := c.openTranscript(sess)
+	if err != nil {
+		t.Fatalf("openTranscript failed: %v", err)
+	}
+
+	go c.listenEvents(sess.ID)
+	time.Sleep(20 * time.Millisecond)
+
+	sm.AppendAudit(sess, events.AuditEntry{Type: events.AuditCmdResult, Content: "go test ./...", ExitCode: 1})
+	sm.AppendAudit(sess, events.AuditEntry{Type: events.AuditLLMChunk, Content: "All green now."})
+
+	deadline := time.Now().Add(2 * time.Second)
+	var transcript string
+	for time.Now().Before(deadline) {
+		data, _ := os.ReadFile(sm.ArtifactPath(sess, TranscriptFileName))
+		transcript = string(data)
+		if strings.Contains(transcript, "All green now.") {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	closeTranscript()
+
+	if !strings.Contains(transcript, "● Command Result") || !strings.Contains(transcript, "go test ./...") {
+		t.Errorf("expected formatted command result in transcript, got %q", transcript)
+	}
+	if !strings.Contains(transcript, "All green now.") {
+		t.Errorf("expected streamed text in transcript, got %q", transcript)
+	}
+	if strings.Contains(transcript, "\x1b") {
+		t.Errorf("expected no escape codes in transcript, got %q", transcript)
+	}
+	time.Sleep(20 * time.Millisecond)
+	c.mu.Lock()
+	terminal := out.String()
+	c.mu.Unlock()
+	if !strings.Contains(terminal, "\x1b[") {
+		t.Error("expected terminal output to keep its colors")
+	}
+
+	// The audit log stays structured JSONL.
+	f, err := os.Open(sm.AuditPath(sess))
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	lines := 0
+	for scanner.Scan() {
+		var entry events.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Errorf("audit line is not JSON: %q", scanner.Text())
+		}
+		lines++
+	}
+	if lines != 2 {
+		t.Errorf("expected 2 audit lines, got %d", lines)
+	}
+}
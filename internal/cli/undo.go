@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+
+	"tenazas/internal/models"
+)
+
+// maxUndoDepth caps how many setting changes /undo can walk back.
+const maxUndoDepth = 20
+
+// sessionSettings is a snapshot of the session fields undoable commands
+// change, taken before the command ran.
+type sessionSettings struct {
+	sessionID    string
+	command      string
+	approvalMode string
+	yolo         bool
+	modelTier    string
+	maxBudgetUSD float64
+	systemPrompt string
+	wrap         bool
+	promptMode   string
+	metadata     map[string]string
+}
+
+func snapshotSettings(sess *models.Session, command string) sessionSettings {
+	s := sessionSettings{
+		sessionID:    sess.ID,
+		command:      command,
+		approvalMode: sess.ApprovalMode,
+		yolo:         sess.Yolo,
+		modelTier:    sess.ModelTier,
+		maxBudgetUSD: sess.MaxBudgetUSD,
+		systemPrompt: sess.SystemPrompt,
+		wrap:         sess.Wrap,
+		promptMode:   sess.PromptMode,
+	}
+	if sess.Metadata != nil {
+		s.metadata = make(map[string]string, len(sess.Metadata))
+		for k, v := range sess.Metadata {
+			s.metadata[k] = v
+		}
+	}
+	return s
+}
+
+func (s sessionSettings) sameAs(o sessionSettings) bool {
+	if s.approvalMode != o.approvalMode || s.yolo != o.yolo || s.modelTier != o.modelTier ||
+		s.maxBudgetUSD != o.maxBudgetUSD || s.systemPrompt != o.systemPrompt ||
+		s.wrap != o.wrap || s.promptMode != o.promptMode || len(s.metadata) != len(o.metadata) {
+		return false
+	}
+	for k, v := range s.metadata {
+		if ov, ok := o.metadata[k]; !ok || ov != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (s sessionSettings) restore(sess *models.Session) {
+	sess.ApprovalMode = s.approvalMode
+	sess.Yolo = s.yolo
+	sess.ModelTier = s.modelTier
+	sess.MaxBudgetUSD = s.maxBudgetUSD
+	sess.SystemPrompt = s.systemPrompt
+	sess.Wrap = s.wrap
+	sess.PromptMode = s.promptMode
+	sess.Metadata = s.metadata
+}
+
+// runUndoable runs an undoable command and, if it changed the session's
+// settings, remembers the previous ones for /undo.
+func (c *CLI) runUndoable(cmd *command, sess *models.Session, args []string) {
+	before := snapshotSettings(c.snapshot(sess), cmd.name)
+	cmd.run(c, sess, args)
+	if before.sameAs(snapshotSettings(c.snapshot(sess), cmd.name)) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.undoStack = append(c.undoStack, before)
+	if len(c.undoStack) > maxUndoDepth {
+		c.undoStack = c.undoStack[len(c.undoStack)-maxUndoDepth:]
+	}
+}
+
+// handleUndo implements "/undo": restore the session settings from before
+// the latest undoable command run on this session.
+func (c *CLI) handleUndo(sess *models.Session) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := len(c.undoStack) - 1; i >= 0; i-- {
+		prev := c.undoStack[i]
+		if prev.sessionID != sess.ID {
+			continue
+		}
+		c.undoStack = append(c.undoStack[:i], c.undoStack[i+1:]...)
+		modeChanged := prev.approvalMode != sess.ApprovalMode
+		c.updateSession(sess, prev.restore)
+		if modeChanged && prev.approvalMode != "" && c.Sm != nil {
+			c.Sm.RememberApprovalMode(sess.CWD, prev.approvalMode)
+		}
+		c.writeLocked(fmt.Sprintf("Undid %s.\n", prev.command))
+		c.drawFooterLocked(sess)
+		return
+	}
+	c.writeLocked("Nothing to undo.\n")
+}
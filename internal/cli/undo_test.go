@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"tenazas/internal/models"
+)
+
+func TestUndoRestoresPreviousMode(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+	cli.handleCommand(sess, "/mode auto_edit")
+	cli.handleCommand(sess, "/mode yolo")
+	if !sess.Yolo {
+		t.Fatal("expected /mode yolo to take effect")
+	}
+
+	cli.handleCommand(sess, "/undo")
+	loaded, _ := cli.Sm.Load(sess.ID)
+	if loaded.ApprovalMode != models.ApprovalModeAutoEdit || loaded.Yolo {
+		t.Errorf("after /undo mode = %s (yolo %v), want auto_edit", loaded.ApprovalMode, loaded.Yolo)
+	}
+	if out := cli.output(); !strings.Contains(out, "Undid /mode") {
+		t.Errorf("expected an undo notice, got %q", out)
+	}
+
+	cli.handleCommand(sess, "/undo")
+	if loaded, _ = cli.Sm.Load(sess.ID); loaded.ApprovalMode != "" {
+		t.Errorf("second /undo should restore the original mode, got %q", loaded.ApprovalMode)
+	}
+}
+
+func TestUndoSkipsCommandsThatChangedNothing(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+	cli.handleCommand(sess, "/tier low")
+	cli.handleCommand(sess, "/budget")
+	cli.handleCommand(sess, "/tier bogus")
+	cli.handleCommand(sess, "/meta set ticket T-1")
+
+	cli.handleCommand(sess, "/undo")
+	if _, ok := sess.Metadata["ticket"]; ok {
+		t.Error("/undo should drop the metadata just set")
+	}
+	cli.handleCommand(sess, "/undo")
+	if sess.ModelTier != "" {
+		t.Errorf("/undo should revert /tier low next, tier = %q", sess.ModelTier)
+	}
+}
+
+func TestUndoWithNothingToUndo(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+
+	cli.handleCommand(sess, "/undo")
+	if out := cli.output(); !strings.Contains(out, "Nothing to undo") {
+		t.Errorf("expected a nothing-to-undo notice, got %q", out)
+	}
+}
+
+func TestUndoStackIsCapped(t *testing.T) {
+	cli, sess, _ := setupTaskTest(t)
+	for i := 0; i < maxUndoDepth+5; i++ {
+		cli.handleCommand(sess, "/budget "+strings.Repeat("1", i+1))
+	}
+	if len(cli.undoStack) != maxUndoDepth {
+		t.Errorf("undo stack holds %d entries, want %d", len(cli.undoStack), maxUndoDepth)
+	}
+}
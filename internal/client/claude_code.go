@@ -180,6 +180,9 @@ func (c *ClaudeCodeClient) buildArgs(opts RunOptions) []string {
 	if opts.MaxBudgetUSD > 0 {
 		args = append(args, "--max-budget-usd", fmt.Sprintf("%.2f", opts.MaxBudgetUSD))
 	}
+	if opts.SystemPrompt != "" {
+		args = append(args, "--append-system-prompt", opts.SystemPrompt)
+	}
 	return args
 }
 
@@ -190,6 +193,9 @@ func (c *ClaudeCodeClient) ResolveModel(tier string) string {
 	return c.models[tier]
 }
 
+// AvailableCommands returns nil: the CLI does not advertise agent commands.
+func (c *ClaudeCodeClient) AvailableCommands() []string { return nil }
+
 func (c *ClaudeCodeClient) logExecution(args []string, prompt string) {
 	logFile, _ := os.OpenFile(c.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if logFile == nil {
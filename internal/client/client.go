@@ -14,20 +14,40 @@ const (
 	ModelTierLow    = "low"
 )
 
+// Stop reasons a client may report for a finished turn (the ACP values).
+const (
+	StopEndTurn         = "end_turn"          // the model finished normally
+	StopMaxTokens       = "max_tokens"        // the response hit the output token limit
+	StopMaxTurnRequests = "max_turn_requests" // the agent hit its limit of model requests in one turn
+	StopRefusal         = "refusal"           // the model refused to continue
+	StopCancelled       = "cancelled"         // the client cancelled the turn
+)
+
 // RunOptions holds all parameters for a single client invocation.
 type RunOptions struct {
 	Ctx          context.Context // cancellation context; nil means no cancellation
 	NativeSID    string          // client-specific session ID for continuity
 	Prompt       string
 	CWD          string
-	ApprovalMode string  // Tenazas approval mode (PLAN, AUTO_EDIT, YOLO)
-	Yolo         bool    // shortcut: bypass all permissions
-	ModelTier    string  // "high", "medium", "low" — mapped per client
-	MaxBudgetUSD float64 // cost ceiling (0 = unlimited)
-	OnThought    func(string) // optional callback for chain-of-thought chunks (used by ACP clients)
-	OnToolEvent  func(name, status, detail string) // optional callback for tool execution events (used by ACP clients)
-	OnIntent     func(string) // optional callback for current task/intent updates (e.g. report_intent)
+	ApprovalMode string                                     // Tenazas approval mode (PLAN, AUTO_EDIT, YOLO)
+	Yolo         bool                                       // shortcut: bypass all permissions
+	ModelTier    string                                     // "high", "medium", "low" — mapped per client
+	MaxBudgetUSD float64                                    // cost ceiling (0 = unlimited)
+	SystemPrompt string                                     // session persona; sent as a system prompt where supported, else see InlinePrompt
+	OnThought    func(string)                               // optional callback for chain-of-thought chunks (used by ACP clients)
+	OnToolEvent  func(name, status, detail string)          // optional callback for tool execution events (used by ACP clients)
+	OnIntent     func(string)                               // optional callback for current task/intent updates (e.g. report_intent)
 	OnPermission func(PermissionRequest) PermissionResponse // optional callback for interactive permission prompts
+	OnStopReason func(string)                               // optional callback for why the turn ended (Stop* constants), for clients that report it
+}
+
+// InlinePrompt returns the prompt with the system prompt prepended, for
+// clients that have no separate system role.
+func (o RunOptions) InlinePrompt() string {
+	if o.SystemPrompt == "" {
+		return o.Prompt
+	}
+	return fmt.Sprintf("### SESSION INSTRUCTIONS:\n%s\n\n### PROMPT:\n%s", o.SystemPrompt, o.Prompt)
 }
 
 // PermissionOption describes one choice in a permission prompt.
@@ -66,6 +86,24 @@ type Client interface {
 
 	// ResolveModel returns the concrete model name for a given tier (e.g. "high" → "gemini-2.5-pro").
 	ResolveModel(tier string) string
+
+	// AvailableCommands returns the names of the commands the agent
+	// advertises, which can be sent as "/name" prompts. Clients whose agents
+	// advertise none return nil.
+	AvailableCommands() []string
+}
+
+// Wire-log levels accepted by LevelLogger.SetLogLevel.
+const (
+	LogLevelOff    = "off"    // write nothing
+	LogLevelErrors = "errors" // failed responses and process exits only, prompt text redacted
+	LogLevelFull   = "full"   // every wire message and all stderr, for debugging
+)
+
+// LevelLogger is implemented by clients that keep a wire trace in the log
+// file and let its verbosity be configured.
+type LevelLogger interface {
+	SetLogLevel(level string)
 }
 
 // registry maps client names to constructor functions.
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 )
 
@@ -466,3 +467,52 @@ echo "{\"type\": \"result\", \"result\": \"model=$MODEL budget=$BUDGET\"}"
 		t.Fatalf("expected 'model=opus budget=10.00', got %q", full)
 	}
 }
+
+func TestClaudeCodeClient_Run_SystemPrompt(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "test.log")
+
+	scriptPath := filepath.Join(tmpDir, "fake_claude.sh")
+	script := `#!/bin/sh
+for arg in "$@"; do
+  if [ "$prev" = "--append-system-prompt" ]; then
+    echo "{\"type\": \"result\", \"result\": \"system:$arg\"}"
+    exit 0
+  fi
+  prev="$arg"
+done
+echo '{"type": "result", "result": "no-system"}'
+`
+	if err := os.WriteFile(scriptPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &ClaudeCodeClient{binPath: scriptPath, logPath: logPath}
+
+	full, err := c.Run(RunOptions{Prompt: "test", CWD: tmpDir, SystemPrompt: "be terse"},
+		func(string) {}, func(string) {},
+	)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if full != "system:be terse" {
+		t.Fatalf("expected 'system:be terse', got %q", full)
+	}
+}
+
+func TestGeminiClient_SystemPromptInlined(t *testing.T) {
+	g := &GeminiClient{}
+	args := g.buildArgs(RunOptions{Prompt: "fix the bug", SystemPrompt: "be terse"})
+	prompt := args[4]
+	if !strings.Contains(prompt, "be terse") || !strings.HasSuffix(prompt, "fix the bug") {
+		t.Errorf("prompt = %q, want the system prompt before the user prompt", prompt)
+	}
+	if strings.Index(prompt, "be terse") > strings.Index(prompt, "fix the bug") {
+		t.Errorf("system prompt should come first: %q", prompt)
+	}
+
+	args = g.buildArgs(RunOptions{Prompt: "fix the bug"})
+	if args[4] != "fix the bug" {
+		t.Errorf("prompt without a system prompt = %q, want it unchanged", args[4])
+	}
+}
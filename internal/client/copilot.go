@@ -2,7 +2,9 @@ package client
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -22,13 +24,13 @@ type CopilotClient struct {
 	logPath string
 	models  map[string]string // tier → model ID
 
-	mu      sync.Mutex // protects process lifecycle (ensureProcess)
-	writeMu sync.Mutex // protects stdin writes
-	proc    *exec.Cmd
-	stdin   io.WriteCloser
-	scanner *bufio.Scanner
-	logFile *os.File
-	nextID  atomic.Int64
+	mu       sync.Mutex // protects process lifecycle (ensureProcess)
+	writeMu  sync.Mutex // protects stdin writes
+	proc     *exec.Cmd
+	stdin    io.WriteCloser
+	reader   *bufio.Reader
+	logFile  *os.File
+	nextID   atomic.Int64
 	initDone bool
 
 	// callbacks holds per-request notification handlers, keyed by session ID.
@@ -37,8 +39,26 @@ type CopilotClient struct {
 	readerDone     chan struct{}
 	stderrBuf      stderrRing
 	loadedSessions sync.Map // sessionID → struct{}
+
+	// maxLineBytes caps a single ACP message; longer lines are logged and
+	// skipped. Zero means acpMaxLineBytes.
+	maxLineBytes int
+
+	// logLevel is one of the LogLevel* constants; empty means LogLevelErrors.
+	logLevel string
+
+	// commands holds the latest availableCommands the agent advertised.
+	commandsMu sync.Mutex
+	commands   []string
 }
 
+// acpMaxLineBytes is the default cap for one JSON-RPC line. It is far above
+// any sane message but stops a runaway line from exhausting memory.
+const acpMaxLineBytes = 64 * 1024 * 1024
+
+// errLineTooLong is returned by readLine after discarding an oversized line.
+var errLineTooLong = errors.New("acp line exceeds size limit")
+
 // acpCallbacks holds the streaming callbacks for an active prompt.
 type acpCallbacks struct {
 	onChunk      func(string)
@@ -73,6 +93,17 @@ func (c *CopilotClient) Name() string { return "copilot" }
 
 func (c *CopilotClient) SetModels(m map[string]string) { c.models = m }
 
+// SetLogLevel sets how much of the ACP wire traffic is written to the log.
+func (c *CopilotClient) SetLogLevel(level string) { c.logLevel = level }
+
+func (c *CopilotClient) level() string {
+	switch c.logLevel {
+	case LogLevelOff, LogLevelFull:
+		return c.logLevel
+	}
+	return LogLevelErrors
+}
+
 func (c *CopilotClient) Run(opts RunOptions, onChunk func(string), onSessionID func(string)) (string, error) {
 	if err := c.ensureProcess(opts); err != nil {
 		return "", fmt.Errorf("copilot acp: %w", err)
@@ -120,13 +151,19 @@ func (c *CopilotClient) Run(opts RunOptions, onChunk func(string), onSessionID f
 	// Send the prompt.
 	result, err := c.call("session/prompt", map[string]any{
 		"sessionId": sessionID,
-		"prompt":    []map[string]any{{"type": "text", "text": opts.Prompt}},
+		"prompt":    []map[string]any{{"type": "text", "text": opts.InlinePrompt()}},
 	})
 	if err != nil {
 		return fullResponse.String(), err
 	}
 
-	c.log("[ACP] prompt complete: %s\n", string(result))
+	c.trace("[ACP] prompt complete: %s\n", string(result))
+	var done struct {
+		StopReason string `json:"stopReason"`
+	}
+	if json.Unmarshal(result, &done) == nil && done.StopReason != "" && opts.OnStopReason != nil {
+		opts.OnStopReason(done.StopReason)
+	}
 	return fullResponse.String(), nil
 }
 
@@ -160,8 +197,11 @@ func (c *CopilotClient) ensureProcess(opts RunOptions) error {
 	c.stderrBuf = stderrRing{max: 2048}
 	var stderrWriters []io.Writer
 	stderrWriters = append(stderrWriters, &c.stderrBuf)
-	logFile, _ := os.OpenFile(c.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if logFile != nil {
+	var logFile *os.File
+	if c.level() != LogLevelOff {
+		logFile, _ = os.OpenFile(c.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	}
+	if logFile != nil && c.level() == LogLevelFull {
 		stderrWriters = append(stderrWriters, logFile)
 	}
 	go io.Copy(io.MultiWriter(stderrWriters...), stderr)
@@ -173,8 +213,7 @@ func (c *CopilotClient) ensureProcess(opts RunOptions) error {
 	c.proc = cmd
 	c.stdin = stdin
 	c.logFile = logFile
-	c.scanner = bufio.NewScanner(stdout)
-	c.scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	c.reader = bufio.NewReaderSize(stdout, 64*1024)
 	c.readerDone = make(chan struct{})
 
 	// Start background reader.
@@ -192,16 +231,28 @@ func (c *CopilotClient) ensureProcess(opts RunOptions) error {
 		return fmt.Errorf("acp initialize: %w", err)
 	}
 	c.initDone = true
-	c.log("[ACP] initialized: %s\n", string(result))
+	c.trace("[ACP] initialized: %s\n", string(result))
+	c.setCommands(result)
 	return nil
 }
 
 // readLoop runs in a goroutine, dispatching JSON-RPC responses and notifications.
 func (c *CopilotClient) readLoop() {
 	defer close(c.readerDone)
-	for c.scanner.Scan() {
-		line := c.scanner.Bytes()
-		c.log("[ACP] ← %s\n", string(line))
+	for {
+		line, err := c.readLine()
+		if err == errLineTooLong {
+			c.log("[ACP] skipped message over %d bytes\n", c.lineLimit())
+			continue
+		}
+		if len(line) == 0 {
+			if err != nil {
+				c.log("[ACP] process exited%s\n", c.stderrTail())
+				return
+			}
+			continue
+		}
+		c.trace("[ACP] ← %s\n", string(line))
 
 		var msg jsonRPCMessage
 		if err := json.Unmarshal(line, &msg); err != nil {
@@ -223,11 +274,47 @@ func (c *CopilotClient) readLoop() {
 	}
 }
 
+func (c *CopilotClient) lineLimit() int {
+	if c.maxLineBytes > 0 {
+		return c.maxLineBytes
+	}
+	return acpMaxLineBytes
+}
+
+// readLine returns the next newline-terminated line without the trailing
+// newline. A line longer than lineLimit is consumed and discarded, returning
+// errLineTooLong so the caller can carry on with the next message.
+func (c *CopilotClient) readLine() ([]byte, error) {
+	var line []byte
+	tooLong := false
+	for {
+		chunk, err := c.reader.ReadSlice('\n')
+		if !tooLong {
+			if len(line)+len(chunk) > c.lineLimit()+1 {
+				tooLong = true
+				line = nil
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if tooLong {
+			if err != nil {
+				return nil, err
+			}
+			return nil, errLineTooLong
+		}
+		return bytes.TrimRight(line, "\r\n"), err
+	}
+}
+
 // handleServerRequest responds to server-initiated JSON-RPC requests.
 // The primary case is session/request_permission: the ACP agent asks the
 // client to approve or deny a tool call.
 func (c *CopilotClient) handleServerRequest(msg *jsonRPCMessage) {
-	c.log("[ACP] server request: method=%s id=%d\n", msg.Method, *msg.ID)
+	c.trace("[ACP] server request: method=%s id=%d\n", msg.Method, *msg.ID)
 
 	switch msg.Method {
 	case "session/request_permission":
@@ -329,7 +416,7 @@ func (c *CopilotClient) respondToServer(id int64, result any) {
 	}
 	data, _ := json.Marshal(resp)
 	data = append(data, '\n')
-	c.log("[ACP] → %s\n", string(data))
+	c.trace("[ACP] → %s\n", string(data))
 
 	c.writeMu.Lock()
 	c.stdin.Write(data)
@@ -358,6 +445,14 @@ func (c *CopilotClient) handleNotification(msg *jsonRPCMessage) {
 	if err := json.Unmarshal(msg.Params, &params); err != nil {
 		return
 	}
+	if params.Update.SessionUpdate == "available_commands_update" {
+		var update struct {
+			Update json.RawMessage `json:"update"`
+		}
+		json.Unmarshal(msg.Params, &update)
+		c.setCommands(update.Update)
+		return
+	}
 
 	cbsVal, ok := c.callbacks.Load(params.SessionID)
 	if !ok {
@@ -427,6 +522,7 @@ func (c *CopilotClient) resolveSession(opts RunOptions) (string, error) {
 	}
 	json.Unmarshal(result, &res)
 	c.loadedSessions.Store(res.SessionID, struct{}{})
+	c.setCommands(result)
 	return res.SessionID, nil
 }
 
@@ -445,6 +541,46 @@ func (c *CopilotClient) mapMode(opts RunOptions) string {
 	}
 }
 
+// AvailableCommands returns the command names from the agent's latest
+// availableCommands list (initialize, session/new or an update).
+func (c *CopilotClient) AvailableCommands() []string {
+	c.commandsMu.Lock()
+	defer c.commandsMu.Unlock()
+	return append([]string(nil), c.commands...)
+}
+
+// setCommands records the availableCommands in raw, if it has any.
+func (c *CopilotClient) setCommands(raw json.RawMessage) {
+	names, ok := parseAvailableCommands(raw)
+	if !ok {
+		return
+	}
+	c.commandsMu.Lock()
+	c.commands = names
+	c.commandsMu.Unlock()
+}
+
+// parseAvailableCommands reads the names from an object's availableCommands
+// field, whose entries carry name, description and input metadata. ok is
+// false when the field is missing.
+func parseAvailableCommands(raw json.RawMessage) (names []string, ok bool) {
+	var res struct {
+		AvailableCommands *[]struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"availableCommands"`
+	}
+	if json.Unmarshal(raw, &res) != nil || res.AvailableCommands == nil {
+		return nil, false
+	}
+	for _, cmd := range *res.AvailableCommands {
+		if name := strings.TrimPrefix(strings.TrimSpace(cmd.Name), "/"); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, true
+}
+
 func (c *CopilotClient) ResolveModel(tier string) string {
 	if tier == "" || len(c.models) == 0 {
 		return ""
@@ -475,7 +611,7 @@ func (c *CopilotClient) sendAndWait(method string, params any) (json.RawMessage,
 	data, _ := json.Marshal(msg)
 	data = append(data, '\n')
 
-	c.log("[ACP] → %s\n", string(data))
+	c.trace("[ACP] → %s\n", string(data))
 	c.writeMu.Lock()
 	_, err := c.stdin.Write(data)
 	c.writeMu.Unlock()
@@ -487,6 +623,11 @@ func (c *CopilotClient) sendAndWait(method string, params any) (json.RawMessage,
 	select {
 	case resp := <-ch:
 		if resp.Error != nil {
+			request := data
+			if c.level() != LogLevelFull {
+				request = redactPrompt(data)
+			}
+			c.log("[ACP] %s failed (code %d): %s; request: %s\n", method, resp.Error.Code, resp.Error.Message, bytes.TrimSpace(request))
 			return nil, fmt.Errorf("acp %s: %s", method, resp.Error.Message)
 		}
 		return resp.Result, nil
@@ -510,12 +651,51 @@ func (c *CopilotClient) CancelSession(sessionID string) error {
 	return err
 }
 
+// log writes to the log file at every level but LogLevelOff; use it for
+// failures. trace is for routine wire traffic and only writes at LogLevelFull.
 func (c *CopilotClient) log(format string, args ...any) {
-	if c.logFile != nil {
+	if c.logFile != nil && c.level() != LogLevelOff {
 		fmt.Fprintf(c.logFile, format, args...)
 	}
 }
 
+func (c *CopilotClient) trace(format string, args ...any) {
+	if c.level() == LogLevelFull {
+		c.log(format, args...)
+	}
+}
+
+// stderrTail formats the last captured stderr for a log line, or "".
+func (c *CopilotClient) stderrTail() string {
+	tail := strings.TrimSpace(c.stderrBuf.String())
+	if tail == "" {
+		return ""
+	}
+	if len(tail) > 512 {
+		tail = tail[len(tail)-512:]
+	}
+	return "; stderr: " + tail
+}
+
+// redactPrompt replaces the prompt content of a JSON-RPC request with its
+// length so failure logs don't leak what the user typed.
+func redactPrompt(data []byte) []byte {
+	var msg struct {
+		JSONRPC string                     `json:"jsonrpc"`
+		ID      *int64                     `json:"id,omitempty"`
+		Method  string                     `json:"method,omitempty"`
+		Params  map[string]json.RawMessage `json:"params,omitempty"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return []byte("[unparseable request redacted]")
+	}
+	if p, ok := msg.Params["prompt"]; ok {
+		msg.Params["prompt"], _ = json.Marshal(fmt.Sprintf("[redacted %d bytes]", len(p)))
+	}
+	out, _ := json.Marshal(msg)
+	return out
+}
+
 // stderrRing captures the last N bytes of stderr for error diagnostics.
 type stderrRing struct {
 	mu  sync.Mutex
@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -563,3 +564,191 @@ done
 		t.Errorf("want 'approved:allow_always', got %q", fullResp)
 	}
 }
+
+// TestCopilotClient_ReadLoopSkipsOversizedLine verifies that a line over the
+// size cap is dropped without stopping dispatch of the messages after it.
+func TestCopilotClient_ReadLoopSkipsOversizedLine(t *testing.T) {
+	huge := `{"jsonrpc":"2.0","method":"session/update","params":{"text":"` + strings.Repeat("x", 4096) + `"}}`
+	valid := `{"jsonrpc":"2.0","id":1,"result":{"ok":true}}`
+
+	c := &CopilotClient{
+		reader:       bufio.NewReaderSize(strings.NewReader(huge+"\n"+valid+"\n"), 16),
+		maxLineBytes: 1024,
+		readerDone:   make(chan struct{}),
+	}
+	ch := make(chan *jsonRPCMessage, 1)
+	c.responses.Store(int64(1), ch)
+
+	go c.readLoop()
+
+	select {
+	case msg := <-ch:
+		if string(msg.Result) != `{"ok":true}` {
+			t.Errorf("unexpected result: %s", msg.Result)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("valid response after oversized line was not dispatched")
+	}
+	<-c.readerDone
+}
+
+// startLogLevelClient runs a mock ACP server that fails session/prompt when
+// the prompt text contains "boom" and returns the client and its log path.
+func startLogLevelClient(t *testing.T, level string) (*CopilotClient, string) {
+	t.Helper()
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not found, skipping log level test")
+	}
+	tmpDir := t.TempDir()
+	scriptPath := tmpDir + "/mock_acp.py"
+	script := `#!/usr/bin/env python3
+import json, sys
+for line in sys.stdin:
+    msg = json.loads(line)
+    method, id = msg.get("method"), msg.get("id")
+    if method == "session/new":
+        out = {"jsonrpc": "2.0", "id": id, "result": {"sessionId": "s1"}}
+    elif method == "session/prompt" and "boom" in json.dumps(msg["params"]["prompt"]):
+        out = {"jsonrpc": "2.0", "id": id, "error": {"code": -32000, "message": "model overloaded"}}
+    else:
+        out = {"jsonrpc": "2.0", "id": id, "result": {}}
+    print(json.dumps(out), flush=True)
+`
+	if err := os.WriteFile(scriptPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	c := &CopilotClient{binPath: scriptPath, logPath: tmpDir + "/test.log"}
+	c.SetLogLevel(level)
+	t.Cleanup(func() {
+		if c.proc != nil {
+			c.proc.Process.Kill()
+		}
+	})
+	return c, c.logPath
+}
+
+func TestCopilotClient_ErrorsLogLevel(t *testing.T) {
+	c, logPath := startLogLevelClient(t, LogLevelErrors)
+	noop := func(string) {}
+
+	if _, err := c.Run(RunOptions{Prompt: "secret plan"}, noop, noop); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	data, _ := os.ReadFile(logPath)
+	if len(data) != 0 {
+		t.Errorf("successful prompt should not be logged at errors level, got:\n%s", data)
+	}
+
+	if _, err := c.Run(RunOptions{Prompt: "secret boom"}, noop, noop); err == nil {
+		t.Fatal("expected the prompt to fail")
+	}
+	data, _ = os.ReadFile(logPath)
+	log := string(data)
+	if !strings.Contains(log, "session/prompt failed") || !strings.Contains(log, "model overloaded") {
+		t.Errorf("expected the failure in the log, got:\n%s", log)
+	}
+	if strings.Contains(log, "secret") {
+		t.Errorf("prompt text should be redacted, got:\n%s", log)
+	}
+}
+
+func TestCopilotClient_FullLogLevelTracesWire(t *testing.T) {
+	c, logPath := startLogLevelClient(t, LogLevelFull)
+	noop := func(string) {}
+
+	if _, err := c.Run(RunOptions{Prompt: "hello there"}, noop, noop); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	data, _ := os.ReadFile(logPath)
+	if !strings.Contains(string(data), "[ACP] →") || !strings.Contains(string(data), "hello there") {
+		t.Errorf("expected the full wire trace, got:\n%s", data)
+	}
+}
+
+// TestCopilotClient_AvailableCommands verifies availableCommands are read from
+// the initialize and session/new results and from update notifications.
+func TestCopilotClient_AvailableCommands(t *testing.T) {
+	c := &CopilotClient{}
+	if got := c.AvailableCommands(); len(got) != 0 {
+		t.Fatalf("before initialize: got %v, want none", got)
+	}
+
+	c.setCommands(json.RawMessage(`{"protocolVersion":1,"availableCommands":[
+		{"name":"review","description":"Review the current diff","input":{"hint":"focus area"}},
+		{"name":"/explain","description":"Explain the code"},
+		{"name":"  ","description":"blank names are skipped"}]}`))
+	if got := c.AvailableCommands(); !reflect.DeepEqual(got, []string{"review", "explain"}) {
+		t.Errorf("after initialize: got %v, want [review explain]", got)
+	}
+
+	// A session/new result without the field keeps the previous list.
+	c.setCommands(json.RawMessage(`{"sessionId":"s1"}`))
+	if got := c.AvailableCommands(); len(got) != 2 {
+		t.Errorf("result without availableCommands replaced the list: %v", got)
+	}
+
+	params, _ := json.Marshal(map[string]any{
+		"sessionId": "s1",
+		"update": map[string]any{
+			"sessionUpdate":     "available_commands_update",
+			"availableCommands": []map[string]any{{"name": "plan", "description": "Make a plan"}},
+		},
+	})
+	c.handleNotification(&jsonRPCMessage{JSONRPC: "2.0", Method: "session/update", Params: params})
+	if got := c.AvailableCommands(); !reflect.DeepEqual(got, []string{"plan"}) {
+		t.Errorf("after update: got %v, want [plan]", got)
+	}
+}
+
+func TestCopilotClient_StopReason(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not found, skipping stop reason test")
+	}
+	tmpDir := t.TempDir()
+	scriptPath := tmpDir + "/mock_acp.py"
+	// The prompt text is the stop reason to answer with; "none" omits it.
+	script := `#!/usr/bin/env python3
+import json, sys
+for line in sys.stdin:
+    msg = json.loads(line)
+    method, id = msg.get("method"), msg.get("id")
+    result = {}
+    if method == "session/new":
+        result = {"sessionId": "s1"}
+    elif method == "session/prompt":
+        reason = msg["params"]["prompt"][0]["text"]
+        if reason != "none":
+            result = {"stopReason": reason}
+    print(json.dumps({"jsonrpc": "2.0", "id": id, "result": result}), flush=True)
+`
+	if err := os.WriteFile(scriptPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	c := &CopilotClient{binPath: scriptPath, logPath: tmpDir + "/test.log"}
+	t.Cleanup(func() {
+		if c.proc != nil {
+			c.proc.Process.Kill()
+		}
+	})
+	noop := func(string) {}
+
+	for _, reason := range []string{StopEndTurn, StopMaxTokens, StopRefusal, StopCancelled, "none"} {
+		var got []string
+		opts := RunOptions{Prompt: reason, OnStopReason: func(r string) { got = append(got, r) }}
+		if _, err := c.Run(opts, noop, noop); err != nil {
+			t.Fatalf("%s: Run failed: %v", reason, err)
+		}
+		want := []string{reason}
+		if reason == "none" {
+			want = nil
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: surfaced stop reasons %v, want %v", reason, got, want)
+		}
+	}
+
+	// Without a callback the reason is simply dropped.
+	if _, err := c.Run(RunOptions{Prompt: StopMaxTokens}, noop, noop); err != nil {
+		t.Fatalf("Run without OnStopReason failed: %v", err)
+	}
+}
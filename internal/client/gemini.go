@@ -48,7 +48,7 @@ func (g *GeminiClient) Run(opts RunOptions, onChunk func(string), onSessionID fu
 		return "", err
 	}
 
-	g.logExecution(args, opts.Prompt)
+	g.logExecution(args, opts.InlinePrompt())
 
 	logFile, _ := os.OpenFile(g.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if logFile != nil {
@@ -108,7 +108,7 @@ func (g *GeminiClient) Run(opts RunOptions, onChunk func(string), onSessionID fu
 }
 
 func (g *GeminiClient) buildArgs(opts RunOptions) []string {
-	args := []string{"-s", "--output-format", "stream-json", "--prompt", opts.Prompt}
+	args := []string{"-s", "--output-format", "stream-json", "--prompt", opts.InlinePrompt()}
 	if opts.NativeSID != "" {
 		args = append(args, "--resume", opts.NativeSID)
 	}
@@ -130,6 +130,9 @@ func (g *GeminiClient) ResolveModel(tier string) string {
 	return g.models[tier]
 }
 
+// AvailableCommands returns nil: the CLI does not advertise agent commands.
+func (g *GeminiClient) AvailableCommands() []string { return nil }
+
 func (g *GeminiClient) logExecution(args []string, prompt string) {
 	logFile, _ := os.OpenFile(g.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if logFile == nil {
@@ -10,17 +10,26 @@ import (
 )
 
 const (
-	DefaultTgInterval = 500 // ms
-	DefaultPageSize   = 5
-	DefaultMaxLoops   = 5
-	DefaultStorageDir = ".tenazas"
-	ConfigFileName    = "config.json"
+	DefaultTgInterval    = 500  // ms
+	DefaultTgDebounce    = 1000 // ms
+	DefaultEscalateAfter = 900  // s
+	DefaultPageSize      = 5
+	DefaultMaxLoops      = 5
+	DefaultStuckRepeats  = 3
+	DefaultStorageDir    = ".tenazas"
+	ConfigFileName       = "config.json"
 )
 
 // ClientConfig holds settings for a single coding-agent client.
 type ClientConfig struct {
 	BinPath string            `json:"bin_path"`
 	Models  map[string]string `json:"models,omitempty"` // tier → model name (high/medium/low)
+	// LogLevel sets the wire trace for clients that keep one (copilot's ACP):
+	// "off", "errors" (default) or "full".
+	LogLevel string `json:"log_level,omitempty"`
+	// AllowedDirs restricts the directories sessions using this client may
+	// run in, subdirectories included; empty means unrestricted.
+	AllowedDirs []string `json:"allowed_dirs,omitempty"`
 }
 
 // ChannelConfig holds settings for an external communication channel.
@@ -29,25 +38,126 @@ type ChannelConfig struct {
 	Token          string  `json:"token,omitempty"`            // bot token
 	AllowedUserIDs []int64 `json:"allowed_user_ids,omitempty"` // whitelist
 	UpdateInterval int     `json:"update_interval,omitempty"`  // ms between streaming edits
+	StatusDebounce int     `json:"status_debounce,omitempty"`  // ms between task status edits per session; -1 disables
+	// AllowedUsernames are whitelisted too ("@name" or "name"); each one's
+	// ID is learned from their first message.
+	AllowedUsernames []string `json:"allowed_usernames,omitempty"`
+	// ActionKeyboard lays out the quick-action buttons under responses, one
+	// list per row: "continue", "new_session", "run", "more", "last",
+	// "settings" or "skill:<name>". Empty keeps the default layout.
+	ActionKeyboard [][]string `json:"action_keyboard,omitempty"`
+	// EscalationChatIDs are told, on top of the allowed users, about an
+	// intervention left unanswered for EscalateAfterSec seconds.
+	EscalationChatIDs []int64 `json:"escalation_chat_ids,omitempty"`
+	EscalateAfterSec  int     `json:"escalate_after_sec,omitempty"`
+	// MaxMessageChars cuts longer messages (default 4000, at most 4096);
+	// TruncationMarker ends a cut message or /last preview (default "...");
+	// PreviewChars is the length of each /last entry (default 300).
+	MaxMessageChars  int    `json:"max_message_chars,omitempty"`
+	TruncationMarker string `json:"truncation_marker,omitempty"`
+	PreviewChars     int    `json:"preview_chars,omitempty"`
+	// StreamMinDelta is the least new text, in characters, worth a live edit
+	// of a streaming response (default 24). StreamFinalOnly turns live edits
+	// off and posts only the final response.
+	StreamMinDelta  int  `json:"stream_min_delta,omitempty"`
+	StreamFinalOnly bool `json:"stream_final_only,omitempty"`
+}
+
+// WebhookConfig holds an HTTP endpoint for machine-readable notifications.
+type WebhookConfig struct {
+	URL     string `json:"url,omitempty"`
+	Secret  string `json:"secret,omitempty"`  // sent in the X-Tenazas-Secret header
+	Retries int    `json:"retries,omitempty"` // extra attempts after a network error or 5xx (default 3, -1 disables)
 }
 
 type Config struct {
 	// Core
-	StorageDir string `json:"storage_dir"`
-	MaxLoops   int    `json:"max_loops"`
+	StorageDir     string `json:"storage_dir"`
+	MaxLoops       int    `json:"max_loops"`
+	IdleTimeoutSec int    `json:"idle_timeout_sec,omitempty"` // park a silent skill run after this many seconds; 0 disables
+	PromptMode     string `json:"prompt_mode,omitempty"`      // "interrupt" (default) or "queue" for prompts sent while one is running
+	// StuckRepeatLimit stops a skill loop for intervention once verification
+	// fails with identical output this many times in a row (default 3, -1
+	// disables), instead of using up MaxLoops.
+	StuckRepeatLimit int `json:"stuck_repeat_limit,omitempty"`
+	// MaxPromptChars caps prompts sent to a client, in characters; 0 means
+	// unlimited. PromptLimitPolicy picks "truncate" (default) or "reject".
+	MaxPromptChars    int    `json:"max_prompt_chars,omitempty"`
+	PromptLimitPolicy string `json:"prompt_limit_policy,omitempty"`
+	// SlowLLMThresholdSec flags LLM calls slower than this in the audit log;
+	// 0 disables the warning (latency is still recorded).
+	SlowLLMThresholdSec float64 `json:"slow_llm_threshold_sec,omitempty"`
+	// ChunkFlushMs and ChunkFlushBytes batch streamed response text into
+	// fewer audit entries, written once the pending text is this old or this
+	// long; both 0 write every chunk. Live output is not delayed.
+	ChunkFlushMs    int `json:"chunk_flush_ms,omitempty"`
+	ChunkFlushBytes int `json:"chunk_flush_bytes,omitempty"`
+	// MaxContinues sends ContinuePrompt (default "Continue.") up to this many
+	// times when a response is cut off at max_tokens, joining the pieces; 0
+	// disables it.
+	MaxContinues   int    `json:"max_continues,omitempty"`
+	ContinuePrompt string `json:"continue_prompt,omitempty"`
+	// MaxConcurrentSessions caps how many skill runs and prompts execute at
+	// once, e.g. when the daemon resumes many sessions; the rest are queued.
+	// 0 means unlimited.
+	MaxConcurrentSessions int `json:"max_concurrent_sessions,omitempty"`
+	// InstructionPaths are extra directories searched for @file instruction
+	// includes after the skill's own directory, e.g. a shared prompts dir or
+	// ".tenazas/prompts" (relative entries are under the session CWD).
+	InstructionPaths []string `json:"instruction_paths,omitempty"`
+
+	// Display
+	TimestampLayout   string `json:"timestamp_layout,omitempty"`   // Go time layout for audit timestamps
+	TimestampTimezone string `json:"timestamp_timezone,omitempty"` // "local" (default) or "utc"
+	Transcript        bool   `json:"transcript,omitempty"`         // mirror session output to a plain-text transcript
+	SessionIDLength   int    `json:"session_id_length,omitempty"`  // characters of a session ID shown in listings (default 8, longer where prefixes collide)
+
+	// Tasks
+	PriorityLabels map[string]int `json:"priority_labels,omitempty"` // label → minimum priority; empty uses the built-in none/low/medium/high/urgent
+	// LabelSkills routes tasks without an explicit skill by label, e.g.
+	// {"bug": "debug"}; the first of a task's labels with a mapping wins.
+	LabelSkills map[string]string `json:"label_skills,omitempty"`
+
+	// Heartbeats
+	HeartbeatSkipDirty bool `json:"heartbeat_skip_dirty,omitempty"` // skip runs in projects with uncommitted git changes
 
 	// Clients
-	DefaultClient    string                  `json:"default_client"`
-	DefaultModelTier string                  `json:"default_model_tier,omitempty"`
-	Clients          map[string]ClientConfig `json:"clients,omitempty"`
+	DefaultClient    string `json:"default_client"`
+	DefaultModelTier string `json:"default_model_tier,omitempty"`
+	// DefaultApprovalMode is the approval mode for new interactive sessions:
+	// "plan" (default), "auto_edit" or "yolo".
+	DefaultApprovalMode string                  `json:"default_approval_mode,omitempty"`
+	Clients             map[string]ClientConfig `json:"clients,omitempty"`
 
 	// Communication
 	Channel ChannelConfig `json:"channel"`
+	// CompletionWebhook is POSTed a JSON summary whenever a skill run
+	// completes, fails or is stopped.
+	CompletionWebhook WebhookConfig `json:"completion_webhook"`
+	// MetricsAddr serves Prometheus metrics at /metrics on this address
+	// (e.g. "127.0.0.1:9464") while the daemon runs; empty disables it.
+	MetricsAddr string `json:"metrics_addr,omitempty"`
 
 	// Legacy (read for backward compat, not written by onboard)
 	GeminiBinPath string `json:"gemini_bin_path,omitempty"`
 }
 
+// ClientAllowedDirs maps each client with AllowedDirs to them.
+func (c *Config) ClientAllowedDirs() map[string][]string {
+	dirs := make(map[string][]string)
+	for name, cc := range c.Clients {
+		if len(cc.AllowedDirs) > 0 {
+			dirs[name] = cc.AllowedDirs
+		}
+	}
+	return dirs
+}
+
+// TimestampUTC reports whether audit timestamps should be rendered in UTC.
+func (c *Config) TimestampUTC() bool {
+	return strings.EqualFold(c.TimestampTimezone, "utc")
+}
+
 // legacyConfig is used to detect and migrate old flat-field config formats.
 type legacyConfig struct {
 	Channel        json.RawMessage `json:"channel,omitempty"`
@@ -124,9 +234,13 @@ func Load() (*Config, error) {
 	}
 	if envIDs := os.Getenv("TENAZAS_ALLOWED_IDS"); envIDs != "" {
 		cfg.Channel.AllowedUserIDs = nil
+		cfg.Channel.AllowedUsernames = nil
 		for _, s := range strings.Split(envIDs, ",") {
-			if id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err == nil {
+			s = strings.TrimSpace(s)
+			if id, err := strconv.ParseInt(s, 10, 64); err == nil {
 				cfg.Channel.AllowedUserIDs = append(cfg.Channel.AllowedUserIDs, id)
+			} else if strings.HasPrefix(s, "@") {
+				cfg.Channel.AllowedUsernames = append(cfg.Channel.AllowedUsernames, s)
 			}
 		}
 	}
@@ -146,6 +260,15 @@ func Load() (*Config, error) {
 	if cfg.Channel.UpdateInterval == 0 {
 		cfg.Channel.UpdateInterval = DefaultTgInterval
 	}
+	if cfg.Channel.StatusDebounce == 0 {
+		cfg.Channel.StatusDebounce = DefaultTgDebounce
+	}
+	if cfg.StuckRepeatLimit == 0 {
+		cfg.StuckRepeatLimit = DefaultStuckRepeats
+	}
+	if cfg.Channel.EscalateAfterSec == 0 {
+		cfg.Channel.EscalateAfterSec = DefaultEscalateAfter
+	}
 
 	os.MkdirAll(cfg.StorageDir, 0755)
 	os.MkdirAll(filepath.Join(cfg.StorageDir, "sessions"), 0755)
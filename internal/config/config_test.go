@@ -16,7 +16,7 @@ func TestLoadConfig(t *testing.T) {
 	os.Setenv("TENAZAS_TG_TOKEN", "test-token")
 	defer os.Unsetenv("TENAZAS_TG_TOKEN")
 
-	os.Setenv("TENAZAS_ALLOWED_IDS", "123, 456")
+	os.Setenv("TENAZAS_ALLOWED_IDS", "123, @alice, 456")
 	defer os.Unsetenv("TENAZAS_ALLOWED_IDS")
 
 	cfg, err := Load()
@@ -35,6 +35,9 @@ func TestLoadConfig(t *testing.T) {
 	if len(cfg.Channel.AllowedUserIDs) != 2 || cfg.Channel.AllowedUserIDs[0] != 123 || cfg.Channel.AllowedUserIDs[1] != 456 {
 		t.Errorf("expected allowed IDs [123, 456], got %v", cfg.Channel.AllowedUserIDs)
 	}
+	if len(cfg.Channel.AllowedUsernames) != 1 || cfg.Channel.AllowedUsernames[0] != "@alice" {
+		t.Errorf("expected allowed usernames [@alice], got %v", cfg.Channel.AllowedUsernames)
+	}
 
 	// Verify directories created
 	if _, err := os.Stat(filepath.Join(tmpDir, "sessions")); os.IsNotExist(err) {
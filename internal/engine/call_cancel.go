@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"context"
+	"errors"
+
+	"tenazas/internal/models"
+)
+
+// errCallCancelled is returned by callLLM when CancelCall aborted the call
+// while the session itself kept running.
+var errCallCancelled = errors.New("call cancelled")
+
+// inflightCall is a callLLM that can be cancelled without touching the
+// session context.
+type inflightCall struct {
+	role   string
+	node   string
+	cancel context.CancelFunc
+}
+
+// startCall derives a cancellable context for one LLM call from the session
+// context and tracks it for CancelCall. finish stops tracking it and reports
+// whether the call alone was cancelled.
+func (e *Engine) startCall(sess *models.Session, role, node string) (ctx context.Context, finish func() bool) {
+	parent := context.Background()
+	if v, ok := e.sessionCtxs.Load(sess.ID); ok {
+		parent = v.(context.Context)
+	}
+	ctx, cancel := context.WithCancel(parent)
+	e.calls.Store(sess.ID, &inflightCall{role: role, node: node, cancel: cancel})
+	return ctx, func() bool {
+		e.calls.Delete(sess.ID)
+		cancelled := ctx.Err() != nil && parent.Err() == nil
+		cancel()
+		return cancelled
+	}
+}
+
+// CancelCall aborts the session's in-flight LLM call if target is empty or
+// names its role or node. The session keeps running: the state counts the
+// call as a failed attempt and retries it. It reports whether a call was
+// cancelled.
+func (e *Engine) CancelCall(sessionID, target string) bool {
+	v, ok := e.calls.Load(sessionID)
+	if !ok {
+		return false
+	}
+	call := v.(*inflightCall)
+	if target != "" && target != call.role && target != call.node {
+		return false
+	}
+	call.cancel()
+	return true
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestCancelCallRetriesWithoutCancellingSession(t *testing.T) {
+	storageDir := t.TempDir()
+
+	// The first call hangs; later calls answer at once.
+	mark := filepath.Join(storageDir, "called")
+	script := filepath.Join(storageDir, "client.sh")
+	os.WriteFile(script, []byte("#!/bin/sh\nif [ ! -f "+mark+" ]; then touch "+mark+"; exec sleep 30; fi\necho ok\n"), 0755)
+
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient(script, storageDir), "gemini", 5)
+
+	skill := &models.SkillGraph{
+		Name:         "cancel-skill",
+		InitialState: "review",
+		States: map[string]models.StateDef{
+			"review": {Type: "action_loop", SessionRole: "reviewer", Instruction: "review it", MaxRetries: 3, Next: "end"},
+			"end":    {Type: "end"},
+		},
+	}
+	sess := &models.Session{ID: "cancel-sess", CWD: storageDir, SkillName: "cancel-skill", RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	done := make(chan struct{})
+	go func() {
+		eng.Run(skill, sess)
+		close(done)
+	}()
+
+	if eng.CancelCall(sess.ID, "coder") {
+		t.Error("expected no call for a role that is not running")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !eng.CancelCall(sess.ID, "reviewer") {
+		if time.Now().After(deadline) {
+			t.Fatal("the reviewer call never started")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected the run to go on after the call was cancelled")
+	}
+
+	if sess.Status != models.StatusCompleted {
+		t.Errorf("expected the retried state to complete, got %s (%s)", sess.Status, sess.PendingFeedback)
+	}
+	if sess.RetryCount != 0 {
+		t.Errorf("expected the retry count to reset on completion, got %d", sess.RetryCount)
+	}
+	cancelled, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return strings.Contains(e.Content, "Cancelled the reviewer call at review")
+	})
+	if len(cancelled) != 1 {
+		t.Errorf("expected one cancellation note, got %d", len(cancelled))
+	}
+	userCancel, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return strings.Contains(e.Content, "cancelled by user")
+	})
+	if len(userCancel) != 0 {
+		t.Error("expected the session itself not to be cancelled")
+	}
+}
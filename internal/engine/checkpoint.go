@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"fmt"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// checkpointNode returns the state a --from-checkpoint run resumes at: the
+// successor of the session's last successfully completed state, or the
+// skill's initial state when nothing has completed yet.
+func checkpointNode(sk *models.SkillGraph, sess *models.Session) (string, error) {
+	if sess.LastGoodNode == "" {
+		return sk.InitialState, nil
+	}
+	state, ok := sk.States[sess.LastGoodNode]
+	if !ok {
+		return "", fmt.Errorf("checkpoint state %q not found in skill %s", sess.LastGoodNode, sk.Name)
+	}
+	if state.Next == "" {
+		return "", fmt.Errorf("checkpoint state %q has no next state", sess.LastGoodNode)
+	}
+	if _, ok := sk.States[state.Next]; !ok {
+		return "", fmt.Errorf("state %q after checkpoint %q not found in skill %s", state.Next, sess.LastGoodNode, sk.Name)
+	}
+	return state.Next, nil
+}
+
+// ResetToCheckpoint rewinds a session so the next Run of sk resumes right
+// after its last successfully completed state, with retry and loop counters
+// reset. It returns the node the run will resume at.
+func (e *Engine) ResetToCheckpoint(sk *models.SkillGraph, sess *models.Session) (string, error) {
+	if e.IsRunning(sess.ID) {
+		return "", fmt.Errorf("session %s is still running", sess.ID)
+	}
+	node, err := checkpointNode(sk, sess)
+	if err != nil {
+		return "", err
+	}
+	e.failures.Delete(sess.ID)
+	e.Sm.Update(sess, func(s *models.Session) {
+		s.ActiveNode = node
+		s.Status = models.StatusRunning
+		s.RetryCount = 0
+		s.LoopCount = 0
+		s.PendingFeedback = ""
+	})
+	e.log(sess, events.AuditStatus, "engine", fmt.Sprintf("Resuming skill %s from checkpoint at node %s", sk.Name, node), events.RoleSystem)
+	return node, nil
+}
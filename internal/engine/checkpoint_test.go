@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestResetToCheckpointResumesAtFailedState(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+
+	marker := filepath.Join(storageDir, "fixed")
+	skill := &models.SkillGraph{
+		Name:         "checkpoint-skill",
+		InitialState: "a",
+		States: map[string]models.StateDef{
+			"a":   {Type: "tool", Command: "echo a >> " + filepath.Join(storageDir, "ran"), Next: "b"},
+			"b":   {Type: "tool", Command: "echo b >> " + filepath.Join(storageDir, "ran"), Next: "c"},
+			"c":   {Type: "tool", Command: "test -f " + marker, Next: "end"},
+			"end": {Type: "end"},
+		},
+	}
+	sess := &models.Session{ID: "checkpoint-sess", CWD: storageDir, SkillName: skill.Name, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	eng.Run(skill, sess)
+	if sess.Status != models.StatusFailed || sess.ActiveNode != "c" {
+		t.Fatalf("expected failure at c, got status=%q node=%q", sess.Status, sess.ActiveNode)
+	}
+	if sess.LastGoodNode != "b" {
+		t.Fatalf("LastGoodNode = %q, want b", sess.LastGoodNode)
+	}
+
+	os.WriteFile(marker, nil, 0644)
+	sess.RetryCount, sess.LoopCount = 2, 3
+	node, err := eng.ResetToCheckpoint(skill, sess)
+	if err != nil {
+		t.Fatalf("ResetToCheckpoint: %v", err)
+	}
+	if node != "c" {
+		t.Errorf("resume node = %q, want c", node)
+	}
+	if sess.RetryCount != 0 || sess.LoopCount != 0 {
+		t.Errorf("expected counters reset, got retry=%d loop=%d", sess.RetryCount, sess.LoopCount)
+	}
+
+	eng.Run(skill, sess)
+	if sess.Status != models.StatusCompleted {
+		t.Fatalf("expected completion after checkpoint retry, got %q", sess.Status)
+	}
+	ran, _ := os.ReadFile(filepath.Join(storageDir, "ran"))
+	if string(ran) != "a\nb\n" {
+		t.Errorf("states before the checkpoint should not rerun, got %q", ran)
+	}
+}
+
+func TestCheckpointNodeWithoutProgressUsesInitialState(t *testing.T) {
+	skill := &models.SkillGraph{Name: "s", InitialState: "start", States: map[string]models.StateDef{"start": {Type: "end"}}}
+	node, err := checkpointNode(skill, &models.Session{})
+	if err != nil || node != "start" {
+		t.Errorf("checkpointNode = %q, %v; want start", node, err)
+	}
+	if _, err := checkpointNode(skill, &models.Session{LastGoodNode: "gone"}); err == nil {
+		t.Error("expected an error for a checkpoint missing from the skill")
+	}
+}
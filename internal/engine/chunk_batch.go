@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// chunkBatch holds streamed text that listeners have seen but the audit log
+// has not, merged into one entry.
+type chunkBatch struct {
+	mu      sync.Mutex
+	pending events.AuditEntry // Content is empty when nothing is pending
+}
+
+// appendChunk publishes a streamed chunk and, when batching is configured,
+// adds it to the session's pending entry instead of writing it at once.
+func (e *Engine) appendChunk(sess *models.Session, entry events.AuditEntry) {
+	if e.ChunkFlushInterval <= 0 && e.ChunkFlushBytes <= 0 {
+		e.Sm.AppendAudit(sess, entry)
+		return
+	}
+	entry.Timestamp = time.Now()
+	e.Sm.PublishAudit(sess, entry)
+
+	v, _ := e.chunkBatches.LoadOrStore(sess.ID, &chunkBatch{})
+	b := v.(*chunkBatch)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p := &b.pending
+	if p.Content != "" && (p.Source != entry.Source || p.Step != entry.Step) {
+		e.Sm.PersistAudit(sess, *p)
+		*p = events.AuditEntry{}
+	}
+	if p.Content == "" {
+		*p = entry
+	} else {
+		p.Content += entry.Content
+	}
+	if (e.ChunkFlushInterval > 0 && entry.Timestamp.Sub(p.Timestamp) >= e.ChunkFlushInterval) ||
+		(e.ChunkFlushBytes > 0 && len(p.Content) >= e.ChunkFlushBytes) {
+		e.Sm.PersistAudit(sess, *p)
+		*p = events.AuditEntry{}
+	}
+}
+
+// flushChunks writes the session's pending streamed text. It runs at the end
+// of every LLM call and before any other entry is logged, so the audit log
+// keeps the order listeners saw.
+func (e *Engine) flushChunks(sess *models.Session) {
+	v, ok := e.chunkBatches.Load(sess.ID)
+	if !ok {
+		return
+	}
+	b := v.(*chunkBatch)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.pending.Content != "" {
+		e.Sm.PersistAudit(sess, b.pending)
+		b.pending = events.AuditEntry{}
+	}
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestChunkBatchingPersistsFewerEntries(t *testing.T) {
+	sm := session.NewManager(t.TempDir())
+	e := NewEngine(sm, nil, "gemini", 5)
+	e.ChunkFlushBytes = 32
+	e.ChunkFlushInterval = time.Hour
+	sess := &models.Session{ID: "sess-chunk-batch", CWD: t.TempDir(), RoleCache: map[string]string{}}
+	sm.Save(sess)
+
+	ch := events.GlobalBus.Subscribe()
+	defer events.GlobalBus.Unsubscribe(ch)
+
+	var want strings.Builder
+	parse := e.OnChunk(sess, &models.StateDef{SessionRole: "default"})
+	for i := 0; i < 80; i++ {
+		c := fmt.Sprintf("w%d ", i)
+		want.WriteString(c)
+		parse(c)
+	}
+	parse("")
+
+	live := 0
+	for done := false; !done; {
+		select {
+		case ev := <-ch:
+			if a, ok := ev.Payload.(events.AuditEntry); ok && ev.SessionID == sess.ID && a.Type == events.AuditLLMChunk {
+				live++
+			}
+		case <-time.After(200 * time.Millisecond):
+			done = true
+		}
+	}
+	if live != 80 {
+		t.Errorf("listeners got %d chunks, want every one of the 80", live)
+	}
+
+	stored, err := sm.FilterAudit(sess, func(a events.AuditEntry) bool { return a.Type == events.AuditLLMChunk })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) == 0 || len(stored) > 20 {
+		t.Errorf("persisted %d chunk entries, want far fewer than 80", len(stored))
+	}
+	var got strings.Builder
+	for _, a := range stored {
+		got.WriteString(a.Content)
+	}
+	if got.String() != want.String() {
+		t.Errorf("reconstructed text differs:\ngot  %q\nwant %q", got.String(), want.String())
+	}
+}
+
+func TestChunkBatchingKeepsOrderAroundOtherEntries(t *testing.T) {
+	sm := session.NewManager(t.TempDir())
+	e := NewEngine(sm, nil, "gemini", 5)
+	e.ChunkFlushInterval = time.Hour
+	sess := &models.Session{ID: "sess-chunk-order", CWD: t.TempDir(), RoleCache: map[string]string{}}
+	sm.Save(sess)
+
+	parse := e.OnChunk(sess, &models.StateDef{SessionRole: "default"})
+	parse("Hello ")
+	parse("there <thought>hmm</thought>")
+	parse(" again")
+	parse("")
+
+	stored, _ := sm.FilterAudit(sess, func(a events.AuditEntry) bool {
+		return a.Type == events.AuditLLMChunk || a.Type == events.AuditLLMThought
+	})
+	var got []string
+	for _, a := range stored {
+		got = append(got, a.Type+":"+a.Content)
+	}
+	want := []string{"llm_response_chunk:Hello there ", "llm_thought:hmm", "llm_response_chunk: again"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("persisted %q, want %q", got, want)
+	}
+}
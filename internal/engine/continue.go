@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"tenazas/internal/client"
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// defaultContinuePrompt asks for the rest of a truncated response when
+// ContinueText is empty.
+const defaultContinuePrompt = "Continue."
+
+// continueLimit is how many continuations a call may send: the skill's
+// max_continues when set (-1 disables them), else MaxContinues.
+func (e *Engine) continueLimit(skill *models.SkillGraph) int {
+	if skill != nil && skill.MaxContinues != 0 {
+		if skill.MaxContinues < 0 {
+			return 0
+		}
+		return skill.MaxContinues
+	}
+	return e.MaxContinues
+}
+
+func (e *Engine) continuePrompt() string {
+	if e.ContinueText != "" {
+		return e.ContinueText
+	}
+	return defaultContinuePrompt
+}
+
+// runContinued runs opts on c and, while the response stops at max_tokens,
+// sends the continuation prompt on the same native session up to limit more
+// times. The pieces are joined into one response.
+func (e *Engine) runContinued(sess *models.Session, c client.Client, opts client.RunOptions, limit int, onChunk, onSID func(string)) (string, error) {
+	var stop string
+	report := opts.OnStopReason
+	opts.OnStopReason = func(reason string) {
+		stop = reason
+		if report != nil {
+			report(reason)
+		}
+	}
+	sid := opts.NativeSID
+	trackSID := func(newSID string) {
+		sid = newSID
+		onSID(newSID)
+	}
+
+	var full strings.Builder
+	for n := 0; ; n++ {
+		stop = ""
+		resp, err := c.Run(opts, onChunk, trackSID)
+		full.WriteString(resp)
+		if err != nil || stop != client.StopMaxTokens || n >= limit || (opts.Ctx != nil && opts.Ctx.Err() != nil) {
+			return full.String(), err
+		}
+		e.log(sess, events.AuditInfo, "engine", fmt.Sprintf("Continuing the truncated response (%d of %d)", n+1, limit), events.RoleSystem)
+		opts.Prompt = e.continuePrompt()
+		opts.NativeSID = sid
+	}
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"tenazas/internal/client"
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func runScriptedPrompt(t *testing.T, eng func(*Engine), turns ...scriptedTurn) (*scriptedClient, *session.Manager, *models.Session) {
+	t.Helper()
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	c := &scriptedClient{turns: turns}
+	e := NewEngine(sm, map[string]client.Client{"gemini": c}, "gemini", 5)
+	eng(e)
+	sess := &models.Session{ID: "cont", CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+	e.ExecutePrompt(sess, "write a long essay")
+	return c, sm, sess
+}
+
+func lastResponse(t *testing.T, sm *session.Manager, sess *models.Session) string {
+	t.Helper()
+	resps, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool { return e.Type == events.AuditLLMResponse })
+	if len(resps) != 1 {
+		t.Fatalf("expected one response entry, got %d", len(resps))
+	}
+	return resps[0].Content
+}
+
+func TestContinueAfterMaxTokens(t *testing.T) {
+	c, sm, sess := runScriptedPrompt(t, func(e *Engine) { e.MaxContinues = 2 },
+		scriptedTurn{text: "The first half, ", stop: client.StopMaxTokens},
+		scriptedTurn{text: "and the rest.", stop: client.StopEndTurn},
+	)
+	if got := lastResponse(t, sm, sess); got != "The first half, and the rest." {
+		t.Errorf("combined response = %q", got)
+	}
+	if len(c.prompts) != 2 || c.prompts[1] != "Continue." {
+		t.Errorf("expected the default continuation prompt once, got %q", c.prompts)
+	}
+	notes, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return e.Type == events.AuditInfo && strings.HasPrefix(e.Content, "Continuing")
+	})
+	if len(notes) != 1 || notes[0].Content != "Continuing the truncated response (1 of 2)" {
+		t.Errorf("expected one continuation note, got %+v", notes)
+	}
+}
+
+func TestContinueStopsAtLimit(t *testing.T) {
+	c, sm, sess := runScriptedPrompt(t, func(e *Engine) {
+		e.MaxContinues = 1
+		e.ContinueText = "Go on."
+	}, scriptedTurn{text: "more ", stop: client.StopMaxTokens})
+	if got := lastResponse(t, sm, sess); got != "more more " {
+		t.Errorf("combined response = %q, want the original and one continuation", got)
+	}
+	if len(c.prompts) != 2 || c.prompts[1] != "Go on." {
+		t.Errorf("expected one custom continuation, got %q", c.prompts)
+	}
+}
+
+func TestContinueDisabledByDefault(t *testing.T) {
+	c, _, _ := runScriptedPrompt(t, func(*Engine) {}, scriptedTurn{text: "cut", stop: client.StopMaxTokens})
+	if len(c.prompts) != 1 {
+		t.Errorf("continuation should be off unless configured, got %d calls", len(c.prompts))
+	}
+}
+
+func TestContinueLimitSkillOverride(t *testing.T) {
+	e := &Engine{MaxContinues: 3}
+	for _, tc := range []struct {
+		skill *models.SkillGraph
+		want  int
+	}{
+		{nil, 3},
+		{&models.SkillGraph{}, 3},
+		{&models.SkillGraph{MaxContinues: 5}, 5},
+		{&models.SkillGraph{MaxContinues: -1}, 0},
+	} {
+		if got := e.continueLimit(tc.skill); got != tc.want {
+			t.Errorf("continueLimit(%+v) = %d, want %d", tc.skill, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestExecutePromptIgnoresBlankPrompts(t *testing.T) {
+	storageDir := t.TempDir()
+	marker := filepath.Join(storageDir, "invoked")
+	scriptPath := filepath.Join(storageDir, "client.sh")
+	os.WriteFile(scriptPath, []byte("#!/bin/sh\ntouch "+marker+"\necho '{\"type\": \"message\", \"content\": \"hi\"}'\n"), 0755)
+
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient(scriptPath, storageDir), "gemini", 5)
+
+	for _, mode := range []string{models.PromptModeInterrupt, models.PromptModeQueue} {
+		sess := &models.Session{ID: "blank-" + mode, CWD: storageDir, PromptMode: mode, Summary: "Keep me", RoleCache: make(map[string]string)}
+		sm.Save(sess)
+
+		for _, prompt := range []string{"", "   ", "\n\t "} {
+			eng.ExecutePrompt(sess, prompt)
+		}
+
+		if _, err := os.Stat(marker); err == nil {
+			t.Fatalf("%s: client was invoked for a blank prompt", mode)
+		}
+		if sess.Summary != "Keep me" {
+			t.Errorf("%s: summary clobbered: %q", mode, sess.Summary)
+		}
+		prompts, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool { return e.Type == events.AuditLLMPrompt })
+		if len(prompts) != 0 {
+			t.Errorf("%s: expected no prompt audit entries, got %d", mode, len(prompts))
+		}
+	}
+}
+
+func TestExecutePromptTrimsSummary(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	sess := &models.Session{ID: "trim", CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	eng.ExecutePrompt(sess, "  \n fix the login bug \n")
+
+	if sess.Summary != "fix the login bug" {
+		t.Errorf("Summary = %q, want trimmed prompt", sess.Summary)
+	}
+}
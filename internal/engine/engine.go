@@ -14,21 +14,72 @@ import (
 	"tenazas/internal/events"
 	"tenazas/internal/models"
 	"tenazas/internal/session"
+	"tenazas/internal/skill"
 )
 
 const resumeSentinel = "Session resumed. Please continue from where you left off."
 
 type Engine struct {
-	Sm            *session.Manager
-	Clients       map[string]client.Client
-	DefaultClient string
-	MaxLoops      int
-	OnPermission  func(client.PermissionRequest) client.PermissionResponse // set by CLI/Telegram for interactive prompts
+	Sm                *session.Manager
+	Clients           map[string]client.Client
+	DefaultClient     string
+	MaxLoops          int
+	OnPermission      func(client.PermissionRequest) client.PermissionResponse // set by CLI/Telegram for interactive prompts
+	IdleTimeout       time.Duration                                            // park a silent Run after this long; 0 disables
+	PromptMode        string                                                   // default for sessions without one; see models.PromptMode*
+	MaxPromptChars    int                                                      // longest prompt sent to a client, in characters; 0 means unlimited
+	PromptLimitPolicy string                                                   // PromptLimitTruncate (default) or PromptLimitReject
+	SlowLLMThreshold  time.Duration                                            // warn when one LLM call takes longer; 0 disables the warning
+	// ChunkFlushInterval and ChunkFlushBytes batch streamed text into fewer
+	// audit entries: chunks still reach listeners as they arrive, but are
+	// written once the pending text is this old or this long. Both 0 writes
+	// every chunk.
+	ChunkFlushInterval time.Duration
+	ChunkFlushBytes    int
+	// EscalateAfter publishes an EventEscalation once an intervention has
+	// waited this long without an answer; 0 disables escalation.
+	EscalateAfter time.Duration
+	// StuckRepeatLimit raises an intervention once a verification fails with
+	// identical output this many times in a row, instead of spending the rest
+	// of the loop budget; 0 disables the check.
+	StuckRepeatLimit int
+	// CompletionWebhook, when set, is POSTed a WebhookPayload whenever a
+	// skill run completes, fails or is stopped.
+	CompletionWebhook *Webhook
+	// AllowedDirs maps a client name to the directories its sessions may
+	// run in (and below); clients without an entry are unrestricted.
+	AllowedDirs map[string][]string
+	// MaxConcurrentSessions caps how many skill runs and prompts execute at
+	// once; the rest wait for a free slot. 0 means unlimited.
+	MaxConcurrentSessions int
+	// MaxContinues re-prompts a response cut off at max_tokens with
+	// ContinueText (default "Continue.") up to this many times, joining the
+	// pieces; 0 disables it. A skill's max_continues overrides it.
+	MaxContinues  int
+	ContinueText  string
 	intervs       map[string]chan string
 	intervsMux    sync.RWMutex
 	running       sync.Map
-	cancelFns     sync.Map // sessionID -> context.CancelFunc
-	sessionCtxs   sync.Map // sessionID -> context.Context
+	cancelFns     sync.Map      // sessionID -> context.CancelFunc
+	sessionCtxs   sync.Map      // sessionID -> context.Context
+	calls         sync.Map      // sessionID -> *inflightCall for the callLLM in flight
+	activity      sync.Map      // sessionID -> time.Time of last log/chunk
+	awaiting      sync.Map      // sessionID -> true while blocked on an intervention
+	working       sync.Map      // sessionID -> true during silent work: a shell command or a wait for a run slot
+	idleParked    sync.Map      // sessionID -> true once the idle watchdog fired
+	stopped       sync.Map      // sessionID -> reason, once StopAll cancelled the run
+	runs          sync.Map      // sessionID -> runInfo of the active Run
+	traceRequests sync.Map      // sessionID -> true when the next Run should be traced
+	traces        sync.Map      // sessionID -> *TraceWriter for the active Run
+	promptQueues  sync.Map      // sessionID -> *promptQueue
+	skillChains   sync.Map      // sessionID -> *skillChain
+	failures      sync.Map      // sessionID -> category of the latest failed command
+	streaks       sync.Map      // sessionID -> *failureStreak of identical verification failures
+	chunkBatches  sync.Map      // sessionID -> *chunkBatch of streamed text not yet written
+	slots         chan struct{} // run slots when MaxConcurrentSessions > 0; see acquireSlot
+	slotsOnce     sync.Once
+	waitPoll      time.Duration  // poll interval for wait states without poll_interval_sec; 0 means defaultWaitPoll
+	counters      engineCounters // activity totals behind Metrics
 }
 
 func NewEngine(sm *session.Manager, clients map[string]client.Client, defaultClient string, maxLoops int) *Engine {
@@ -60,11 +111,26 @@ func (e *Engine) resolveClient(sess *models.Session) client.Client {
 	return nil
 }
 
+// AgentCommands returns the commands advertised by the session's client.
+func (e *Engine) AgentCommands(sess *models.Session) []string {
+	if c := e.resolveClient(sess); c != nil {
+		return c.AvailableCommands()
+	}
+	return nil
+}
+
 func (e *Engine) IsRunning(sessionID string) bool {
 	_, ok := e.running.Load(sessionID)
 	return ok
 }
 
+// AwaitingIntervention reports whether the session's run is blocked waiting
+// for ResolveIntervention.
+func (e *Engine) AwaitingIntervention(sessionID string) bool {
+	_, ok := e.awaiting.Load(sessionID)
+	return ok
+}
+
 func (e *Engine) CancelSession(sessionID string) {
 	if fn, ok := e.cancelFns.Load(sessionID); ok {
 		fn.(context.CancelFunc)()
@@ -72,11 +138,26 @@ func (e *Engine) CancelSession(sessionID string) {
 }
 
 func (e *Engine) Run(skill *models.SkillGraph, sess *models.Session) {
-	if e.IsRunning(sess.ID) {
+	if _, busy := e.running.LoadOrStore(sess.ID, true); busy {
 		return
 	}
-	e.running.Store(sess.ID, true)
-	defer e.running.Delete(sess.ID)
+	defer e.releaseRun(sess)
+	e.run(skill, sess)
+}
+
+// run executes skill on sess. The caller holds the session's running entry.
+func (e *Engine) run(skill *models.SkillGraph, sess *models.Session) {
+	e.stopped.Delete(sess.ID)
+	e.runs.Store(sess.ID, runInfo{start: time.Now(), skill: skill.Name})
+	defer e.runs.Delete(sess.ID)
+	if !e.cwdAllowed(sess) || !e.canFinish(skill, sess) || !e.toolsReady(skill, sess) {
+		return
+	}
+	slot, ok := e.acquireSlot(sess)
+	if !ok {
+		return
+	}
+	defer slot.release()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	e.cancelFns.Store(sess.ID, cancel)
@@ -86,6 +167,23 @@ func (e *Engine) Run(skill *models.SkillGraph, sess *models.Session) {
 		e.cancelFns.Delete(sess.ID)
 		e.sessionCtxs.Delete(sess.ID)
 	}()
+	stopWatchdog := e.startIdleWatchdog(sess.ID, cancel)
+	defer e.parkIfIdle(sess)
+	defer e.markStopped(sess)
+	defer stopWatchdog()
+
+	var tr *TraceWriter
+	if _, ok := e.traceRequests.LoadAndDelete(sess.ID); ok {
+		tr = NewTraceWriter(e.Sm.ArtifactPath(sess, TraceFileName), sess.ID, skill.Name)
+		tr.SetIncludes(skill.IncludeSources)
+		e.traces.Store(sess.ID, tr)
+		defer func() {
+			e.traces.Delete(sess.ID)
+			if err := tr.Finish(sess.Status); err != nil {
+				e.log(sess, events.AuditInfo, "engine", "Could not write run trace: "+err.Error(), events.RoleSystem)
+			}
+		}()
+	}
 
 	e.publishTaskStatus(sess.ID, events.TaskStateStarted, nil)
 	e.initializeExecution(skill, sess)
@@ -98,39 +196,69 @@ func (e *Engine) Run(skill *models.SkillGraph, sess *models.Session) {
 		}
 
 		if state.Type == "end" {
+			tr.Enter(sess.ActiveNode, state.Type)
 			e.terminate(sess, models.StatusCompleted, "Skill completed successfully")
 			break
 		}
 
 		if sess.Status == models.StatusIntervention {
+			// A run waiting on a human must not hold a slot: the prompt
+			// that answers it may need one.
+			slot.release()
 			e.awaitIntervention(skill, &state, sess)
+			if !e.retakeSlot(ctx, sess, slot) {
+				break
+			}
 			if sess.Status != models.StatusRunning {
 				continue
 			}
 		}
 
+		node := sess.ActiveNode
+		state.SessionRole = state.ResolvedRole(node)
+		tr.Enter(node, state.Type)
 		switch state.Type {
 		case "action_loop":
 			e.executeActionLoop(skill, &state, sess)
 		case "tool":
 			e.executeTool(&state, sess)
+		case "wait":
+			e.executeWait(&state, sess)
 		default:
 			e.terminate(sess, models.StatusFailed, "Unknown state type: "+state.Type)
 		}
+		tr.Leave(sess.ActiveNode)
 	}
 }
 
-func (e *Engine) initializeExecution(skill *models.SkillGraph, sess *models.Session) {
+func (e *Engine) initializeExecution(sk *models.SkillGraph, sess *models.Session) {
 	if sess.ActiveNode == "" {
-		sess.ActiveNode = skill.InitialState
-		sess.Status = models.StatusRunning
-		sess.LoopCount = 0
-		e.Sm.Save(sess)
-		e.log(sess, events.AuditStatus, "engine", fmt.Sprintf("Started skill %s at node %s", skill.Name, sess.ActiveNode), events.RoleSystem)
+		e.Sm.Update(sess, func(s *models.Session) {
+			s.ActiveNode = sk.InitialState
+			s.Status = models.StatusRunning
+			s.LoopCount = 0
+			s.LastGoodNode = ""
+			s.AddTags(sk.Tags)
+		})
+		e.log(sess, events.AuditStatus, "engine", fmt.Sprintf("Started skill %s at node %s", sk.Name, sess.ActiveNode), events.RoleSystem)
+		warnings := skill.Validate(sk)
+		problems, _ := skill.CheckTermination(sk)
+		for _, w := range append(warnings, problems...) {
+			e.log(sess, events.AuditInfo, "engine", "Skill warning: "+w, events.RoleSystem)
+		}
 	} else if sess.Status == models.StatusRunning && sess.PendingFeedback == "" {
-		sess.PendingFeedback = resumeSentinel
-		e.Sm.Save(sess)
+		e.Sm.Update(sess, func(s *models.Session) { s.PendingFeedback = resumeSentinel })
+	}
+}
+
+// canFinish refuses to run a skill whose graph has no reachable end state,
+// which could only stop on MaxLoops or an error.
+func (e *Engine) canFinish(sk *models.SkillGraph, sess *models.Session) bool {
+	if _, err := skill.CheckTermination(sk); err != nil {
+		e.terminate(sess, models.StatusFailed, fmt.Sprintf("Refusing to run skill %s: %v", sk.Name, err))
+		return false
 	}
+	return true
 }
 
 func (e *Engine) shouldContinue(sess *models.Session) bool {
@@ -143,41 +271,62 @@ func (e *Engine) shouldContinue(sess *models.Session) bool {
 }
 
 func (e *Engine) terminate(sess *models.Session, status, reason string) {
-	sess.Status = status
+	e.Sm.Update(sess, func(s *models.Session) { s.Status = status })
 	e.log(sess, events.AuditStatus, "engine", fmt.Sprintf("Status: %s - %s", status, reason), events.RoleSystem)
-	e.Sm.Save(sess)
 
 	state := events.TaskStateCompleted
 	if status == models.StatusFailed {
 		state = events.TaskStateFailed
 	}
 	e.publishTaskStatus(sess.ID, state, map[string]string{"reason": reason})
+	e.notifyWebhook(sess, status, reason)
 }
 
 func (e *Engine) awaitIntervention(skill *models.SkillGraph, state *models.StateDef, sess *models.Session) {
-	e.log(sess, events.AuditIntervention, "engine", fmt.Sprintf("Waiting for intervention at %s", sess.ActiveNode), events.RoleSystem)
+	msg := fmt.Sprintf("Waiting for intervention at %s", sess.ActiveNode)
+	category := e.failureCategory(sess.ID)
+	if category != "" {
+		msg += " [" + category + "]"
+	}
+	e.log(sess, events.AuditIntervention, "engine", msg, events.RoleSystem)
 
-	e.publishTaskStatus(sess.ID, events.TaskStateBlocked, map[string]string{
+	details := map[string]string{
 		"node":        sess.ActiveNode,
 		"instruction": state.Instruction,
 		"reason":      sess.PendingFeedback,
-	})
+	}
+	if category != "" {
+		details["category"] = category
+	}
+	e.publishTaskStatus(sess.ID, events.TaskStateBlocked, details)
+	e.counters.interventions.Add(1)
 
+	e.awaiting.Store(sess.ID, true)
+	stopEscalation := e.scheduleEscalation(sess, details)
 	action := <-e.getInterventionChan(sess.ID)
+	stopEscalation()
+	e.awaiting.Delete(sess.ID)
+	e.streaks.Delete(sess.ID)
+	e.touch(sess.ID)
 
 	switch action {
 	case "retry":
-		sess.RetryCount = 0
-		sess.Status = models.StatusRunning
+		e.Sm.Update(sess, func(s *models.Session) {
+			s.RetryCount = 0
+			s.Status = models.StatusRunning
+		})
 	case "proceed_to_fail":
-		sess.RetryCount = 0
-		sess.LoopCount = 0
-		sess.Status = models.StatusRunning
+		e.Sm.Update(sess, func(s *models.Session) {
+			s.RetryCount = 0
+			s.LoopCount = 0
+			s.Status = models.StatusRunning
+		})
 		e.transitionToFailRoute(skill, state, sess, "User manually triggered fail route")
 	case "abort":
-		sess.Status = models.StatusFailed
+		e.terminate(sess, models.StatusFailed, "Aborted at intervention")
+	default:
+		e.Sm.Save(sess)
 	}
-	e.Sm.Save(sess)
 }
 
 func (e *Engine) publishTaskStatus(sessID string, state string, details map[string]string) {
@@ -193,8 +342,9 @@ func (e *Engine) publishTaskStatus(sessID string, state string, details map[stri
 
 func (e *Engine) executeActionLoop(skill *models.SkillGraph, state *models.StateDef, sess *models.Session) {
 	if state.PreActionCmd != "" && sess.RetryCount == 0 {
-		if exitCode, output := e.RunShell(state.PreActionCmd, sess.CWD); exitCode != 0 {
+		if exitCode, output := e.runShell(sess, state.PreActionCmd); exitCode != 0 {
 			e.logCmd(sess, "engine", fmt.Sprintf("pre_action_cmd failed (Exit Code: %d): %s", exitCode, output), exitCode)
+			e.recordFailure(sess.ID, exitCode, output)
 			e.handleRetry(state, sess, fmt.Sprintf("Pre-action command failed (Exit Code: %d):\n%s", exitCode, output))
 			return
 		}
@@ -205,7 +355,7 @@ func (e *Engine) executeActionLoop(skill *models.SkillGraph, state *models.State
 		e.handleRetry(state, sess, "Client execution error: "+err.Error())
 		return
 	}
-	sess.PendingFeedback = ""
+	e.Sm.Update(sess, func(s *models.Session) { s.PendingFeedback = "" })
 	e.log(sess, events.AuditLLMResponse, state.SessionRole, response, events.RoleAssistant)
 
 	if state.VerifyCmd == "" {
@@ -213,7 +363,7 @@ func (e *Engine) executeActionLoop(skill *models.SkillGraph, state *models.State
 		return
 	}
 
-	exitCode, output := e.RunShell(state.VerifyCmd, sess.CWD)
+	exitCode, output := e.runShell(sess, state.VerifyCmd)
 	e.logCmd(sess, "engine", fmt.Sprintf("Verification Result (Exit Code: %d):\n%s", exitCode, output), exitCode)
 
 	if exitCode == 0 {
@@ -225,47 +375,63 @@ func (e *Engine) executeActionLoop(skill *models.SkillGraph, state *models.State
 
 func (e *Engine) executeTool(state *models.StateDef, sess *models.Session) {
 	e.log(sess, events.AuditInfo, "engine", "Executing tool: "+state.Command, events.RoleSystem)
-	exitCode, out := e.RunShell(state.Command, sess.CWD)
+	exitCode, out := e.runShell(sess, state.Command)
 	e.logCmd(sess, "engine", fmt.Sprintf("Exit Code: %d\nOutput: %s", exitCode, out), exitCode)
 
-	sess.RetryCount = 0
-	sess.PendingFeedback = out
-
-	if exitCode == 0 {
-		sess.ActiveNode = state.Next
-	} else if state.OnFailRoute != "" {
-		sess.ActiveNode = state.OnFailRoute
-	} else {
-		e.terminate(sess, models.StatusFailed, fmt.Sprintf("Tool failed (Exit Code: %d): %s", exitCode, out))
+	if exitCode != 0 && state.OnFailRoute == "" {
+		e.Sm.Update(sess, func(s *models.Session) {
+			s.RetryCount = 0
+			s.PendingFeedback = out
+		})
+		e.terminate(sess, models.StatusFailed, fmt.Sprintf("Tool failed (Exit Code: %d) [%s]: %s", exitCode, classifyFailure(exitCode, out), out))
 		return
 	}
-	e.Sm.Save(sess)
+	e.Sm.Update(sess, func(s *models.Session) {
+		s.RetryCount = 0
+		s.PendingFeedback = out
+		if exitCode == 0 {
+			s.LastGoodNode = s.ActiveNode
+			s.ActiveNode = state.Next
+		} else {
+			s.ActiveNode = state.OnFailRoute
+		}
+	})
 }
 
 func (e *Engine) callLLM(skill *models.SkillGraph, state *models.StateDef, sess *models.Session) (string, error) {
-	prompt := e.BuildPrompt(state, sess)
+	prompt, ok := e.limitPrompt(sess, e.BuildPrompt(state, sess))
+	if !ok {
+		return "", fmt.Errorf("prompt exceeds max_prompt_chars (%d)", e.MaxPromptChars)
+	}
 
+	// The CLI and Telegram change these settings while the run goes on.
+	settings := e.Sm.Snapshot(sess)
 	roleID := sess.RoleCache[state.SessionRole]
 	approvalMode := state.ApprovalMode
 	if approvalMode == "" {
-		approvalMode = sess.ApprovalMode
+		approvalMode = settings.ApprovalMode
 	}
 	modelTier := state.ModelTier
 	if modelTier == "" {
-		modelTier = sess.ModelTier
+		modelTier = settings.ModelTier
 	}
 
 	// Resolve the concrete model name for logging.
-	c := e.resolveClient(sess)
+	c := e.resolveClient(settings)
 	modelName := ""
 	if c != nil {
 		modelName = c.ResolveModel(modelTier)
 	}
 
+	// The resume prompt is the engine's doing, not the user's.
+	promptType, promptRole := events.AuditLLMPrompt, events.RoleUser
+	if sess.PendingFeedback == resumeSentinel {
+		promptType, promptRole = events.AuditResume, events.RoleSystem
+	}
 	e.Sm.AppendAudit(sess, events.AuditEntry{
-		Type:      events.AuditLLMPrompt,
+		Type:      promptType,
 		Source:    state.SessionRole,
-		Role:      events.RoleUser,
+		Role:      promptRole,
 		Step:      stepTag(sess),
 		ModelTier: modelTier,
 		Model:     modelName,
@@ -273,12 +439,12 @@ func (e *Engine) callLLM(skill *models.SkillGraph, state *models.StateDef, sess
 	})
 
 	// Skill-level budget overrides session-level.
-	budget := sess.MaxBudgetUSD
+	budget := settings.MaxBudgetUSD
 	if skill != nil && skill.MaxBudgetUSD > 0 {
 		budget = skill.MaxBudgetUSD
 	}
 
-	yolo := sess.Yolo || strings.EqualFold(approvalMode, models.ApprovalModeYolo)
+	yolo := settings.Yolo || strings.EqualFold(approvalMode, models.ApprovalModeYolo)
 
 	opts := client.RunOptions{
 		NativeSID:    roleID,
@@ -288,8 +454,10 @@ func (e *Engine) callLLM(skill *models.SkillGraph, state *models.StateDef, sess
 		Yolo:         yolo,
 		ModelTier:    modelTier,
 		MaxBudgetUSD: budget,
-		OnThought: func(t string) { e.log(sess, events.AuditLLMThought, state.SessionRole, t, events.RoleAssistant) },
-		OnIntent:  func(text string) { e.log(sess, events.AuditIntent, state.SessionRole, text, events.RoleAssistant) },
+		SystemPrompt: settings.SystemPrompt,
+		OnThought:    func(t string) { e.log(sess, events.AuditLLMThought, state.SessionRole, t, events.RoleAssistant) },
+		OnIntent:     func(text string) { e.log(sess, events.AuditIntent, state.SessionRole, text, events.RoleAssistant) },
+		OnStopReason: func(reason string) { e.logStopReason(sess, state.SessionRole, reason) },
 		OnToolEvent: func(name, status, detail string) {
 			msg := name
 			if status != "" {
@@ -304,34 +472,38 @@ func (e *Engine) callLLM(skill *models.SkillGraph, state *models.StateDef, sess
 	if !yolo && e.OnPermission != nil {
 		opts.OnPermission = e.OnPermission
 	}
-	if v, ok := e.sessionCtxs.Load(sess.ID); ok {
-		opts.Ctx = v.(context.Context)
-	}
+	ctx, finish := e.startCall(sess, state.SessionRole, sess.ActiveNode)
+	opts.Ctx = ctx
 
 	onChunk := e.OnChunk(sess, state)
-	resp, err := c.Run(opts, onChunk, e.onSID(sess, state))
+	start := time.Now()
+	resp, err := e.runContinued(sess, c, opts, e.continueLimit(skill), onChunk, e.onSID(sess, state))
 	onChunk("")
+	e.recordLatency(sess, state.SessionRole, sess.ActiveNode, time.Since(start))
+	if finish() {
+		e.log(sess, events.AuditInfo, "engine", fmt.Sprintf("Cancelled the %s call at %s", state.SessionRole, sess.ActiveNode), events.RoleSystem)
+		return "", errCallCancelled
+	}
 	return resp, err
 }
 
 func (e *Engine) handleRetry(state *models.StateDef, sess *models.Session, feedback string) {
-	sess.RetryCount++
-	if sess.PendingFeedback != "" && !strings.Contains(sess.PendingFeedback, feedback) {
-		sess.PendingFeedback = sess.PendingFeedback + "\n\nAdditional Error: " + feedback
-	} else {
-		sess.PendingFeedback = feedback
-	}
+	e.Sm.Update(sess, func(s *models.Session) {
+		s.RetryCount++
+		if s.PendingFeedback != "" && !strings.Contains(s.PendingFeedback, feedback) {
+			s.PendingFeedback = s.PendingFeedback + "\n\nAdditional Error: " + feedback
+		} else {
+			s.PendingFeedback = feedback
+		}
 
-	if state.MaxRetries > 0 && sess.RetryCount >= state.MaxRetries {
-		sess.Status = models.StatusIntervention
-	}
-	e.Sm.Save(sess)
+		if state.MaxRetries > 0 && s.RetryCount >= state.MaxRetries {
+			s.Status = models.StatusIntervention
+		}
+	})
 }
 
 func (e *Engine) handleLoopFailure(skill *models.SkillGraph, state *models.StateDef, sess *models.Session, exitCode int, output string) {
-	sess.LoopCount++
-	sess.RetryCount++
-
+	e.recordFailure(sess.ID, exitCode, output)
 	feedback := state.OnFailPrompt
 	if feedback == "" {
 		feedback = "Command failed with exit code {{exit_code}}.\n\nOutput:\n{{output}}"
@@ -346,40 +518,61 @@ func (e *Engine) handleLoopFailure(skill *models.SkillGraph, state *models.State
 		limit = skill.MaxLoops
 	}
 
-	if sess.LoopCount >= limit {
-		sess.PendingFeedback = feedback
-		sess.Status = models.StatusIntervention
-	} else if state.MaxRetries > 0 && sess.RetryCount <= state.MaxRetries {
-		// Retry the same state with feedback before following fail_route
-		sess.PendingFeedback = feedback
-	} else {
+	repeats := e.repeatFailure(sess.ID, exitCode, output)
+	stuck := e.stuck(repeats)
+	if stuck {
+		e.failures.Store(sess.ID, FailureNoProgress)
+		e.log(sess, events.AuditInfo, "engine", fmt.Sprintf("No progress: the same failure repeated %d times in a row", repeats), events.RoleSystem)
+	}
+
+	followRoute := false
+	e.Sm.Update(sess, func(s *models.Session) {
+		s.LoopCount++
+		s.RetryCount++
+		if s.LoopCount >= limit || stuck {
+			s.PendingFeedback = feedback
+			s.Status = models.StatusIntervention
+		} else if state.MaxRetries > 0 && s.RetryCount <= state.MaxRetries {
+			// Retry the same state with feedback before following fail_route
+			s.PendingFeedback = feedback
+		} else {
+			followRoute = true
+		}
+	})
+	if followRoute {
 		e.transitionToFailRoute(skill, state, sess, feedback)
 	}
-	e.Sm.Save(sess)
 }
 
 func (e *Engine) transitionToFailRoute(skill *models.SkillGraph, state *models.StateDef, sess *models.Session, feedback string) {
 	if state.OnFailRoute == "" {
-		sess.Status = models.StatusIntervention
-		sess.PendingFeedback = feedback
-		e.Sm.Save(sess)
+		e.Sm.Update(sess, func(s *models.Session) {
+			s.Status = models.StatusIntervention
+			s.PendingFeedback = feedback
+		})
 		return
 	}
 	e.log(sess, events.AuditInfo, "engine", fmt.Sprintf("Fail route: %s (Loop %d)", state.OnFailRoute, sess.LoopCount), events.RoleSystem)
-	sess.PendingFeedback = feedback
-	sess.ActiveNode = state.OnFailRoute
-	sess.RetryCount = 0
+	e.Sm.Update(sess, func(s *models.Session) {
+		s.PendingFeedback = feedback
+		s.ActiveNode = state.OnFailRoute
+		s.RetryCount = 0
+	})
 }
 
 func (e *Engine) completeState(state *models.StateDef, sess *models.Session, output string) {
 	if state.PostActionCmd != "" {
-		e.RunShell(state.PostActionCmd, sess.CWD)
-	}
-	sess.RetryCount = 0
-	sess.LoopCount = 0
-	sess.PendingFeedback = output
-	sess.ActiveNode = state.Next
-	e.Sm.Save(sess)
+		e.runShell(sess, state.PostActionCmd)
+	}
+	e.failures.Delete(sess.ID)
+	e.streaks.Delete(sess.ID)
+	e.Sm.Update(sess, func(s *models.Session) {
+		s.RetryCount = 0
+		s.LoopCount = 0
+		s.PendingFeedback = output
+		s.LastGoodNode = s.ActiveNode
+		s.ActiveNode = state.Next
+	})
 }
 
 func (e *Engine) BuildPrompt(state *models.StateDef, sess *models.Session) string {
@@ -399,16 +592,21 @@ func (e *Engine) OnChunk(sess *models.Session, state *models.StateDef) func(stri
 	parser := &ThoughtParser{
 		OnThought: func(t string) { e.log(sess, events.AuditLLMThought, state.SessionRole, t, events.RoleAssistant) },
 		OnText: func(t string) {
-			e.Sm.AppendAudit(sess, events.AuditEntry{Type: events.AuditLLMChunk, Source: state.SessionRole, Role: events.RoleAssistant, Step: stepTag(sess), Content: t})
+			e.touch(sess.ID)
+			e.appendChunk(sess, events.AuditEntry{Type: events.AuditLLMChunk, Source: state.SessionRole, Role: events.RoleAssistant, Step: stepTag(sess), Content: t})
 		},
 	}
-	return parser.Parse
+	return func(chunk string) {
+		parser.Parse(chunk)
+		if chunk == "" {
+			e.flushChunks(sess)
+		}
+	}
 }
 
 func (e *Engine) onSID(sess *models.Session, state *models.StateDef) func(string) {
 	return func(sid string) {
-		sess.RoleCache[state.SessionRole] = sid
-		e.Sm.Save(sess)
+		e.Sm.Update(sess, func(s *models.Session) { s.RoleCache[state.SessionRole] = sid })
 	}
 }
 
@@ -423,17 +621,26 @@ func (e *Engine) ExecuteCommand(sess *models.Session, cmd string) {
 		}
 	}
 	e.running.Store(sess.ID, true)
-	defer e.running.Delete(sess.ID)
+	defer e.releaseRun(sess)
 
 	e.resumeAndRun(sess, func() {
 		e.log(sess, events.AuditInfo, "user", fmt.Sprintf("User approved command: %s", cmd), events.RoleUser)
-		exitCode, output := e.RunShell(cmd, sess.CWD)
+		exitCode, output := e.runShell(sess, cmd)
 		e.logCmd(sess, "engine", fmt.Sprintf("Exit Code: %d\n%s", exitCode, output), exitCode)
-		e.executePromptInternal(sess, output)
+		e.executePromptInternal(sess, output, false)
 	})
 }
 
 func (e *Engine) ExecutePrompt(sess *models.Session, prompt string) {
+	// A stray Enter or whitespace-only message is a no-op, not an API call.
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return
+	}
+	if e.promptMode(sess) == models.PromptModeQueue {
+		e.enqueuePrompt(sess, prompt)
+		return
+	}
 	// Cancel any in-flight prompt for this session before starting a new one
 	if e.IsRunning(sess.ID) {
 		e.CancelSession(sess.ID)
@@ -445,19 +652,34 @@ func (e *Engine) ExecutePrompt(sess *models.Session, prompt string) {
 			time.Sleep(100 * time.Millisecond)
 		}
 	}
+	e.runPrompt(sess, prompt)
+}
+
+func (e *Engine) runPrompt(sess *models.Session, prompt string) {
 	e.running.Store(sess.ID, true)
-	defer e.running.Delete(sess.ID)
+	defer e.releaseRun(sess)
+	e.sendPrompt(sess, prompt)
+}
+
+// sendPrompt sends prompt on sess. The caller holds the session's running entry.
+func (e *Engine) sendPrompt(sess *models.Session, prompt string) {
+	e.stopped.Delete(sess.ID)
+	defer e.markStopped(sess)
+	slot, ok := e.acquireSlot(sess)
+	if !ok {
+		return
+	}
+	defer slot.release()
 
 	e.resumeAndRun(sess, func() {
-		e.executePromptInternal(sess, prompt)
+		e.executePromptInternal(sess, prompt, true)
 	})
 }
 
 func (e *Engine) resumeAndRun(sess *models.Session, f func()) {
 	wasIntervention := sess.Status == models.StatusIntervention
 	if sess.Status != models.StatusRunning {
-		sess.Status = models.StatusRunning
-		e.Sm.Save(sess)
+		e.Sm.Update(sess, func(s *models.Session) { s.Status = models.StatusRunning })
 	}
 
 	f()
@@ -467,21 +689,34 @@ func (e *Engine) resumeAndRun(sess *models.Session, f func()) {
 	}
 }
 
-func (e *Engine) executePromptInternal(sess *models.Session, prompt string) {
+// executePromptInternal sends prompt to the session's client. refs expands
+// @path references; only text the user typed gets it, never command output.
+func (e *Engine) executePromptInternal(sess *models.Session, prompt string, refs bool) {
 	// Auto-set summary from first user prompt
-	if sess.Summary == "" && prompt != "" {
-		summary := prompt
+	if sess.Summary == "" && strings.TrimSpace(prompt) != "" {
+		summary := strings.TrimSpace(prompt)
 		if len(summary) > 80 {
 			summary = summary[:77] + "..."
 		}
-		sess.Summary = summary
-		e.Sm.Save(sess)
+		e.Sm.Update(sess, func(s *models.Session) { s.Summary = summary })
 	}
 
-	c := e.resolveClient(sess)
+	if !e.promptCWDAllowed(sess) {
+		return
+	}
+	if refs {
+		prompt = e.expandFileRefs(sess, prompt)
+	}
+	prompt, ok := e.limitPrompt(sess, prompt)
+	if !ok {
+		return
+	}
+
+	settings := e.Sm.Snapshot(sess)
+	c := e.resolveClient(settings)
 	modelName := ""
 	if c != nil {
-		modelName = c.ResolveModel(sess.ModelTier)
+		modelName = c.ResolveModel(settings.ModelTier)
 	}
 
 	e.Sm.AppendAudit(sess, events.AuditEntry{
@@ -489,7 +724,7 @@ func (e *Engine) executePromptInternal(sess *models.Session, prompt string) {
 		Source:    "user",
 		Role:      events.RoleUser,
 		Step:      stepTag(sess),
-		ModelTier: sess.ModelTier,
+		ModelTier: settings.ModelTier,
 		Model:     modelName,
 		Content:   prompt,
 	})
@@ -508,11 +743,13 @@ func (e *Engine) executePromptInternal(sess *models.Session, prompt string) {
 		NativeSID:    sess.RoleCache["default"],
 		Prompt:       prompt,
 		CWD:          sess.CWD,
-		ApprovalMode: sess.ApprovalMode,
-		Yolo:         sess.Yolo,
-		ModelTier:    sess.ModelTier,
-		MaxBudgetUSD: sess.MaxBudgetUSD,
+		ApprovalMode: settings.ApprovalMode,
+		Yolo:         settings.Yolo,
+		ModelTier:    settings.ModelTier,
+		MaxBudgetUSD: settings.MaxBudgetUSD,
+		SystemPrompt: settings.SystemPrompt,
 		OnThought:    func(t string) { e.log(sess, events.AuditLLMThought, "default", t, events.RoleAssistant) },
+		OnStopReason: func(reason string) { e.logStopReason(sess, "default", reason) },
 		OnToolEvent: func(name, status, detail string) {
 			msg := name
 			if status != "" {
@@ -524,62 +761,66 @@ func (e *Engine) executePromptInternal(sess *models.Session, prompt string) {
 			e.log(sess, events.AuditCmdResult, "default", msg, events.RoleSystem)
 		},
 	}
-	if !sess.Yolo && e.OnPermission != nil {
+	if !settings.Yolo && e.OnPermission != nil {
 		opts.OnPermission = e.OnPermission
 	}
 
 	onChunk := e.OnChunk(sess, &models.StateDef{SessionRole: "default"})
-	resp, err := c.Run(opts, onChunk, func(newSID string) {
-		sess.RoleCache["default"] = newSID
-		e.Sm.Save(sess)
+	start := time.Now()
+	resp, err := e.runContinued(sess, c, opts, e.MaxContinues, onChunk, func(newSID string) {
+		e.Sm.Update(sess, func(s *models.Session) { s.RoleCache["default"] = newSID })
 	})
 	onChunk("")
+	e.recordLatency(sess, "default", "", time.Since(start))
 
 	if err != nil {
 		if ctx.Err() == context.Canceled {
 			e.log(sess, events.AuditInfo, "engine", "Operation cancelled by user", events.RoleSystem)
 		} else {
-			e.log(sess, events.AuditInfo, "engine", "LLM Error: "+err.Error(), events.RoleSystem)
+			e.logError(sess, "engine", "LLM Error: "+err.Error())
 		}
 	} else {
 		e.log(sess, events.AuditLLMResponse, "default", resp, events.RoleAssistant)
 	}
 }
 
+// ResolveInstruction returns instr, or for an @file include the file's
+// content without front matter. Includes are searched in the storage skills
+// directory, the session CWD, the storage root and then the configured
+// include paths.
 func (e *Engine) ResolveInstruction(instr, cwd string) string {
 	if !strings.HasPrefix(instr, "@") {
 		return instr
 	}
 
-	filename := strings.TrimPrefix(instr, "@")
-	fullPath, err := e.Sm.Storage.ResolveAssetPath(instr, e.Sm.Storage.BaseDir)
-	if err != nil {
+	st := e.Sm.Storage
+	roots := append([]string{filepath.Join(st.BaseDir, "skills"), cwd}, st.IncludeRoots(st.BaseDir, cwd)...)
+	path, err := st.FindInclude(instr, roots)
+	if err == os.ErrPermission {
 		return "Error: " + err.Error()
 	}
-
-	paths := []string{
-		filepath.Join(e.Sm.Storage.BaseDir, "skills", filename),
-		filepath.Join(cwd, filename),
-		fullPath,
+	var data []byte
+	if err == nil {
+		data, err = os.ReadFile(path)
 	}
-
-	for _, path := range paths {
-		if data, err := os.ReadFile(path); err == nil {
-			content := string(data)
-			if strings.HasPrefix(content, "---") {
-				parts := strings.SplitN(content, "---", 3)
-				if len(parts) == 3 {
-					content = strings.TrimSpace(parts[2])
-				}
-			}
-			return content
+	if err != nil {
+		return "Error: Could not load instruction file " + strings.TrimPrefix(instr, "@")
+	}
+	content := string(data)
+	if strings.HasPrefix(content, "---") {
+		parts := strings.SplitN(content, "---", 3)
+		if len(parts) == 3 {
+			content = strings.TrimSpace(parts[2])
 		}
 	}
-
-	return "Error: Could not load instruction file " + filename
+	return content
 }
 
 func (e *Engine) RunShell(cmdStr, cwd string) (int, string) {
+	e.counters.shellCommands.Add(1)
+	if !e.shellAllowed(cwd) {
+		return 126, fmt.Sprintf("Error: refusing to run a command in %s: outside every client's allowed_dirs", cwd)
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -608,6 +849,8 @@ func (e *Engine) RunShell(cmdStr, cwd string) (int, string) {
 }
 
 func (e *Engine) log(sess *models.Session, eventType, source, content, role string) {
+	e.touch(sess.ID)
+	e.flushChunks(sess)
 	e.Sm.AppendAudit(sess, events.AuditEntry{
 		Type:    eventType,
 		Source:  source,
@@ -617,7 +860,24 @@ func (e *Engine) log(sess *models.Session, eventType, source, content, role stri
 	})
 }
 
+// logError logs content as an info entry flagged as an error.
+func (e *Engine) logError(sess *models.Session, source, content string) {
+	e.touch(sess.ID)
+	e.flushChunks(sess)
+	e.Sm.AppendAudit(sess, events.AuditEntry{
+		Type:    events.AuditInfo,
+		Source:  source,
+		Role:    events.RoleSystem,
+		Step:    stepTag(sess),
+		Content: content,
+		Error:   true,
+	})
+}
+
 func (e *Engine) logCmd(sess *models.Session, source, content string, exitCode int) {
+	e.touch(sess.ID)
+	e.traceFor(sess.ID).RecordExit(exitCode)
+	e.flushChunks(sess)
 	e.Sm.AppendAudit(sess, events.AuditEntry{
 		Type:     events.AuditCmdResult,
 		Source:   source,
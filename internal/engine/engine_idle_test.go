@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestIdleTimeoutParksSilentSession(t *testing.T) {
+	storageDir, _ := os.MkdirTemp("", "tenazas-engine-idle-*")
+	defer os.RemoveAll(storageDir)
+
+	// A client binary that hangs without producing any output.
+	hang := filepath.Join(storageDir, "hang.sh")
+	os.WriteFile(hang, []byte("#!/bin/sh\nexec sleep 30\n"), 0755)
+
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient(hang, storageDir), "gemini", 5)
+	eng.IdleTimeout = 200 * time.Millisecond
+
+	skill := &models.SkillGraph{
+		Name:         "idle-skill",
+		InitialState: "work",
+		States: map[string]models.StateDef{
+			"work": {Type: "action_loop", SessionRole: "coder", Instruction: "do it", Next: "end"},
+			"end":  {Type: "end"},
+		},
+	}
+	sess := &models.Session{ID: "idle-sess", CWD: storageDir, SkillName: "idle-skill", RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	done := make(chan struct{})
+	go func() {
+		eng.Run(skill, sess)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected silent session to be parked by the idle watchdog")
+	}
+
+	if sess.Status != models.StatusIntervention {
+		t.Errorf("expected status %s, got %s", models.StatusIntervention, sess.Status)
+	}
+	if !strings.Contains(sess.PendingFeedback, "No activity") {
+		t.Errorf("expected no-activity reason, got %q", sess.PendingFeedback)
+	}
+	if eng.IsRunning(sess.ID) {
+		t.Error("expected session to no longer be running")
+	}
+}
+
+func TestIdleTimeoutKeepsActiveSession(t *testing.T) {
+	storageDir, _ := os.MkdirTemp("", "tenazas-engine-active-*")
+	defer os.RemoveAll(storageDir)
+
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	eng.IdleTimeout = 400 * time.Millisecond
+
+	// Each step is shorter than the timeout, but the whole run is longer.
+	skill := &models.SkillGraph{
+		Name:         "busy-skill",
+		InitialState: "s1",
+		States: map[string]models.StateDef{
+			"s1":  {Type: "tool", Command: "sleep 0.15", Next: "s2"},
+			"s2":  {Type: "tool", Command: "sleep 0.15", Next: "s3"},
+			"s3":  {Type: "tool", Command: "sleep 0.15", Next: "s4"},
+			"s4":  {Type: "tool", Command: "sleep 0.15", Next: "end"},
+			"end": {Type: "end"},
+		},
+	}
+	sess := &models.Session{ID: "active-sess", CWD: storageDir, SkillName: "busy-skill", RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	eng.Run(skill, sess)
+
+	if sess.Status != models.StatusCompleted {
+		t.Errorf("expected active session to complete, got %s (%s)", sess.Status, sess.PendingFeedback)
+	}
+}
+
+func TestIdleTimeoutSparesSlowSilentTool(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	eng.IdleTimeout = 200 * time.Millisecond
+
+	// The tool prints nothing for several timeouts, but is still working.
+	skill := &models.SkillGraph{
+		Name:         "slow-tool",
+		InitialState: "build",
+		States: map[string]models.StateDef{
+			"build": {Type: "tool", Command: "sleep 0.8", Next: "end"},
+			"end":   {Type: "end"},
+		},
+	}
+	sess := &models.Session{ID: "slow-tool-sess", CWD: storageDir, SkillName: "slow-tool", RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	eng.Run(skill, sess)
+
+	if sess.Status != models.StatusCompleted {
+		t.Errorf("expected a slow silent tool to finish, got %s (%s)", sess.Status, sess.PendingFeedback)
+	}
+}
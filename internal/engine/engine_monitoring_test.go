@@ -88,8 +88,10 @@ func TestEngineMonitoringBlockedEvent(t *testing.T) {
 				Instruction: "fail",
 				VerifyCmd:   "false", // Always fails
 				MaxRetries:  1,
+				Next:        "done",
 				// No OnFailRoute means it will hit intervention/blocked
 			},
+			"done": {Type: "end"},
 		},
 	}
 
@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestStatesWithoutRoleGetDistinctCacheKeys(t *testing.T) {
+	storageDir, _ := os.MkdirTemp("", "tenazas-engine-roles-*")
+	defer os.RemoveAll(storageDir)
+
+	sm := session.NewManager(storageDir)
+
+	// Each invocation reports a distinct native session ID.
+	script := `#!/bin/bash
+echo "{\"type\": \"init\", \"session_id\": \"sid-$$\"}"
+echo '{"type": "message", "content": "ok"}'
+`
+	scriptPath := filepath.Join(storageDir, "roles.sh")
+	os.WriteFile(scriptPath, []byte(script), 0755)
+
+	eng := NewEngine(sm, newTestClient(scriptPath, storageDir), "gemini", 2)
+	skill := &models.SkillGraph{
+		Name:         "roles-skill",
+		InitialState: "plan",
+		States: map[string]models.StateDef{
+			"plan": {Type: "action_loop", Instruction: "plan it", Next: "code"},
+			"code": {Type: "action_loop", Instruction: "code it", Next: "done"},
+			"done": {Type: "end"},
+		},
+	}
+	sess := &models.Session{ID: "roles-sess", CWD: storageDir, SkillName: "roles-skill", RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	eng.Run(skill, sess)
+
+	if sess.Status != models.StatusCompleted {
+		t.Fatalf("expected completed, got %s", sess.Status)
+	}
+	if _, ok := sess.RoleCache[""]; ok {
+		t.Error("expected no empty-string role cache key")
+	}
+	planSID, codeSID := sess.RoleCache["plan"], sess.RoleCache["code"]
+	if planSID == "" || codeSID == "" || planSID == codeSID {
+		t.Errorf("expected distinct cached sessions for plan and code, got %v", sess.RoleCache)
+	}
+
+	sources := map[string]bool{}
+	var warned bool
+	sm.IterateAudit(sess, func(e events.AuditEntry) bool {
+		if e.Type == events.AuditLLMResponse {
+			sources[e.Source] = true
+		}
+		if e.Type == events.AuditInfo && strings.HasPrefix(e.Content, "Skill warning:") {
+			warned = true
+		}
+		return true
+	})
+	if !sources["plan"] || !sources["code"] {
+		t.Errorf("expected responses logged under resolved roles, got %v", sources)
+	}
+	if warned {
+		t.Error("expected no skill warnings for roles left to default")
+	}
+}
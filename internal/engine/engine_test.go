@@ -495,3 +495,88 @@ echo '{"type": "message", "content": "Direct response"}'
 		t.Error("expected to find LLM response in audit logs")
 	}
 }
+
+func TestEngineResolveInstructionFromIncludePath(t *testing.T) {
+	storageDir, shared := t.TempDir(), t.TempDir()
+	os.WriteFile(filepath.Join(shared, "shared.md"), []byte("Shared Instruction"), 0644)
+
+	sm := session.NewManager(storageDir)
+	sm.Storage.IncludePaths = []string{shared}
+	engine := NewEngine(sm, nil, "gemini", 5)
+
+	if instr := engine.ResolveInstruction("@shared.md", t.TempDir()); instr != "Shared Instruction" {
+		t.Errorf("expected 'Shared Instruction', got %q", instr)
+	}
+	if instr := engine.ResolveInstruction("@../secret.md", shared); !strings.HasPrefix(instr, "Error: ") {
+		t.Errorf("expected a ../ include to be rejected, got %q", instr)
+	}
+}
+
+func TestRunRefusesSkillWithoutReachableEnd(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	sess := &models.Session{ID: "sess-no-end", CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	sk := &models.SkillGraph{
+		Name:         "endless",
+		InitialState: "check",
+		States: map[string]models.StateDef{
+			"check": {Type: "tool", Command: "touch ran", Next: "check"},
+			"done":  {Type: "end"},
+		},
+	}
+	eng.Run(sk, sess)
+
+	if sess.Status != models.StatusFailed {
+		t.Errorf("status = %s, want failed", sess.Status)
+	}
+	if _, err := os.Stat(filepath.Join(storageDir, "ran")); err == nil {
+		t.Error("no state should run for a skill that cannot finish")
+	}
+	refused, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return strings.Contains(e.Content, "Refusing to run skill endless") && strings.Contains(e.Content, "done")
+	})
+	if len(refused) != 1 {
+		t.Error("expected the refusal to be logged with the unreachable end state")
+	}
+}
+
+func TestRunAddsSkillTagsToSession(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	engine := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+
+	skill := &models.SkillGraph{
+		Name:         "ship",
+		InitialState: "done",
+		Tags:         []string{"deploy", "Hotfix"},
+		States:       map[string]models.StateDef{"done": {Type: "end"}},
+	}
+	sess := &models.Session{
+		ID:        "sess-tags",
+		CWD:       storageDir,
+		RoleCache: make(map[string]string),
+		Metadata:  map[string]string{models.MetaTags: "hotfix", "ticket": "OPS-1"},
+	}
+	sm.Save(sess)
+
+	engine.Run(skill, sess)
+
+	loaded, _ := sm.Load(sess.ID)
+	if got := loaded.Metadata[models.MetaTags]; got != "hotfix,deploy" {
+		t.Errorf("tags = %q, want the session's tags followed by the new skill tags", got)
+	}
+	if found, _ := sm.Search("deploy"); len(found) != 1 {
+		t.Errorf("session should be found by its inherited tag, got %d matches", len(found))
+	}
+
+	plain := &models.Session{ID: "sess-untagged", CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(plain)
+	skill.Tags = nil
+	engine.Run(skill, plain)
+	if loaded, _ := sm.Load(plain.ID); loaded.Metadata != nil {
+		t.Errorf("an untagged skill should leave metadata empty, got %v", loaded.Metadata)
+	}
+}
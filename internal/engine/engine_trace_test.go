@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestRunTraceRecordsStatesAndTransitions(t *testing.T) {
+	storageDir, _ := os.MkdirTemp("", "tenazas-engine-trace-*")
+	defer os.RemoveAll(storageDir)
+
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+
+	skill := &models.SkillGraph{
+		Name:         "trace-skill",
+		InitialState: "build",
+		States: map[string]models.StateDef{
+			"build": {Type: "tool", Command: "true", Next: "check"},
+			"check": {Type: "tool", Command: "exit 3", OnFailRoute: "done"},
+			"done":  {Type: "end"},
+		},
+	}
+	sess := &models.Session{ID: "trace-sess", CWD: storageDir, SkillName: "trace-skill", RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	eng.TraceNextRun(sess.ID)
+	eng.Run(skill, sess)
+
+	data, err := os.ReadFile(sm.ArtifactPath(sess, TraceFileName))
+	if err != nil {
+		t.Fatalf("expected trace file: %v", err)
+	}
+	var trace Trace
+	if err := json.Unmarshal(data, &trace); err != nil {
+		t.Fatalf("invalid trace JSON: %v", err)
+	}
+
+	if trace.Skill != "trace-skill" || trace.Status != models.StatusCompleted {
+		t.Errorf("unexpected trace header: skill=%q status=%q", trace.Skill, trace.Status)
+	}
+	if len(trace.Steps) != 3 {
+		t.Fatalf("expected 3 steps, got %d: %+v", len(trace.Steps), trace.Steps)
+	}
+
+	build, check := trace.Steps[0], trace.Steps[1]
+	if build.Node != "build" || build.Type != "tool" || build.Transition != "check" {
+		t.Errorf("unexpected first step: %+v", build)
+	}
+	if len(build.ExitCodes) != 1 || build.ExitCodes[0] != 0 {
+		t.Errorf("expected exit code 0 on build, got %v", build.ExitCodes)
+	}
+	if check.Node != "check" || check.Transition != "done" {
+		t.Errorf("unexpected second step: %+v", check)
+	}
+	if len(check.ExitCodes) != 1 || check.ExitCodes[0] != 3 {
+		t.Errorf("expected exit code 3 on check, got %v", check.ExitCodes)
+	}
+	if check.End.Before(check.Start) {
+		t.Error("expected step end after start")
+	}
+}
+
+func TestRunWithoutTraceWritesNothing(t *testing.T) {
+	storageDir, _ := os.MkdirTemp("", "tenazas-engine-notrace-*")
+	defer os.RemoveAll(storageDir)
+
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	skill := &models.SkillGraph{Name: "s", InitialState: "end", States: map[string]models.StateDef{"end": {Type: "end"}}}
+	sess := &models.Session{ID: "notrace", CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	eng.Run(skill, sess)
+
+	if _, err := os.Stat(sm.ArtifactPath(sess, TraceFileName)); !os.IsNotExist(err) {
+		t.Errorf("expected no trace file without --trace, got err=%v", err)
+	}
+}
+
+func TestTraceWriterNilSafe(t *testing.T) {
+	var w *TraceWriter
+	w.Enter("a", "tool")
+	w.RecordLLM(time.Second)
+	w.RecordExit(1)
+	if err := w.Leave("b"); err != nil {
+		t.Errorf("expected nil writer to be a no-op, got %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// scheduleEscalation publishes one EventEscalation if the intervention
+// described by details is still waiting after EscalateAfter. The returned
+// func cancels it and must be called once the intervention is answered.
+func (e *Engine) scheduleEscalation(sess *models.Session, details map[string]string) func() {
+	after := e.EscalateAfter
+	if after <= 0 {
+		return func() {}
+	}
+	payload := events.EscalationPayload{Node: details["node"], Reason: details["reason"], Waiting: after}
+	timer := time.AfterFunc(after, func() {
+		e.log(sess, events.AuditInfo, "engine", fmt.Sprintf("Escalated: intervention at %s unanswered for %s", payload.Node, after), events.RoleSystem)
+		events.GlobalBus.Publish(events.Event{Type: events.EventEscalation, SessionID: sess.ID, Payload: payload})
+	})
+	return func() { timer.Stop() }
+}
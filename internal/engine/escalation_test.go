@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+// countEscalations collects escalation events for sessionID until d passes.
+func countEscalations(ch chan events.Event, sessionID string, d time.Duration) int {
+	n := 0
+	deadline := time.After(d)
+	for {
+		select {
+		case ev := <-ch:
+			if ev.Type == events.EventEscalation && ev.SessionID == sessionID {
+				n++
+			}
+		case <-deadline:
+			return n
+		}
+	}
+}
+
+func startIntervention(t *testing.T, after time.Duration) (*Engine, *models.Session, chan struct{}) {
+	t.Helper()
+	sm := session.NewManager(t.TempDir())
+	e := NewEngine(sm, nil, "gemini", 5)
+	e.EscalateAfter = after
+	sess := &models.Session{ID: "sess-escalate-" + t.Name(), CWD: t.TempDir(), RoleCache: map[string]string{}, ActiveNode: "build", PendingFeedback: "tests failed"}
+	sm.Save(sess)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.awaitIntervention(&models.SkillGraph{Name: "ci"}, &models.StateDef{Instruction: "fix it"}, sess)
+	}()
+	return e, sess, done
+}
+
+func TestEscalationFiresOnceAfterWindow(t *testing.T) {
+	ch := events.GlobalBus.Subscribe()
+	defer events.GlobalBus.Unsubscribe(ch)
+
+	e, sess, done := startIntervention(t, 150*time.Millisecond)
+
+	if n := countEscalations(ch, sess.ID, 80*time.Millisecond); n != 0 {
+		t.Fatalf("escalated %d times before the window elapsed", n)
+	}
+	if n := countEscalations(ch, sess.ID, 300*time.Millisecond); n != 1 {
+		t.Fatalf("escalated %d times after the window, want exactly 1", n)
+	}
+
+	e.ResolveIntervention(sess.ID, "abort")
+	<-done
+}
+
+func TestEscalationCancelledWhenAnswered(t *testing.T) {
+	ch := events.GlobalBus.Subscribe()
+	defer events.GlobalBus.Unsubscribe(ch)
+
+	e, sess, done := startIntervention(t, 150*time.Millisecond)
+	for {
+		if _, waiting := e.awaiting.Load(sess.ID); waiting {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	e.ResolveIntervention(sess.ID, "abort")
+	<-done
+
+	if n := countEscalations(ch, sess.ID, 300*time.Millisecond); n != 0 {
+		t.Errorf("escalated %d times for an answered intervention", n)
+	}
+}
@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Failure categories attached to interventions so users can tell at a glance
+// what went wrong.
+const (
+	FailureCompile    = "compile-error"
+	FailureTest       = "test-failure"
+	FailureTimeout    = "timeout"
+	FailureNotFound   = "command-not-found"
+	FailurePermission = "permission-denied"
+	FailureCommand    = "command-failed"
+	// FailureNoProgress marks an intervention raised because the same
+	// failure kept repeating; see Engine.StuckRepeatLimit.
+	FailureNoProgress = "no-progress"
+)
+
+var (
+	compilePatterns = []string{
+		"syntax error", "syntaxerror", "undefined:", "cannot find symbol", "compilation failed",
+		"build failed", "[build failed]", "error[e", "error ts", "cannot use ", "undeclared name",
+	}
+	testPatterns = []string{
+		"--- fail", "fail\t", "tests failed", "test failed", "failed tests", "assertionerror",
+		"assertion failed", " failing", "failures:",
+	}
+)
+
+// classifyFailure maps a failed command's exit code and output to a short
+// category tag. Well-known exit codes win over output matching; it returns
+// "" for exit code 0.
+func classifyFailure(exitCode int, output string) string {
+	switch exitCode {
+	case 0:
+		return ""
+	case 124:
+		return FailureTimeout
+	case 126:
+		return FailurePermission
+	case 127:
+		return FailureNotFound
+	}
+
+	lower := strings.ToLower(output)
+	switch {
+	case strings.Contains(lower, "command not found"):
+		return FailureNotFound
+	case strings.Contains(lower, "permission denied"):
+		return FailurePermission
+	case strings.Contains(lower, "timed out"):
+		return FailureTimeout
+	case containsAny(lower, compilePatterns):
+		return FailureCompile
+	case containsAny(lower, testPatterns):
+		return FailureTest
+	}
+	return FailureCommand
+}
+
+func containsAny(s string, patterns []string) bool {
+	for _, p := range patterns {
+		if strings.Contains(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordFailure remembers the category of the session's latest failure so
+// the next intervention can report it.
+func (e *Engine) recordFailure(sessID string, exitCode int, output string) {
+	if category := classifyFailure(exitCode, output); category != "" {
+		e.failures.Store(sessID, category)
+	}
+}
+
+// failureCategory returns the category of the session's latest failure, if any.
+func (e *Engine) failureCategory(sessID string) string {
+	if v, ok := e.failures.Load(sessID); ok {
+		return v.(string)
+	}
+	return ""
+}
+
+// failureStreak tracks how many times in a row a session's verification
+// failed with the same exit code and output.
+type failureStreak struct {
+	sum   uint64
+	count int
+}
+
+// repeatFailure records a failed verification and returns how many
+// consecutive failures, this one included, produced identical output.
+func (e *Engine) repeatFailure(sessID string, exitCode int, output string) int {
+	h := fnv.New64a()
+	h.Write([]byte(strconv.Itoa(exitCode)))
+	h.Write([]byte{0})
+	h.Write([]byte(output))
+	sum := h.Sum64()
+
+	v, _ := e.streaks.LoadOrStore(sessID, &failureStreak{})
+	streak := v.(*failureStreak)
+	if streak.count > 0 && streak.sum == sum {
+		streak.count++
+	} else {
+		streak.sum, streak.count = sum, 1
+	}
+	return streak.count
+}
+
+// stuck reports whether the latest failure repeated often enough that the
+// agent is making no progress.
+func (e *Engine) stuck(repeats int) bool {
+	return e.StuckRepeatLimit > 0 && repeats >= e.StuckRepeatLimit
+}
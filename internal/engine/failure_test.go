@@ -0,0 +1,181 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		exitCode int
+		output   string
+		want     string
+	}{
+		{"success", 0, "ok", ""},
+		{"timeout exit code", 124, "", FailureTimeout},
+		{"not found exit code", 127, "bash: gotest: command not found", FailureNotFound},
+		{"not executable", 126, "", FailurePermission},
+		{"permission in output", 1, "open /etc/shadow: permission denied", FailurePermission},
+		{"go compile", 2, "./main.go:10:2: undefined: foo", FailureCompile},
+		{"rust compile", 101, "error[E0425]: cannot find value `x`", FailureCompile},
+		{"go test", 1, "--- FAIL: TestThing (0.00s)\nFAIL\ttenazas/x\t0.01s", FailureTest},
+		{"pytest", 1, "AssertionError: 1 != 2", FailureTest},
+		{"timed out message", 1, "Error: Command timed out after 30s", FailureTimeout},
+		{"unknown", 3, "something odd", FailureCommand},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyFailure(tt.exitCode, tt.output); got != tt.want {
+				t.Errorf("classifyFailure(%d, %q) = %q, want %q", tt.exitCode, tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterventionIncludesFailureCategory(t *testing.T) {
+	storageDir := t.TempDir()
+	script := filepath.Join(storageDir, "ok.sh")
+	os.WriteFile(script, []byte("#!/bin/sh\necho '{\"type\": \"message\", \"content\": \"done\"}'\n"), 0755)
+
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient(script, storageDir), "gemini", 5)
+
+	skill := &models.SkillGraph{
+		Name:         "cat-skill",
+		InitialState: "work",
+		States: map[string]models.StateDef{
+			"work": {Type: "action_loop", SessionRole: "coder", Instruction: "fix", VerifyCmd: "echo 'no such tool' >&2; exit 127", MaxRetries: 1, Next: "end"},
+			"end":  {Type: "end"},
+		},
+	}
+	sess := &models.Session{ID: "cat-sess", CWD: storageDir, SkillName: "cat-skill", RoleCache: map[string]string{}}
+	sm.Save(sess)
+
+	ch := events.GlobalBus.Subscribe()
+	defer events.GlobalBus.Unsubscribe(ch)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		eng.Run(skill, sess)
+	}()
+
+	deadline := time.After(10 * time.Second)
+	for {
+		select {
+		case ev := <-ch:
+			p, ok := ev.Payload.(events.TaskStatusPayload)
+			if ev.SessionID != sess.ID || !ok || p.State != events.TaskStateBlocked {
+				continue
+			}
+			if p.Details["category"] != FailureNotFound {
+				t.Errorf("expected category %q, got %q", FailureNotFound, p.Details["category"])
+			}
+			for {
+				if _, waiting := eng.awaiting.Load(sess.ID); waiting {
+					break
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+			eng.ResolveIntervention(sess.ID, "abort")
+			<-done
+			entries, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool { return e.Type == events.AuditIntervention })
+			if len(entries) == 0 || !strings.Contains(entries[len(entries)-1].Content, "["+FailureNotFound+"]") {
+				t.Errorf("expected intervention log tagged with category, got %v", entries)
+			}
+			return
+		case <-deadline:
+			t.Fatal("expected the session to block for intervention")
+		}
+	}
+}
+
+// runUntilBlocked runs a skill whose verify_cmd always fails and returns
+// the session's loop count when it first blocks for intervention.
+func runUntilBlocked(t *testing.T, verifyCmd string, stuckLimit int) (int, string) {
+	t.Helper()
+	storageDir := t.TempDir()
+	script := filepath.Join(storageDir, "ok.sh")
+	os.WriteFile(script, []byte("#!/bin/sh\necho '{\"type\": \"message\", \"content\": \"done\"}'\n"), 0755)
+
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient(script, storageDir), "gemini", 6)
+	eng.StuckRepeatLimit = stuckLimit
+
+	skill := &models.SkillGraph{
+		Name:         "stuck-skill",
+		InitialState: "work",
+		States: map[string]models.StateDef{
+			"work": {Type: "action_loop", SessionRole: "coder", Instruction: "fix", VerifyCmd: verifyCmd, MaxRetries: 20, Next: "end"},
+			"end":  {Type: "end"},
+		},
+	}
+	sess := &models.Session{ID: "stuck-" + t.Name(), CWD: storageDir, SkillName: "stuck-skill", RoleCache: map[string]string{}}
+	sm.Save(sess)
+
+	ch := events.GlobalBus.Subscribe()
+	defer events.GlobalBus.Unsubscribe(ch)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		eng.Run(skill, sess)
+	}()
+
+	deadline := time.After(10 * time.Second)
+	for {
+		select {
+		case ev := <-ch:
+			p, ok := ev.Payload.(events.TaskStatusPayload)
+			if ev.SessionID != sess.ID || !ok || p.State != events.TaskStateBlocked {
+				continue
+			}
+			for {
+				if _, waiting := eng.awaiting.Load(sess.ID); waiting {
+					break
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+			loops := sess.LoopCount
+			eng.ResolveIntervention(sess.ID, "abort")
+			<-done
+			return loops, p.Details["category"]
+		case <-deadline:
+			t.Fatal("expected the session to block for intervention")
+		}
+	}
+}
+
+func TestIdenticalFailuresStopEarly(t *testing.T) {
+	loops, category := runUntilBlocked(t, "echo 'FAIL: same'; exit 1", 3)
+	if loops != 3 {
+		t.Errorf("expected intervention after 3 identical failures, got %d loops", loops)
+	}
+	if category != FailureNoProgress {
+		t.Errorf("category = %q, want %q", category, FailureNoProgress)
+	}
+}
+
+func TestVaryingFailuresUseFullBudget(t *testing.T) {
+	loops, category := runUntilBlocked(t, "date +%s%N; exit 1", 3)
+	if loops != 6 {
+		t.Errorf("expected the full loop budget of 6, got %d loops", loops)
+	}
+	if category == FailureNoProgress {
+		t.Error("varying failures should not be reported as no progress")
+	}
+}
+
+func TestStuckDetectionDisabled(t *testing.T) {
+	if loops, _ := runUntilBlocked(t, "echo 'FAIL: same'; exit 1", 0); loops != 6 {
+		t.Errorf("with the check disabled, expected 6 loops, got %d", loops)
+	}
+}
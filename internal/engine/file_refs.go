@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+const (
+	maxFileRefBytes = 64 << 10  // largest file one @path reference may include
+	maxFileRefTotal = 256 << 10 // all files included in one prompt together
+)
+
+// fileRefPattern matches an @path reference at the start of a prompt or
+// after whitespace, so e-mail addresses are left alone.
+var fileRefPattern = regexp.MustCompile(`(^|\s)@([^\s@]+)`)
+
+// expandFileRefs appends the content of every file prompt references as
+// @path, resolved against the session CWD. A reference that can't be
+// included stays as written and gets a note in the session explaining why.
+func (e *Engine) expandFileRefs(sess *models.Session, prompt string) string {
+	var files strings.Builder
+	seen := make(map[string]bool)
+	total := 0
+	for _, m := range fileRefPattern.FindAllStringSubmatch(prompt, -1) {
+		ref := strings.TrimRight(m[2], `.,;:!?)]}"'`)
+		if ref == "" || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		data, err := e.readFileRef(sess, ref)
+		if err == nil && total+len(data) > maxFileRefTotal {
+			err = fmt.Errorf("the prompt's files would exceed %d KB together", maxFileRefTotal>>10)
+		}
+		if err != nil {
+			e.log(sess, events.AuditInfo, "engine", fmt.Sprintf("@%s not included: %v", ref, err), events.RoleSystem)
+			continue
+		}
+		total += len(data)
+		fmt.Fprintf(&files, "\n\n<file path=%q>\n%s\n</file>", ref, strings.TrimRight(string(data), "\n"))
+	}
+	return prompt + files.String()
+}
+
+// readFileRef reads the file behind an @path reference, refusing anything
+// outside the session CWD (after resolving ".." and symlinks) or the
+// client's allowed_dirs, directories, binaries and files over
+// maxFileRefBytes.
+func (e *Engine) readFileRef(sess *models.Session, ref string) ([]byte, error) {
+	path := ref
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(sess.CWD, path)
+	}
+	if !isWithinAllowed(path, []string{sess.CWD}) {
+		return nil, errors.New("outside the session directory")
+	}
+	if e.CheckCWD(sess.Client, path) != nil {
+		return nil, errors.New("outside the client's allowed_dirs")
+	}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, errors.New("no such file")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, errors.New("is a directory")
+	}
+	if info.Size() > maxFileRefBytes {
+		return nil, fmt.Errorf("file is %d KB, over the %d KB limit", (info.Size()+1023)>>10, maxFileRefBytes>>10)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return nil, errors.New("binary file")
+	}
+	return data, nil
+}
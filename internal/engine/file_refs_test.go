@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func setupFileRefTest(t *testing.T) (*Engine, *session.Manager, *models.Session) {
+	t.Helper()
+	storageDir := t.TempDir()
+	cwd := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	sess := &models.Session{ID: "refs", CWD: cwd, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+	return eng, sm, sess
+}
+
+func fileRefNotes(sm *session.Manager, sess *models.Session) []string {
+	entries, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool { return e.Type == events.AuditInfo })
+	var notes []string
+	for _, e := range entries {
+		notes = append(notes, e.Content)
+	}
+	return notes
+}
+
+func TestExpandFileRefs(t *testing.T) {
+	eng, sm, sess := setupFileRefTest(t)
+	os.MkdirAll(filepath.Join(sess.CWD, "pkg"), 0755)
+	os.WriteFile(filepath.Join(sess.CWD, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(sess.CWD, "pkg", "util.go"), []byte("package pkg\n"), 0644)
+
+	got := eng.expandFileRefs(sess, "explain @main.go, then @pkg/util.go and @main.go again (mail me@example.com)")
+	want := "explain @main.go, then @pkg/util.go and @main.go again (mail me@example.com)" +
+		"\n\n<file path=\"main.go\">\npackage main\n</file>" +
+		"\n\n<file path=\"pkg/util.go\">\npackage pkg\n</file>"
+	if got != want {
+		t.Errorf("expanded prompt =\n%q\nwant\n%q", got, want)
+	}
+	if notes := fileRefNotes(sm, sess); len(notes) != 0 {
+		t.Errorf("expected no notes, got %v", notes)
+	}
+}
+
+func TestExpandFileRefsMissingAndOversized(t *testing.T) {
+	eng, sm, sess := setupFileRefTest(t)
+	os.WriteFile(filepath.Join(sess.CWD, "big.log"), []byte(strings.Repeat("x", maxFileRefBytes+1)), 0644)
+
+	prompt := "compare @missing.go with @big.log"
+	if got := eng.expandFileRefs(sess, prompt); got != prompt {
+		t.Errorf("unreadable references should leave the prompt as written, got %q", got)
+	}
+	notes := fileRefNotes(sm, sess)
+	if len(notes) != 2 || notes[0] != "@missing.go not included: no such file" ||
+		!strings.HasPrefix(notes[1], "@big.log not included: file is 65 KB, over the 64 KB limit") {
+		t.Errorf("unexpected notes %q", notes)
+	}
+}
+
+func TestExecutePromptSendsReferencedFile(t *testing.T) {
+	eng, sm, sess := setupFileRefTest(t)
+	os.WriteFile(filepath.Join(sess.CWD, "notes.txt"), []byte("remember the milk"), 0644)
+
+	eng.ExecutePrompt(sess, "summarize @notes.txt")
+
+	prompts, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool { return e.Type == events.AuditLLMPrompt })
+	if len(prompts) != 1 || !strings.Contains(prompts[0].Content, "<file path=\"notes.txt\">\nremember the milk\n</file>") {
+		t.Errorf("expected the file in the sent prompt, got %+v", prompts)
+	}
+}
+
+func TestExpandFileRefsRefusesPathsOutsideCWD(t *testing.T) {
+	eng, sm, sess := setupFileRefTest(t)
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret.txt")
+	os.WriteFile(secret, []byte("hunter2"), 0644)
+	os.Symlink(secret, filepath.Join(sess.CWD, "link.txt"))
+	os.Symlink(outside, filepath.Join(sess.CWD, "linkdir"))
+	rel, _ := filepath.Rel(sess.CWD, secret)
+
+	refs := []string{secret, rel, "link.txt", "linkdir/secret.txt"}
+	prompt := "read @" + strings.Join(refs, " @")
+	if got := eng.expandFileRefs(sess, prompt); got != prompt {
+		t.Errorf("references outside the CWD should not be included, got %q", got)
+	}
+	notes := fileRefNotes(sm, sess)
+	if len(notes) != len(refs) {
+		t.Fatalf("expected a note per reference, got %q", notes)
+	}
+	for i, ref := range refs {
+		if want := "@" + ref + " not included: outside the session directory"; notes[i] != want {
+			t.Errorf("note %d = %q, want %q", i, notes[i], want)
+		}
+	}
+}
+
+func TestExecuteCommandDoesNotExpandFileRefsInOutput(t *testing.T) {
+	eng, sm, sess := setupFileRefTest(t)
+	os.WriteFile(filepath.Join(sess.CWD, "notes.txt"), []byte("remember the milk"), 0644)
+
+	eng.ExecuteCommand(sess, "echo see @notes.txt")
+
+	prompts, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool { return e.Type == events.AuditLLMPrompt })
+	if len(prompts) != 1 || !strings.Contains(prompts[0].Content, "see @notes.txt") || strings.Contains(prompts[0].Content, "<file") {
+		t.Errorf("command output should be sent as is, got %+v", prompts)
+	}
+}
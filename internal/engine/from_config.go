@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"tenazas/internal/client"
+	"tenazas/internal/config"
+	"tenazas/internal/session"
+)
+
+// NewFromConfig builds an engine from cfg: one client per cfg.Clients entry
+// (logging to <storage_dir>/tenazas.log) and cfg's limits. A client that
+// cannot be built is left out and reported in the returned error; the engine
+// is returned either way, so callers choose whether that is fatal.
+func NewFromConfig(sm *session.Manager, cfg *config.Config) (*Engine, error) {
+	logPath := filepath.Join(cfg.StorageDir, "tenazas.log")
+	clients := make(map[string]client.Client)
+	var errs []error
+	for name, cc := range cfg.Clients {
+		c, err := client.NewClient(name, cc.BinPath, logPath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("client %q: %w", name, err))
+			continue
+		}
+		if len(cc.Models) > 0 {
+			c.SetModels(cc.Models)
+		}
+		if ll, ok := c.(client.LevelLogger); ok && cc.LogLevel != "" {
+			ll.SetLogLevel(cc.LogLevel)
+		}
+		clients[name] = c
+	}
+
+	eng := NewEngine(sm, clients, cfg.DefaultClient, cfg.MaxLoops)
+	eng.IdleTimeout = time.Duration(cfg.IdleTimeoutSec) * time.Second
+	eng.PromptMode = cfg.PromptMode
+	eng.MaxPromptChars = cfg.MaxPromptChars
+	eng.PromptLimitPolicy = cfg.PromptLimitPolicy
+	eng.SlowLLMThreshold = time.Duration(cfg.SlowLLMThresholdSec * float64(time.Second))
+	eng.ChunkFlushInterval = time.Duration(cfg.ChunkFlushMs) * time.Millisecond
+	eng.ChunkFlushBytes = cfg.ChunkFlushBytes
+	eng.AllowedDirs = cfg.ClientAllowedDirs()
+	eng.MaxContinues = cfg.MaxContinues
+	eng.ContinueText = cfg.ContinuePrompt
+	eng.MaxConcurrentSessions = cfg.MaxConcurrentSessions
+	if cfg.StuckRepeatLimit > 0 {
+		eng.StuckRepeatLimit = cfg.StuckRepeatLimit
+	}
+	if wh := cfg.CompletionWebhook; wh.URL != "" {
+		eng.CompletionWebhook = &Webhook{URL: wh.URL, Secret: wh.Secret, Retries: wh.Retries}
+	}
+	return eng, errors.Join(errs...)
+}
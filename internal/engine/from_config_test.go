@@ -0,0 +1,37 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"tenazas/internal/config"
+	"tenazas/internal/session"
+)
+
+func TestNewFromConfigKeepsWorkingClients(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		StorageDir:     dir,
+		DefaultClient:  "gemini",
+		MaxLoops:       4,
+		MaxPromptChars: 2000,
+		Clients: map[string]config.ClientConfig{
+			"gemini":  {BinPath: "gemini"},
+			"unknown": {BinPath: "nope"},
+		},
+	}
+
+	eng, err := NewFromConfig(session.NewManager(dir), cfg)
+	if err == nil || !strings.Contains(err.Error(), `client "unknown"`) {
+		t.Errorf("expected the unknown client to be reported, got %v", err)
+	}
+	if eng == nil {
+		t.Fatal("expected an engine despite the client error")
+	}
+	if _, ok := eng.Clients["gemini"]; !ok || len(eng.Clients) != 1 {
+		t.Errorf("expected only the gemini client, got %v", eng.Clients)
+	}
+	if eng.MaxLoops != 4 || eng.MaxPromptChars != 2000 {
+		t.Errorf("expected cfg limits on the engine, got loops=%d prompt chars=%d", eng.MaxLoops, eng.MaxPromptChars)
+	}
+}
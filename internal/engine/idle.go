@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// touch records activity for a session so the idle watchdog does not park it.
+func (e *Engine) touch(sessID string) {
+	e.activity.Store(sessID, time.Now())
+}
+
+// quietly marks sess as doing silent work, such as a shell command, until
+// the returned function is called, so the idle watchdog does not park it
+// meanwhile.
+func (e *Engine) quietly(sessID string) func() {
+	e.working.Store(sessID, true)
+	return func() {
+		e.working.Delete(sessID)
+		e.touch(sessID)
+	}
+}
+
+// runShell runs cmd in the session's CWD as silent work; see quietly.
+func (e *Engine) runShell(sess *models.Session, cmd string) (int, string) {
+	defer e.quietly(sess.ID)()
+	return e.RunShell(cmd, sess.CWD)
+}
+
+// lastActivity returns the last recorded activity time for a session.
+func (e *Engine) lastActivity(sessID string) time.Time {
+	if v, ok := e.activity.Load(sessID); ok {
+		return v.(time.Time)
+	}
+	return time.Time{}
+}
+
+// startIdleWatchdog cancels the run when the session stays silent for longer
+// than IdleTimeout. Time spent waiting for a human intervention, or doing
+// silent work (see quietly), does not count.
+// The returned stop function must be called once the run ends.
+func (e *Engine) startIdleWatchdog(sessID string, cancel context.CancelFunc) func() {
+	e.touch(sessID)
+	if e.IdleTimeout <= 0 {
+		return func() { e.activity.Delete(sessID) }
+	}
+
+	interval := e.IdleTimeout / 4
+	if interval > time.Second {
+		interval = time.Second
+	}
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_, waiting := e.awaiting.Load(sessID)
+				_, working := e.working.Load(sessID)
+				if waiting || working {
+					e.touch(sessID)
+					continue
+				}
+				if time.Since(e.lastActivity(sessID)) >= e.IdleTimeout {
+					e.idleParked.Store(sessID, true)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+		e.activity.Delete(sessID)
+	}
+}
+
+// parkIfIdle moves a session cancelled by the idle watchdog into intervention
+// and reports whether it did so.
+func (e *Engine) parkIfIdle(sess *models.Session) bool {
+	if _, ok := e.idleParked.LoadAndDelete(sess.ID); !ok {
+		return false
+	}
+	reason := fmt.Sprintf("No activity for %s; session parked", e.IdleTimeout)
+	e.Sm.Update(sess, func(s *models.Session) {
+		s.Status = models.StatusIntervention
+		s.PendingFeedback = reason
+	})
+	e.log(sess, events.AuditIntervention, "engine", reason, events.RoleSystem)
+	e.publishTaskStatus(sess.ID, events.TaskStateBlocked, map[string]string{
+		"node":   sess.ActiveNode,
+		"reason": reason,
+	})
+	return true
+}
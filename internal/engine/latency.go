@@ -0,0 +1,34 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// recordLatency logs how long an LLM call for node took and warns when it
+// exceeded SlowLLMThreshold, so skill authors can spot slow states.
+func (e *Engine) recordLatency(sess *models.Session, source, node string, d time.Duration) {
+	e.traceFor(sess.ID).RecordLLM(d)
+	e.countLLMCall(node, d)
+
+	where := "prompt"
+	if node != "" {
+		where = "state " + node
+	}
+	content := fmt.Sprintf("LLM latency for %s: %s", where, d.Round(time.Millisecond))
+	if e.SlowLLMThreshold > 0 && d > e.SlowLLMThreshold {
+		content = fmt.Sprintf("⚠ Slow %s: LLM call took %s (threshold %s)", where, d.Round(time.Millisecond), e.SlowLLMThreshold)
+	}
+	e.touch(sess.ID)
+	e.Sm.AppendAudit(sess, events.AuditEntry{
+		Type:       events.AuditInfo,
+		Source:     source,
+		Role:       events.RoleSystem,
+		Step:       stepTag(sess),
+		Content:    content,
+		DurationMs: d.Milliseconds(),
+	})
+}
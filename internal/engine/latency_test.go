@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func latencyEntries(sm *session.Manager, sess *models.Session) []events.AuditEntry {
+	entries, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return e.Type == events.AuditInfo && e.DurationMs > 0
+	})
+	return entries
+}
+
+func runSlowSkill(t *testing.T, threshold time.Duration) (*session.Manager, *models.Session) {
+	t.Helper()
+	storageDir := t.TempDir()
+	scriptPath := filepath.Join(storageDir, "slow.sh")
+	os.WriteFile(scriptPath, []byte("#!/bin/sh\nsleep 0.2\necho '{\"type\": \"message\", \"content\": \"done\"}'\n"), 0755)
+
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient(scriptPath, storageDir), "gemini", 5)
+	eng.SlowLLMThreshold = threshold
+
+	sk := &models.SkillGraph{
+		Name:         "slow",
+		InitialState: "think",
+		States: map[string]models.StateDef{
+			"think": {Type: "action_loop", Instruction: "ponder", Next: "end"},
+			"end":   {Type: "end"},
+		},
+	}
+	sess := &models.Session{ID: "slow-" + threshold.String(), CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+	eng.Run(sk, sess)
+	return sm, sess
+}
+
+func TestActionLoopRecordsLLMLatency(t *testing.T) {
+	sm, sess := runSlowSkill(t, 0)
+
+	entries := latencyEntries(sm, sess)
+	if len(entries) != 1 {
+		t.Fatalf("expected one latency entry, got %+v", entries)
+	}
+	if entries[0].DurationMs < 200 {
+		t.Errorf("DurationMs = %d, want at least the stub's 200ms delay", entries[0].DurationMs)
+	}
+	if !strings.Contains(entries[0].Content, "LLM latency for state think") {
+		t.Errorf("unexpected latency entry %q", entries[0].Content)
+	}
+}
+
+func TestSlowStateWarningPastThreshold(t *testing.T) {
+	sm, sess := runSlowSkill(t, 50*time.Millisecond)
+
+	entries := latencyEntries(sm, sess)
+	if len(entries) != 1 || !strings.Contains(entries[0].Content, "Slow state think") || !strings.Contains(entries[0].Content, "threshold 50ms") {
+		t.Fatalf("expected a slow-state warning, got %+v", entries)
+	}
+
+	sm, sess = runSlowSkill(t, time.Minute)
+	if entries := latencyEntries(sm, sess); len(entries) != 1 || strings.Contains(entries[0].Content, "Slow") {
+		t.Errorf("expected no warning under the threshold, got %+v", entries)
+	}
+}
+
+func TestExecutePromptRecordsLLMLatency(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	sess := &models.Session{ID: "prompt-latency", CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	eng.ExecutePrompt(sess, "hello")
+
+	entries, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return e.Type == events.AuditInfo && strings.HasPrefix(e.Content, "LLM latency for prompt")
+	})
+	if len(entries) != 1 {
+		t.Fatalf("expected one prompt latency entry, got %+v", entries)
+	}
+}
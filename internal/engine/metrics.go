@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// engineCounters are the running totals behind Engine.Metrics, updated
+// atomically from every session's goroutine.
+type engineCounters struct {
+	llmCalls      atomic.Int64
+	shellCommands atomic.Int64
+	interventions atomic.Int64
+	stateCalls    atomic.Int64 // LLM calls made for a skill state
+	stateNanos    atomic.Int64 // their summed latency
+}
+
+// Metrics is a snapshot of the engine's activity since it was created.
+type Metrics struct {
+	Running       int   // sessions with a Run or prompt in flight
+	LLMCalls      int64 // calls to any client, skill states and plain prompts
+	ShellCommands int64 // commands run for tool states, verify, pre/post actions
+	Interventions int64 // interventions waited on
+	// StateCalls and StateLatency describe the LLM calls of skill states;
+	// their ratio is the average state latency.
+	StateCalls   int64
+	StateLatency time.Duration
+}
+
+// Metrics returns the engine's counters.
+func (e *Engine) Metrics() Metrics {
+	m := Metrics{
+		LLMCalls:      e.counters.llmCalls.Load(),
+		ShellCommands: e.counters.shellCommands.Load(),
+		Interventions: e.counters.interventions.Load(),
+		StateCalls:    e.counters.stateCalls.Load(),
+		StateLatency:  time.Duration(e.counters.stateNanos.Load()),
+	}
+	e.running.Range(func(_, _ interface{}) bool {
+		m.Running++
+		return true
+	})
+	return m
+}
+
+// countLLMCall records one finished LLM call; node is empty for prompts
+// outside a skill.
+func (e *Engine) countLLMCall(node string, d time.Duration) {
+	e.counters.llmCalls.Add(1)
+	if node != "" {
+		e.counters.stateCalls.Add(1)
+		e.counters.stateNanos.Add(int64(d))
+	}
+}
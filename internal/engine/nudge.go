@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"fmt"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// maxNudgeOutput caps the failing command output repeated by Nudge, keeping
+// its tail where errors usually are.
+const maxNudgeOutput = 1500
+
+// Nudge re-publishes the TaskStateBlocked details of a session waiting for an
+// intervention, so UIs can ask the question again. It reads the persisted
+// session and never advances the run. Sessions not in intervention are left
+// alone and return an error.
+func (e *Engine) Nudge(sessionID string) (map[string]string, error) {
+	sess, err := e.Sm.Load(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if sess.Status != models.StatusIntervention {
+		return nil, fmt.Errorf("session is not waiting for an intervention (status: %s)", sess.Status)
+	}
+
+	details := map[string]string{
+		"node":   sess.ActiveNode,
+		"reason": sess.PendingFeedback,
+	}
+	if sess.SkillName != "" {
+		if sk, err := e.Sm.LoadSkillFor(sess.CWD, sess.SkillName); err == nil {
+			details["instruction"] = sk.States[sess.ActiveNode].Instruction
+		}
+	}
+	if category := e.failureCategory(sess.ID); category != "" {
+		details["category"] = category
+	}
+	if out := e.lastFailingOutput(sess); out != "" {
+		details["last_output"] = out
+	}
+	e.publishTaskStatus(sess.ID, events.TaskStateBlocked, details)
+	return details, nil
+}
+
+// lastFailingOutput returns the tail of the session's most recent failed
+// command result, or "".
+func (e *Engine) lastFailingOutput(sess *models.Session) string {
+	failed, err := e.Sm.FilterAudit(sess, func(a events.AuditEntry) bool {
+		return a.Type == events.AuditCmdResult && a.ExitCode != 0
+	})
+	if err != nil || len(failed) == 0 {
+		return ""
+	}
+	out := failed[len(failed)-1].Content
+	if len(out) > maxNudgeOutput {
+		out = "..." + out[len(out)-maxNudgeOutput:]
+	}
+	return out
+}
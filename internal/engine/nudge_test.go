@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+// blockedEvents collects TaskStateBlocked events for sessionID published
+// within a short window.
+func blockedEvents(ch chan events.Event, sessionID string) []map[string]string {
+	var got []map[string]string
+	timeout := time.After(100 * time.Millisecond)
+	for {
+		select {
+		case e := <-ch:
+			p, ok := e.Payload.(events.TaskStatusPayload)
+			if e.SessionID == sessionID && e.Type == events.EventTaskStatus && ok && p.State == events.TaskStateBlocked {
+				got = append(got, p.Details)
+			}
+		case <-timeout:
+			return got
+		}
+	}
+}
+
+func TestNudgeRepublishesBlockedDetails(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+
+	sk := models.SkillGraph{
+		Name:         "nudgy",
+		InitialState: "verify",
+		States: map[string]models.StateDef{
+			"verify": {Type: "tool", Instruction: "run the tests", Next: "end"},
+			"end":    {Type: "end"},
+		},
+	}
+	os.MkdirAll(filepath.Join(storageDir, "skills", "nudgy"), 0755)
+	data, _ := json.Marshal(sk)
+	os.WriteFile(filepath.Join(storageDir, "skills", "nudgy", "skill.json"), data, 0644)
+
+	sess := &models.Session{
+		ID:              "nudge-blocked",
+		CWD:             storageDir,
+		SkillName:       "nudgy",
+		ActiveNode:      "verify",
+		Status:          models.StatusIntervention,
+		PendingFeedback: "tests failed",
+		RoleCache:       make(map[string]string),
+	}
+	sm.Save(sess)
+	sm.AppendAudit(sess, events.AuditEntry{Type: events.AuditCmdResult, Content: "FAIL: TestLogin", ExitCode: 1})
+
+	ch := events.GlobalBus.Subscribe()
+	defer events.GlobalBus.Unsubscribe(ch)
+
+	details, err := eng.Nudge(sess.ID)
+	if err != nil {
+		t.Fatalf("Nudge: %v", err)
+	}
+	want := map[string]string{
+		"node":        "verify",
+		"instruction": "run the tests",
+		"reason":      "tests failed",
+		"last_output": "FAIL: TestLogin",
+	}
+	for k, v := range want {
+		if details[k] != v {
+			t.Errorf("details[%q] = %q, want %q", k, details[k], v)
+		}
+	}
+	if got := blockedEvents(ch, sess.ID); len(got) != 1 || got[0]["reason"] != "tests failed" {
+		t.Errorf("expected one blocked event, got %v", got)
+	}
+	if reloaded, _ := sm.Load(sess.ID); reloaded.Status != models.StatusIntervention || reloaded.ActiveNode != "verify" {
+		t.Errorf("nudge should not advance the session, got status %s at %s", reloaded.Status, reloaded.ActiveNode)
+	}
+}
+
+func TestNudgeIgnoresSessionsNotBlocked(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	sess := &models.Session{ID: "nudge-idle", CWD: storageDir, Status: models.StatusIdle, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	ch := events.GlobalBus.Subscribe()
+	defer events.GlobalBus.Unsubscribe(ch)
+
+	if _, err := eng.Nudge(sess.ID); err == nil {
+		t.Error("expected an error for a session not in intervention")
+	}
+	if got := blockedEvents(ch, sess.ID); len(got) != 0 {
+		t.Errorf("expected no events, got %v", got)
+	}
+}
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"tenazas/internal/models"
+)
+
+// preflightTimeout bounds each probe command of a skill's RequiredTools.
+const preflightTimeout = 10 * time.Second
+
+// preflight checks that every tool the skill requires is available in cwd.
+// A bare name is looked up on PATH, a path is resolved against cwd, and an
+// entry with arguments is run as a probe command that must exit 0.
+func preflight(sk *models.SkillGraph, cwd string) error {
+	var missing []string
+	for _, tool := range sk.RequiredTools {
+		tool = strings.TrimSpace(tool)
+		if tool == "" {
+			continue
+		}
+		if !toolAvailable(tool, cwd) {
+			missing = append(missing, tool)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required tools: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func toolAvailable(tool, cwd string) bool {
+	if strings.ContainsAny(tool, " \t") {
+		ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "bash", "-c", tool)
+		cmd.Dir = cwd
+		return cmd.Run() == nil
+	}
+	if strings.Contains(tool, "/") {
+		if !filepath.IsAbs(tool) {
+			tool = filepath.Join(cwd, tool)
+		}
+		info, err := os.Stat(tool)
+		return err == nil && !info.IsDir() && info.Mode()&0111 != 0
+	}
+	_, err := exec.LookPath(tool)
+	return err == nil
+}
+
+// toolsReady refuses to run a skill whose required tools are missing, so
+// the problem surfaces before any state runs rather than mid-skill.
+func (e *Engine) toolsReady(sk *models.SkillGraph, sess *models.Session) bool {
+	if err := preflight(sk, sess.CWD); err != nil {
+		e.terminate(sess, models.StatusFailed, fmt.Sprintf("Refusing to run skill %s: %v", sk.Name, err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestPreflightPresentTools(t *testing.T) {
+	cwd := t.TempDir()
+	if err := os.WriteFile(filepath.Join(cwd, "build.sh"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	sk := &models.SkillGraph{RequiredTools: []string{"bash", "./build.sh", "test -f build.sh"}}
+	if err := preflight(sk, cwd); err != nil {
+		t.Errorf("preflight: %v", err)
+	}
+}
+
+func TestPreflightMissingTools(t *testing.T) {
+	cwd := t.TempDir()
+	sk := &models.SkillGraph{RequiredTools: []string{"bash", "tenazas-no-such-tool", "./missing.sh", "test -f nope"}}
+	err := preflight(sk, cwd)
+	if err == nil {
+		t.Fatal("expected preflight to fail")
+	}
+	for _, tool := range []string{"tenazas-no-such-tool", "./missing.sh", "test -f nope"} {
+		if !strings.Contains(err.Error(), tool) {
+			t.Errorf("error %q does not name %q", err, tool)
+		}
+	}
+	if strings.Contains(err.Error(), "bash") {
+		t.Errorf("error %q names a present tool", err)
+	}
+}
+
+func TestRunRefusesSkillWithMissingTools(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	sess := &models.Session{ID: "sess-missing-tool", CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	sk := &models.SkillGraph{
+		Name:          "needs-tool",
+		InitialState:  "check",
+		RequiredTools: []string{"tenazas-no-such-tool"},
+		States: map[string]models.StateDef{
+			"check": {Type: "tool", Command: "touch ran", Next: "done"},
+			"done":  {Type: "end"},
+		},
+	}
+	eng.Run(sk, sess)
+
+	if sess.Status != models.StatusFailed {
+		t.Errorf("status = %s, want failed", sess.Status)
+	}
+	if _, err := os.Stat(filepath.Join(storageDir, "ran")); err == nil {
+		t.Error("no state should run when a required tool is missing")
+	}
+	refused, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return strings.Contains(e.Content, "Refusing to run skill needs-tool") && strings.Contains(e.Content, "tenazas-no-such-tool")
+	})
+	if len(refused) != 1 {
+		t.Error("expected the refusal to name the missing tool")
+	}
+}
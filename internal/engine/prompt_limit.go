@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"fmt"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// Policies for prompts longer than Engine.MaxPromptChars.
+const (
+	PromptLimitTruncate = "truncate" // send the head of the prompt plus a marker
+	PromptLimitReject   = "reject"   // don't send the prompt at all
+)
+
+// limitPrompt enforces MaxPromptChars on prompt, counted in characters
+// (runes). It returns the prompt to send and false when the prompt was
+// rejected; either outcome is logged so the user sees why.
+func (e *Engine) limitPrompt(sess *models.Session, prompt string) (string, bool) {
+	if e.MaxPromptChars <= 0 {
+		return prompt, true
+	}
+	runes := []rune(prompt)
+	if len(runes) <= e.MaxPromptChars {
+		return prompt, true
+	}
+	if e.PromptLimitPolicy == PromptLimitReject {
+		e.logError(sess, "engine", fmt.Sprintf("Prompt rejected: %d characters exceeds the %d-character limit (max_prompt_chars). Shorten it and try again.", len(runes), e.MaxPromptChars))
+		return "", false
+	}
+	head, marker := truncatePrompt(runes, e.MaxPromptChars)
+	dropped := len(runes) - len(head)
+	e.log(sess, events.AuditInfo, "engine", fmt.Sprintf("Prompt truncated: %d characters exceeds the %d-character limit (max_prompt_chars); the last %d were dropped.", len(runes), e.MaxPromptChars, dropped), events.RoleSystem)
+	return string(head) + marker, true
+}
+
+// truncatePrompt cuts runes so that the kept head plus the marker naming
+// the dropped count fit in limit. Dropping more can lengthen the count, so
+// the head shrinks until both fit. A limit too small for the marker gets a
+// bare cut.
+func truncatePrompt(runes []rune, limit int) ([]rune, string) {
+	keep := limit
+	for keep > 0 {
+		marker := fmt.Sprintf("\n\n[... truncated %d characters ...]", len(runes)-keep)
+		if keep+len(marker) <= limit {
+			return runes[:keep], marker
+		}
+		keep = limit - len(marker)
+	}
+	return runes[:limit], ""
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestLimitPromptBoundary(t *testing.T) {
+	sm := session.NewManager(t.TempDir())
+	sess := &models.Session{ID: "limit", CWD: t.TempDir(), RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	for _, policy := range []string{PromptLimitTruncate, PromptLimitReject} {
+		eng := NewEngine(sm, nil, "gemini", 5)
+		eng.MaxPromptChars = 5
+		eng.PromptLimitPolicy = policy
+
+		// Characters, not bytes: five multi-byte runes fit exactly.
+		if got, ok := eng.limitPrompt(sess, "héllö"); !ok || got != "héllö" {
+			t.Errorf("%s: prompt at the limit = %q, %v; want it unchanged", policy, got, ok)
+		}
+
+		got, ok := eng.limitPrompt(sess, "héllö!")
+		switch policy {
+		case PromptLimitTruncate:
+			// No room for the marker under a 5-character limit: a bare cut.
+			if !ok || got != "héllö" {
+				t.Errorf("truncate: got %q, %v", got, ok)
+			}
+		case PromptLimitReject:
+			if ok || got != "" {
+				t.Errorf("reject: got %q, %v; want rejection", got, ok)
+			}
+		}
+	}
+
+	notes, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool { return e.Type == events.AuditInfo })
+	if len(notes) != 2 || !strings.Contains(notes[0].Content, "truncated") || !strings.Contains(notes[1].Content, "rejected") {
+		t.Errorf("expected one truncation and one rejection notice, got %+v", notes)
+	}
+	if len(notes) == 2 && (notes[0].Error || !notes[1].Error) {
+		t.Errorf("expected only the rejection to be flagged as an error, got %+v", notes)
+	}
+}
+
+func TestExecutePromptRejectsOverlongPrompt(t *testing.T) {
+	storageDir := t.TempDir()
+	marker := filepath.Join(storageDir, "invoked")
+	scriptPath := filepath.Join(storageDir, "client.sh")
+	os.WriteFile(scriptPath, []byte("#!/bin/sh\ntouch "+marker+"\necho '{\"type\": \"message\", \"content\": \"hi\"}'\n"), 0755)
+
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient(scriptPath, storageDir), "gemini", 5)
+	eng.MaxPromptChars = 10
+	eng.PromptLimitPolicy = PromptLimitReject
+	sess := &models.Session{ID: "reject", CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	eng.ExecutePrompt(sess, strings.Repeat("x", 11))
+
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("client was invoked for a rejected prompt")
+	}
+	prompts, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool { return e.Type == events.AuditLLMPrompt })
+	if len(prompts) != 0 {
+		t.Errorf("expected no prompt audit entries, got %d", len(prompts))
+	}
+}
+
+func TestExecutePromptTruncatesOverlongPrompt(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	eng.MaxPromptChars = 50
+	sess := &models.Session{ID: "truncate", CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	eng.ExecutePrompt(sess, strings.Repeat("x", 60))
+
+	prompts, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool { return e.Type == events.AuditLLMPrompt })
+	if len(prompts) != 1 {
+		t.Fatalf("expected one prompt audit entry, got %d", len(prompts))
+	}
+	// The marker counts against the limit: 15 characters plus 35 of marker.
+	if want := strings.Repeat("x", 15) + "\n\n[... truncated 45 characters ...]"; prompts[0].Content != want {
+		t.Errorf("sent prompt = %q, want %q", prompts[0].Content, want)
+	}
+}
+
+func TestTruncatePromptStaysWithinLimit(t *testing.T) {
+	for _, n := range []int{41, 60, 139, 140, 1000, 10050} {
+		for _, limit := range []int{40, 45, 100} {
+			if n <= limit {
+				continue
+			}
+			head, marker := truncatePrompt([]rune(strings.Repeat("x", n)), limit)
+			if got := len(head) + len([]rune(marker)); got > limit {
+				t.Errorf("n=%d limit=%d: sent %d characters", n, limit, got)
+			}
+			if marker != "" && !strings.Contains(marker, fmt.Sprintf(" %d characters", n-len(head))) {
+				t.Errorf("n=%d limit=%d: marker %q does not name the %d dropped", n, limit, marker, n-len(head))
+			}
+		}
+	}
+}
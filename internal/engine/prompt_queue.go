@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"fmt"
+	"sync"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// promptQueue holds the prompts waiting for the session's running entry.
+// Like a skillChain, it is drained by whoever holds that entry when it
+// finishes its own work; see releaseRun.
+type promptQueue struct {
+	mu      sync.Mutex
+	pending []string
+}
+
+// promptMode resolves the session's prompt mode, falling back to the engine
+// default and then to interrupt.
+func (e *Engine) promptMode(sess *models.Session) string {
+	if mode := e.Sm.Snapshot(sess).PromptMode; mode != "" {
+		return mode
+	}
+	if e.PromptMode != "" {
+		return e.PromptMode
+	}
+	return models.PromptModeInterrupt
+}
+
+// QueuedPrompts returns how many prompts are waiting behind the in-flight one.
+func (e *Engine) QueuedPrompts(sessionID string) int {
+	v, ok := e.promptQueues.Load(sessionID)
+	if !ok {
+		return 0
+	}
+	q := v.(*promptQueue)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// enqueuePrompt runs prompt once everything ahead of it has finished. If
+// the session is idle the caller runs it at once; otherwise the prompt is
+// appended to the queue and the call returns, leaving it to the run (or
+// prompt) holding the session.
+func (e *Engine) enqueuePrompt(sess *models.Session, prompt string) {
+	q := e.promptQueue(sess.ID)
+
+	q.mu.Lock()
+	if _, busy := e.running.LoadOrStore(sess.ID, true); busy {
+		q.pending = append(q.pending, prompt)
+		n := len(q.pending)
+		q.mu.Unlock()
+		e.log(sess, events.AuditInfo, "engine", fmt.Sprintf("Prompt queued (%d waiting)", n), events.RoleSystem)
+		return
+	}
+	q.mu.Unlock()
+	defer e.releaseRun(sess)
+	e.sendPrompt(sess, prompt)
+}
+
+func (e *Engine) promptQueue(sessID string) *promptQueue {
+	v, _ := e.promptQueues.LoadOrStore(sessID, &promptQueue{})
+	return v.(*promptQueue)
+}
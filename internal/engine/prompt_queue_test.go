@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+// slowFirstScript answers "reply:<prompt>", taking a while for the prompt "first".
+const slowFirstScript = `#!/bin/sh
+P=""
+while [ $# -gt 0 ]; do
+  if [ "$1" = "--prompt" ]; then P="$2"; fi
+  shift
+done
+echo '{"type": "init", "session_id": "sid-q"}'
+case "$P" in
+  *first*) sleep 1 ;;
+esac
+echo "{\"type\": \"message\", \"content\": \"reply:$P\"}"
+`
+
+func newPromptModeEngine(t *testing.T, mode string) (*Engine, *session.Manager, *models.Session) {
+	t.Helper()
+	storageDir := t.TempDir()
+	scriptPath := filepath.Join(storageDir, "slow.sh")
+	os.WriteFile(scriptPath, []byte(slowFirstScript), 0755)
+
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient(scriptPath, storageDir), "gemini", 5)
+	eng.PromptMode = mode
+	sess := &models.Session{ID: "sess-" + mode, CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+	return eng, sm, sess
+}
+
+func waitRunning(t *testing.T, eng *Engine, id string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !eng.IsRunning(id) {
+		if time.Now().After(deadline) {
+			t.Fatal("first prompt never started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Give the client process time to start before the second prompt arrives.
+	time.Sleep(200 * time.Millisecond)
+}
+
+func responses(sm *session.Manager, sess *models.Session) []string {
+	entries, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return e.Type == events.AuditLLMResponse
+	})
+	var out []string
+	for _, e := range entries {
+		out = append(out, e.Content)
+	}
+	return out
+}
+
+func TestExecutePrompt_InterruptCancelsInFlight(t *testing.T) {
+	eng, sm, sess := newPromptModeEngine(t, models.PromptModeInterrupt)
+
+	done := make(chan struct{})
+	go func() {
+		eng.ExecutePrompt(sess, "first")
+		close(done)
+	}()
+	waitRunning(t, eng, sess.ID)
+	eng.ExecutePrompt(sess, "second")
+	<-done
+
+	got := responses(sm, sess)
+	if len(got) != 1 || !strings.Contains(got[0], "reply:second") {
+		t.Fatalf("expected only the second prompt to answer, got %v", got)
+	}
+	cancelled, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return e.Content == "Operation cancelled by user"
+	})
+	if len(cancelled) != 1 {
+		t.Errorf("expected the first prompt to be cancelled, got %d cancellations", len(cancelled))
+	}
+}
+
+func TestExecutePrompt_QueueRunsInOrder(t *testing.T) {
+	eng, sm, sess := newPromptModeEngine(t, models.PromptModeQueue)
+
+	done := make(chan struct{})
+	go func() {
+		eng.ExecutePrompt(sess, "first")
+		close(done)
+	}()
+	waitRunning(t, eng, sess.ID)
+
+	// The second prompt returns as soon as it is queued.
+	eng.ExecutePrompt(sess, "second")
+	if n := eng.QueuedPrompts(sess.ID); n != 1 {
+		t.Errorf("expected 1 queued prompt, got %d", n)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("queue was not drained")
+	}
+
+	got := responses(sm, sess)
+	if len(got) != 2 || !strings.Contains(got[0], "reply:first") || !strings.Contains(got[1], "reply:second") {
+		t.Fatalf("expected first then second, got %v", got)
+	}
+	queued, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return strings.HasPrefix(e.Content, "Prompt queued")
+	})
+	if len(queued) != 1 {
+		t.Errorf("expected one queued notice, got %d", len(queued))
+	}
+}
+
+func TestExecutePrompt_QueueBehindRunStartsWhenItEnds(t *testing.T) {
+	eng, sm, sess := newPromptModeEngine(t, models.PromptModeQueue)
+	eng.waitPoll = 10 * time.Millisecond
+	holder := &models.SkillGraph{
+		Name:         "wait-go",
+		InitialState: "wait",
+		States: map[string]models.StateDef{
+			"wait": {Type: "wait", WaitFile: "go", Next: "end"},
+			"end":  {Type: "end"},
+		},
+	}
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		eng.Run(holder, sess)
+	}()
+	waitFor(t, "the run to start waiting", func() bool {
+		_, ok := eng.sessionCtxs.Load(sess.ID)
+		return ok
+	})
+
+	promptDone := make(chan struct{})
+	go func() {
+		defer close(promptDone)
+		eng.ExecutePrompt(sess, "second")
+	}()
+	select {
+	case <-promptDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("a prompt queued behind a run should return at once")
+	}
+	if n := eng.QueuedPrompts(sess.ID); n != 1 {
+		t.Fatalf("expected 1 queued prompt, got %d", n)
+	}
+	if got := responses(sm, sess); len(got) != 0 {
+		t.Fatalf("the queued prompt ran during the skill run: %v", got)
+	}
+
+	os.WriteFile(filepath.Join(sess.CWD, "go"), []byte("ok"), 0644)
+	select {
+	case <-runDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run did not finish")
+	}
+	if got := responses(sm, sess); len(got) != 1 || !strings.Contains(got[0], "reply:second") {
+		t.Errorf("expected the queued prompt to run after the skill, got %v", got)
+	}
+	if eng.IsRunning(sess.ID) || eng.QueuedPrompts(sess.ID) != 0 {
+		t.Error("expected the session to be free once the queue drained")
+	}
+}
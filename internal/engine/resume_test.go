@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func promptEntries(sm *session.Manager, sess *models.Session) []events.AuditEntry {
+	entries, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return e.Type == events.AuditLLMPrompt || e.Type == events.AuditResume
+	})
+	return entries
+}
+
+func TestResumePromptIsSystemNote(t *testing.T) {
+	storageDir := t.TempDir()
+	scriptPath := filepath.Join(storageDir, "ok.sh")
+	os.WriteFile(scriptPath, []byte("#!/bin/sh\necho '{\"type\": \"message\", \"content\": \"ok\"}'\n"), 0755)
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient(scriptPath, storageDir), "gemini", 5)
+
+	sk := &models.SkillGraph{
+		Name:         "resumable",
+		InitialState: "work",
+		States: map[string]models.StateDef{
+			"work": {Type: "action_loop", Instruction: "keep going", Next: "end"},
+			"end":  {Type: "end"},
+		},
+	}
+	// An interrupted run: still running, parked at a node, no feedback.
+	sess := &models.Session{
+		ID:         "resumed",
+		CWD:        storageDir,
+		ActiveNode: "work",
+		Status:     models.StatusRunning,
+		RoleCache:  make(map[string]string),
+	}
+	sm.Save(sess)
+	eng.Run(sk, sess)
+
+	entries := promptEntries(sm, sess)
+	if len(entries) != 1 {
+		t.Fatalf("expected one prompt entry, got %+v", entries)
+	}
+	if entries[0].Type != events.AuditResume || entries[0].Role != events.RoleSystem {
+		t.Errorf("resume prompt logged as %s/%s, want %s/%s", entries[0].Type, entries[0].Role, events.AuditResume, events.RoleSystem)
+	}
+}
+
+func TestFreshPromptIsUserInput(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+
+	sk := &models.SkillGraph{
+		Name:         "fresh",
+		InitialState: "work",
+		States: map[string]models.StateDef{
+			"work": {Type: "action_loop", Instruction: "start", Next: "end"},
+			"end":  {Type: "end"},
+		},
+	}
+	sess := &models.Session{ID: "fresh", CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+	eng.Run(sk, sess)
+
+	entries := promptEntries(sm, sess)
+	if len(entries) != 1 || entries[0].Type != events.AuditLLMPrompt || entries[0].Role != events.RoleUser {
+		t.Errorf("expected one user prompt, got %+v", entries)
+	}
+}
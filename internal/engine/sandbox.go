@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tenazas/internal/models"
+)
+
+// CheckCWD returns an error when cwd is outside the AllowedDirs of the named
+// client ("" means the default client). Clients without AllowedDirs may work
+// anywhere.
+func (e *Engine) CheckCWD(clientName, cwd string) error {
+	if clientName == "" {
+		clientName = e.DefaultClient
+	}
+	allowed := e.AllowedDirs[clientName]
+	if len(allowed) == 0 || isWithinAllowed(cwd, allowed) {
+		return nil
+	}
+	return fmt.Errorf("directory %s is outside the allowed_dirs of client %s", cwd, clientName)
+}
+
+// cwdAllowed fails a skill run whose session directory the client may not use.
+func (e *Engine) cwdAllowed(sess *models.Session) bool {
+	if err := e.CheckCWD(sess.Client, sess.CWD); err != nil {
+		e.terminate(sess, models.StatusFailed, "Refusing to run: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// promptCWDAllowed is cwdAllowed for plain prompts, which leave the session
+// usable: the refusal is only logged.
+func (e *Engine) promptCWDAllowed(sess *models.Session) bool {
+	if err := e.CheckCWD(sess.Client, sess.CWD); err != nil {
+		e.logError(sess, "engine", "Prompt rejected: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// shellAllowed reports whether RunShell may use cwd. RunShell has no
+// session, so a directory passes when any client could work there: it is
+// unrestricted only while some client has no AllowedDirs.
+func (e *Engine) shellAllowed(cwd string) bool {
+	if len(e.AllowedDirs) == 0 {
+		return true
+	}
+	var union []string
+	for name := range e.Clients {
+		dirs := e.AllowedDirs[name]
+		if len(dirs) == 0 {
+			return true
+		}
+		union = append(union, dirs...)
+	}
+	return isWithinAllowed(cwd, union)
+}
+
+// isWithinAllowed reports whether cwd is one of the allowed directories or
+// below one. Both sides are made absolute, cleaned of ".." and resolved
+// through symlinks first, so neither a traversal nor a link pointing
+// outside escapes the sandbox.
+func isWithinAllowed(cwd string, allowed []string) bool {
+	target, err := canonicalDir(cwd)
+	if err != nil {
+		return false
+	}
+	for _, dir := range allowed {
+		root, err := canonicalDir(dir)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(root, target)
+		if err != nil {
+			continue
+		}
+		if rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))) {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalDir returns the absolute, symlink-free form of dir. A path that
+// doesn't exist yet is resolved through its deepest existing parent.
+func canonicalDir(dir string) (string, error) {
+	if strings.HasPrefix(dir, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, dir[1:])
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	var missing []string
+	for {
+		resolved, err := filepath.EvalSymlinks(abs)
+		if err == nil {
+			return filepath.Join(append([]string{resolved}, missing...)...), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return "", err
+		}
+		missing = append([]string{filepath.Base(abs)}, missing...)
+		abs = parent
+	}
+}
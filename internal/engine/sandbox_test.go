@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestIsWithinAllowed(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "projects", "app")
+	other := filepath.Join(root, "secrets")
+	os.MkdirAll(filepath.Join(project, "src"), 0755)
+	os.MkdirAll(other, 0755)
+	link := filepath.Join(project, "escape")
+	if err := os.Symlink(other, link); err != nil {
+		t.Fatal(err)
+	}
+	allowed := []string{filepath.Join(root, "projects")}
+
+	cases := []struct {
+		cwd  string
+		want bool
+	}{
+		{project, true},
+		{filepath.Join(project, "src"), true},
+		{filepath.Join(root, "projects"), true},
+		{filepath.Join(project, "not-created-yet"), true},
+		{other, false},
+		{root, false},
+		{filepath.Join(root, "projects-old"), false},
+		{filepath.Join(project, "..", "..", "secrets"), false},
+		{link, false},
+	}
+	for _, c := range cases {
+		if got := isWithinAllowed(c.cwd, allowed); got != c.want {
+			t.Errorf("isWithinAllowed(%q) = %v, want %v", c.cwd, got, c.want)
+		}
+	}
+}
+
+func TestAllowedDirsRefuseRunAndShell(t *testing.T) {
+	storageDir := t.TempDir()
+	allowedDir := t.TempDir()
+	script := filepath.Join(storageDir, "ok.sh")
+	os.WriteFile(script, []byte("#!/bin/sh\necho '{\"type\": \"message\", \"content\": \"done\"}'\n"), 0755)
+
+	sm := session.NewManager(storageDir)
+	e := NewEngine(sm, newTestClient(script, storageDir), "gemini", 5)
+	e.AllowedDirs = map[string][]string{"gemini": {allowedDir}}
+	sk := &models.SkillGraph{
+		Name:         "stub",
+		InitialState: "ask",
+		States: map[string]models.StateDef{
+			"ask": {Type: "action_loop", SessionRole: "coder", Instruction: "go", Next: "end"},
+			"end": {Type: "end"},
+		},
+	}
+
+	inside := &models.Session{ID: "sandbox-in", CWD: allowedDir, RoleCache: map[string]string{}}
+	sm.Save(inside)
+	e.Run(sk, inside)
+	if inside.Status != models.StatusCompleted {
+		t.Errorf("a run inside allowed_dirs ended %s", inside.Status)
+	}
+
+	outside := &models.Session{ID: "sandbox-out", CWD: storageDir, RoleCache: map[string]string{}}
+	sm.Save(outside)
+	e.Run(sk, outside)
+	if outside.Status != models.StatusFailed {
+		t.Errorf("a run outside allowed_dirs ended %s, want failed", outside.Status)
+	}
+	last, _ := sm.GetLastAudit(outside, 1)
+	if len(last) != 1 || !strings.Contains(last[0].Content, "outside the allowed_dirs of client gemini") {
+		t.Errorf("expected the refusal in the audit log, got %+v", last)
+	}
+
+	if code, out := e.RunShell("echo hi", filepath.Join(allowedDir, "..", filepath.Base(storageDir))); code == 0 || !strings.Contains(out, "allowed_dirs") {
+		t.Errorf("RunShell escaping the sandbox = %d %q, want a refusal", code, out)
+	}
+	if code, out := e.RunShell("echo hi", allowedDir); code != 0 || out != "hi\n" {
+		t.Errorf("RunShell inside the sandbox = %d %q", code, out)
+	}
+
+	e.AllowedDirs = nil
+	if err := e.CheckCWD("", storageDir); err != nil {
+		t.Errorf("no allowed_dirs should be unrestricted, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// skillChain holds the skills waiting to run one after another on a session.
+// Whoever holds the session's running entry when it finishes its own work
+// runs them, so no other run can slip in between two skills of the chain.
+type skillChain struct {
+	mu              sync.Mutex
+	pending         []*models.SkillGraph
+	continueOnError bool
+}
+
+// RunChain runs skills in order on sess, each starting once the previous one
+// has finished. A skill that does not complete stops the chain unless
+// continueOnError is set. If the session is idle RunChain blocks until the
+// chain is done; otherwise the skills are appended to its chain and RunChain
+// returns at once, leaving them to the run (or prompt) holding the session.
+func (e *Engine) RunChain(sess *models.Session, skills []*models.SkillGraph, continueOnError bool) {
+	if len(skills) == 0 {
+		return
+	}
+	q := e.skillChain(sess.ID)
+
+	q.mu.Lock()
+	q.pending = append(q.pending, skills...)
+	q.continueOnError = q.continueOnError || continueOnError
+	if _, busy := e.running.LoadOrStore(sess.ID, true); busy {
+		n := len(q.pending)
+		q.mu.Unlock()
+		e.log(sess, events.AuditInfo, "engine", fmt.Sprintf("Skill queued: %s (%d waiting)", skillNames(skills), n), events.RoleSystem)
+		return
+	}
+	q.mu.Unlock()
+	e.releaseRun(sess)
+}
+
+func (e *Engine) skillChain(sessID string) *skillChain {
+	v, _ := e.skillChains.LoadOrStore(sessID, &skillChain{})
+	return v.(*skillChain)
+}
+
+// releaseRun runs the skills chained on sess and then the queued prompts,
+// and gives up the session's running entry. The caller holds that entry;
+// keeping it across the chain is what keeps each skill's Run from finding
+// the session busy. The entry is dropped under the chain's and the prompt
+// queue's locks so RunChain and enqueuePrompt either see it held and queue,
+// or see it free and run their work themselves.
+func (e *Engine) releaseRun(sess *models.Session) {
+	q := e.skillChain(sess.ID)
+	pq := e.promptQueue(sess.ID)
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.continueOnError = false
+			pq.mu.Lock()
+			if len(pq.pending) == 0 {
+				e.running.Delete(sess.ID)
+				pq.mu.Unlock()
+				q.mu.Unlock()
+				return
+			}
+			prompt := pq.pending[0]
+			pq.pending = pq.pending[1:]
+			pq.mu.Unlock()
+			q.mu.Unlock()
+			e.sendPrompt(sess, prompt)
+			continue
+		}
+		sk := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		e.resetForSkill(sess, sk)
+		e.run(sk, sess)
+
+		q.mu.Lock()
+		if sess.Status == models.StatusCompleted || q.continueOnError {
+			q.mu.Unlock()
+			continue
+		}
+		skipped := q.pending
+		q.pending = nil
+		q.mu.Unlock()
+		if len(skipped) > 0 {
+			e.log(sess, events.AuditInfo, "engine", fmt.Sprintf("Skill chain stopped: %s ended %s; skipped %s", sk.Name, sess.Status, skillNames(skipped)), events.RoleSystem)
+		}
+	}
+}
+
+// QueuedSkills returns the names of the skills waiting in the session's
+// chain, and whether the chain goes on past failures.
+func (e *Engine) QueuedSkills(sessionID string) ([]string, bool) {
+	v, ok := e.skillChains.Load(sessionID)
+	if !ok {
+		return nil, false
+	}
+	q := v.(*skillChain)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	names := make([]string, len(q.pending))
+	for i, sk := range q.pending {
+		names[i] = sk.Name
+	}
+	return names, q.continueOnError
+}
+
+// resetForSkill clears the previous run's position so the next Run of sk
+// starts at its initial state.
+func (e *Engine) resetForSkill(sess *models.Session, sk *models.SkillGraph) {
+	e.Sm.Update(sess, func(s *models.Session) {
+		s.SkillName = sk.Name
+		s.ActiveNode = ""
+		s.Status = models.StatusIdle
+		s.RetryCount = 0
+		s.LoopCount = 0
+		s.PendingFeedback = ""
+		s.LastGoodNode = ""
+	})
+}
+
+func skillNames(skills []*models.SkillGraph) string {
+	names := make([]string, len(skills))
+	for i, sk := range skills {
+		names[i] = sk.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func chainSkill(name, initial string) *models.SkillGraph {
+	return &models.SkillGraph{
+		Name:         name,
+		InitialState: initial,
+		States:       map[string]models.StateDef{"end": {Type: "end"}},
+	}
+}
+
+// brokenSkill starts but fails at once on a state of unknown type.
+func brokenSkill(name string) *models.SkillGraph {
+	return &models.SkillGraph{
+		Name:         name,
+		InitialState: "bad",
+		States: map[string]models.StateDef{
+			"bad": {Type: "bogus", Next: "end"},
+			"end": {Type: "end"},
+		},
+	}
+}
+
+// startedSkills lists the skills whose runs started, in order.
+func startedSkills(sm *session.Manager, sess *models.Session) []string {
+	entries, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return e.Type == events.AuditStatus && strings.HasPrefix(e.Content, "Started skill ")
+	})
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.Fields(e.Content)[2])
+	}
+	return names
+}
+
+func setupChain(t *testing.T) (*Engine, *session.Manager, *models.Session) {
+	t.Helper()
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	sess := &models.Session{ID: "chain", CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+	return eng, sm, sess
+}
+
+func TestRunChainRunsSkillsInOrder(t *testing.T) {
+	eng, sm, sess := setupChain(t)
+
+	eng.RunChain(sess, []*models.SkillGraph{chainSkill("first", "end"), chainSkill("second", "end")}, false)
+
+	if got := strings.Join(startedSkills(sm, sess), ","); got != "first,second" {
+		t.Errorf("started %q, want first,second", got)
+	}
+	if sess.Status != models.StatusCompleted || sess.SkillName != "second" {
+		t.Errorf("expected the last skill to complete, got %s/%s", sess.SkillName, sess.Status)
+	}
+	if queued, _ := eng.QueuedSkills(sess.ID); len(queued) != 0 {
+		t.Errorf("expected an empty queue, got %v", queued)
+	}
+}
+
+func TestRunChainStopsOnFailure(t *testing.T) {
+	eng, sm, sess := setupChain(t)
+
+	eng.RunChain(sess, []*models.SkillGraph{brokenSkill("broken"), chainSkill("after", "end")}, false)
+
+	if got := strings.Join(startedSkills(sm, sess), ","); got != "broken" {
+		t.Errorf("started %q, want only broken", got)
+	}
+	stopped, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return strings.HasPrefix(e.Content, "Skill chain stopped: broken ended failed; skipped after")
+	})
+	if len(stopped) != 1 {
+		t.Error("expected the chain stop to be logged")
+	}
+}
+
+func TestRunChainContinueOnError(t *testing.T) {
+	eng, sm, sess := setupChain(t)
+
+	eng.RunChain(sess, []*models.SkillGraph{brokenSkill("broken"), chainSkill("after", "end")}, true)
+
+	if got := strings.Join(startedSkills(sm, sess), ","); got != "broken,after" {
+		t.Errorf("started %q, want broken,after", got)
+	}
+	if sess.Status != models.StatusCompleted {
+		t.Errorf("expected the second skill to complete, got %s", sess.Status)
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRunChainBehindRunStartsWhenItEnds(t *testing.T) {
+	eng, sm, sess := setupChain(t)
+	eng.waitPoll = 10 * time.Millisecond
+	holder := &models.SkillGraph{
+		Name:         "wait-go",
+		InitialState: "wait",
+		States: map[string]models.StateDef{
+			"wait": {Type: "wait", WaitFile: "go", Next: "end"},
+			"end":  {Type: "end"},
+		},
+	}
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		eng.Run(holder, sess)
+	}()
+	waitFor(t, "the run to start waiting", func() bool {
+		_, ok := eng.sessionCtxs.Load(sess.ID)
+		return ok
+	})
+
+	chainDone := make(chan struct{})
+	go func() {
+		defer close(chainDone)
+		eng.RunChain(sess, []*models.SkillGraph{chainSkill("next", "end")}, false)
+	}()
+	select {
+	case <-chainDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("RunChain on a busy session should queue and return")
+	}
+	if queued, _ := eng.QueuedSkills(sess.ID); strings.Join(queued, ",") != "next" {
+		t.Fatalf("expected next to be queued, got %v", queued)
+	}
+
+	os.WriteFile(filepath.Join(sess.CWD, "go"), []byte("ok"), 0644)
+	select {
+	case <-runDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run did not finish")
+	}
+	if got := strings.Join(startedSkills(sm, sess), ","); got != "wait-go,next" {
+		t.Errorf("started %q, want wait-go,next", got)
+	}
+	if sess.Status != models.StatusCompleted || sess.SkillName != "next" || eng.IsRunning(sess.ID) {
+		t.Errorf("expected next to complete and free the session, got %s/%s", sess.SkillName, sess.Status)
+	}
+}
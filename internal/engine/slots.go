@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// runSlots returns the semaphore limiting concurrent runs and prompts, or
+// nil when MaxConcurrentSessions leaves them unlimited.
+func (e *Engine) runSlots() chan struct{} {
+	e.slotsOnce.Do(func() {
+		if e.MaxConcurrentSessions > 0 {
+			e.slots = make(chan struct{}, e.MaxConcurrentSessions)
+		}
+	})
+	return e.slots
+}
+
+// runSlot is a run's or prompt's claim on one of the MaxConcurrentSessions
+// slots. Without a limit it holds nothing and every call succeeds.
+type runSlot struct {
+	slots chan struct{}
+	held  bool
+}
+
+// release frees the slot; releasing one not held does nothing.
+func (s *runSlot) release() {
+	if s.held {
+		s.held = false
+		<-s.slots
+	}
+}
+
+// acquireSlot takes a slot for sess, waiting with a "queued" status entry
+// when all are busy. The session keeps counting as running while it waits.
+// CancelSession abandons the wait, and ok is then false.
+func (e *Engine) acquireSlot(sess *models.Session) (slot *runSlot, ok bool) {
+	slot = &runSlot{slots: e.runSlots()}
+	if slot.slots == nil {
+		return slot, true
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e.cancelFns.Store(sess.ID, cancel)
+	defer e.cancelFns.Delete(sess.ID)
+	return slot, e.retakeSlot(ctx, sess, slot)
+}
+
+// retakeSlot waits for slot to be held again, giving up once ctx is done.
+// Run uses it to get back the slot it freed while waiting on a human.
+func (e *Engine) retakeSlot(ctx context.Context, sess *models.Session, slot *runSlot) bool {
+	if slot.slots == nil || slot.held {
+		return true
+	}
+	select {
+	case slot.slots <- struct{}{}:
+		slot.held = true
+		return true
+	default:
+	}
+
+	e.log(sess, events.AuditStatus, "engine", fmt.Sprintf("Session queued: %d sessions already running (max_concurrent_sessions)", cap(slot.slots)), events.RoleSystem)
+	defer e.quietly(sess.ID)()
+	select {
+	case slot.slots <- struct{}{}:
+		slot.held = true
+		e.log(sess, events.AuditStatus, "engine", "Session dequeued, starting", events.RoleSystem)
+		return true
+	case <-ctx.Done():
+		e.log(sess, events.AuditStatus, "engine", "Session cancelled while queued", events.RoleSystem)
+		return false
+	}
+}
@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"tenazas/internal/client"
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+// gatedClient holds every Run until release is closed, counting how many
+// are in flight.
+type gatedClient struct {
+	release chan struct{}
+	active  atomic.Int32
+}
+
+func (c *gatedClient) Name() string { return "gemini" }
+
+func (c *gatedClient) Run(opts client.RunOptions, onChunk func(string), onSessionID func(string)) (string, error) {
+	c.active.Add(1)
+	defer c.active.Add(-1)
+	<-c.release
+	onChunk("ok")
+	return "ok", nil
+}
+
+func (c *gatedClient) SetModels(map[string]string) {}
+func (c *gatedClient) ResolveModel(string) string  { return "" }
+func (c *gatedClient) AvailableCommands() []string { return nil }
+
+func TestMaxConcurrentSessionsQueuesExtraStarts(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	c := &gatedClient{release: make(chan struct{})}
+	eng := NewEngine(sm, map[string]client.Client{"gemini": c}, "gemini", 5)
+	eng.MaxConcurrentSessions = 2
+
+	var sessions []*models.Session
+	done := make([]chan struct{}, 3)
+	for i := range done {
+		sess := &models.Session{ID: fmt.Sprintf("slot-%d", i), CWD: storageDir, RoleCache: make(map[string]string)}
+		sm.Save(sess)
+		sessions = append(sessions, sess)
+		done[i] = make(chan struct{})
+		go func(i int) {
+			defer close(done[i])
+			eng.ExecutePrompt(sessions[i], "hello")
+		}(i)
+		if i < 2 {
+			waitFor(t, "a slot to be taken", func() bool { return int(c.active.Load()) == i+1 })
+		}
+	}
+
+	last := sessions[2]
+	waitFor(t, "the third session to queue", func() bool {
+		queued, _ := sm.FilterAudit(last, func(e events.AuditEntry) bool {
+			return e.Type == events.AuditStatus && strings.HasPrefix(e.Content, "Session queued")
+		})
+		return len(queued) == 1
+	})
+	if !eng.IsRunning(last.ID) {
+		t.Error("a queued session should still count as running")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := c.active.Load(); n != 2 {
+		t.Fatalf("%d prompts in flight, want 2 while the third waits", n)
+	}
+
+	close(c.release)
+	for i, ch := range done {
+		select {
+		case <-ch:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("prompt %d never finished", i)
+		}
+	}
+	if entries, _ := sm.GetLastAudit(last, 1); len(entries) == 0 || entries[0].Type != events.AuditLLMResponse {
+		t.Errorf("queued prompt should run once a slot frees, last entry %+v", entries)
+	}
+}
+
+func TestCancelWhileQueued(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	c := &gatedClient{release: make(chan struct{})}
+	eng := NewEngine(sm, map[string]client.Client{"gemini": c}, "gemini", 5)
+	eng.MaxConcurrentSessions = 1
+
+	busy := &models.Session{ID: "busy", CWD: storageDir, RoleCache: make(map[string]string)}
+	waiting := &models.Session{ID: "waiting", CWD: storageDir, RoleCache: make(map[string]string)}
+	sm.Save(busy)
+	sm.Save(waiting)
+	busyDone := make(chan struct{})
+	go func() {
+		defer close(busyDone)
+		eng.ExecutePrompt(busy, "hello")
+	}()
+	defer func() {
+		close(c.release)
+		<-busyDone
+	}()
+	waitFor(t, "the slot to be taken", func() bool { return c.active.Load() == 1 })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		eng.ExecutePrompt(waiting, "hello")
+	}()
+	waitFor(t, "the second session to queue", func() bool {
+		_, ok := eng.cancelFns.Load(waiting.ID)
+		return ok
+	})
+	eng.CancelSession(waiting.ID)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("cancel did not end the queued wait")
+	}
+	if eng.IsRunning(waiting.ID) || c.active.Load() != 1 {
+		t.Error("a cancelled queued session should neither run nor hold a slot")
+	}
+}
+
+func TestInterventionPromptGetsSlot(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	c := &scriptedClient{turns: []scriptedTurn{{text: "fixed"}}}
+	eng := NewEngine(sm, map[string]client.Client{"gemini": c}, "gemini", 5)
+	eng.MaxConcurrentSessions = 1
+
+	sess := &models.Session{
+		ID:         "blocked",
+		CWD:        storageDir,
+		Status:     models.StatusIntervention,
+		ActiveNode: "check",
+		RoleCache:  make(map[string]string),
+	}
+	sm.Save(sess)
+	skill := &models.SkillGraph{Name: "s", InitialState: "check", States: map[string]models.StateDef{
+		"check": {Type: "tool", Command: "true", Next: "end"},
+		"end":   {Type: "end"},
+	}}
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		eng.Run(skill, sess)
+	}()
+	waitFor(t, "the run to block on the intervention", func() bool { return eng.AwaitingIntervention(sess.ID) })
+
+	// Answering the intervention with a prompt needs the only slot.
+	promptDone := make(chan struct{})
+	go func() {
+		defer close(promptDone)
+		eng.runPrompt(sess, "try again with the fix")
+	}()
+	for _, ch := range []chan struct{}{promptDone, runDone} {
+		select {
+		case <-ch:
+		case <-time.After(3 * time.Second):
+			t.Fatal("the intervention prompt starved waiting for the blocked run's slot")
+		}
+	}
+	if sess.Status != models.StatusCompleted {
+		t.Errorf("run ended %s, want it to resume and complete", sess.Status)
+	}
+	if len(c.prompts) != 1 {
+		t.Errorf("client got %d prompts, want 1", len(c.prompts))
+	}
+}
@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"sort"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// StopAll cancels every run and prompt in flight in this engine, including
+// sessions waiting on an intervention, drops their queued skills and prompts,
+// and returns their IDs. Each session is left idle with reason logged once
+// its run unwinds.
+func (e *Engine) StopAll(reason string) []string {
+	var ids []string
+	e.running.Range(func(k, _ interface{}) bool {
+		ids = append(ids, k.(string))
+		return true
+	})
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		e.stopped.Store(id, reason)
+		e.dropQueued(id)
+		e.CancelSession(id)
+		if _, waiting := e.awaiting.Load(id); waiting {
+			// An unknown action makes awaitIntervention return without
+			// changing the session; the cancelled context ends the run.
+			select {
+			case e.getInterventionChan(id) <- "stop":
+			default:
+			}
+		}
+	}
+	return ids
+}
+
+// dropQueued discards the skills and prompts waiting behind a session's
+// current run, so its chain or queue ends with the run.
+func (e *Engine) dropQueued(sessID string) {
+	if v, ok := e.skillChains.Load(sessID); ok {
+		q := v.(*skillChain)
+		q.mu.Lock()
+		q.pending = nil
+		q.mu.Unlock()
+	}
+	if v, ok := e.promptQueues.Load(sessID); ok {
+		q := v.(*promptQueue)
+		q.mu.Lock()
+		q.pending = nil
+		q.mu.Unlock()
+	}
+}
+
+// markStopped leaves a session stopped by StopAll idle and reports whether
+// it did so.
+func (e *Engine) markStopped(sess *models.Session) bool {
+	v, ok := e.stopped.LoadAndDelete(sess.ID)
+	if !ok {
+		return false
+	}
+	reason := v.(string)
+	e.Sm.Update(sess, func(s *models.Session) { s.Status = models.StatusIdle })
+	e.log(sess, events.AuditStatus, "engine", "Status: idle - Stopped: "+reason, events.RoleSystem)
+	e.publishTaskStatus(sess.ID, events.TaskStateStopped, map[string]string{"reason": reason})
+	e.notifyWebhook(sess, models.StatusIdle, "Stopped: "+reason)
+	return true
+}
@@ -0,0 +1,29 @@
+package engine
+
+import (
+	"tenazas/internal/client"
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// stopReasonNotes explains the stop reasons that leave a response short of
+// a normal end of turn.
+var stopReasonNotes = map[string]string{
+	client.StopMaxTokens:       "the response hit the model's output limit and may be cut off",
+	client.StopMaxTurnRequests: "the agent hit its request limit for one turn",
+	client.StopRefusal:         "the model refused to continue",
+	client.StopCancelled:       "the client cancelled the turn",
+}
+
+// logStopReason notes in the session why a call ended, unless it ended
+// normally.
+func (e *Engine) logStopReason(sess *models.Session, role, reason string) {
+	if reason == "" || reason == client.StopEndTurn {
+		return
+	}
+	msg := "Stop reason: " + reason
+	if note := stopReasonNotes[reason]; note != "" {
+		msg += " (" + note + ")"
+	}
+	e.log(sess, events.AuditInfo, role, msg, events.RoleSystem)
+}
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"tenazas/internal/client"
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+// scriptedTurn is one answer of a scriptedClient.
+type scriptedTurn struct {
+	text string
+	stop string // reported through OnStopReason when set
+}
+
+// scriptedClient answers each Run with its next turn, repeating the last.
+type scriptedClient struct {
+	turns   []scriptedTurn
+	prompts []string
+}
+
+func (c *scriptedClient) Name() string { return "gemini" }
+
+func (c *scriptedClient) Run(opts client.RunOptions, onChunk func(string), onSessionID func(string)) (string, error) {
+	c.prompts = append(c.prompts, opts.Prompt)
+	turn := c.turns[0]
+	if len(c.turns) > 1 {
+		c.turns = c.turns[1:]
+	}
+	onChunk(turn.text)
+	if turn.stop != "" && opts.OnStopReason != nil {
+		opts.OnStopReason(turn.stop)
+	}
+	return turn.text, nil
+}
+
+func (c *scriptedClient) SetModels(map[string]string) {}
+func (c *scriptedClient) ResolveModel(string) string  { return "" }
+func (c *scriptedClient) AvailableCommands() []string { return nil }
+
+func TestStopReasonLogged(t *testing.T) {
+	for _, tc := range []struct {
+		reason string
+		want   string
+	}{
+		{client.StopEndTurn, ""},
+		{client.StopMaxTokens, "Stop reason: max_tokens (the response hit the model's output limit and may be cut off)"},
+		{client.StopRefusal, "Stop reason: refusal (the model refused to continue)"},
+		{"something_new", "Stop reason: something_new"},
+	} {
+		t.Run(tc.reason, func(t *testing.T) {
+			storageDir := t.TempDir()
+			sm := session.NewManager(storageDir)
+			c := &scriptedClient{turns: []scriptedTurn{{text: "hi", stop: tc.reason}}}
+			eng := NewEngine(sm, map[string]client.Client{"gemini": c}, "gemini", 5)
+			sess := &models.Session{ID: "stop", CWD: storageDir, RoleCache: make(map[string]string)}
+			sm.Save(sess)
+
+			eng.ExecutePrompt(sess, "hello")
+
+			notes, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+				return e.Type == events.AuditInfo && strings.HasPrefix(e.Content, "Stop reason")
+			})
+			switch {
+			case tc.want == "" && len(notes) != 0:
+				t.Errorf("a normal end of turn should not be logged, got %q", notes[0].Content)
+			case tc.want != "" && (len(notes) != 1 || notes[0].Content != tc.want):
+				t.Errorf("expected %q, got %+v", tc.want, notes)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestStopAllCancelsEveryRun(t *testing.T) {
+	storageDir := t.TempDir()
+	script := filepath.Join(storageDir, "ok.sh")
+	os.WriteFile(script, []byte("#!/bin/sh\necho '{\"type\": \"message\", \"content\": \"done\"}'\n"), 0755)
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient(script, storageDir), "gemini", 5)
+	eng.waitPoll = 20 * time.Millisecond
+
+	waiting := &models.SkillGraph{
+		Name:         "wait-forever",
+		InitialState: "wait",
+		States: map[string]models.StateDef{
+			"wait": {Type: "wait", WaitFile: "never", Next: "done"},
+			"done": {Type: "end"},
+		},
+	}
+	blocked := &models.SkillGraph{
+		Name:         "needs-help",
+		InitialState: "work",
+		States: map[string]models.StateDef{
+			"work": {Type: "action_loop", SessionRole: "coder", Instruction: "fix", VerifyCmd: "exit 1", Next: "done"},
+			"done": {Type: "end"},
+		},
+	}
+
+	var sessions []*models.Session
+	var wg sync.WaitGroup
+	for i, sk := range []*models.SkillGraph{waiting, waiting, blocked} {
+		sess := &models.Session{ID: "stop-" + string(rune('a'+i)), CWD: storageDir, RoleCache: map[string]string{}}
+		sm.Save(sess)
+		sessions = append(sessions, sess)
+		wg.Add(1)
+		go func(sk *models.SkillGraph, sess *models.Session) {
+			defer wg.Done()
+			eng.Run(sk, sess)
+		}(sk, sess)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, awaiting := eng.awaiting.Load("stop-c")
+		if awaiting && eng.IsRunning("stop-a") && eng.IsRunning("stop-b") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("sessions did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ids := eng.StopAll("maintenance")
+	if strings.Join(ids, ",") != "stop-a,stop-b,stop-c" {
+		t.Errorf("StopAll returned %v", ids)
+	}
+
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("runs did not stop")
+	}
+
+	for _, sess := range sessions {
+		if eng.IsRunning(sess.ID) {
+			t.Errorf("%s still running", sess.ID)
+		}
+		if sess.Status != models.StatusIdle {
+			t.Errorf("%s status = %s, want idle", sess.ID, sess.Status)
+		}
+		logged, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+			return e.Type == events.AuditStatus && strings.Contains(e.Content, "Stopped: maintenance")
+		})
+		if len(logged) != 1 {
+			t.Errorf("%s: expected one stop entry, got %d", sess.ID, len(logged))
+		}
+	}
+}
+
+func TestStopAllWithNothingRunning(t *testing.T) {
+	eng := NewEngine(session.NewManager(t.TempDir()), nil, "gemini", 5)
+	if ids := eng.StopAll("idle"); len(ids) != 0 {
+		t.Errorf("StopAll returned %v, want none", ids)
+	}
+}
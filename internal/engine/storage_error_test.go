@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestRunSurvivesStorageWriteErrors(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+
+	sess := &models.Session{ID: "ro-run", CWD: t.TempDir(), RoleCache: make(map[string]string)}
+	// A file where the session's workspace directory belongs fails every write.
+	workspace := filepath.Join(storageDir, sm.Storage.WorkspaceDir(sess.CWD))
+	os.MkdirAll(filepath.Dir(workspace), 0755)
+	os.WriteFile(workspace, []byte("x"), 0644)
+
+	ch := events.GlobalBus.Subscribe()
+	defer events.GlobalBus.Unsubscribe(ch)
+
+	skill := &models.SkillGraph{
+		Name:         "ro-skill",
+		InitialState: "build",
+		States: map[string]models.StateDef{
+			"build": {Type: "tool", Command: "true", Next: "end"},
+			"end":   {Type: "end"},
+		},
+	}
+	done := make(chan struct{})
+	go func() {
+		eng.Run(skill, sess)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the run to finish despite write errors")
+	}
+	if sess.Status != models.StatusCompleted {
+		t.Errorf("expected the in-memory run to complete, got %s", sess.Status)
+	}
+
+	warnings := 0
+	for {
+		select {
+		case e := <-ch:
+			if _, ok := e.Payload.(events.StorageErrorPayload); ok && e.SessionID == sess.ID {
+				warnings++
+			}
+			continue
+		case <-time.After(50 * time.Millisecond):
+		}
+		break
+	}
+	if warnings == 0 || warnings > 2 {
+		t.Errorf("expected one warning per kind of write, got %d", warnings)
+	}
+}
@@ -0,0 +1,173 @@
+package engine
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TraceFileName is the per-session artifact name of a skill run trace.
+const TraceFileName = "trace.json"
+
+// TraceStep records one visit to a skill state.
+type TraceStep struct {
+	Node         string    `json:"node"`
+	Type         string    `json:"type"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	LLMLatencyMs []int64   `json:"llm_latency_ms,omitempty"`
+	ExitCodes    []int     `json:"exit_codes,omitempty"`
+	Transition   string    `json:"transition,omitempty"`
+}
+
+// Trace is the machine-readable record of a single skill run.
+type Trace struct {
+	SessionID string      `json:"session_id"`
+	Skill     string      `json:"skill"`
+	Start     time.Time   `json:"start"`
+	End       time.Time   `json:"end"`
+	Status    string      `json:"status,omitempty"`
+	Steps     []TraceStep `json:"steps"`
+	// Includes maps states to the file their @file instruction came from.
+	Includes map[string]string `json:"includes,omitempty"`
+}
+
+// TraceWriter accumulates a Trace and persists it to disk after every step.
+// All methods are safe to call on a nil *TraceWriter, which records nothing.
+type TraceWriter struct {
+	path  string
+	mu    sync.Mutex
+	trace Trace
+	open  bool // whether the last step is still in progress
+}
+
+func NewTraceWriter(path, sessionID, skill string) *TraceWriter {
+	return &TraceWriter{
+		path:  path,
+		trace: Trace{SessionID: sessionID, Skill: skill, Start: time.Now(), Steps: []TraceStep{}},
+	}
+}
+
+// SetIncludes records where the skill's @file instructions were read from.
+func (w *TraceWriter) SetIncludes(sources map[string]string) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.trace.Includes = sources
+}
+
+// Enter starts a new step for the given node.
+func (w *TraceWriter) Enter(node, stateType string) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.trace.Steps = append(w.trace.Steps, TraceStep{Node: node, Type: stateType, Start: time.Now()})
+	w.open = true
+}
+
+// RecordLLM adds an LLM call latency to the current step.
+func (w *TraceWriter) RecordLLM(d time.Duration) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if step := w.current(); step != nil {
+		step.LLMLatencyMs = append(step.LLMLatencyMs, d.Milliseconds())
+	}
+}
+
+// RecordExit adds a shell exit code to the current step.
+func (w *TraceWriter) RecordExit(code int) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if step := w.current(); step != nil {
+		step.ExitCodes = append(step.ExitCodes, code)
+	}
+}
+
+// Leave closes the current step with the node it transitioned to, which is
+// empty when the run stays on the same node, and flushes the trace.
+func (w *TraceWriter) Leave(next string) error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if step := w.current(); step != nil {
+		step.End = time.Now()
+		if next != step.Node {
+			step.Transition = next
+		}
+		w.open = false
+	}
+	return w.flushLocked()
+}
+
+// Finish records the final status of the run and flushes the trace.
+func (w *TraceWriter) Finish(status string) error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if step := w.current(); step != nil {
+		step.End = time.Now()
+		w.open = false
+	}
+	w.trace.End = time.Now()
+	w.trace.Status = status
+	return w.flushLocked()
+}
+
+// Snapshot returns a copy of the trace recorded so far.
+func (w *TraceWriter) Snapshot() Trace {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	t := w.trace
+	t.Steps = append([]TraceStep(nil), w.trace.Steps...)
+	return t
+}
+
+func (w *TraceWriter) current() *TraceStep {
+	if !w.open || len(w.trace.Steps) == 0 {
+		return nil
+	}
+	return &w.trace.Steps[len(w.trace.Steps)-1]
+}
+
+func (w *TraceWriter) flushLocked() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(w.trace, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := w.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, w.path)
+}
+
+// TraceNextRun enables run tracing for the next Run of the given session.
+func (e *Engine) TraceNextRun(sessionID string) {
+	e.traceRequests.Store(sessionID, true)
+}
+
+func (e *Engine) traceFor(sessionID string) *TraceWriter {
+	if v, ok := e.traces.Load(sessionID); ok {
+		return v.(*TraceWriter)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// defaultWaitPoll is how often a wait state re-checks its condition.
+const defaultWaitPoll = 2 * time.Second
+
+// executeWait blocks a "wait" state until its file appears or changes, or its
+// command exits 0, moving to Next; on timeout it takes OnFailRoute, or fails
+// the run when there is none. Cancelling the run stops the wait.
+func (e *Engine) executeWait(state *models.StateDef, sess *models.Session) {
+	ctx := context.Background()
+	if v, ok := e.sessionCtxs.Load(sess.ID); ok {
+		ctx = v.(context.Context)
+	}
+
+	poll := e.waitPoll
+	if state.PollIntervalSec > 0 {
+		poll = time.Duration(state.PollIntervalSec) * time.Second
+	}
+	if poll <= 0 {
+		poll = defaultWaitPoll
+	}
+	var deadline <-chan time.Time
+	if state.TimeoutSec > 0 {
+		timer := time.NewTimer(time.Duration(state.TimeoutSec) * time.Second)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	file := state.WaitFile
+	if file != "" && !filepath.IsAbs(file) {
+		file = filepath.Join(sess.CWD, file)
+	}
+	baseline := fileStamp(file)
+
+	e.log(sess, events.AuditInfo, "engine", "Waiting for "+waitDescription(state), events.RoleSystem)
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		if reason, ok := e.waitSatisfied(state, sess, file, baseline); ok {
+			e.log(sess, events.AuditInfo, "engine", "Wait satisfied: "+reason, events.RoleSystem)
+			e.Sm.Update(sess, func(s *models.Session) {
+				s.RetryCount = 0
+				s.PendingFeedback = ""
+				s.LastGoodNode = s.ActiveNode
+				s.ActiveNode = state.Next
+			})
+			return
+		}
+		e.touch(sess.ID)
+
+		select {
+		case <-ctx.Done():
+			e.log(sess, events.AuditInfo, "engine", "Wait cancelled", events.RoleSystem)
+			return
+		case <-deadline:
+			msg := fmt.Sprintf("Wait timed out after %ds for %s", state.TimeoutSec, waitDescription(state))
+			if state.OnFailRoute == "" {
+				e.terminate(sess, models.StatusFailed, msg)
+				return
+			}
+			e.log(sess, events.AuditInfo, "engine", msg, events.RoleSystem)
+			e.Sm.Update(sess, func(s *models.Session) {
+				s.RetryCount = 0
+				s.PendingFeedback = msg
+				s.ActiveNode = state.OnFailRoute
+			})
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitSatisfied reports whether the wait condition holds, and why.
+func (e *Engine) waitSatisfied(state *models.StateDef, sess *models.Session, file string, baseline string) (string, bool) {
+	if file != "" {
+		if stamp := fileStamp(file); stamp != "" && stamp != baseline {
+			return state.WaitFile + " is ready", true
+		}
+	}
+	if state.Command != "" {
+		if exitCode, _ := e.runShell(sess, state.Command); exitCode == 0 {
+			return "command succeeded: " + state.Command, true
+		}
+	}
+	return "", false
+}
+
+// fileStamp identifies a file's current version, or "" when it is missing.
+func fileStamp(path string) string {
+	if path == "" {
+		return ""
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size())
+}
+
+func waitDescription(state *models.StateDef) string {
+	switch {
+	case state.WaitFile != "" && state.Command != "":
+		return fmt.Sprintf("%s or `%s`", state.WaitFile, state.Command)
+	case state.WaitFile != "":
+		return state.WaitFile
+	case state.Command != "":
+		return "`" + state.Command + "`"
+	}
+	return "timeout"
+}
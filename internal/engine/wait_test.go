@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func newWaitEngine(t *testing.T) (*Engine, *session.Manager, string) {
+	t.Helper()
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	eng.waitPoll = 20 * time.Millisecond
+	return eng, sm, storageDir
+}
+
+func TestWaitStateProceedsWhenFileAppears(t *testing.T) {
+	eng, sm, dir := newWaitEngine(t)
+	skill := &models.SkillGraph{
+		Name:         "wait-skill",
+		InitialState: "wait_ci",
+		States: map[string]models.StateDef{
+			"wait_ci": {Type: "wait", WaitFile: "ci.done", TimeoutSec: 10, Next: "done", OnFailRoute: "timeout"},
+			"done":    {Type: "end"},
+			"timeout": {Type: "tool", Command: "exit 1"},
+		},
+	}
+	sess := &models.Session{ID: "wait-file", CWD: dir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		os.WriteFile(filepath.Join(dir, "ci.done"), []byte("ok"), 0644)
+	}()
+	eng.Run(skill, sess)
+
+	if sess.Status != models.StatusCompleted {
+		t.Fatalf("expected completion once the file appeared, got %q at %q", sess.Status, sess.ActiveNode)
+	}
+	infos, _ := sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		return e.Type == events.AuditInfo && strings.HasPrefix(e.Content, "Wait")
+	})
+	if len(infos) < 2 || !strings.Contains(infos[0].Content, "ci.done") {
+		t.Errorf("expected wait start and satisfied audit entries, got %+v", infos)
+	}
+}
+
+func TestWaitStateTakesFailRouteOnTimeout(t *testing.T) {
+	eng, sm, dir := newWaitEngine(t)
+	skill := &models.SkillGraph{
+		Name:         "wait-skill",
+		InitialState: "wait_deploy",
+		States: map[string]models.StateDef{
+			"wait_deploy": {Type: "wait", Command: "test -f never", TimeoutSec: 1, Next: "done", OnFailRoute: "rollback"},
+			"done":        {Type: "end"},
+			"rollback":    {Type: "tool", Command: "touch rolled-back", Next: "done"},
+		},
+	}
+	sess := &models.Session{ID: "wait-timeout", CWD: dir, RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	start := time.Now()
+	eng.Run(skill, sess)
+
+	if time.Since(start) < time.Second {
+		t.Errorf("wait ended before its timeout")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "rolled-back")); err != nil {
+		t.Errorf("expected the fail route to run after the timeout")
+	}
+	if sess.Status != models.StatusCompleted {
+		t.Errorf("expected the run to complete via the fail route, got %q", sess.Status)
+	}
+}
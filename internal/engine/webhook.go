@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// WebhookSecretHeader carries Webhook.Secret so receivers can reject posts
+// that did not come from this engine.
+const WebhookSecretHeader = "X-Tenazas-Secret"
+
+const (
+	defaultWebhookRetries = 3
+	defaultWebhookBackoff = 2 * time.Second
+	webhookTimeout        = 10 * time.Second
+)
+
+// Webhook is an HTTP endpoint told about every skill run that completes,
+// fails or is stopped, for integrations that don't read Telegram.
+type Webhook struct {
+	URL     string
+	Secret  string        // sent in WebhookSecretHeader when set
+	Retries int           // extra attempts after a network error or 5xx; 0 means defaultWebhookRetries, -1 none
+	Backoff time.Duration // wait before the first retry, doubled for each next one; 0 means defaultWebhookBackoff
+	Client  *http.Client  // nil uses a client with webhookTimeout
+}
+
+// WebhookPayload is the JSON body POSTed to a Webhook.
+type WebhookPayload struct {
+	SessionID   string    `json:"session_id"`
+	Status      string    `json:"status"` // models.StatusCompleted, models.StatusFailed, or models.StatusIdle when stopped
+	Reason      string    `json:"reason"`
+	DurationSec float64   `json:"duration_sec"`
+	Skill       string    `json:"skill,omitempty"`
+	CWD         string    `json:"cwd"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
+
+// Send POSTs p, retrying network errors and 5xx responses with exponential
+// backoff. Other 4xx/3xx answers are final.
+func (w *Webhook) Send(p WebhookPayload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout}
+	}
+	retries := w.Retries
+	if retries == 0 {
+		retries = defaultWebhookRetries
+	} else if retries < 0 {
+		retries = 0
+	}
+	backoff := w.Backoff
+	if backoff <= 0 {
+		backoff = defaultWebhookBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = w.post(client, body)
+		if err == nil || attempt >= retries || !retryable(err) {
+			return err
+		}
+		time.Sleep(backoff << attempt)
+	}
+}
+
+type webhookStatusError int
+
+func (e webhookStatusError) Error() string {
+	return fmt.Sprintf("webhook answered %d %s", int(e), http.StatusText(int(e)))
+}
+
+func retryable(err error) bool {
+	if code, ok := err.(webhookStatusError); ok {
+		return code >= 500
+	}
+	return true
+}
+
+func (w *Webhook) post(client *http.Client, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" {
+		req.Header.Set(WebhookSecretHeader, w.Secret)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return webhookStatusError(resp.StatusCode)
+	}
+	return nil
+}
+
+// runInfo describes an active Run for the completion webhook.
+type runInfo struct {
+	start time.Time
+	skill string
+}
+
+// notifyWebhook sends the outcome of the skill run of sess to
+// CompletionWebhook in the background, logging to the session if every
+// attempt fails. Sessions without an active Run (prompts) are not reported.
+func (e *Engine) notifyWebhook(sess *models.Session, status, reason string) {
+	if e.CompletionWebhook == nil || e.CompletionWebhook.URL == "" {
+		return
+	}
+	v, ok := e.runs.Load(sess.ID)
+	if !ok {
+		return
+	}
+	run := v.(runInfo)
+	now := time.Now()
+	p := WebhookPayload{
+		SessionID:   sess.ID,
+		Status:      status,
+		Reason:      reason,
+		DurationSec: now.Sub(run.start).Seconds(),
+		Skill:       run.skill,
+		CWD:         sess.CWD,
+		FinishedAt:  now,
+	}
+	go func() {
+		if err := e.CompletionWebhook.Send(p); err != nil {
+			e.log(sess, events.AuditInfo, "engine", "Completion webhook failed: "+err.Error(), events.RoleSystem)
+		}
+	}()
+}
@@ -0,0 +1,178 @@
+package engine
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func TestCompletionWebhookPayload(t *testing.T) {
+	received := make(chan WebhookPayload, 1)
+	var secret atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret.Store(r.Header.Get(WebhookSecretHeader))
+		var p WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		received <- p
+	}))
+	defer srv.Close()
+
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	eng.CompletionWebhook = &Webhook{URL: srv.URL, Secret: "s3cret"}
+	sess := &models.Session{ID: t.Name(), CWD: storageDir, SkillName: "hooked", RoleCache: make(map[string]string)}
+	sm.Save(sess)
+
+	sk := &models.SkillGraph{
+		Name:         "hooked",
+		InitialState: "check",
+		States: map[string]models.StateDef{
+			"check": {Type: "tool", Command: "true", Next: "done"},
+			"done":  {Type: "end"},
+		},
+	}
+	eng.Run(sk, sess)
+
+	select {
+	case p := <-received:
+		if p.SessionID != sess.ID || p.Status != models.StatusCompleted || p.Skill != "hooked" || p.CWD != storageDir {
+			t.Errorf("unexpected payload %+v", p)
+		}
+		if p.Reason != "Skill completed successfully" {
+			t.Errorf("reason = %q", p.Reason)
+		}
+		if p.DurationSec <= 0 || p.FinishedAt.IsZero() {
+			t.Errorf("expected a duration and finish time, got %+v", p)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+	if got, _ := secret.Load().(string); got != "s3cret" {
+		t.Errorf("%s = %q, want the shared secret", WebhookSecretHeader, got)
+	}
+}
+
+func TestWebhookRetriesServerErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	wh := &Webhook{URL: srv.URL, Backoff: time.Millisecond}
+	if err := wh.Send(WebhookPayload{SessionID: "s"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 2 failures and a success", calls)
+	}
+}
+
+func TestWebhookGivesUp(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		retries int
+		want    int32
+	}{
+		{"5xx exhausts retries", http.StatusInternalServerError, 2, 3},
+		{"4xx is final", http.StatusUnauthorized, 2, 1},
+		{"retries disabled", http.StatusServiceUnavailable, -1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			wh := &Webhook{URL: srv.URL, Retries: tt.retries, Backoff: time.Millisecond}
+			if err := wh.Send(WebhookPayload{}); err == nil {
+				t.Error("expected an error")
+			}
+			if calls != tt.want {
+				t.Errorf("calls = %d, want %d", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletionWebhookOnAbortAndStop(t *testing.T) {
+	received := make(chan WebhookPayload, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p WebhookPayload
+		json.NewDecoder(r.Body).Decode(&p)
+		received <- p
+	}))
+	defer srv.Close()
+	next := func() WebhookPayload {
+		t.Helper()
+		select {
+		case p := <-received:
+			return p
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook was not called")
+		}
+		return WebhookPayload{}
+	}
+
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := NewEngine(sm, newTestClient("echo", storageDir), "gemini", 5)
+	eng.CompletionWebhook = &Webhook{URL: srv.URL}
+	eng.waitPoll = 20 * time.Millisecond
+
+	// Neither session has SkillName set: the payload takes it from the run.
+	blocked := &models.Session{ID: "abort-me", CWD: storageDir, Status: models.StatusIntervention, ActiveNode: "check", RoleCache: map[string]string{}}
+	sm.Save(blocked)
+	abortDone := make(chan struct{})
+	go func() {
+		defer close(abortDone)
+		eng.Run(&models.SkillGraph{Name: "blocked", InitialState: "check", States: map[string]models.StateDef{
+			"check": {Type: "tool", Command: "true", Next: "done"},
+			"done":  {Type: "end"},
+		}}, blocked)
+	}()
+	waitFor(t, "the run to block on the intervention", func() bool {
+		_, waiting := eng.awaiting.Load(blocked.ID)
+		return waiting
+	})
+	eng.ResolveIntervention(blocked.ID, "abort")
+	<-abortDone
+	if p := next(); p.SessionID != blocked.ID || p.Status != models.StatusFailed || p.Skill != "blocked" {
+		t.Errorf("abort payload = %+v, want a failed run of skill blocked", p)
+	}
+
+	waiting := &models.Session{ID: "stop-me", CWD: storageDir, RoleCache: map[string]string{}}
+	sm.Save(waiting)
+	stopDone := make(chan struct{})
+	go func() {
+		defer close(stopDone)
+		eng.Run(&models.SkillGraph{Name: "wait-forever", InitialState: "wait", States: map[string]models.StateDef{
+			"wait": {Type: "wait", WaitFile: "never", Next: "done"},
+			"done": {Type: "end"},
+		}}, waiting)
+	}()
+	waitFor(t, "the run to start waiting", func() bool {
+		_, ok := eng.sessionCtxs.Load(waiting.ID)
+		return ok
+	})
+	eng.StopAll("maintenance")
+	<-stopDone
+	if p := next(); p.SessionID != waiting.ID || p.Status != models.StatusIdle || p.Skill != "wait-forever" || p.Reason != "Stopped: maintenance" {
+		t.Errorf("stop payload = %+v, want an idle run of skill wait-forever", p)
+	}
+}
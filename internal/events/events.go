@@ -13,6 +13,8 @@ const (
 	EventIntervention EventType = "intervention"
 	EventStatus       EventType = "status"
 	EventTaskStatus   EventType = "task_status"
+	EventStorageError EventType = "storage_error"
+	EventEscalation   EventType = "escalation"
 )
 
 // Audit type constants identify the kind of audit log entry.
@@ -26,6 +28,7 @@ const (
 	AuditIntervention = "intervention"
 	AuditStatus       = "status"
 	AuditInfo         = "info"
+	AuditResume       = "resume" // the prompt that restarts an interrupted run; shown as a system note
 )
 
 // Task state constants for task lifecycle events.
@@ -34,6 +37,7 @@ const (
 	TaskStateBlocked   = "TASK_BLOCKED"
 	TaskStateCompleted = "TASK_COMPLETED"
 	TaskStateFailed    = "TASK_FAILED"
+	TaskStateStopped   = "TASK_STOPPED" // cancelled by stop-all; the session is idle
 )
 
 // Conversation role constants indicate who is speaking in the audit log.
@@ -54,6 +58,12 @@ type AuditEntry struct {
 	Model     string    `json:"model,omitempty"`
 	Content   string    `json:"content"`
 	ExitCode  int       `json:"exit_code,omitempty"`
+	// DurationMs is the wall-clock time of the operation the entry measures
+	// (e.g. an LLM call), in milliseconds.
+	DurationMs int64 `json:"duration_ms,omitempty"`
+	// Error marks an entry reporting a failure (an LLM error or a rejected
+	// prompt), so callers can tell failures apart without parsing Content.
+	Error bool `json:"error,omitempty"`
 }
 
 // AuditFormatter defines how to render audit logs for different UIs.
@@ -67,6 +77,26 @@ type TaskStatusPayload struct {
 	Details map[string]string `json:"details"`
 }
 
+// StorageErrorPayload reports that state could not be written to the
+// storage dir. It is published once per kind of write until one succeeds.
+type StorageErrorPayload struct {
+	Op  string `json:"op"` // what failed to save: "session", "audit" or "task"
+	Err string `json:"err"`
+}
+
+// Message is the warning shown to users.
+func (p StorageErrorPayload) Message() string {
+	return "Could not save " + p.Op + " to storage: " + p.Err + ". Changes may be lost; further failures are not repeated."
+}
+
+// EscalationPayload reports an intervention nobody has answered within the
+// escalation window. It is published at most once per intervention.
+type EscalationPayload struct {
+	Node    string        `json:"node"`
+	Reason  string        `json:"reason,omitempty"`
+	Waiting time.Duration `json:"waiting"`
+}
+
 // Event is the unit of communication on the EventBus.
 type Event struct {
 	Type      EventType
@@ -3,6 +3,7 @@ package formatter
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"tenazas/internal/events"
 )
@@ -12,15 +13,47 @@ func isCommandFailure(e events.AuditEntry) bool {
 	return e.ExitCode != 0
 }
 
+// TimeFormat controls how audit timestamps are rendered.
+type TimeFormat struct {
+	Layout string // Go time layout; empty means the caller's default
+	UTC    bool   // render in UTC instead of local time
+}
+
+// Format renders t using the configured layout, or fallback when none is set.
+func (tf TimeFormat) Format(t time.Time, fallback string) string {
+	layout := tf.Layout
+	if layout == "" {
+		layout = fallback
+	}
+	if tf.UTC {
+		t = t.UTC()
+	} else {
+		t = t.Local()
+	}
+	return t.Format(layout)
+}
+
 // AnsiFormatter renders audit entries for terminal output.
-type AnsiFormatter struct{}
+// Entries are prefixed with their timestamp only when Time.Layout is set.
+type AnsiFormatter struct {
+	Time TimeFormat
+}
 
 func (f *AnsiFormatter) Format(e events.AuditEntry) string {
+	if f.Time.Layout == "" || e.Timestamp.IsZero() {
+		return f.format(e)
+	}
+	return fmt.Sprintf("\x1b[2m[%s]\x1b[0m %s", f.Time.Format(e.Timestamp, ""), f.format(e))
+}
+
+func (f *AnsiFormatter) format(e events.AuditEntry) string {
 	switch e.Type {
 	case events.AuditInfo:
 		return fmt.Sprintf("\x1b[32m● \x1b[0m%s", e.Content)
 	case events.AuditLLMPrompt:
 		return fmt.Sprintf("\x1b[2m● Thinking...\x1b[0m")
+	case events.AuditResume:
+		return "\x1b[2m↻ resumed\x1b[0m"
 	case events.AuditLLMResponse:
 		return fmt.Sprintf("\x1b[32m● Response:\x1b[0m\n%s", e.Content)
 	case events.AuditLLMThought:
@@ -41,9 +74,19 @@ func (f *AnsiFormatter) Format(e events.AuditEntry) string {
 }
 
 // HtmlFormatter renders audit entries for Telegram HTML output.
-type HtmlFormatter struct{}
+// Entries are prefixed with their timestamp only when Time.Layout is set.
+type HtmlFormatter struct {
+	Time TimeFormat
+}
 
 func (f *HtmlFormatter) Format(e events.AuditEntry) string {
+	if f.Time.Layout == "" || e.Timestamp.IsZero() {
+		return f.format(e)
+	}
+	return "<i>[" + f.Escape(f.Time.Format(e.Timestamp, "")) + "]</i> " + f.format(e)
+}
+
+func (f *HtmlFormatter) format(e events.AuditEntry) string {
 	content := f.Escape(e.Content)
 	switch e.Type {
 	case events.AuditInfo:
@@ -52,7 +95,9 @@ func (f *HtmlFormatter) Format(e events.AuditEntry) string {
 		}
 		return "ℹ️ <i>" + content + "</i>"
 	case events.AuditLLMPrompt:
-		return "🟡 <b>PROMPT (" + e.Source + "):</b>\n<code>" + content + "</code>"
+		return "🟡 <b>PROMPT (" + f.Escape(e.Source) + "):</b>\n<code>" + content + "</code>"
+	case events.AuditResume:
+		return "↻ <i>resumed</i>"
 	case events.AuditLLMResponse:
 		return "🟢 <b>RESPONSE:</b>\n" + content
 	case events.AuditCmdResult:
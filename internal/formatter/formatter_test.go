@@ -67,3 +67,20 @@ func TestHtmlFormatter(t *testing.T) {
 		}
 	})
 }
+
+func TestResumeRendersAsSystemNote(t *testing.T) {
+	e := events.AuditEntry{
+		Type:    events.AuditResume,
+		Role:    events.RoleSystem,
+		Content: "do the thing\n\n### SESSION CONTEXT:\nSession resumed. Please continue from where you left off.",
+	}
+	for name, f := range map[string]events.AuditFormatter{"ansi": &AnsiFormatter{}, "html": &HtmlFormatter{}} {
+		out := f.Format(e)
+		if !strings.Contains(out, "↻ resumed") && !strings.Contains(out, "↻ <i>resumed</i>") {
+			t.Errorf("%s: expected a resumed note, got %q", name, out)
+		}
+		if strings.Contains(out, "PROMPT") || strings.Contains(out, "SESSION CONTEXT") {
+			t.Errorf("%s: resume should not render as a prompt, got %q", name, out)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package formatter
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ansiPattern matches CSI sequences (colors, cursor moves) and the short
+// two-byte escapes (e.g. ESC 7 / ESC 8 save and restore cursor).
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b[@-Z\\-_78]`)
+
+// StripANSI removes terminal escape sequences and carriage returns from s.
+func StripANSI(s string) string {
+	s = ansiPattern.ReplaceAllString(s, "")
+	return strings.ReplaceAll(s, "\r", "")
+}
+
+// PlainWriter forwards writes to W with terminal escape sequences removed.
+type PlainWriter struct {
+	W io.Writer
+}
+
+func (p *PlainWriter) Write(b []byte) (int, error) {
+	plain := StripANSI(string(b))
+	if plain == "" {
+		return len(b), nil
+	}
+	if _, err := io.WriteString(p.W, plain); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -0,0 +1,25 @@
+package formatter
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStripANSI(t *testing.T) {
+	in := "\x1b7\x1b[12;1H\x1b[32m● \x1b[0mhello\r\n\x1b8"
+	if got := StripANSI(in); got != "● hello\n" {
+		t.Errorf("StripANSI = %q", got)
+	}
+}
+
+func TestPlainWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &PlainWriter{W: &buf}
+	n, err := w.Write([]byte("\x1b[31mred\x1b[0m"))
+	if err != nil || n != len("\x1b[31mred\x1b[0m") {
+		t.Fatalf("unexpected write result n=%d err=%v", n, err)
+	}
+	if buf.String() != "red" {
+		t.Errorf("expected plain text, got %q", buf.String())
+	}
+}
@@ -0,0 +1,30 @@
+package formatter
+
+import "strings"
+
+// DefaultShortIDLength is how many characters of a session ID are shown
+// when no session_id_length is configured.
+const DefaultShortIDLength = 8
+
+// ShortID returns the first min characters of id, lengthened just enough
+// that no other ID in all shares the shown prefix. all is the listing id is
+// shown in and may include id itself. min <= 0 uses DefaultShortIDLength.
+func ShortID(id string, all []string, min int) string {
+	if min <= 0 {
+		min = DefaultShortIDLength
+	}
+	for n := min; n < len(id); n++ {
+		prefix := id[:n]
+		unique := true
+		for _, other := range all {
+			if other != id && strings.HasPrefix(other, prefix) {
+				unique = false
+				break
+			}
+		}
+		if unique {
+			return prefix
+		}
+	}
+	return id
+}
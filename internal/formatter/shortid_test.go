@@ -0,0 +1,34 @@
+package formatter
+
+import "testing"
+
+func TestShortIDWithoutCollisions(t *testing.T) {
+	all := []string{"3f2a9c1e-aaaa", "7b1d0e44-bbbb", "c09e5a21-cccc"}
+	for _, id := range all {
+		if got := ShortID(id, all, 8); got != id[:8] {
+			t.Errorf("ShortID(%q) = %q, want %q", id, got, id[:8])
+		}
+	}
+	if got := ShortID("short", nil, 8); got != "short" {
+		t.Errorf("ShortID of an ID shorter than min = %q, want it whole", got)
+	}
+}
+
+func TestShortIDExtendsCollidingPrefixes(t *testing.T) {
+	all := []string{"3f2a9c1e-a1b2", "3f2a9c1e-a1c3", "3f2a9c1f-0000"}
+	want := []string{"3f2a9c1e-a1b", "3f2a9c1e-a1c", "3f2a9c1f"}
+	for i, id := range all {
+		if got := ShortID(id, all, 8); got != want[i] {
+			t.Errorf("ShortID(%q) = %q, want %q", id, got, want[i])
+		}
+	}
+}
+
+func TestShortIDUsesConfiguredLength(t *testing.T) {
+	if got := ShortID("3f2a9c1e", nil, 4); got != "3f2a" {
+		t.Errorf("ShortID with length 4 = %q, want 3f2a", got)
+	}
+	if got := ShortID("3f2a9c1e77", nil, 0); got != "3f2a9c1e" {
+		t.Errorf("ShortID with no length = %q, want the default", got)
+	}
+}
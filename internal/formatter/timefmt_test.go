@@ -0,0 +1,42 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+)
+
+func TestTimeFormatUTCLayout(t *testing.T) {
+	loc := time.FixedZone("UTC+5", 5*3600)
+	ts := time.Date(2024, 3, 1, 14, 30, 0, 0, loc) // 09:30 UTC
+
+	tf := TimeFormat{Layout: "2006-01-02T15:04Z07:00", UTC: true}
+	if got := tf.Format(ts, "15:04"); got != "2024-03-01T09:30Z" {
+		t.Errorf("expected UTC rendering, got %q", got)
+	}
+
+	// An empty layout falls back to the caller's default.
+	if got := (TimeFormat{UTC: true}).Format(ts, "15:04"); got != "09:30" {
+		t.Errorf("expected fallback layout in UTC, got %q", got)
+	}
+}
+
+func TestFormattersRenderConfiguredTimestamp(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	e := events.AuditEntry{Timestamp: ts, Type: events.AuditInfo, Content: "hello"}
+	tf := TimeFormat{Layout: "15:04 MST", UTC: true}
+
+	if out := (&AnsiFormatter{Time: tf}).Format(e); !strings.Contains(out, "[09:30 UTC]") {
+		t.Errorf("expected ANSI output to include timestamp, got %q", out)
+	}
+	if out := (&HtmlFormatter{Time: tf}).Format(e); !strings.HasPrefix(out, "<i>[09:30 UTC]</i> ") {
+		t.Errorf("expected HTML output to include timestamp, got %q", out)
+	}
+
+	// Default formatters keep the previous timestamp-free output.
+	if out := (&AnsiFormatter{}).Format(e); strings.Contains(out, "09:30") {
+		t.Errorf("expected no timestamp by default, got %q", out)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -30,6 +31,14 @@ type Runner struct {
 	engine    *engine.Engine
 	notifier  Notifier
 	running   sync.Map
+
+	// SkipDirty skips triggers whose project has uncommitted git changes,
+	// so the agent never builds on top of someone's unfinished work.
+	SkipDirty bool
+
+	// LabelSkills routes a claimed task without its own skill to the skill
+	// mapped to its labels (the "label_skills" config).
+	LabelSkills task.LabelSkills
 }
 
 func NewRunner(configDir string, sm *session.Manager, eng *engine.Engine, notifier Notifier) *Runner {
@@ -97,6 +106,10 @@ func (h *Runner) RunLoop(hb models.Heartbeat) {
 
 func (h *Runner) Trigger(hb models.Heartbeat) {
 	h.log(fmt.Sprintf("Triggering heartbeat: %s", hb.Name))
+	if reason := h.skipReason(hb.Path); reason != "" {
+		h.log(fmt.Sprintf("Heartbeat %s: Skipping, %s", hb.Name, reason))
+		return
+	}
 
 	tasksDir := h.resolveTasksDir(hb.Path)
 	tasks, _ := task.ListTasks(tasksDir)
@@ -114,10 +127,22 @@ func (h *Runner) Trigger(hb models.Heartbeat) {
 			now := time.Now().Truncate(time.Second)
 			activeTask.StartedAt = &now
 		}
-		task.WriteTask(activeTask.FilePath, activeTask)
+		h.sm.ReportWrite("", "task", task.WriteTask(activeTask.FilePath, activeTask))
 	}
 
-	for _, skillName := range hb.Skills {
+	skills := hb.Skills
+	if activeTask != nil {
+		if routed := h.LabelSkills.SkillFor(activeTask); routed != "" {
+			h.log(fmt.Sprintf("Heartbeat %s: Task %s routes to skill %s", hb.Name, activeTask.ID, routed))
+			skills = []string{routed}
+		}
+	}
+
+	for _, skillName := range skills {
+		if Paused(h.configDir) {
+			h.log(fmt.Sprintf("Heartbeat %s: Paused, not running %s", hb.Name, skillName))
+			break
+		}
 		h.log(fmt.Sprintf("Heartbeat %s: Running skill %s", hb.Name, skillName))
 		sess, err := h.runSkillHeadless(hb.Name, skillName, hb.Path, activeTask)
 		if err != nil {
@@ -129,7 +154,7 @@ func (h *Runner) Trigger(hb models.Heartbeat) {
 			h.log(summary)
 			if activeTask != nil {
 				activeTask.FailureCount++
-				task.WriteTask(activeTask.FilePath, activeTask)
+				h.sm.ReportWrite("", "task", task.WriteTask(activeTask.FilePath, activeTask))
 			}
 			break
 		}
@@ -140,6 +165,26 @@ func (h *Runner) Trigger(hb models.Heartbeat) {
 	}
 }
 
+// skipReason explains why a trigger in dir must not run, or returns "".
+func (h *Runner) skipReason(dir string) string {
+	if Paused(h.configDir) {
+		return "heartbeats are paused (tenazas resume-all to resume)"
+	}
+	if h.SkipDirty && gitDirty(dir) {
+		return "uncommitted changes in " + dir
+	}
+	return ""
+}
+
+// gitDirty reports whether dir is a git work tree with uncommitted changes.
+// Directories outside git count as clean.
+func gitDirty(dir string) bool {
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return err == nil && len(out) > 0
+}
+
 func (h *Runner) findInProgressTask(tasks []*task.Task) *task.Task {
 	for _, t := range tasks {
 		if t.Status == task.StatusInProgress {
@@ -150,9 +195,9 @@ func (h *Runner) findInProgressTask(tasks []*task.Task) *task.Task {
 }
 
 func (h *Runner) blockTask(hbName string, t *task.Task) {
-	t.Status = task.StatusBlocked
+	t.SetStatus(task.StatusBlocked, time.Now().Truncate(time.Second))
 	t.ClearOwnership()
-	task.WriteTask(t.FilePath, t)
+	h.sm.ReportWrite("", "task", task.WriteTask(t.FilePath, t))
 	msg := fmt.Sprintf("🚨 Task %s blocked after 3 failures in heartbeat %s", t.ID, hbName)
 	h.log(fmt.Sprintf("Heartbeat %s: %s", hbName, msg))
 
@@ -165,7 +210,7 @@ func (h *Runner) blockTask(hbName string, t *task.Task) {
 }
 
 func (h *Runner) runSkillHeadless(hbName, skillName, cwd string, activeTask *task.Task) (*models.Session, error) {
-	skill, err := h.sm.LoadSkill(skillName)
+	skill, err := h.sm.LoadSkillFor(cwd, skillName)
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +235,9 @@ func (h *Runner) runSkillHeadless(hbName, skillName, cwd string, activeTask *tas
 
 	if activeTask != nil {
 		sess.TaskID = activeTask.ID
+		if activeTask.ApplySkillDefaults(skill.DefaultLabels, skill.DefaultSkill) {
+			h.sm.ReportWrite("", "task", task.WriteTask(activeTask.FilePath, activeTask))
+		}
 	}
 	sess.LastUpdated = time.Now()
 
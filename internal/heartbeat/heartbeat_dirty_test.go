@@ -0,0 +1,67 @@
+package heartbeat
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func initGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	return dir
+}
+
+func TestSkipDirtyDecision(t *testing.T) {
+	repo := initGitRepo(t)
+	h := &Runner{SkipDirty: true}
+
+	if reason := h.skipReason(repo); reason != "" {
+		t.Errorf("clean repo should run, got %q", reason)
+	}
+	if reason := h.skipReason(t.TempDir()); reason != "" {
+		t.Errorf("non-repo directory should run, got %q", reason)
+	}
+
+	os.WriteFile(filepath.Join(repo, "wip.go"), []byte("package wip\n"), 0644)
+	if reason := h.skipReason(repo); !strings.Contains(reason, "uncommitted changes") {
+		t.Errorf("dirty repo should be skipped, got %q", reason)
+	}
+
+	h.SkipDirty = false
+	if reason := h.skipReason(repo); reason != "" {
+		t.Errorf("dirty repo should run with the option off, got %q", reason)
+	}
+}
+
+func TestTriggerLogsDirtySkip(t *testing.T) {
+	repo := initGitRepo(t)
+	os.WriteFile(filepath.Join(repo, "wip.go"), []byte("package wip\n"), 0644)
+	storageDir := t.TempDir()
+	h := NewRunner(storageDir, session.NewManager(storageDir), nil, nil)
+	h.SkipDirty = true
+
+	h.Trigger(models.Heartbeat{Name: "dirty", Path: repo, Skills: []string{"build"}})
+
+	data, _ := os.ReadFile(filepath.Join(storageDir, "heartbeats.log"))
+	log := string(data)
+	if !strings.Contains(log, "Heartbeat dirty: Skipping, uncommitted changes in "+repo) {
+		t.Errorf("expected a skip entry, got:\n%s", log)
+	}
+	if strings.Contains(log, "Running skill") {
+		t.Errorf("expected no skill to run, got:\n%s", log)
+	}
+}
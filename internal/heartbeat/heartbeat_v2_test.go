@@ -262,3 +262,36 @@ func TestHeartbeatStartedAtIdempotent(t *testing.T) {
 		t.Errorf("Expected StartedAt to remain %v, got %v (should not be overwritten)", originalStarted, *updatedTask.StartedAt)
 	}
 }
+
+func TestHeartbeatRoutesTaskByLabel(t *testing.T) {
+	tmpStorage := t.TempDir()
+	sm := session.NewManager(tmpStorage)
+	c, _ := client.NewClient("gemini", "echo '{\"type\": \"init\", \"session_id\": \"sid-hb\"}'", filepath.Join(tmpStorage, "tenazas.log"))
+	eng := engine.NewEngine(sm, map[string]client.Client{"gemini": c}, "gemini", 5)
+
+	for _, name := range []string{"skill1", "debug"} {
+		os.MkdirAll(filepath.Join(tmpStorage, "skills", name), 0755)
+		sk := models.SkillGraph{Name: name, InitialState: "end", States: map[string]models.StateDef{"end": {Type: "end"}}}
+		data, _ := json.Marshal(sk)
+		os.WriteFile(filepath.Join(tmpStorage, "skills", name, "skill.json"), data, 0644)
+	}
+
+	tasksDir := filepath.Join(tmpStorage, "tasks", storage.Slugify(tmpStorage))
+	os.MkdirAll(tasksDir, 0755)
+	taskPath := filepath.Join(tasksDir, "TSK-000001.md")
+	task.WriteTask(taskPath, &task.Task{ID: "TSK-000001", Title: "Crash", Status: task.StatusInProgress, Labels: []string{"bug"}, FilePath: taskPath})
+
+	hb := models.Heartbeat{Name: "test-route", Interval: "1m", Path: tmpStorage, Skills: []string{"skill1"}}
+	runner := NewRunner(tmpStorage, sm, eng, nil)
+	runner.LabelSkills = task.NewLabelSkills(map[string]string{"bug": "debug"})
+	runner.Trigger(hb)
+
+	list, _, _ := sm.List(0, 10)
+	ran := map[string]bool{}
+	for _, s := range list {
+		ran[s.SkillName] = true
+	}
+	if !ran["debug"] || ran["skill1"] {
+		t.Errorf("expected only the label-routed skill to run, ran %v", ran)
+	}
+}
@@ -0,0 +1,107 @@
+package heartbeat
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PauseFileName marks heartbeats as paused. "tenazas stop-all" writes it and
+// "tenazas resume-all" removes it; a daemon sharing the storage dir stops its
+// runs when the file appears or changes.
+const PauseFileName = "heartbeats.paused"
+
+// stopPollInterval is how often WatchStopRequests looks for the pause file.
+const stopPollInterval = 2 * time.Second
+
+func pausePath(configDir string) string {
+	return filepath.Join(configDir, PauseFileName)
+}
+
+// Pause stops heartbeats in configDir from triggering until Resume, and asks
+// any daemon watching it to stop its running sessions.
+func Pause(configDir, reason string) error {
+	line := fmt.Sprintf("%s %s\n", time.Now().Format(time.RFC3339), reason)
+	return os.WriteFile(pausePath(configDir), []byte(line), 0644)
+}
+
+// Resume lets heartbeats in configDir trigger again.
+func Resume(configDir string) error {
+	if err := os.Remove(pausePath(configDir)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Paused reports whether heartbeats in configDir are paused.
+func Paused(configDir string) bool {
+	_, err := os.Stat(pausePath(configDir))
+	return err == nil
+}
+
+// pauseReason returns the reason recorded by Pause, or "".
+func pauseReason(configDir string) string {
+	data, err := os.ReadFile(pausePath(configDir))
+	if err != nil {
+		return ""
+	}
+	line := strings.TrimSpace(string(data))
+	if _, reason, ok := strings.Cut(line, " "); ok {
+		return reason
+	}
+	return line
+}
+
+// StopAll pauses heartbeats and stops every session running in this
+// process's engine, returning their IDs.
+func (h *Runner) StopAll(reason string) ([]string, error) {
+	if err := Pause(h.configDir, reason); err != nil {
+		return nil, err
+	}
+	h.log("Stop-all: heartbeats paused (" + reason + ")")
+	return h.engine.StopAll(reason), nil
+}
+
+// ResumeAll lets heartbeats trigger again after StopAll.
+func (h *Runner) ResumeAll() error {
+	if err := Resume(h.configDir); err != nil {
+		return err
+	}
+	h.log("Resume-all: heartbeats resumed")
+	return nil
+}
+
+// WatchStopRequests stops this process's sessions whenever the pause file is
+// written, so "tenazas stop-all" run elsewhere reaches the daemon. It never
+// returns.
+func (h *Runner) WatchStopRequests() {
+	seen := h.pauseStamp()
+	for {
+		time.Sleep(stopPollInterval)
+		seen = h.checkStopRequest(seen)
+	}
+}
+
+// checkStopRequest stops all sessions if the pause file is newer than seen
+// and returns the stamp to compare against next time.
+func (h *Runner) checkStopRequest(seen time.Time) time.Time {
+	stamp := h.pauseStamp()
+	if stamp.IsZero() || !stamp.After(seen) {
+		return stamp
+	}
+	reason := pauseReason(h.configDir)
+	if ids := h.engine.StopAll(reason); len(ids) > 0 {
+		h.log(fmt.Sprintf("Stop-all: stopped %d session(s): %s", len(ids), strings.Join(ids, ", ")))
+	}
+	return stamp
+}
+
+func (h *Runner) pauseStamp() time.Time {
+	info, err := os.Stat(pausePath(h.configDir))
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
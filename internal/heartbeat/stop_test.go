@@ -0,0 +1,114 @@
+package heartbeat
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tenazas/internal/engine"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+func writeSkill(t *testing.T, storageDir string, sk models.SkillGraph) {
+	t.Helper()
+	dir := filepath.Join(storageDir, "skills", sk.Name)
+	os.MkdirAll(dir, 0755)
+	data, _ := json.Marshal(sk)
+	if err := os.WriteFile(filepath.Join(dir, "skill.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStopAllPausesHeartbeats(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := engine.NewEngine(sm, nil, "gemini", 5)
+	runner := NewRunner(storageDir, sm, eng, nil)
+
+	writeSkill(t, storageDir, models.SkillGraph{
+		Name:         "touch-skill",
+		InitialState: "touch",
+		States: map[string]models.StateDef{
+			"touch": {Type: "tool", Command: "touch ran", Next: "done"},
+			"done":  {Type: "end"},
+		},
+	})
+	hb := models.Heartbeat{Name: "paused-hb", Skills: []string{"touch-skill"}, Path: storageDir}
+	marker := filepath.Join(storageDir, "ran")
+
+	if _, err := runner.StopAll("maintenance"); err != nil {
+		t.Fatalf("StopAll: %v", err)
+	}
+	if !Paused(storageDir) {
+		t.Fatal("heartbeats should be paused after StopAll")
+	}
+	runner.Trigger(hb)
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("a paused heartbeat must not run its skills")
+	}
+
+	if err := runner.ResumeAll(); err != nil {
+		t.Fatalf("ResumeAll: %v", err)
+	}
+	runner.Trigger(hb)
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("the heartbeat should run again after ResumeAll")
+	}
+}
+
+func TestStopRequestFromAnotherProcess(t *testing.T) {
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	eng := engine.NewEngine(sm, nil, "gemini", 5)
+	runner := NewRunner(storageDir, sm, eng, nil)
+
+	sk := &models.SkillGraph{
+		Name:         "wait-forever",
+		InitialState: "wait",
+		States: map[string]models.StateDef{
+			"wait": {Type: "wait", WaitFile: "never", Next: "done"},
+			"done": {Type: "end"},
+		},
+	}
+	var sessions []*models.Session
+	done := make(chan struct{}, 2)
+	for _, id := range []string{"hb-stop-1", "hb-stop-2"} {
+		sess := &models.Session{ID: id, CWD: storageDir, RoleCache: map[string]string{}}
+		sm.Save(sess)
+		sessions = append(sessions, sess)
+		go func() {
+			eng.Run(sk, sess)
+			done <- struct{}{}
+		}()
+	}
+	for !eng.IsRunning("hb-stop-1") || !eng.IsRunning("hb-stop-2") {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	seen := runner.checkStopRequest(time.Time{})
+	if !eng.IsRunning("hb-stop-1") {
+		t.Fatal("no pause file yet, nothing should stop")
+	}
+
+	// What `tenazas stop-all` does from its own process.
+	if err := Pause(storageDir, "tenazas stop-all"); err != nil {
+		t.Fatal(err)
+	}
+	runner.checkStopRequest(seen)
+
+	for range sessions {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("runs did not stop")
+		}
+	}
+	for _, sess := range sessions {
+		if sess.Status != models.StatusIdle {
+			t.Errorf("%s status = %s, want idle", sess.ID, sess.Status)
+		}
+	}
+}
@@ -122,7 +122,7 @@ func Summarize(entries []events.AuditEntry, sess *models.Session) Summary {
 		}
 
 		switch e.Type {
-		case events.AuditLLMPrompt:
+		case events.AuditLLMPrompt, events.AuditResume:
 			s.PromptCount++
 		case events.AuditLLMResponse:
 			s.ResponseCount++
@@ -243,6 +243,8 @@ func typeBadgeFor(typ string) string {
 	switch typ {
 	case events.AuditLLMPrompt:
 		return "\x1b[34mPROMPT\x1b[0m"
+	case events.AuditResume:
+		return "\x1b[2mRESUME\x1b[0m"
 	case events.AuditLLMResponse:
 		return "\x1b[32mRESPONSE\x1b[0m"
 	case events.AuditLLMChunk:
@@ -0,0 +1,170 @@
+// Package metrics serves sessions, tasks and engine activity in the
+// Prometheus text exposition format.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"tenazas/internal/engine"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+	"tenazas/internal/task"
+)
+
+// maxSessions bounds how many sessions one scrape loads.
+const maxSessions = 10000
+
+// Collector gathers the metrics on every scrape; nothing is cached.
+type Collector struct {
+	Sm         *session.Manager
+	Engine     *engine.Engine // nil skips the engine counters
+	StorageDir string         // holds tasks/<project>/ directories
+}
+
+// Serve exposes c at /metrics on addr until the listener fails.
+func Serve(addr string, c *Collector) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", c)
+	return http.ListenAndServe(addr, mux)
+}
+
+// ServeHTTP implements http.Handler.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := c.Write(&buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// Write renders every metric to w.
+func (c *Collector) Write(w io.Writer) error {
+	p := &printer{w: w}
+
+	if c.Sm != nil {
+		sessions, _, err := c.Sm.List(0, maxSessions)
+		if err != nil {
+			return err
+		}
+		byStatus := map[string]int{
+			models.StatusIdle: 0, models.StatusRunning: 0, models.StatusIntervention: 0,
+			models.StatusCompleted: 0, models.StatusFailed: 0,
+		}
+		for _, s := range sessions {
+			status := s.Status
+			if status == "" {
+				status = models.StatusIdle
+			}
+			byStatus[status]++
+		}
+		p.header("tenazas_sessions", "gauge", "Sessions by status.")
+		for _, status := range sortedKeys(byStatus) {
+			p.sample("tenazas_sessions", labels("status", status), float64(byStatus[status]))
+		}
+	}
+
+	if c.StorageDir != "" {
+		if err := c.writeTasks(p); err != nil {
+			return err
+		}
+	}
+
+	if c.Engine != nil {
+		m := c.Engine.Metrics()
+		p.metric("tenazas_active_sessions", "gauge", "Sessions with a skill run or prompt in flight.", float64(m.Running))
+		p.metric("tenazas_llm_calls_total", "counter", "LLM calls made by the engine.", float64(m.LLMCalls))
+		p.metric("tenazas_shell_commands_total", "counter", "Shell commands run by the engine.", float64(m.ShellCommands))
+		p.metric("tenazas_interventions_total", "counter", "Interventions the engine waited on.", float64(m.Interventions))
+
+		p.header("tenazas_state_latency_seconds", "summary", "LLM latency of skill states.")
+		p.sample("tenazas_state_latency_seconds_sum", "", m.StateLatency.Seconds())
+		p.sample("tenazas_state_latency_seconds_count", "", float64(m.StateCalls))
+		avg := 0.0
+		if m.StateCalls > 0 {
+			avg = m.StateLatency.Seconds() / float64(m.StateCalls)
+		}
+		p.metric("tenazas_state_latency_avg_seconds", "gauge", "Average LLM latency of skill states.", avg)
+	}
+	return p.err
+}
+
+// writeTasks reports the tasks of every project under StorageDir/tasks.
+func (c *Collector) writeTasks(p *printer) error {
+	root := filepath.Join(c.StorageDir, "tasks")
+	entries, err := os.ReadDir(root)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	p.header("tenazas_tasks", "gauge", "Tasks by project and status, excluding archived ones.")
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		tasks, err := task.ListTasks(filepath.Join(root, entry.Name()))
+		if err != nil {
+			continue
+		}
+		byStatus := map[string]int{task.StatusTodo: 0, task.StatusInProgress: 0, task.StatusDone: 0, task.StatusBlocked: 0}
+		for _, t := range tasks {
+			byStatus[t.Status]++
+		}
+		for _, status := range sortedKeys(byStatus) {
+			p.sample("tenazas_tasks", labels("project", entry.Name(), "status", status), float64(byStatus[status]))
+		}
+	}
+	return nil
+}
+
+// printer writes exposition lines, keeping the first error.
+type printer struct {
+	w   io.Writer
+	err error
+}
+
+func (p *printer) printf(format string, args ...interface{}) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+func (p *printer) header(name, kind, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (p *printer) sample(name, labels string, v float64) {
+	p.printf("%s%s %g\n", name, labels, v)
+}
+
+func (p *printer) metric(name, kind, help string, v float64) {
+	p.header(name, kind, help)
+	p.sample(name, "", v)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labels renders name/value pairs as {a="1",b="2"}.
+func labels(pairs ...string) string {
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, pairs[i], labelEscaper.Replace(pairs[i+1])))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
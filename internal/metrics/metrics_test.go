@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tenazas/internal/client"
+	"tenazas/internal/engine"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+	"tenazas/internal/task"
+)
+
+func runStubSession(t *testing.T, storageDir string) (*session.Manager, *engine.Engine) {
+	t.Helper()
+	script := filepath.Join(storageDir, "ok.sh")
+	os.WriteFile(script, []byte("#!/bin/sh\necho '{\"type\": \"message\", \"content\": \"done\"}'\n"), 0755)
+	c, _ := client.NewClient("gemini", script, filepath.Join(storageDir, "tenazas.log"))
+
+	sm := session.NewManager(storageDir)
+	eng := engine.NewEngine(sm, map[string]client.Client{"gemini": c}, "gemini", 5)
+	sk := &models.SkillGraph{
+		Name:         "stub",
+		InitialState: "ask",
+		States: map[string]models.StateDef{
+			"ask": {Type: "action_loop", SessionRole: "coder", Instruction: "go", VerifyCmd: "true", Next: "end"},
+			"end": {Type: "end"},
+		},
+	}
+	sess := &models.Session{ID: t.Name(), CWD: storageDir, SkillName: "stub", RoleCache: map[string]string{}}
+	sm.Save(sess)
+	eng.Run(sk, sess)
+	if sess.Status != models.StatusCompleted {
+		t.Fatalf("stub session ended %s", sess.Status)
+	}
+	return sm, eng
+}
+
+func TestMetricsReflectActivity(t *testing.T) {
+	storageDir := t.TempDir()
+	sm, eng := runStubSession(t, storageDir)
+
+	tasksDir := filepath.Join(storageDir, "tasks", "my-project")
+	os.MkdirAll(tasksDir, 0755)
+	for _, tk := range []*task.Task{
+		{ID: "TSK-000001", Title: "a", Status: task.StatusTodo},
+		{ID: "TSK-000002", Title: "b", Status: task.StatusDone},
+		{ID: "TSK-000003", Title: "c", Status: task.StatusDone},
+	} {
+		task.WriteTask(filepath.Join(tasksDir, tk.ID+".md"), tk)
+	}
+
+	var buf bytes.Buffer
+	c := &Collector{Sm: sm, Engine: eng, StorageDir: storageDir}
+	if err := c.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`tenazas_sessions{status="completed"} 1`,
+		`tenazas_sessions{status="running"} 0`,
+		`tenazas_tasks{project="my-project",status="todo"} 1`,
+		`tenazas_tasks{project="my-project",status="done"} 2`,
+		"tenazas_active_sessions 0",
+		"tenazas_llm_calls_total 1",
+		"tenazas_shell_commands_total 1",
+		"tenazas_interventions_total 0",
+		"tenazas_state_latency_seconds_count 1",
+		"# TYPE tenazas_llm_calls_total counter",
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	storageDir := t.TempDir()
+	sm, eng := runStubSession(t, storageDir)
+
+	rec := httptest.NewRecorder()
+	(&Collector{Sm: sm, Engine: eng}).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Result().Body)
+	if rec.Code != 200 || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), "tenazas_llm_calls_total 1") {
+		t.Errorf("handler body lacks the engine counters:\n%s", body)
+	}
+}
+
+func TestLabelsEscaping(t *testing.T) {
+	if got := labels("project", "a\"b\\c\nd"); got != `{project="a\"b\\c\nd"}` {
+		t.Errorf("labels = %s", got)
+	}
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -20,8 +21,27 @@ const (
 	ApprovalModePlan     = "PLAN"
 	ApprovalModeAutoEdit = "AUTO_EDIT"
 	ApprovalModeYolo     = "YOLO"
+
+	PromptModeInterrupt = "interrupt" // a new prompt cancels the in-flight one
+	PromptModeQueue     = "queue"     // a new prompt waits for the in-flight one
 )
 
+// ApprovalModeNames are the approval modes as users type them, in cycle order.
+var ApprovalModeNames = []string{"plan", "auto_edit", "yolo"}
+
+// ParseApprovalMode maps a mode name as users write it (plan, auto_edit,
+// yolo, any case) to its ApprovalMode constant. "auto", "edit" and
+// "auto-edit" are accepted for auto_edit.
+func ParseApprovalMode(s string) (string, bool) {
+	switch mode := strings.ToUpper(strings.TrimSpace(s)); mode {
+	case ApprovalModePlan, ApprovalModeAutoEdit, ApprovalModeYolo:
+		return mode, true
+	case "AUTO", "EDIT", "AUTO-EDIT", "AUTOEDIT":
+		return ApprovalModeAutoEdit, true
+	}
+	return "", false
+}
+
 // SkillGraph defines a skill as a state machine.
 type SkillGraph struct {
 	Name         string              `json:"skill_name"`
@@ -30,6 +50,26 @@ type SkillGraph struct {
 	MaxLoops     int                 `json:"max_loops"`
 	MaxBudgetUSD float64             `json:"max_budget_usd,omitempty"`
 	States       map[string]StateDef `json:"states"`
+
+	// MaxContinues overrides the engine's max_continues for the skill's
+	// LLM calls; -1 turns continuation off.
+	MaxContinues int `json:"max_continues,omitempty"`
+
+	// RequiredTools must be available before the skill starts: binaries on
+	// PATH, paths relative to the session CWD, or probe commands that exit 0.
+	RequiredTools []string `json:"required_tools,omitempty"`
+
+	// IncludeSources maps each state whose instruction was an @file include
+	// to the file it was read from. Filled when the skill is loaded.
+	IncludeSources map[string]string `json:"-"`
+
+	// Applied to tasks the skill creates or claims; explicit values win.
+	DefaultLabels []string `json:"default_labels,omitempty"`
+	DefaultSkill  string   `json:"default_skill,omitempty"`
+
+	// Tags categorize the skill for "/skills --tag"; a run adds them to the
+	// session's tags.
+	Tags []string `json:"tags,omitempty"`
 }
 
 // StateDef defines a single state within a SkillGraph.
@@ -48,6 +88,21 @@ type StateDef struct {
 	ModelTier     string `json:"model_tier,omitempty"`
 	Command       string `json:"command,omitempty"`
 	IsTerminal    bool   `json:"is_terminal,omitempty"`
+
+	// "wait" states: block until WaitFile appears or changes, or Command exits
+	// 0, then go to Next; after TimeoutSec (0 waits indefinitely) go to OnFailRoute.
+	WaitFile        string `json:"wait_file,omitempty"`
+	TimeoutSec      int    `json:"timeout_sec,omitempty"`
+	PollIntervalSec int    `json:"poll_interval_sec,omitempty"`
+}
+
+// ResolvedRole returns the session role used for caching the state's native
+// client session, defaulting to the state name when SessionRole is unset.
+func (s StateDef) ResolvedRole(stateName string) string {
+	if s.SessionRole != "" {
+		return s.SessionRole
+	}
+	return stateName
 }
 
 // Session represents a Tenazas session.
@@ -75,6 +130,73 @@ type Session struct {
 	MonitoringMessageID int64             `json:"monitoring_message_id,omitempty"`
 	TaskID              string            `json:"task_id,omitempty"`
 	Ephemeral           bool              `json:"ephemeral,omitempty"`
+	Wrap                bool              `json:"wrap,omitempty"`           // reflow CLI output to the terminal width
+	PromptMode          string            `json:"prompt_mode,omitempty"`    // PromptModeInterrupt (default) or PromptModeQueue
+	Metadata            map[string]string `json:"metadata,omitempty"`       // free-form tags set by users and integrations (ticket IDs, PRs)
+	LastGoodNode        string            `json:"last_good_node,omitempty"` // last skill state that completed successfully; see --from-checkpoint
+	SystemPrompt        string            `json:"system_prompt,omitempty"`  // persona/instructions sent with every prompt; see /persona
+}
+
+// Clone returns a copy of s that shares no maps with it.
+func (s *Session) Clone() *Session {
+	c := *s
+	c.RoleCache = cloneStrings(s.RoleCache)
+	c.Metadata = cloneStrings(s.Metadata)
+	return &c
+}
+
+func cloneStrings(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// MetaTags is the Metadata key holding a session's comma-separated tags.
+const MetaTags = "tags"
+
+// Tags returns the session's tags from its Metadata.
+func (s *Session) Tags() []string {
+	var tags []string
+	for _, t := range strings.Split(s.Metadata[MetaTags], ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// AddTags appends the tags the session lacks to its Metadata, keeping the
+// existing ones first.
+func (s *Session) AddTags(tags []string) {
+	merged := s.Tags()
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" || containsFold(merged, t) {
+			continue
+		}
+		merged = append(merged, t)
+	}
+	if len(merged) == 0 {
+		return
+	}
+	if s.Metadata == nil {
+		s.Metadata = make(map[string]string)
+	}
+	s.Metadata[MetaTags] = strings.Join(merged, ",")
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
 }
 
 // EnsureLocalDir creates a .tenazas directory in the session's CWD.
@@ -102,4 +224,5 @@ type EngineInterface interface {
 	ResolveIntervention(id, action string)
 	IsRunning(sessionID string) bool
 	CancelSession(sessionID string)
+	Nudge(sessionID string) (map[string]string, error)
 }
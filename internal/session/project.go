@@ -0,0 +1,52 @@
+package session
+
+import (
+	"path/filepath"
+
+	"tenazas/internal/models"
+	"tenazas/internal/storage"
+)
+
+// ProjectState is what is remembered about a project (a session CWD)
+// between sessions, in <storage>/projects/<slug>.json.
+type ProjectState struct {
+	// ApprovalMode is the last approval mode chosen in the project, restored
+	// for its new sessions. Yolo is never remembered.
+	ApprovalMode string `json:"approval_mode,omitempty"`
+}
+
+func projectStatePath(cwd string) string {
+	return filepath.Join("projects", storage.Slugify(cwd)+".json")
+}
+
+// ProjectState returns the remembered state of the project at cwd; it is
+// empty when nothing was saved.
+func (sm *Manager) ProjectState(cwd string) ProjectState {
+	var st ProjectState
+	_ = sm.Storage.ReadJSON(projectStatePath(cwd), &st)
+	return st
+}
+
+// RememberApprovalMode records mode as the project's approval mode for the
+// sessions created there later. Yolo is not recorded, so a project never
+// starts unattended edits by itself.
+func (sm *Manager) RememberApprovalMode(cwd, mode string) error {
+	if cwd == "" || mode == models.ApprovalModeYolo {
+		return nil
+	}
+	st := sm.ProjectState(cwd)
+	if st.ApprovalMode == mode {
+		return nil
+	}
+	st.ApprovalMode = mode
+	return sm.Storage.WriteJSON(projectStatePath(cwd), st)
+}
+
+// ProjectApprovalMode is the approval mode for a new session in cwd: the
+// remembered one, or plan.
+func (sm *Manager) ProjectApprovalMode(cwd string) string {
+	if mode := sm.ProjectState(cwd).ApprovalMode; mode != "" {
+		return mode
+	}
+	return models.ApprovalModePlan
+}
@@ -0,0 +1,39 @@
+package session
+
+import (
+	"testing"
+
+	"tenazas/internal/models"
+)
+
+func TestNewSessionRestoresProjectApprovalMode(t *testing.T) {
+	sm := NewManager(t.TempDir())
+	projA, projB := t.TempDir(), t.TempDir()
+
+	first, _ := sm.Create(projA, "first")
+	if first.ApprovalMode != models.ApprovalModePlan {
+		t.Fatalf("expected a fresh project to start in plan, got %s", first.ApprovalMode)
+	}
+	if err := sm.RememberApprovalMode(projA, models.ApprovalModeAutoEdit); err != nil {
+		t.Fatal(err)
+	}
+
+	if again, _ := sm.Create(projA, "again"); again.ApprovalMode != models.ApprovalModeAutoEdit {
+		t.Errorf("expected the project's mode to be restored, got %s", again.ApprovalMode)
+	}
+	if other, _ := sm.Create(projB, "other"); other.ApprovalMode != models.ApprovalModePlan {
+		t.Errorf("expected another project to use the default, got %s", other.ApprovalMode)
+	}
+}
+
+func TestYoloIsNotRemembered(t *testing.T) {
+	sm := NewManager(t.TempDir())
+	proj := t.TempDir()
+
+	sm.RememberApprovalMode(proj, models.ApprovalModeAutoEdit)
+	sm.RememberApprovalMode(proj, models.ApprovalModeYolo)
+
+	if got := sm.ProjectApprovalMode(proj); got != models.ApprovalModeAutoEdit {
+		t.Errorf("expected yolo to leave the remembered mode alone, got %s", got)
+	}
+}
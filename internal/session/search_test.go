@@ -0,0 +1,44 @@
+package session
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/models"
+)
+
+func TestSessionManagerSearchMatchesMetadata(t *testing.T) {
+	sm := NewManager(t.TempDir())
+	cwd := t.TempDir()
+	now := time.Now()
+
+	tagged := &models.Session{ID: "tagged", CWD: cwd, Title: "Fix login", LastUpdated: now, Metadata: map[string]string{"ticket": "ENG-1234"}}
+	plain := &models.Session{ID: "plain", CWD: cwd, Title: "Refactor", LastUpdated: now}
+	sm.Save(tagged)
+	sm.Save(plain)
+
+	matches, err := sm.Search("eng-1234")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(matches) != 1 || matches[0].ID != "tagged" {
+		t.Fatalf("expected only the tagged session, got %+v", matches)
+	}
+
+	matches, _ = sm.Search("refactor")
+	if len(matches) != 1 || matches[0].ID != "plain" {
+		t.Errorf("expected title match, got %+v", matches)
+	}
+}
+
+func TestSessionMetadataOmittedWhenEmpty(t *testing.T) {
+	data, err := json.Marshal(&models.Session{ID: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "metadata") {
+		t.Errorf("empty metadata should be omitted: %s", data)
+	}
+}
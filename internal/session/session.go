@@ -1,6 +1,7 @@
 package session
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,6 +9,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -22,6 +24,8 @@ import (
 type Manager struct {
 	StoragePath string
 	Storage     *storage.Storage
+	locks       sync.Map // session ID -> *sync.Mutex serializing Save/Update
+	failing     sync.Map // write op -> true while its writes fail (see ReportWrite)
 }
 
 func NewManager(storagePath string) *Manager {
@@ -39,7 +43,7 @@ func (sm *Manager) Create(cwd, title string) (*models.Session, error) {
 		CreatedAt:    time.Now(),
 		LastUpdated:  time.Now(),
 		RoleCache:    make(map[string]string),
-		ApprovalMode: models.ApprovalModePlan,
+		ApprovalMode: sm.ProjectApprovalMode(cwd),
 		Status:       models.StatusIdle,
 	}
 	if err := sm.Save(sess); err != nil {
@@ -136,10 +140,60 @@ func (sm *Manager) updateGlobalIndex(s *models.Session) {
 	}
 }
 
+// lock acquires the per-session lock and returns its release function.
+func (sm *Manager) lock(id string) func() {
+	v, _ := sm.locks.LoadOrStore(id, &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
 func (sm *Manager) Save(s *models.Session) error {
+	defer sm.lock(s.ID)()
+	return sm.save(s)
+}
+
+// Update applies fn to s and saves it under the session lock, so a mutation
+// and the marshalling of another goroutine's Save never interleave. Use it
+// for any session that is shared between goroutines (engine, CLI, Telegram).
+// fn must not call back into the Manager's Save or Update for the same session.
+func (sm *Manager) Update(s *models.Session, fn func(*models.Session)) error {
+	defer sm.lock(s.ID)()
+	fn(s)
+	return sm.save(s)
+}
+
+// UpdateSession loads the stored session, applies fn and saves it, all under
+// the session lock. Use it when the caller holds no live copy, so fields
+// written by other goroutines since the last load are not overwritten.
+func (sm *Manager) UpdateSession(id string, fn func(*models.Session)) (*models.Session, error) {
+	defer sm.lock(id)()
+	s, err := sm.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	fn(s)
+	if err := sm.save(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Snapshot returns a copy of s taken under the session lock. A goroutine
+// reading a session another one may be updating (the CLI showing a session
+// the engine runs, or the engine reading settings the CLI changes) reads the
+// snapshot instead of s.
+func (sm *Manager) Snapshot(s *models.Session) *models.Session {
+	defer sm.lock(s.ID)()
+	return s.Clone()
+}
+
+func (sm *Manager) save(s *models.Session) error {
 	s.LastUpdated = time.Now()
 	relPath := sm.metaPath(s.CWD, s.ID, s.Archived)
-	if err := sm.Storage.WriteJSON(relPath, s); err != nil {
+	err := sm.Storage.WriteJSON(relPath, s)
+	sm.ReportWrite(s.ID, "session", err)
+	if err != nil {
 		return err
 	}
 	sm.updateIndex(s.ID, s.CWD)
@@ -204,12 +258,8 @@ func (sm *Manager) Archive(id string) error {
 }
 
 func (sm *Manager) Rename(id string, newTitle string) error {
-	sess, err := sm.Load(id)
-	if err != nil {
-		return err
-	}
-	sess.Title = newTitle
-	return sm.Save(sess)
+	_, err := sm.UpdateSession(id, func(s *models.Session) { s.Title = newTitle })
+	return err
 }
 
 func (sm *Manager) GetLatest() (*models.Session, error) {
@@ -349,11 +399,76 @@ func (sm *Manager) slowListInternal(page, pageSize int, includeEphemeral bool) (
 	return sessions, totalFiltered, nil
 }
 
+// Search returns active sessions whose ID, title, summary, skill or metadata
+// (keys and values) contain query, case-insensitively, newest first.
+func (sm *Manager) Search(query string) ([]models.Session, error) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	var matches []models.Session
+	for page := 0; ; page++ {
+		sessions, total, err := sm.ListActive(page, 100)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range sessions {
+			if sessionMatches(&s, q) {
+				matches = append(matches, s)
+			}
+		}
+		if len(sessions) == 0 || (page+1)*100 >= total {
+			break
+		}
+	}
+	return matches, nil
+}
+
+func sessionMatches(s *models.Session, q string) bool {
+	if q == "" {
+		return true
+	}
+	for _, field := range []string{s.ID, s.Title, s.Summary, s.SkillName} {
+		if strings.Contains(strings.ToLower(field), q) {
+			return true
+		}
+	}
+	for k, v := range s.Metadata {
+		if strings.Contains(strings.ToLower(k), q) || strings.Contains(strings.ToLower(v), q) {
+			return true
+		}
+	}
+	return false
+}
+
 func (sm *Manager) AppendAudit(s *models.Session, entry events.AuditEntry) error {
 	if entry.Timestamp.IsZero() {
 		entry.Timestamp = time.Now()
 	}
 
+	// Listeners get the entry even when it cannot be written.
+	err := sm.PersistAudit(s, entry)
+	events.GlobalBus.Publish(events.Event{Type: events.EventAudit, SessionID: s.ID, Payload: entry})
+	return err
+}
+
+// PublishAudit delivers entry to listeners without writing it, for callers
+// that persist entries later in batches with PersistAudit.
+func (sm *Manager) PublishAudit(s *models.Session, entry events.AuditEntry) {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	events.GlobalBus.Publish(events.Event{Type: events.EventAudit, SessionID: s.ID, Payload: entry})
+}
+
+// PersistAudit writes entry to the session's audit log without publishing it.
+func (sm *Manager) PersistAudit(s *models.Session, entry events.AuditEntry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	err := sm.writeAudit(s, entry)
+	sm.ReportWrite(s.ID, "audit", err)
+	return err
+}
+
+func (sm *Manager) writeAudit(s *models.Session, entry events.AuditEntry) error {
 	relDir := sm.Storage.WorkspaceDir(s.CWD)
 	fPath := filepath.Join(sm.StoragePath, relDir, s.ID+".audit.jsonl")
 
@@ -378,17 +493,40 @@ func (sm *Manager) AppendAudit(s *models.Session, entry events.AuditEntry) error
 	data, _ := json.Marshal(entry)
 	data = append(data, '\n')
 	_, err = f.Write(data)
-
-	events.GlobalBus.Publish(events.Event{Type: events.EventAudit, SessionID: s.ID, Payload: entry})
 	return err
 }
 
+// ReportWrite records the outcome of a storage write of kind op ("session",
+// "audit", "task"). The first failure publishes an EventStorageError so UIs
+// warn once instead of on every write; a success of the same op re-arms it.
+func (sm *Manager) ReportWrite(sessionID, op string, err error) {
+	if err == nil {
+		sm.failing.Delete(op)
+		return
+	}
+	if _, already := sm.failing.LoadOrStore(op, true); already {
+		return
+	}
+	events.GlobalBus.Publish(events.Event{
+		Type:      events.EventStorageError,
+		SessionID: sessionID,
+		Payload:   events.StorageErrorPayload{Op: op, Err: err.Error()},
+	})
+}
+
 // AuditPath returns the filesystem path to the session's audit JSONL file.
 func (sm *Manager) AuditPath(s *models.Session) string {
 	relDir := sm.Storage.WorkspaceDir(s.CWD)
 	return filepath.Join(sm.StoragePath, relDir, s.ID+".audit.jsonl")
 }
 
+// ArtifactPath returns the path of a per-session file kept alongside the
+// audit log, named "<session-id>.<name>" (e.g. "<id>.trace.json").
+func (sm *Manager) ArtifactPath(s *models.Session, name string) string {
+	relDir := sm.Storage.WorkspaceDir(s.CWD)
+	return filepath.Join(sm.StoragePath, relDir, s.ID+"."+name)
+}
+
 func (sm *Manager) GetLastAudit(s *models.Session, n int) ([]events.AuditEntry, error) {
 	relDir := sm.Storage.WorkspaceDir(s.CWD)
 	fPath := filepath.Join(sm.StoragePath, relDir, s.ID+".audit.jsonl")
@@ -464,6 +602,50 @@ func (sm *Manager) GetLastAudit(s *models.Session, n int) ([]events.AuditEntry,
 	return result, nil
 }
 
+// IterateAudit walks the session's audit log from oldest to newest, calling fn
+// for each decodable entry. Iteration stops early when fn returns false.
+// A missing audit file is not an error.
+func (sm *Manager) IterateAudit(s *models.Session, fn func(events.AuditEntry) bool) error {
+	f, err := os.Open(sm.AuditPath(s))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry events.AuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		if !fn(entry) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// FilterAudit returns all audit entries for which keep returns true, oldest first.
+// A nil keep matches every entry.
+func (sm *Manager) FilterAudit(s *models.Session, keep func(events.AuditEntry) bool) ([]events.AuditEntry, error) {
+	var result []events.AuditEntry
+	err := sm.IterateAudit(s, func(e events.AuditEntry) bool {
+		if keep == nil || keep(e) {
+			result = append(result, e)
+		}
+		return true
+	})
+	return result, err
+}
+
 func (sm *Manager) RefreshSkillRegistry() error {
 	skills, err := skill.List(sm.StoragePath)
 	if err != nil {
@@ -488,10 +670,17 @@ func (sm *Manager) RefreshSkillRegistry() error {
 }
 
 func (sm *Manager) GetActiveSkills() ([]string, error) {
+	return sm.ActiveSkillsFor("")
+}
+
+// ActiveSkillsFor is like GetActiveSkills but also includes the enabled
+// project skills under cwd.
+func (sm *Manager) ActiveSkillsFor(cwd string) ([]string, error) {
 	registry := make(map[string]bool)
 	_ = sm.Storage.ReadJSON("skills_registry.json", &registry)
 
-	skills, _ := skill.List(sm.StoragePath)
+	entries, _ := skill.ListAll(sm.StoragePath, cwd)
+	skills := skill.Names(entries)
 	var active []string
 	for _, s := range skills {
 		enabled, ok := registry[s]
@@ -511,6 +700,12 @@ func (sm *Manager) ToggleSkill(name string, enabled bool) error {
 
 // LoadSkill is a convenience method that loads a skill checking the active registry.
 func (sm *Manager) LoadSkill(skillName string) (*models.SkillGraph, error) {
-	active, _ := sm.GetActiveSkills()
-	return skill.Load(sm.Storage, skillName, active)
+	return sm.LoadSkillFor("", skillName)
+}
+
+// LoadSkillFor loads a skill for a session rooted at cwd, preferring the
+// project's .tenazas/skills over the global skills directory.
+func (sm *Manager) LoadSkillFor(cwd, skillName string) (*models.SkillGraph, error) {
+	active, _ := sm.ActiveSkillsFor(cwd)
+	return skill.LoadFrom(sm.Storage, skillName, active, cwd)
 }
@@ -190,3 +190,55 @@ func TestSessionManagerRename(t *testing.T) {
 		t.Errorf("expected title 'New Title', got %s", loaded.Title)
 	}
 }
+
+func TestIterateAudit(t *testing.T) {
+	storageDir, _ := os.MkdirTemp("", "tenazas-test-iterate-*")
+	defer os.RemoveAll(storageDir)
+
+	sm := NewManager(storageDir)
+	s := &models.Session{ID: "iter", CWD: storageDir}
+	sm.Save(s)
+
+	// Missing audit file is not an error.
+	if err := sm.IterateAudit(s, func(events.AuditEntry) bool { return true }); err != nil {
+		t.Fatalf("expected no error for missing audit file, got %v", err)
+	}
+
+	for _, typ := range []string{events.AuditInfo, events.AuditCmdResult, events.AuditInfo, events.AuditCmdResult} {
+		sm.AppendAudit(s, events.AuditEntry{Type: typ, Content: typ})
+	}
+
+	var seen int
+	sm.IterateAudit(s, func(events.AuditEntry) bool {
+		seen++
+		return seen < 2
+	})
+	if seen != 2 {
+		t.Errorf("expected iteration to stop after 2 entries, got %d", seen)
+	}
+
+	cmds, err := sm.FilterAudit(s, func(e events.AuditEntry) bool { return e.Type == events.AuditCmdResult })
+	if err != nil {
+		t.Fatalf("FilterAudit failed: %v", err)
+	}
+	if len(cmds) != 2 {
+		t.Errorf("expected 2 cmd_result entries, got %d", len(cmds))
+	}
+}
+
+func TestSessionSystemPromptRoundTrip(t *testing.T) {
+	sm := NewManager(t.TempDir())
+	sess := &models.Session{ID: "persona-sess", CWD: t.TempDir(), RoleCache: make(map[string]string)}
+	if err := sm.Save(sess); err != nil {
+		t.Fatal(err)
+	}
+	sm.Update(sess, func(s *models.Session) { s.SystemPrompt = "You are a careful reviewer." })
+
+	loaded, err := sm.Load("persona-sess")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.SystemPrompt != "You are a careful reviewer." {
+		t.Errorf("SystemPrompt = %q after reload", loaded.SystemPrompt)
+	}
+}
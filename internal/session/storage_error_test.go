@@ -0,0 +1,95 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+// breakWorkspace makes every write of cwd's sessions fail by putting a file
+// where their workspace directory should be.
+func breakWorkspace(t *testing.T, sm *Manager, cwd string) func() {
+	t.Helper()
+	dir := filepath.Join(sm.StoragePath, sm.Storage.WorkspaceDir(cwd))
+	os.MkdirAll(filepath.Dir(dir), 0755)
+	if err := os.WriteFile(dir, []byte("not a directory"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		os.Remove(dir)
+		os.MkdirAll(dir, 0755)
+	}
+}
+
+// drainEvents collects the events of sessionID published so far.
+func drainEvents(ch chan events.Event, sessionID string) []events.Event {
+	var got []events.Event
+	for {
+		select {
+		case e := <-ch:
+			if e.SessionID == sessionID {
+				got = append(got, e)
+			}
+		case <-time.After(50 * time.Millisecond):
+			return got
+		}
+	}
+}
+
+func TestWriteFailuresAreReportedOnce(t *testing.T) {
+	sm := NewManager(t.TempDir())
+	sess := &models.Session{ID: "ro-sess", CWD: "/ro/project"}
+	repair := breakWorkspace(t, sm, sess.CWD)
+
+	ch := events.GlobalBus.Subscribe()
+	defer events.GlobalBus.Unsubscribe(ch)
+	drainEvents(ch, sess.ID)
+
+	for i := 0; i < 3; i++ {
+		if err := sm.Save(sess); err == nil {
+			t.Fatal("expected Save to fail")
+		}
+		if err := sm.AppendAudit(sess, events.AuditEntry{Type: events.AuditInfo, Content: "step"}); err == nil {
+			t.Fatal("expected AppendAudit to fail")
+		}
+	}
+
+	var audits int
+	warned := map[string]int{}
+	for _, e := range drainEvents(ch, sess.ID) {
+		switch p := e.Payload.(type) {
+		case events.AuditEntry:
+			audits++
+		case events.StorageErrorPayload:
+			warned[p.Op]++
+		}
+	}
+	if warned["session"] != 1 || warned["audit"] != 1 {
+		t.Errorf("expected one warning per kind of write, got %v", warned)
+	}
+	if audits != 3 {
+		t.Errorf("expected unsaved audit entries to still reach listeners, got %d", audits)
+	}
+
+	// A successful write re-arms the warning.
+	repair()
+	if err := sm.AppendAudit(sess, events.AuditEntry{Type: events.AuditInfo, Content: "ok"}); err != nil {
+		t.Fatalf("expected the write to succeed once repaired: %v", err)
+	}
+	os.RemoveAll(filepath.Join(sm.StoragePath, sm.Storage.WorkspaceDir(sess.CWD)))
+	breakWorkspace(t, sm, sess.CWD)
+	sm.AppendAudit(sess, events.AuditEntry{Type: events.AuditInfo, Content: "broken again"})
+	warned = map[string]int{}
+	for _, e := range drainEvents(ch, sess.ID) {
+		if p, ok := e.Payload.(events.StorageErrorPayload); ok {
+			warned[p.Op]++
+		}
+	}
+	if warned["audit"] != 1 {
+		t.Errorf("expected a new warning after recovering, got %v", warned)
+	}
+}
@@ -0,0 +1,74 @@
+package session
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"tenazas/internal/models"
+)
+
+// TestUpdateConcurrentBudgetAndNode drives budget updates (as the CLI does)
+// and node transitions (as the engine does) on one shared session. Run with
+// -race to check the in-memory pointer is never written while being saved.
+func TestUpdateConcurrentBudgetAndNode(t *testing.T) {
+	sm := NewManager(t.TempDir())
+	sess := &models.Session{ID: "race-sess", CWD: "/tmp/race", RoleCache: map[string]string{}}
+	if err := sm.Save(sess); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 50
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= n; i++ {
+			sm.Update(sess, func(s *models.Session) { s.MaxBudgetUSD = float64(i) })
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= n; i++ {
+			sm.Update(sess, func(s *models.Session) {
+				s.ActiveNode = fmt.Sprintf("node-%d", i)
+				s.RoleCache["coder"] = fmt.Sprintf("sid-%d", i)
+			})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			sm.Save(sess)
+		}
+	}()
+	wg.Wait()
+
+	loaded, err := sm.Load(sess.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.MaxBudgetUSD != n || loaded.ActiveNode != fmt.Sprintf("node-%d", n) {
+		t.Errorf("lost write: budget=%v node=%q", loaded.MaxBudgetUSD, loaded.ActiveNode)
+	}
+}
+
+func TestUpdateSessionMergesWithStoredState(t *testing.T) {
+	sm := NewManager(t.TempDir())
+	sess := &models.Session{ID: "merge-sess", CWD: "/tmp/merge", ActiveNode: "build"}
+	sm.Save(sess)
+
+	// Another writer moves the node; a later budget update must keep it.
+	sm.Update(sess, func(s *models.Session) { s.ActiveNode = "verify" })
+	updated, err := sm.UpdateSession(sess.ID, func(s *models.Session) { s.MaxBudgetUSD = 3 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ActiveNode != "verify" || updated.MaxBudgetUSD != 3 {
+		t.Errorf("expected node verify and budget 3, got %q / %v", updated.ActiveNode, updated.MaxBudgetUSD)
+	}
+
+	if _, err := sm.UpdateSession("missing", func(*models.Session) {}); err == nil {
+		t.Error("expected error for unknown session")
+	}
+}
@@ -0,0 +1,142 @@
+package skill
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"tenazas/internal/models"
+	"tenazas/internal/storage"
+)
+
+// Report is the outcome of checking one skill. Errors make the skill
+// unusable; warnings are the authoring hints of Validate and CheckTermination.
+type Report struct {
+	Name     string
+	Errors   []string
+	Warnings []string
+}
+
+// OK reports whether the skill has no errors.
+func (r Report) OK() bool {
+	return len(r.Errors) == 0
+}
+
+var stateTypes = map[string]bool{"action_loop": true, "tool": true, "wait": true, "end": true}
+
+var modelTiers = map[string]bool{"high": true, "medium": true, "low": true}
+
+// Check runs every static check on g without executing it: transitions to
+// missing states, unknown state types, invalid per-state approval modes and
+// model tiers, unreachable or missing end states, and Validate's warnings.
+func Check(g *models.SkillGraph) Report {
+	r := Report{Name: g.Name}
+
+	names := make([]string, 0, len(g.States))
+	for name := range g.States {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		state := g.States[name]
+		if !stateTypes[state.Type] {
+			r.Errors = append(r.Errors, fmt.Sprintf("state %q has unknown type %q", name, state.Type))
+		}
+		if state.Type != "end" {
+			if state.Next == "" {
+				r.Errors = append(r.Errors, fmt.Sprintf("state %q has no next state", name))
+			} else if _, ok := g.States[state.Next]; !ok {
+				r.Errors = append(r.Errors, fmt.Sprintf("state %q: next state %q not found", name, state.Next))
+			}
+			if _, ok := g.States[state.OnFailRoute]; state.OnFailRoute != "" && !ok {
+				r.Errors = append(r.Errors, fmt.Sprintf("state %q: on_fail_route %q not found", name, state.OnFailRoute))
+			}
+		}
+		if _, ok := models.ParseApprovalMode(state.ApprovalMode); state.ApprovalMode != "" && !ok {
+			r.Errors = append(r.Errors, fmt.Sprintf("state %q has invalid approval_mode %q (want plan, auto_edit or yolo)", name, state.ApprovalMode))
+		}
+		if state.ModelTier != "" && !modelTiers[state.ModelTier] {
+			r.Errors = append(r.Errors, fmt.Sprintf("state %q has invalid model_tier %q (want high, medium or low)", name, state.ModelTier))
+		}
+	}
+
+	problems, err := CheckTermination(g)
+	if err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	}
+	r.Warnings = append(Validate(g), problems...)
+	return r
+}
+
+// CheckSkills loads and checks each named skill, project skills under cwd
+// first. A skill that fails to load is reported with the load error.
+func CheckSkills(st *storage.Storage, cwd string, names []string) []Report {
+	reports := make([]Report, 0, len(names))
+	for _, name := range names {
+		g, err := LoadFrom(st, name, []string{name}, cwd)
+		if err != nil {
+			if os.IsNotExist(err) {
+				err = fmt.Errorf("skill not found")
+				if hint := NotFoundHint(st.BaseDir, cwd, name); hint != "" {
+					err = fmt.Errorf("skill not found; %s", hint)
+				}
+			}
+			reports = append(reports, Report{Name: name, Errors: []string{err.Error()}})
+			continue
+		}
+		r := Check(g)
+		r.Name = name
+		reports = append(reports, r)
+	}
+	return reports
+}
+
+// RenderReports writes one block per skill and returns how many are invalid.
+func RenderReports(w io.Writer, reports []Report) int {
+	invalid := 0
+	for _, r := range reports {
+		status := "ok"
+		if !r.OK() {
+			status = "INVALID"
+			invalid++
+		}
+		fmt.Fprintf(w, "%s: %s\n", r.Name, status)
+		for _, e := range r.Errors {
+			fmt.Fprintf(w, "  error:   %s\n", e)
+		}
+		for _, warn := range r.Warnings {
+			fmt.Fprintf(w, "  warning: %s\n", warn)
+		}
+	}
+	fmt.Fprintf(w, "\n%d skill(s) checked, %d invalid\n", len(reports), invalid)
+	return invalid
+}
+
+// HandleCommand runs "tenazas skills <subcommand>" and returns the exit code.
+func HandleCommand(st *storage.Storage, cwd string, args []string, w io.Writer) int {
+	const usage = "Usage: tenazas skills validate <name>|--all"
+	if len(args) != 2 || args[0] != "validate" {
+		fmt.Fprintln(w, usage)
+		return 2
+	}
+
+	names := []string{args[1]}
+	if args[1] == "--all" {
+		entries, err := ListAll(st.BaseDir, cwd)
+		if err != nil {
+			fmt.Fprintf(w, "Error listing skills: %v\n", err)
+			return 1
+		}
+		names = Names(entries)
+		if len(names) == 0 {
+			fmt.Fprintln(w, "No skills found.")
+			return 0
+		}
+	}
+	if RenderReports(w, CheckSkills(st, cwd, names)) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package skill
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tenazas/internal/models"
+	"tenazas/internal/storage"
+)
+
+func TestCheckValidSkill(t *testing.T) {
+	g := &models.SkillGraph{
+		Name:         "ship",
+		InitialState: "code",
+		States: map[string]models.StateDef{
+			"code": {Type: "action_loop", SessionRole: "coder", ApprovalMode: "auto_edit", ModelTier: "high", Next: "lint", OnFailRoute: "fail"},
+			"lint": {Type: "tool", Command: "true", Next: "done", OnFailRoute: "fail"},
+			"done": {Type: "end"},
+			"fail": {Type: "end"},
+		},
+	}
+	if r := Check(g); !r.OK() || len(r.Warnings) != 0 {
+		t.Errorf("expected a clean report, got %+v", r)
+	}
+}
+
+func TestCheckReportsSpecificErrors(t *testing.T) {
+	g := &models.SkillGraph{
+		Name:         "broken",
+		InitialState: "plan",
+		States: map[string]models.StateDef{
+			"plan":  {Type: "action_loop", SessionRole: "agent", ApprovalMode: "sometimes", ModelTier: "huge", Next: "build"},
+			"code":  {Type: "action_loop", SessionRole: "agent", Next: "plan", OnFailRoute: "rollback"},
+			"done":  {Type: "end"},
+			"weird": {Type: "loop", Next: "done"},
+		},
+	}
+	r := Check(g)
+	want := []string{
+		`state "code": on_fail_route "rollback" not found`,
+		`state "plan" has invalid approval_mode "sometimes"`,
+		`state "plan" has invalid model_tier "huge"`,
+		`state "plan": next state "build" not found`,
+		`state "weird" has unknown type "loop"`,
+		`no end state is reachable from initial state "plan"`,
+	}
+	joined := strings.Join(r.Errors, "\n")
+	for _, w := range want {
+		if !strings.Contains(joined, w) {
+			t.Errorf("missing error %q in:\n%s", w, joined)
+		}
+	}
+	if !strings.Contains(strings.Join(r.Warnings, "\n"), `share session_role "agent"`) {
+		t.Errorf("expected the shared-role warning, got %v", r.Warnings)
+	}
+}
+
+func TestHandleCommandValidate(t *testing.T) {
+	storageDir := t.TempDir()
+	cwd := t.TempDir()
+	writeSkill(t, filepath.Join(storageDir, "skills"), "good", "ok")
+	bad := `{"skill_name": "bad", "initial_state": "start", "states": {"start": {"type": "tool", "command": "true", "next": "missing"}}}`
+	os.WriteFile(filepath.Join(storageDir, "skills", "bad.json"), []byte(bad), 0644)
+	st := storage.NewStorage(storageDir)
+
+	var out bytes.Buffer
+	if code := HandleCommand(st, cwd, []string{"validate", "good"}, &out); code != 0 {
+		t.Errorf("validate good exited %d:\n%s", code, out.String())
+	}
+
+	out.Reset()
+	code := HandleCommand(st, cwd, []string{"validate", "--all"}, &out)
+	if code != 1 {
+		t.Errorf("validate --all exited %d, want 1", code)
+	}
+	for _, want := range []string{"good: ok", "bad: INVALID", `error:   state "start": next state "missing" not found`, "2 skill(s) checked, 1 invalid"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if code := HandleCommand(st, cwd, []string{"validate", "god"}, &out); code != 1 || !strings.Contains(out.String(), "skill not found; did you mean good") {
+		t.Errorf("unknown skill: exit %d, output:\n%s", code, out.String())
+	}
+}
@@ -0,0 +1,66 @@
+package skill_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tenazas/internal/session"
+)
+
+func writeIncludeSkill(t *testing.T, storageDir, instruction string) {
+	t.Helper()
+	dir := filepath.Join(storageDir, "skills", "inc")
+	os.MkdirAll(dir, 0755)
+	data := `{"skill_name": "inc", "initial_state": "start", "states": {"start": {"type": "llm", "instruction": "` + instruction + `"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "skill.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIncludeResolvesFromConfiguredPath(t *testing.T) {
+	storageDir, cwd, shared := t.TempDir(), t.TempDir(), t.TempDir()
+	os.WriteFile(filepath.Join(shared, "review.md"), []byte("shared review"), 0644)
+	os.MkdirAll(filepath.Join(cwd, ".tenazas", "prompts"), 0755)
+	os.WriteFile(filepath.Join(cwd, ".tenazas", "prompts", "local.md"), []byte("local prompt"), 0644)
+
+	sm := session.NewManager(storageDir)
+	sm.Storage.IncludePaths = []string{shared, ".tenazas/prompts"}
+
+	for _, tt := range []struct{ include, content, source string }{
+		{"@review.md", "shared review", filepath.Join(shared, "review.md")},
+		{"@local.md", "local prompt", filepath.Join(cwd, ".tenazas", "prompts", "local.md")},
+	} {
+		writeIncludeSkill(t, storageDir, tt.include)
+		sk, err := sm.LoadSkillFor(cwd, "inc")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.include, err)
+		}
+		if got := sk.States["start"].Instruction; got != tt.content {
+			t.Errorf("%s: instruction = %q, want %q", tt.include, got, tt.content)
+		}
+		if got := sk.IncludeSources["start"]; got != tt.source {
+			t.Errorf("%s: source = %q, want %q", tt.include, got, tt.source)
+		}
+	}
+}
+
+func TestIncludeTraversalIsRejected(t *testing.T) {
+	storageDir, root := t.TempDir(), t.TempDir()
+	shared := filepath.Join(root, "prompts")
+	os.MkdirAll(shared, 0755)
+	os.WriteFile(filepath.Join(root, "secret.txt"), []byte("sensitive"), 0644)
+
+	sm := session.NewManager(storageDir)
+	sm.Storage.IncludePaths = []string{shared}
+	writeIncludeSkill(t, storageDir, "@../secret.txt")
+
+	_, err := sm.LoadSkillFor(t.TempDir(), "inc")
+	if !errors.Is(err, os.ErrPermission) {
+		t.Errorf("expected a permission error for a ../ include, got %v", err)
+	}
+	if _, err := sm.Storage.FindInclude("@../secret.txt", []string{shared}); !errors.Is(err, os.ErrPermission) {
+		t.Errorf("expected FindInclude to reject ../, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tenazas/internal/storage"
+)
+
+func writeSkill(t *testing.T, dir, name, instruction string) {
+	t.Helper()
+	skillDir := filepath.Join(dir, name)
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"skill_name": "` + name + `", "initial_state": "start", "states": {"start": {"type": "end", "instruction": "` + instruction + `"}}}`
+	if err := os.WriteFile(filepath.Join(skillDir, "skill.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectSkillShadowsGlobal(t *testing.T) {
+	storageDir := t.TempDir()
+	cwd := t.TempDir()
+	writeSkill(t, filepath.Join(storageDir, "skills"), "deploy", "global")
+	writeSkill(t, ProjectDir(cwd), "deploy", "project")
+
+	sk, err := LoadFrom(storage.NewStorage(storageDir), "deploy", []string{"deploy"}, cwd)
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if got := sk.States["start"].Instruction; got != "project" {
+		t.Errorf("expected the project skill, got instruction %q", got)
+	}
+
+	entries, _ := ListAll(storageDir, cwd)
+	if len(entries) != 1 || entries[0].Source != SourceProject {
+		t.Errorf("expected one project entry, got %+v", entries)
+	}
+
+	sk, _ = Load(storage.NewStorage(storageDir), "deploy", []string{"deploy"})
+	if got := sk.States["start"].Instruction; got != "global" {
+		t.Errorf("without a project dir expected the global skill, got %q", got)
+	}
+}
+
+func TestListAllMergesProjectAndGlobal(t *testing.T) {
+	storageDir := t.TempDir()
+	cwd := t.TempDir()
+	writeSkill(t, filepath.Join(storageDir, "skills"), "deploy", "global")
+	writeSkill(t, ProjectDir(cwd), "lint", "project")
+
+	entries, err := ListAll(storageDir, cwd)
+	if err != nil {
+		t.Fatalf("ListAll: %v", err)
+	}
+	sources := make(map[string]string)
+	for _, e := range entries {
+		sources[e.Name] = e.Source
+	}
+	if sources["deploy"] != SourceGlobal || sources["lint"] != SourceProject || len(sources) != 2 {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestSkillTags(t *testing.T) {
+	storageDir := t.TempDir()
+	cwd := t.TempDir()
+	skillsDir := filepath.Join(storageDir, "skills")
+	os.MkdirAll(skillsDir, 0755)
+	os.WriteFile(filepath.Join(skillsDir, "ship.json"),
+		[]byte(`{"skill_name": "ship", "initial_state": "s", "tags": ["deploy", "prod"], "states": {"s": {"type": "end"}}}`), 0644)
+	writeSkill(t, ProjectDir(cwd), "lint", "project")
+
+	sk, err := Load(storage.NewStorage(storageDir), "ship", []string{"ship"})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(sk.Tags) != 2 || sk.Tags[0] != "deploy" || sk.Tags[1] != "prod" {
+		t.Errorf("Tags = %v, want [deploy prod]", sk.Tags)
+	}
+
+	entries, _ := ListAll(storageDir, cwd)
+	tagged := FilterByTag(entries, "DEPLOY")
+	if len(tagged) != 1 || tagged[0].Name != "ship" || len(tagged[0].Tags) != 2 {
+		t.Errorf("FilterByTag(deploy) = %+v, want the ship skill with its tags", tagged)
+	}
+	for _, e := range entries {
+		if e.Name == "lint" && e.Tags != nil {
+			t.Errorf("untagged skill got tags %v", e.Tags)
+		}
+	}
+}
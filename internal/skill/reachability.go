@@ -0,0 +1,110 @@
+package skill
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"tenazas/internal/models"
+)
+
+// CheckTermination reports whether g can finish. err is set when no end
+// state is reachable from InitialState, so a run could only stop on
+// MaxLoops or an error. problems lists end states that can never be reached
+// and reachable states with no path to an end state.
+func CheckTermination(g *models.SkillGraph) (problems []string, err error) {
+	if _, ok := g.States[g.InitialState]; !ok {
+		return nil, fmt.Errorf("initial state %q not found", g.InitialState)
+	}
+
+	var ends []string
+	for name, state := range g.States {
+		if state.Type == "end" {
+			ends = append(ends, name)
+		}
+	}
+	sort.Strings(ends)
+	if len(ends) == 0 {
+		return nil, fmt.Errorf("skill %s has no end state", g.Name)
+	}
+
+	reachable := reachableFrom(g, g.InitialState)
+	// finishing holds the states with a path to some end state: walk the
+	// transitions backwards from the ends.
+	incoming := make(map[string][]string)
+	for name, state := range g.States {
+		for _, to := range transitions(g, state) {
+			incoming[to] = append(incoming[to], name)
+		}
+	}
+	finishing := make(map[string]bool)
+	queue := append([]string(nil), ends...)
+	for _, end := range ends {
+		finishing[end] = true
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, from := range incoming[name] {
+			if !finishing[from] {
+				finishing[from] = true
+				queue = append(queue, from)
+			}
+		}
+	}
+
+	var unreachable []string
+	for _, end := range ends {
+		if !reachable[end] {
+			unreachable = append(unreachable, end)
+			problems = append(problems, fmt.Sprintf("end state %q is unreachable from initial state %q", end, g.InitialState))
+		}
+	}
+	if len(unreachable) == len(ends) {
+		return problems, fmt.Errorf("no end state is reachable from initial state %q (unreachable: %s)", g.InitialState, strings.Join(unreachable, ", "))
+	}
+
+	var stuck []string
+	for name := range reachable {
+		if !finishing[name] {
+			stuck = append(stuck, name)
+		}
+	}
+	sort.Strings(stuck)
+	for _, name := range stuck {
+		problems = append(problems, fmt.Sprintf("state %q can never reach an end state", name))
+	}
+	return problems, nil
+}
+
+// reachableFrom returns the states a run starting at start can visit.
+func reachableFrom(g *models.SkillGraph, start string) map[string]bool {
+	seen := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, to := range transitions(g, g.States[name]) {
+			if !seen[to] {
+				seen[to] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+	return seen
+}
+
+// transitions returns the existing states state can move to: Next on
+// success and OnFailRoute on failure (or a manual proceed_to_fail).
+func transitions(g *models.SkillGraph, state models.StateDef) []string {
+	if state.Type == "end" {
+		return nil
+	}
+	var out []string
+	for _, to := range []string{state.Next, state.OnFailRoute} {
+		if _, ok := g.States[to]; ok && to != "" {
+			out = append(out, to)
+		}
+	}
+	return out
+}
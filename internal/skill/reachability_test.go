@@ -0,0 +1,75 @@
+package skill
+
+import (
+	"strings"
+	"testing"
+
+	"tenazas/internal/models"
+)
+
+func TestCheckTerminationWithoutEndState(t *testing.T) {
+	g := &models.SkillGraph{
+		Name:         "loop",
+		InitialState: "code",
+		States: map[string]models.StateDef{
+			"code": {Type: "action_loop", Next: "test"},
+			"test": {Type: "tool", Command: "go test", Next: "code", OnFailRoute: "code"},
+		},
+	}
+	if _, err := CheckTermination(g); err == nil || !strings.Contains(err.Error(), "no end state") {
+		t.Errorf("CheckTermination err = %v, want a missing end state error", err)
+	}
+}
+
+func TestCheckTerminationWithUnreachableEnd(t *testing.T) {
+	g := &models.SkillGraph{
+		Name:         "orphan",
+		InitialState: "code",
+		States: map[string]models.StateDef{
+			"code": {Type: "action_loop", Next: "test"},
+			"test": {Type: "tool", Command: "go test", Next: "code"},
+			"done": {Type: "end"},
+		},
+	}
+	problems, err := CheckTermination(g)
+	if err == nil || !strings.Contains(err.Error(), "done") {
+		t.Errorf("CheckTermination err = %v, want an unreachable end error naming done", err)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0], `end state "done" is unreachable`) {
+		t.Errorf("problems = %v", problems)
+	}
+}
+
+func TestCheckTerminationReportsStuckStates(t *testing.T) {
+	g := &models.SkillGraph{
+		Name:         "partial",
+		InitialState: "code",
+		States: map[string]models.StateDef{
+			"code":  {Type: "action_loop", Next: "done", OnFailRoute: "debug"},
+			"debug": {Type: "action_loop", Next: "debug"},
+			"done":  {Type: "end"},
+		},
+	}
+	problems, err := CheckTermination(g)
+	if err != nil {
+		t.Fatalf("a graph with a reachable end should run, got %v", err)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0], `"debug" can never reach an end state`) {
+		t.Errorf("problems = %v, want debug reported", problems)
+	}
+}
+
+func TestCheckTerminationAcceptsWellFormedGraph(t *testing.T) {
+	g := &models.SkillGraph{
+		Name:         "ok",
+		InitialState: "code",
+		States: map[string]models.StateDef{
+			"code": {Type: "action_loop", Next: "test"},
+			"test": {Type: "tool", Command: "go test", Next: "done", OnFailRoute: "code"},
+			"done": {Type: "end"},
+		},
+	}
+	if problems, err := CheckTermination(g); err != nil || len(problems) != 0 {
+		t.Errorf("CheckTermination = %v, %v; want no problems", problems, err)
+	}
+}
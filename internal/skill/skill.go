@@ -12,8 +12,53 @@ import (
 	"tenazas/internal/storage"
 )
 
+// Skill sources reported by ListAll.
+const (
+	SourceGlobal  = "global"
+	SourceProject = "project"
+)
+
+// Entry is a discoverable skill and where it was found.
+type Entry struct {
+	Name   string
+	Source string   // SourceGlobal or SourceProject
+	Tags   []string // from the skill's "tags"
+}
+
+// HasTag reports whether the skill is tagged tag, ignoring case.
+func (e Entry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByTag returns the entries tagged tag, in order.
+func FilterByTag(entries []Entry, tag string) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if e.HasTag(tag) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// ProjectDir returns the project-local skills directory for a session CWD.
+func ProjectDir(cwd string) string {
+	return filepath.Join(cwd, ".tenazas", "skills")
+}
+
 // Load reads and resolves a skill from disk.
 func Load(st *storage.Storage, skillName string, activeSkills []string) (*models.SkillGraph, error) {
+	return LoadFrom(st, skillName, activeSkills, "")
+}
+
+// LoadFrom is like Load but first looks in the project skills directory
+// under cwd, so project skills shadow global ones of the same name.
+func LoadFrom(st *storage.Storage, skillName string, activeSkills []string, cwd string) (*models.SkillGraph, error) {
 	found := false
 	for _, s := range activeSkills {
 		if s == skillName {
@@ -25,7 +70,10 @@ func Load(st *storage.Storage, skillName string, activeSkills []string) (*models
 		return nil, fmt.Errorf("skill %s is disabled", skillName)
 	}
 
-	path := st.ResolveSkillPath(skillName)
+	path := projectSkillPath(cwd, skillName)
+	if path == "" {
+		path = st.ResolveSkillPath(skillName)
+	}
 	if path == "" {
 		return nil, os.ErrNotExist
 	}
@@ -44,11 +92,15 @@ func Load(st *storage.Storage, skillName string, activeSkills []string) (*models
 
 	for name, state := range skill.States {
 		if strings.HasPrefix(state.Instruction, "@") {
-			resolved, err := st.ResolveInstruction(state.Instruction, skill.BaseDir)
+			resolved, source, err := st.ResolveInstructionFrom(state.Instruction, skill.BaseDir, cwd)
 			if err != nil {
 				return nil, err
 			}
 			state.Instruction = resolved
+			if skill.IncludeSources == nil {
+				skill.IncludeSources = make(map[string]string)
+			}
+			skill.IncludeSources[name] = source
 		}
 		if strings.HasPrefix(state.PreActionCmd, "@") {
 			resolved, err := st.ResolveAssetPath(state.PreActionCmd, skill.BaseDir)
@@ -77,10 +129,68 @@ func Load(st *storage.Storage, skillName string, activeSkills []string) (*models
 	return &skill, nil
 }
 
-// List returns the names of all discoverable skills.
+func projectSkillPath(cwd, name string) string {
+	if cwd == "" {
+		return ""
+	}
+	dir := ProjectDir(cwd)
+	for _, p := range []string{filepath.Join(dir, name, "skill.json"), filepath.Join(dir, name+".json")} {
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// ListAll merges the project skills under cwd with the global ones, project
+// skills first. A project skill shadows a global skill of the same name.
+// An empty cwd lists only global skills.
+func ListAll(storageDir, cwd string) ([]Entry, error) {
+	var entries []Entry
+	seen := make(map[string]bool)
+	add := func(dir, source string) {
+		for _, name := range skillsIn(dir) {
+			if !seen[name] {
+				entries = append(entries, Entry{Name: name, Source: source, Tags: tagsIn(dir, name)})
+				seen[name] = true
+			}
+		}
+	}
+	if cwd != "" {
+		add(ProjectDir(cwd), SourceProject)
+	}
+	for _, dir := range globalDirs(storageDir) {
+		add(dir, SourceGlobal)
+	}
+	return entries, nil
+}
+
+// Names returns the skill names of entries, in order.
+func Names(entries []Entry) []string {
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name
+	}
+	return names
+}
+
+// List returns the names of all discoverable global skills.
 func List(storageDir string) ([]string, error) {
 	var skills []string
+	seen := make(map[string]bool)
+	for _, dir := range globalDirs(storageDir) {
+		for _, name := range skillsIn(dir) {
+			if !seen[name] {
+				skills = append(skills, name)
+				seen[name] = true
+			}
+		}
+	}
+	return skills, nil
+}
 
+// globalDirs returns the directories holding global skills, in lookup order.
+func globalDirs(storageDir string) []string {
 	dirs := []string{
 		filepath.Join(storageDir, "skills"),
 	}
@@ -93,27 +203,40 @@ func List(storageDir string) ([]string, error) {
 			}
 		}
 	}
+	return dirs
+}
 
-	seen := make(map[string]bool)
-	for _, dir := range dirs {
-		entries, _ := os.ReadDir(dir)
-		for _, e := range entries {
-			name := e.Name()
-			if e.IsDir() {
-				if _, err := os.Stat(filepath.Join(dir, name, "skill.json")); err == nil {
-					if !seen[name] {
-						skills = append(skills, name)
-						seen[name] = true
-					}
-				}
-			} else if strings.HasSuffix(name, ".json") {
-				name = strings.TrimSuffix(name, ".json")
-				if !seen[name] {
-					skills = append(skills, name)
-					seen[name] = true
-				}
+// tagsIn reads the tags of the skill name in dir without resolving the
+// rest of it. A skill that can't be read has no tags.
+func tagsIn(dir, name string) []string {
+	for _, p := range []string{filepath.Join(dir, name, "skill.json"), filepath.Join(dir, name+".json")} {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		var meta struct {
+			Tags []string `json:"tags"`
+		}
+		json.Unmarshal(data, &meta)
+		return meta.Tags
+	}
+	return nil
+}
+
+// skillsIn returns the skills in dir: subdirectories holding a skill.json
+// and standalone <name>.json files.
+func skillsIn(dir string) []string {
+	var names []string
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() {
+			if _, err := os.Stat(filepath.Join(dir, name, "skill.json")); err == nil {
+				names = append(names, name)
 			}
+		} else if strings.HasSuffix(name, ".json") {
+			names = append(names, strings.TrimSuffix(name, ".json"))
 		}
 	}
-	return skills, nil
+	return names
 }
@@ -0,0 +1,115 @@
+package skill
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxSuggestions caps how many names Suggest returns.
+const maxSuggestions = 3
+
+// Suggest returns the names in all that are close to name, best match first.
+// A name matches when one is a prefix of the other or when their edit
+// distance is small relative to the length of name.
+func Suggest(name string, all []string) []string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil
+	}
+	threshold := len(name) / 3
+	if threshold < 2 {
+		threshold = 2
+	}
+
+	type match struct {
+		name string
+		dist int
+	}
+	var matches []match
+	for _, candidate := range all {
+		c := strings.ToLower(candidate)
+		if c == name {
+			continue
+		}
+		d := levenshtein(name, c)
+		if strings.HasPrefix(c, name) || strings.HasPrefix(name, c) {
+			d = 0
+		}
+		if d <= threshold {
+			matches = append(matches, match{candidate, d})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].dist != matches[j].dist {
+			return matches[i].dist < matches[j].dist
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	var result []string
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		result = append(result, matches[i].name)
+	}
+	return result
+}
+
+// DidYouMean formats the suggestions for name as a short hint, or returns ""
+// when nothing is close enough.
+func DidYouMean(name string, all []string) string {
+	suggestions := Suggest(name, all)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("did you mean %s?", strings.Join(suggestions, ", "))
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
+
+// NotFoundHint returns a "did you mean" hint when name is not one of the
+// skills discoverable from cwd's project or storageDir, or "" when it exists
+// or nothing is close.
+func NotFoundHint(storageDir, cwd, name string) string {
+	entries, err := ListAll(storageDir, cwd)
+	if err != nil {
+		return ""
+	}
+	all := Names(entries)
+	for _, s := range all {
+		if s == name {
+			return ""
+		}
+	}
+	return DidYouMean(name, all)
+}
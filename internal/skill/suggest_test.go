@@ -0,0 +1,58 @@
+package skill
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	all := []string{"code-review", "tdd_feature_dev", "deploy", "docs"}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"code-reveiw", []string{"code-review"}},
+		{"deplyo", []string{"deploy"}},
+		{"code", []string{"code-review"}},
+		{"kubernetes-migration", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := Suggest(tt.name, all); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	if got := DidYouMean("deplo", []string{"deploy"}); got != "did you mean deploy?" {
+		t.Errorf("unexpected hint: %q", got)
+	}
+	if got := DidYouMean("zzzzzzzz", []string{"deploy"}); got != "" {
+		t.Errorf("expected no hint, got %q", got)
+	}
+}
+
+func TestNotFoundHintIncludesProjectSkills(t *testing.T) {
+	storageDir, cwd := t.TempDir(), t.TempDir()
+	writeSkill(t, filepath.Join(storageDir, "skills"), "deploy", "ship")
+	writeSkill(t, ProjectDir(cwd), "release-notes", "write")
+
+	if got := NotFoundHint(storageDir, cwd, "release-note"); got != "did you mean release-notes?" {
+		t.Errorf("expected a project skill suggestion, got %q", got)
+	}
+	if got := NotFoundHint(storageDir, cwd, "release-notes"); got != "" {
+		t.Errorf("expected no hint for an existing project skill, got %q", got)
+	}
+	if got := NotFoundHint(storageDir, "", "release-note"); got != "" {
+		t.Errorf("expected no project suggestion without a cwd, got %q", got)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	if d := levenshtein("kitten", "sitting"); d != 3 {
+		t.Errorf("expected distance 3, got %d", d)
+	}
+}
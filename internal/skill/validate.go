@@ -0,0 +1,65 @@
+package skill
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"tenazas/internal/models"
+)
+
+// Validate inspects a skill graph for likely authoring mistakes and returns
+// human-readable warnings. An empty result means nothing suspicious was found.
+func Validate(g *models.SkillGraph) []string {
+	var warnings []string
+
+	names := make([]string, 0, len(g.States))
+	for name := range g.States {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		state := g.States[name]
+		if state.Type == "wait" && state.WaitFile == "" && state.Command == "" {
+			warnings = append(warnings, fmt.Sprintf("wait state %q has neither wait_file nor command; it only ends on timeout", name))
+		}
+	}
+
+	byRole := make(map[string][]string)
+	for _, name := range names {
+		state := g.States[name]
+		if state.Type != "action_loop" {
+			continue
+		}
+		// A state without session_role gets its own role, named after it.
+		role := state.ResolvedRole(name)
+		byRole[role] = append(byRole[role], name)
+	}
+
+	roles := make([]string, 0, len(byRole))
+	for role := range byRole {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	// States sharing a role share one native client session, so their
+	// per-state client settings must agree.
+	for _, role := range roles {
+		states := byRole[role]
+		if len(states) < 2 {
+			continue
+		}
+		first := g.States[states[0]]
+		for _, name := range states[1:] {
+			other := g.States[name]
+			if other.ModelTier != first.ModelTier || other.ApprovalMode != first.ApprovalMode {
+				warnings = append(warnings, fmt.Sprintf("states %s share session_role %q but use different model_tier/approval_mode; give them distinct roles",
+					strings.Join(states, ", "), role))
+				break
+			}
+		}
+	}
+
+	return warnings
+}
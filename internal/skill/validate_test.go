@@ -0,0 +1,68 @@
+package skill
+
+import (
+	"strings"
+	"testing"
+
+	"tenazas/internal/models"
+)
+
+func TestValidateAcceptsMissingRole(t *testing.T) {
+	g := &models.SkillGraph{
+		States: map[string]models.StateDef{
+			"plan": {Type: "action_loop"},
+			"code": {Type: "action_loop", SessionRole: "coder"},
+			"lint": {Type: "tool", Command: "true"},
+		},
+	}
+	if warnings := Validate(g); len(warnings) != 0 {
+		t.Errorf("expected the default role to pass silently, got %v", warnings)
+	}
+
+	// The default role still conflicts with an explicit one of the same name.
+	g.States["review"] = models.StateDef{Type: "action_loop", SessionRole: "plan", ModelTier: "low"}
+	if warnings := Validate(g); len(warnings) != 1 || !strings.Contains(warnings[0], "plan, review") {
+		t.Errorf("expected one shared-role warning, got %v", warnings)
+	}
+}
+
+func TestValidateWarnsOnConflictingSharedRole(t *testing.T) {
+	g := &models.SkillGraph{
+		States: map[string]models.StateDef{
+			"design": {Type: "action_loop", SessionRole: "agent", ModelTier: "high"},
+			"code":   {Type: "action_loop", SessionRole: "agent", ModelTier: "low"},
+		},
+	}
+	warnings := Validate(g)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "code, design") {
+		t.Errorf("expected one shared-role warning, got %v", warnings)
+	}
+
+	// Sharing a role with matching settings is intentional and fine.
+	g.States["code"] = models.StateDef{Type: "action_loop", SessionRole: "agent", ModelTier: "high"}
+	if warnings := Validate(g); len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+}
+
+func TestResolvedRoleDefaultsToStateName(t *testing.T) {
+	if got := (models.StateDef{}).ResolvedRole("plan"); got != "plan" {
+		t.Errorf("expected default role 'plan', got %q", got)
+	}
+	if got := (models.StateDef{SessionRole: "coder"}).ResolvedRole("plan"); got != "coder" {
+		t.Errorf("expected explicit role 'coder', got %q", got)
+	}
+}
+
+func TestValidateWarnsOnWaitWithoutCondition(t *testing.T) {
+	g := &models.SkillGraph{
+		States: map[string]models.StateDef{
+			"idle":   {Type: "wait", TimeoutSec: 30},
+			"deploy": {Type: "wait", WaitFile: "deploy.done"},
+		},
+	}
+	warnings := Validate(g)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `"idle"`) {
+		t.Errorf("expected one warning for the wait state without a condition, got %v", warnings)
+	}
+}
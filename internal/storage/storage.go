@@ -2,6 +2,7 @@ package storage
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +11,10 @@ import (
 // Storage provides basic file operations for JSON models.
 type Storage struct {
 	BaseDir string
+	// IncludePaths are extra directories searched for @file instruction
+	// includes, after the skill's own directory. Relative entries are
+	// resolved against the session CWD.
+	IncludePaths []string
 }
 
 func NewStorage(baseDir string) *Storage {
@@ -53,6 +58,18 @@ func (s *Storage) ReadJSON(relPath string, out interface{}) error {
 	return json.NewDecoder(f).Decode(out)
 }
 
+// CheckWritable reports whether files can be created in dir, by writing and
+// removing a probe file.
+func CheckWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".write-probe-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
 // Slugify maps a directory path to a safe filename slug.
 func Slugify(path string) string {
 	slug := strings.ReplaceAll(path, string(filepath.Separator), "-")
@@ -103,19 +120,67 @@ func (s *Storage) ResolveSkillPath(name string) string {
 
 // ResolveInstruction resolves an instruction content. If it starts with @, it reads from a file.
 func (s *Storage) ResolveInstruction(path string, skillBaseDir string) (string, error) {
-	fullPath, err := s.ResolveAssetPath(path, skillBaseDir)
-	if err != nil {
-		return path, err
+	content, _, err := s.ResolveInstructionFrom(path, skillBaseDir, "")
+	return content, err
+}
+
+// ResolveInstructionFrom is like ResolveInstruction but also searches the
+// IncludePaths (relative ones under cwd), and returns the file the
+// instruction was read from, or "" when it was not an include.
+func (s *Storage) ResolveInstructionFrom(path, skillBaseDir, cwd string) (string, string, error) {
+	if !strings.HasPrefix(path, "@") {
+		return path, "", nil
 	}
-	if fullPath == path {
-		return path, nil
+	fullPath, err := s.FindInclude(path, s.IncludeRoots(skillBaseDir, cwd))
+	if err != nil {
+		return "", "", err
 	}
-
 	data, err := os.ReadFile(fullPath)
 	if err != nil {
-		return "", err
+		return "", "", err
+	}
+	return string(data), fullPath, nil
+}
+
+// IncludeRoots lists the directories searched for @file includes: first
+// dir (when set), then the IncludePaths. Relative IncludePaths are joined to
+// cwd and skipped when there is none.
+func (s *Storage) IncludeRoots(dir, cwd string) []string {
+	var roots []string
+	if dir != "" {
+		roots = append(roots, dir)
+	}
+	for _, p := range s.IncludePaths {
+		if !filepath.IsAbs(p) {
+			if cwd == "" {
+				continue
+			}
+			p = filepath.Join(cwd, p)
+		}
+		roots = append(roots, p)
+	}
+	return roots
+}
+
+// FindInclude returns the first existing file named by an @file include
+// under roots. Absolute names and names escaping a root (e.g. "../x") are
+// rejected with os.ErrPermission.
+func (s *Storage) FindInclude(path string, roots []string) (string, error) {
+	filename := strings.TrimPrefix(path, "@")
+	if filepath.IsAbs(filename) {
+		return "", os.ErrPermission
+	}
+	for _, root := range roots {
+		fullPath := filepath.Join(root, filename)
+		rel, err := filepath.Rel(root, fullPath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", os.ErrPermission
+		}
+		if info, err := os.Stat(fullPath); err == nil && !info.IsDir() {
+			return fullPath, nil
+		}
 	}
-	return string(data), nil
+	return "", fmt.Errorf("%s not found in %s: %w", filename, strings.Join(roots, ", "), os.ErrNotExist)
 }
 
 // ResolveAssetPath resolves an asset path relative to the skill directory.
@@ -0,0 +1,66 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+// processAlive reports whether pid is a running process. EPERM means it
+// exists but belongs to another user.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// OrphanedTasks returns the in-progress tasks whose owner process is gone,
+// e.g. after a CLI crash. Tasks without an owner PID are left out: there is
+// nothing to check them against.
+func OrphanedTasks(tasks []*Task) []*Task {
+	var orphans []*Task
+	for _, t := range tasks {
+		if t.Status == StatusInProgress && t.OwnerPID > 0 && !processAlive(t.OwnerPID) {
+			orphans = append(orphans, t)
+		}
+	}
+	return orphans
+}
+
+// runDoctor reports the orphaned tasks among tasks to w and, with fix,
+// resets each one to todo the way "work reset" does.
+func runDoctor(w io.Writer, tasks []*Task, fix bool) error {
+	orphans := OrphanedTasks(tasks)
+	if len(orphans) == 0 {
+		fmt.Fprintln(w, "No orphaned tasks.")
+		return nil
+	}
+	for _, t := range orphans {
+		if !fix {
+			fmt.Fprintf(w, "Orphaned: %s — %s (owner PID %d is not running)\n", t.ID, t.Title, t.OwnerPID)
+			continue
+		}
+		pid := t.OwnerPID
+		t.Reset()
+		if err := WriteTask(t.FilePath, t); err != nil {
+			return fmt.Errorf("resetting %s: %w", t.ID, err)
+		}
+		fmt.Fprintf(w, "Reset: %s — %s (owner PID %d was not running)\n", t.ID, t.Title, pid)
+	}
+	if !fix {
+		fmt.Fprintln(w, "Run 'tenazas work doctor --fix' to reset them to todo.")
+	}
+	return nil
+}
+
+func handleWorkDoctor(tasksDir string, args []string) {
+	fix, args := extractBoolFlag(args, "--fix")
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: tenazas work doctor [--fix]")
+		os.Exit(1)
+	}
+	if err := runDoctor(os.Stdout, listTasksOrDie(tasksDir), fix); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,67 @@
+package task
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// deadPID returns the PID of a process that has already exited.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return cmd.Process.Pid
+}
+
+func TestWorkDoctor(t *testing.T) {
+	_, tasksDir, cleanup := setupTasksDir(t)
+	defer cleanup()
+	started := time.Now().Add(-time.Hour).Truncate(time.Second)
+	dead := deadPID(t)
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000001", Title: "Crashed", Status: StatusInProgress,
+		OwnerPID: dead, OwnerSessionID: "sess-1", FailureCount: 1, StartedAt: &started})
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000002", Title: "Running", Status: StatusInProgress, OwnerPID: os.Getpid()})
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000003", Title: "Unowned", Status: StatusInProgress})
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000004", Title: "Finished", Status: StatusDone, OwnerPID: dead})
+
+	tasks, err := ListTasks(tasksDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := runDoctor(&buf, tasks, false); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, "Orphaned: TSK-000001 — Crashed") ||
+		strings.Count(out, "Orphaned:") != 1 || !strings.Contains(out, "--fix") {
+		t.Errorf("expected only the crashed task reported, got:\n%s", out)
+	}
+	if tk := readTestTask(t, tasksDir, "TSK-000001"); tk.Status != StatusInProgress {
+		t.Errorf("a report without --fix changed the task to %s", tk.Status)
+	}
+
+	buf.Reset()
+	if err := runDoctor(&buf, tasks, true); err != nil {
+		t.Fatal(err)
+	}
+	tk := readTestTask(t, tasksDir, "TSK-000001")
+	if tk.Status != StatusTodo || tk.OwnerPID != 0 || tk.OwnerSessionID != "" || tk.FailureCount != 0 || tk.StartedAt != nil {
+		t.Errorf("--fix should reset the task like work reset, got %+v", tk)
+	}
+	if tk := readTestTask(t, tasksDir, "TSK-000002"); tk.Status != StatusInProgress || tk.OwnerPID != os.Getpid() {
+		t.Errorf("a task owned by a live process must be left alone, got %+v", tk)
+	}
+
+	tasks, _ = ListTasks(tasksDir)
+	buf.Reset()
+	runDoctor(&buf, tasks, false)
+	if buf.String() != "No orphaned tasks.\n" {
+		t.Errorf("after --fix: %q", buf.String())
+	}
+}
@@ -0,0 +1,132 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// graphTitleLen is how much of a title "work graph" puts in a node label.
+const graphTitleLen = 30
+
+const graphCycleWarning = "warning: the dependency graph contains a cycle"
+
+// statusFill is the node color of each status in "work graph" output.
+var statusFill = map[string]string{
+	StatusTodo:       "#e0e0e0",
+	StatusInProgress: "#fff3b0",
+	StatusBlocked:    "#ffcdd2",
+	StatusDone:       "#c8e6c9",
+}
+
+// graphEdge points from a task to one it blocks.
+type graphEdge struct {
+	From, To string
+}
+
+// dependencyEdges collects the edges recorded on either side of each
+// dependency, deduplicated and sorted. Edges to tasks not in tasks are
+// dropped.
+func dependencyEdges(tasks []*Task) []graphEdge {
+	taskMap := buildTaskMap(tasks)
+	seen := make(map[graphEdge]bool)
+	var edges []graphEdge
+	add := func(e graphEdge) {
+		if taskMap[e.From] == nil || taskMap[e.To] == nil || seen[e] {
+			return
+		}
+		seen[e] = true
+		edges = append(edges, e)
+	}
+	for _, t := range tasks {
+		for _, id := range t.BlockedBy {
+			add(graphEdge{From: id, To: t.ID})
+		}
+		for _, id := range t.Blocks {
+			add(graphEdge{From: t.ID, To: id})
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
+
+// sortedByID returns a copy of tasks ordered by ID.
+func sortedByID(tasks []*Task) []*Task {
+	sorted := append([]*Task(nil), tasks...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// RenderDOT writes the dependency graph of tasks in Graphviz DOT, one
+// filled node per task colored by status.
+func RenderDOT(w io.Writer, tasks []*Task) {
+	fmt.Fprintln(w, "digraph tasks {")
+	if HasCycle(tasks) {
+		fmt.Fprintf(w, "  // %s\n", graphCycleWarning)
+	}
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box, style=filled];")
+	for _, t := range sortedByID(tasks) {
+		label := dotEscaper.Replace(t.ID) + `\n` + dotEscaper.Replace(truncateTitle(t.Title, graphTitleLen))
+		fmt.Fprintf(w, "  %q [label=\"%s\", fillcolor=%q];\n", t.ID, label, statusFill[t.Status])
+	}
+	for _, e := range dependencyEdges(tasks) {
+		fmt.Fprintf(w, "  %q -> %q;\n", e.From, e.To)
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// mermaidID makes a task ID safe as a Mermaid node ID.
+func mermaidID(id string) string {
+	return strings.ReplaceAll(id, "-", "_")
+}
+
+// mermaidClass names the Mermaid class of a status.
+func mermaidClass(status string) string {
+	return strings.ReplaceAll(status, "-", "_")
+}
+
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;")
+
+// RenderMermaid writes the dependency graph of tasks as a Mermaid
+// "graph TD" block, with one class per status.
+func RenderMermaid(w io.Writer, tasks []*Task) {
+	fmt.Fprintln(w, "graph TD")
+	if HasCycle(tasks) {
+		fmt.Fprintf(w, "  %%%% %s\n", graphCycleWarning)
+	}
+	for _, t := range sortedByID(tasks) {
+		label := mermaidEscaper.Replace(t.ID + ": " + truncateTitle(t.Title, graphTitleLen))
+		fmt.Fprintf(w, "  %s[\"%s\"]:::%s\n", mermaidID(t.ID), label, mermaidClass(t.Status))
+	}
+	for _, e := range dependencyEdges(tasks) {
+		fmt.Fprintf(w, "  %s --> %s\n", mermaidID(e.From), mermaidID(e.To))
+	}
+	for _, status := range []string{StatusTodo, StatusInProgress, StatusBlocked, StatusDone} {
+		fmt.Fprintf(w, "  classDef %s fill:%s\n", mermaidClass(status), statusFill[status])
+	}
+}
+
+func handleWorkGraph(tasksDir string, args []string) {
+	mermaid, args := extractBoolFlag(args, "--mermaid")
+	_, args = extractBoolFlag(args, "--dot")
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: tenazas work graph [--dot|--mermaid]")
+		os.Exit(1)
+	}
+	tasks := listTasksOrDie(tasksDir)
+	if mermaid {
+		RenderMermaid(os.Stdout, tasks)
+		return
+	}
+	RenderDOT(os.Stdout, tasks)
+}
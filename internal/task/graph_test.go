@@ -0,0 +1,56 @@
+package task
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func graphTestTasks() []*Task {
+	return []*Task{
+		{ID: "TSK-000002", Title: `Write the "parser" for a very long feature name`, Status: StatusBlocked, BlockedBy: []string{"TSK-000001", "TSK-000009"}},
+		{ID: "TSK-000001", Title: "Design", Status: StatusDone, Blocks: []string{"TSK-000002"}},
+		{ID: "TSK-000003", Title: "Ship", Status: StatusTodo, BlockedBy: []string{"TSK-000002"}},
+	}
+}
+
+func TestRenderDOT(t *testing.T) {
+	var buf bytes.Buffer
+	RenderDOT(&buf, graphTestTasks())
+	out := buf.String()
+	for _, want := range []string{
+		`"TSK-000001" [label="TSK-000001\nDesign", fillcolor="#c8e6c9"];`,
+		`"TSK-000002" [label="TSK-000002\nWrite the \"parser\" for a very…", fillcolor="#ffcdd2"];`,
+		`"TSK-000001" -> "TSK-000002";`,
+		`"TSK-000002" -> "TSK-000003";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "->") != 2 {
+		t.Errorf("edges should be deduplicated and unknown tasks dropped:\n%s", out)
+	}
+	if strings.Contains(out, "cycle") {
+		t.Errorf("acyclic graph should carry no warning:\n%s", out)
+	}
+}
+
+func TestRenderMermaid(t *testing.T) {
+	tasks := graphTestTasks()
+	tasks[1].BlockedBy = []string{"TSK-000003"}
+	var buf bytes.Buffer
+	RenderMermaid(&buf, tasks)
+	out := buf.String()
+	for _, want := range []string{
+		"graph TD\n  %% warning: the dependency graph contains a cycle\n",
+		`TSK_000002["TSK-000002: Write the #quot;parser#quot; for a very…"]:::blocked`,
+		"TSK_000001 --> TSK_000002\n",
+		"TSK_000003 --> TSK_000001\n",
+		"classDef in_progress fill:#fff3b0\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultIDWidth is the zero-padded width of task numbers (TSK-000001).
+const DefaultIDWidth = 6
+
+// maxIDWidth bounds the configurable width.
+const maxIDWidth = 12
+
+// idWidthFile holds a project's task number width, next to .task_sequence.
+const idWidthFile = ".task_id_width"
+
+// IDWidth returns the zero-padded width of new task numbers in tasksDir.
+// Without a valid setting it is DefaultIDWidth.
+func IDWidth(tasksDir string) int {
+	data, err := os.ReadFile(filepath.Join(tasksDir, idWidthFile))
+	if err != nil {
+		return DefaultIDWidth
+	}
+	w, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || w < 1 || w > maxIDWidth {
+		return DefaultIDWidth
+	}
+	return w
+}
+
+// SetIDWidth sets the width of task numbers generated in tasksDir from now
+// on. Existing tasks keep their IDs and are still found by number.
+func SetIDWidth(tasksDir string, width int) error {
+	if width < 1 || width > maxIDWidth {
+		return fmt.Errorf("task ID width must be between 1 and %d, got %d", maxIDWidth, width)
+	}
+	if err := os.MkdirAll(tasksDir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(tasksDir, idWidthFile), []byte(strconv.Itoa(width)+"\n"), 0644)
+}
+
+// formatTaskID renders task number n at the given width.
+func formatTaskID(n, width int) string {
+	return fmt.Sprintf("%s%0*d", taskIDPrefix, width, n)
+}
+
+// taskNumber parses the number of a TSK-<digits> ID.
+func taskNumber(id string) (int, bool) {
+	digits, ok := strings.CutPrefix(id, taskIDPrefix)
+	if !ok || digits == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// NormalizeTaskIDIn is NormalizeTaskID for the project in tasksDir: bare
+// numbers are padded to its IDWidth, and an ID whose number matches a task
+// stored at another width (e.g. from before the width changed) resolves to
+// that task's ID.
+func NormalizeTaskIDIn(tasksDir, input string) string {
+	id := normalizeTaskIDWidth(input, IDWidth(tasksDir))
+	n, ok := taskNumber(id)
+	if !ok {
+		return id
+	}
+	if _, err := os.Stat(filepath.Join(tasksDir, id+".md")); err == nil {
+		return id
+	}
+	if existing := findIDByNumber(tasksDir, n); existing != "" {
+		return existing
+	}
+	return id
+}
+
+// findIDByNumber returns the ID of the task file in dir numbered n, at any
+// width, or "".
+func findIDByNumber(dir string, n int) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".md")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if m, ok := taskNumber(id); ok && m == n {
+			return id
+		}
+	}
+	return ""
+}
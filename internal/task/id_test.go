@@ -0,0 +1,84 @@
+package task
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetNextTaskIDCustomWidth(t *testing.T) {
+	dir := t.TempDir()
+	if err := SetIDWidth(dir, 4); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"TSK-0001", "TSK-0002"} {
+		id, err := GetNextTaskID(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != want {
+			t.Errorf("GetNextTaskID = %q, want %q", id, want)
+		}
+	}
+}
+
+func TestIDWidthDefaultsAndValidation(t *testing.T) {
+	dir := t.TempDir()
+	if w := IDWidth(dir); w != DefaultIDWidth {
+		t.Errorf("IDWidth without a setting = %d, want %d", w, DefaultIDWidth)
+	}
+	for _, bad := range []int{0, -1, maxIDWidth + 1} {
+		if err := SetIDWidth(dir, bad); err == nil {
+			t.Errorf("SetIDWidth(%d) should fail", bad)
+		}
+	}
+}
+
+func TestNormalizeTaskIDInCustomWidth(t *testing.T) {
+	dir := t.TempDir()
+	SetIDWidth(dir, 4)
+	writeTestTask(t, dir, &Task{ID: "TSK-000003", Title: "old six-digit task", Status: StatusTodo})
+
+	tests := []struct {
+		input, want string
+	}{
+		{"7", "TSK-0007"},
+		{"0012", "TSK-0012"},
+		{"tsk-0007", "TSK-0007"},
+		{"3", "TSK-000003"}, // resolves to the existing 6-digit file
+		{"TSK-0003", "TSK-000003"},
+		{"tsk-3", "TSK-000003"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeTaskIDIn(dir, tt.input); got != tt.want {
+			t.Errorf("NormalizeTaskIDIn(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFindAndListTasksAcrossWidths(t *testing.T) {
+	dir := t.TempDir()
+	writeTestTask(t, dir, &Task{ID: "TSK-000123", Title: "old", Status: StatusTodo})
+	writeTestTask(t, dir, &Task{ID: "TSK-000009", Title: "older", Status: StatusTodo})
+	SetIDWidth(dir, 4)
+	writeTestTask(t, dir, &Task{ID: "TSK-0124", Title: "new", Status: StatusTodo})
+
+	found, err := FindTask(dir, "TSK-0123")
+	if err != nil || found.ID != "TSK-000123" {
+		t.Fatalf("FindTask(TSK-0123) = %v, %v; want the 6-digit task", found, err)
+	}
+	if _, err := FindTask(dir, "TSK-0500"); err == nil {
+		t.Error("FindTask should fail for a number with no task")
+	}
+
+	tasks, err := ListTasks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, tk := range tasks {
+		ids = append(ids, tk.ID)
+	}
+	if got := strings.Join(ids, ","); got != "TSK-000009,TSK-000123,TSK-0124" {
+		t.Errorf("ListTasks order = %s, want numeric order", got)
+	}
+}
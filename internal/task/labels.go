@@ -0,0 +1,90 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalizeLabel lowercases a label and maps it onto the [a-z0-9-_] charset:
+// runs of whitespace become '-', any other character is dropped.
+func NormalizeLabel(label string) string {
+	var sb strings.Builder
+	lastDash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(label)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			sb.WriteRune(r)
+			lastDash = false
+		case r == '-' || r == ' ' || r == '\t':
+			if !lastDash {
+				sb.WriteRune('-')
+				lastDash = true
+			}
+		}
+	}
+	return strings.Trim(sb.String(), "-")
+}
+
+// NormalizeLabels normalizes each label, dropping empty results and
+// duplicates while keeping first-seen order. It returns a warning for every
+// label that needed more than trimming and case folding.
+func NormalizeLabels(labels []string) ([]string, []string) {
+	var out, warnings []string
+	seen := make(map[string]bool, len(labels))
+	for _, raw := range labels {
+		norm := NormalizeLabel(raw)
+		folded := strings.ToLower(strings.TrimSpace(raw))
+		switch {
+		case norm == "" && folded != "":
+			warnings = append(warnings, fmt.Sprintf("label %q dropped: no valid characters (allowed: a-z, 0-9, -, _)", raw))
+		case norm != folded:
+			warnings = append(warnings, fmt.Sprintf("label %q normalized to %q", raw, norm))
+		}
+		if norm == "" || seen[norm] {
+			continue
+		}
+		seen[norm] = true
+		out = append(out, norm)
+	}
+	return out, warnings
+}
+
+// MergeLabels returns explicit followed by defaults, normalized and deduped,
+// so explicit labels keep their order and defaults only fill in.
+func MergeLabels(explicit, defaults []string) []string {
+	all := make([]string, 0, len(explicit)+len(defaults))
+	all = append(all, explicit...)
+	all = append(all, defaults...)
+	merged, _ := NormalizeLabels(all)
+	return merged
+}
+
+// ApplySkillDefaults merges a skill's default labels into the task and binds
+// skill when the task has none. It reports whether the task changed.
+func (t *Task) ApplySkillDefaults(labels []string, skill string) bool {
+	changed := false
+	if len(labels) > 0 {
+		merged := MergeLabels(t.Labels, labels)
+		if !equalStrings(merged, t.Labels) {
+			t.Labels = merged
+			changed = true
+		}
+	}
+	if t.Skill == "" && skill != "" {
+		t.Skill = skill
+		changed = true
+	}
+	return changed
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
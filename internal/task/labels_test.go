@@ -0,0 +1,68 @@
+package task
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeLabels_CaseFoldAndDedupe(t *testing.T) {
+	got, warnings := NormalizeLabels([]string{"Bug", " bug ", "BUG", "ui", "UI"})
+	if want := []string{"bug", "ui"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("labels = %v, want %v", got, want)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("case folding should not warn, got %v", warnings)
+	}
+}
+
+func TestNormalizeLabels_IllegalCharacters(t *testing.T) {
+	got, warnings := NormalizeLabels([]string{"needs review", "v1.2!", "@@@", "back_end", "front--end"})
+	want := []string{"needs-review", "v12", "back_end", "front-end"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("labels = %v, want %v", got, want)
+	}
+	if len(warnings) != 4 {
+		t.Errorf("expected 4 warnings (3 transformed, 1 dropped), got %d: %v", len(warnings), warnings)
+	}
+}
+
+func TestNormalizeLabels_EmptyInput(t *testing.T) {
+	got, warnings := NormalizeLabels([]string{"", "  "})
+	if len(got) != 0 || len(warnings) != 0 {
+		t.Errorf("expected no labels and no warnings, got %v / %v", got, warnings)
+	}
+}
+
+func TestExtractLabelsFlag(t *testing.T) {
+	raw, rest, err := extractLabelsFlag([]string{"--labels", "a,B", "Title", "Desc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw != "a,B" || !reflect.DeepEqual(rest, []string{"Title", "Desc"}) {
+		t.Errorf("raw=%q rest=%v", raw, rest)
+	}
+	if _, _, err := extractLabelsFlag([]string{"Title", "--labels"}); err == nil {
+		t.Error("expected error for missing --labels value")
+	}
+}
+
+func TestApplySkillDefaults(t *testing.T) {
+	tk := &Task{Labels: []string{"urgent", "bug"}}
+	if !tk.ApplySkillDefaults([]string{"Bug", "triage"}, "fixer") {
+		t.Fatal("expected task to change")
+	}
+	if want := []string{"urgent", "bug", "triage"}; !reflect.DeepEqual(tk.Labels, want) {
+		t.Errorf("labels = %v, want %v", tk.Labels, want)
+	}
+	if tk.Skill != "fixer" {
+		t.Errorf("expected default skill to be bound, got %q", tk.Skill)
+	}
+
+	tk.Skill = "explicit"
+	if tk.ApplySkillDefaults([]string{"bug"}, "fixer") {
+		t.Error("expected no change when defaults are already present")
+	}
+	if tk.Skill != "explicit" {
+		t.Errorf("explicit skill must win, got %q", tk.Skill)
+	}
+}
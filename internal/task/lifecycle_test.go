@@ -136,7 +136,7 @@ func TestTimestampOnNext(t *testing.T) {
 	before := time.Now().Truncate(time.Second)
 
 	// Trigger work next
-	HandleWorkCommand(tmpStorage, []string{"next"})
+	HandleWorkCommand(tmpStorage, Settings{}, []string{"next"})
 
 	// Read back the task
 	updated, err := ReadTask(taskPath)
@@ -187,7 +187,7 @@ func TestTimestampOnComplete(t *testing.T) {
 	}
 
 	// Trigger work complete
-	HandleWorkCommand(tmpStorage, []string{"complete"})
+	HandleWorkCommand(tmpStorage, Settings{}, []string{"complete"})
 
 	// Read back the task
 	updated, err := ReadTask(taskPath)
@@ -237,7 +237,7 @@ func TestTimestampDuration(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	HandleWorkCommand(tmpStorage, []string{"complete"})
+	HandleWorkCommand(tmpStorage, Settings{}, []string{"complete"})
 
 	updated, err := ReadTask(taskPath)
 	if err != nil {
@@ -279,7 +279,7 @@ func TestOwnerFieldsOnNext(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	HandleWorkCommand(tmpStorage, []string{"next"})
+	HandleWorkCommand(tmpStorage, Settings{}, []string{"next"})
 
 	updated, err := ReadTask(taskPath)
 	if err != nil {
@@ -323,7 +323,7 @@ func TestOwnerFieldsClearedOnComplete(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	HandleWorkCommand(tmpStorage, []string{"complete"})
+	HandleWorkCommand(tmpStorage, Settings{}, []string{"complete"})
 
 	updated, err := ReadTask(taskPath)
 	if err != nil {
@@ -430,7 +430,7 @@ func TestWorkInitMigration(t *testing.T) {
 	}
 
 	// Run work init which should trigger migration
-	HandleWorkCommand(tmpStorage, []string{"init"})
+	HandleWorkCommand(tmpStorage, Settings{}, []string{"init"})
 
 	// Verify old file is migrated to TSK-001234.md
 	v2Path := filepath.Join(tasksDir, "TSK-001234.md")
@@ -473,7 +473,7 @@ func TestWorkInitSummary(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	HandleWorkCommand(tmpStorage, []string{"init"})
+	HandleWorkCommand(tmpStorage, Settings{}, []string{"init"})
 
 	w.Close()
 	os.Stdout = oldStdout
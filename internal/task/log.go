@@ -0,0 +1,193 @@
+package task
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/formatter"
+)
+
+// DefaultLogTail is how many log entries "show --log" renders.
+const DefaultLogTail = 20
+
+// LogEntry is one line of a task's execution log (logs/<id>.jsonl).
+type LogEntry struct {
+	Time     time.Time
+	Event    string
+	Message  string
+	ExitCode int
+}
+
+// LogPath returns the path of a task's execution log.
+func LogPath(tasksDir, id string) string {
+	return filepath.Join(tasksDir, "logs", id+".jsonl")
+}
+
+// ReadLog returns the last limit entries of a task's log (all when limit <= 0).
+// Blank and malformed lines are skipped; a missing log yields no entries.
+func ReadLog(tasksDir, id string, limit int) ([]LogEntry, error) {
+	f, err := os.Open(LogPath(tasksDir, id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []LogEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		entry, ok := parseLogLine(scanner.Bytes())
+		if !ok {
+			continue
+		}
+		entries = append(entries, entry)
+		if limit > 0 && len(entries) > limit {
+			entries = entries[1:]
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// parseLogLine accepts the field names the task log writers have used
+// (timestamp/time/ts, event/type, message/msg/content/detail).
+func parseLogLine(line []byte) (LogEntry, bool) {
+	var raw map[string]any
+	if err := json.Unmarshal(line, &raw); err != nil || len(raw) == 0 {
+		return LogEntry{}, false
+	}
+	entry := LogEntry{
+		Event:   firstString(raw, "event", "type"),
+		Message: firstString(raw, "message", "msg", "content", "detail"),
+	}
+	if code, ok := raw["exit_code"].(float64); ok {
+		entry.ExitCode = int(code)
+	}
+	if ts := firstString(raw, "timestamp", "time", "ts"); ts != "" {
+		entry.Time, _ = time.Parse(time.RFC3339Nano, ts)
+	}
+	if entry.Event == "" && entry.Message == "" {
+		return LogEntry{}, false
+	}
+	return entry, true
+}
+
+func firstString(raw map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if v, ok := raw[k]; ok {
+			switch s := v.(type) {
+			case string:
+				return s
+			case nil:
+			default:
+				return fmt.Sprint(s)
+			}
+		}
+	}
+	return ""
+}
+
+// RenderLog writes log entries as an activity section for RenderShow output.
+func RenderLog(w io.Writer, entries []LogEntry) {
+	fmt.Fprintf(w, "\n── Activity %s\n", strings.Repeat("─", 28))
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "  (no log entries)")
+		return
+	}
+	for _, e := range entries {
+		when := "                   "
+		if !e.Time.IsZero() {
+			when = e.Time.Local().Format("2006-01-02 15:04:05")
+		}
+		line := fmt.Sprintf("  %s  %-12s %s", when, e.Event, e.Message)
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+}
+
+// logTimeFormat stamps "work log" entries like RenderLog does.
+var logTimeFormat = formatter.TimeFormat{Layout: "2006-01-02 15:04:05"}
+
+// RenderLogEntries writes entries through the audit formatter, the way
+// session logs are shown, so event types that match audit types (status,
+// cmd_result, llm_response…) get their usual styling.
+func RenderLogEntries(w io.Writer, entries []LogEntry) {
+	f := &formatter.AnsiFormatter{Time: logTimeFormat}
+	for _, e := range entries {
+		fmt.Fprintln(w, f.Format(events.AuditEntry{Timestamp: e.Time, Type: e.Event, Content: e.Message, ExitCode: e.ExitCode}))
+	}
+}
+
+func handleWorkLog(tasksDir string, args []string) {
+	const usage = "Usage: tenazas work log <task-id> [--tail N] [--raw]"
+	raw, args := extractBoolFlag(args, "--raw")
+	tail := 0
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--tail" {
+			n, err := strconv.Atoi(nextFlagValue(args, &i, "--tail"))
+			if err != nil || n < 1 {
+				fmt.Fprintln(os.Stderr, "Error: --tail must be a positive integer")
+				os.Exit(1)
+			}
+			tail = n
+			continue
+		}
+		rest = append(rest, args[i])
+	}
+	if len(rest) != 1 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	id, _ := findTaskOrDie(tasksDir, rest[0])
+	if err := writeTaskLog(os.Stdout, tasksDir, id, tail, raw); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading task log: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// writeTaskLog prints the log of task id: verbatim when raw (the last tail
+// lines when tail > 0), else its last tail entries formatted. ANSI styling
+// is dropped when w is not a terminal.
+func writeTaskLog(w io.Writer, tasksDir, id string, tail int, raw bool) error {
+	if _, err := os.Stat(LogPath(tasksDir, id)); os.IsNotExist(err) {
+		fmt.Fprintf(w, "No log recorded for %s yet.\n", id)
+		return nil
+	}
+	if raw {
+		data, err := os.ReadFile(LogPath(tasksDir, id))
+		if err != nil {
+			return err
+		}
+		lines := strings.SplitAfter(strings.TrimRight(string(data), "\n"), "\n")
+		if tail > 0 && len(lines) > tail {
+			lines = lines[len(lines)-tail:]
+		}
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(lines, ""), "\n"))
+		return nil
+	}
+
+	entries, err := ReadLog(tasksDir, id, tail)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(w, "The log of %s has no entries.\n", id)
+		return nil
+	}
+	if f, ok := w.(*os.File); !ok || !isTerminal(f) {
+		w = &formatter.PlainWriter{W: w}
+	}
+	RenderLogEntries(w, entries)
+	return nil
+}
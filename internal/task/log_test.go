@@ -0,0 +1,98 @@
+package task
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadLogSkipsMalformedLines(t *testing.T) {
+	tasksDir := t.TempDir()
+	writeTestLog(t, tasksDir, "TSK-000001", strings.Join([]string{
+		`{"timestamp":"2025-01-02T10:00:00Z","event":"claimed","message":"picked up by heartbeat"}`,
+		`not json`,
+		``,
+		`{}`,
+		`{"time":"2025-01-02T10:05:00Z","type":"failed","msg":"exit 1"}`,
+	}, "\n"))
+
+	entries, err := ReadLog(tasksDir, "TSK-000001", 0)
+	if err != nil {
+		t.Fatalf("ReadLog: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if entries[0].Event != "claimed" || entries[1].Event != "failed" || entries[1].Message != "exit 1" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+	if !entries[0].Time.Equal(time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("timestamp not parsed: %v", entries[0].Time)
+	}
+
+	tail, _ := ReadLog(tasksDir, "TSK-000001", 1)
+	if len(tail) != 1 || tail[0].Event != "failed" {
+		t.Errorf("expected only the last entry, got %+v", tail)
+	}
+
+	missing, err := ReadLog(tasksDir, "TSK-000099", 0)
+	if err != nil || len(missing) != 0 {
+		t.Errorf("missing log should be empty, got %v, %v", missing, err)
+	}
+}
+
+func TestRenderShowWithLogAppendsActivityAfterContent(t *testing.T) {
+	tasksDir := t.TempDir()
+	writeTestLog(t, tasksDir, "TSK-000001", `{"event":"run","message":"heartbeat nightly"}`+"\n"+`{"event":"done"}`)
+
+	tk := &Task{ID: "TSK-000001", Title: "Fix login", Status: StatusDone, Content: "The body of the task."}
+	var buf bytes.Buffer
+	RenderShow(&buf, tk, map[string]*Task{tk.ID: tk}, nil)
+	entries, _ := ReadLog(tasksDir, tk.ID, DefaultLogTail)
+	RenderLog(&buf, entries)
+
+	out := buf.String()
+	body := strings.Index(out, "The body of the task.")
+	run := strings.Index(out, "heartbeat nightly")
+	done := strings.Index(out, "done")
+	if body < 0 || run < 0 || done < 0 {
+		t.Fatalf("expected body and log entries in output:\n%s", out)
+	}
+	if run < body {
+		t.Errorf("log entries should come after the task body:\n%s", out)
+	}
+}
+
+func TestWriteTaskLog(t *testing.T) {
+	tasksDir := t.TempDir()
+	lines := []string{
+		`{"timestamp":"2025-01-02T10:00:00Z","event":"status","message":"claimed by heartbeat"}`,
+		`{"timestamp":"2025-01-02T10:01:00Z","event":"cmd_result","message":"go test ./...","exit_code":1}`,
+		`{"timestamp":"2025-01-02T10:02:00Z","event":"info","message":"retrying"}`,
+	}
+	writeTestLog(t, tasksDir, "TSK-000001", strings.Join(lines, "\n")+"\n")
+
+	var buf bytes.Buffer
+	if err := writeTaskLog(&buf, tasksDir, "TSK-000001", 2, false); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "claimed") || !strings.Contains(out, "● Command Result\n  └ go test ./...") || !strings.Contains(out, "● retrying") {
+		t.Errorf("expected the last two entries formatted, got:\n%s", out)
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("output to a non-terminal should be plain, got %q", out)
+	}
+
+	buf.Reset()
+	writeTaskLog(&buf, tasksDir, "TSK-000001", 1, true)
+	if buf.String() != lines[2]+"\n" {
+		t.Errorf("--raw --tail 1 = %q, want the last line verbatim", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeTaskLog(&buf, tasksDir, "TSK-000002", 0, false); err != nil || buf.String() != "No log recorded for TSK-000002 yet.\n" {
+		t.Errorf("missing log: %q, %v", buf.String(), err)
+	}
+}
@@ -0,0 +1,85 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MoveTask moves task id from srcDir to the tasks dir of another project.
+// The task gets a fresh ID from the target's sequence and its log moves with
+// it. Dependency edges cannot cross projects, so they are dropped and
+// reported as warnings; a task that still blocks unfinished work, or one
+// that is in progress, is not moved.
+func MoveTask(srcDir, dstDir, id string) (*Task, []string, error) {
+	if filepath.Clean(srcDir) == filepath.Clean(dstDir) {
+		return nil, nil, fmt.Errorf("task %s is already in that project", id)
+	}
+	tasks, err := ListTasks(srcDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	taskMap := buildTaskMap(tasks)
+	t, ok := taskMap[id]
+	if !ok {
+		return nil, nil, fmt.Errorf("task %s not found", id)
+	}
+	if t.Status == StatusInProgress {
+		return nil, nil, fmt.Errorf("cannot move %s while it is in progress", id)
+	}
+	for _, depID := range t.Blocks {
+		if dep, ok := taskMap[depID]; ok && dep.Status != StatusDone && sliceContains(dep.BlockedBy, id) {
+			return nil, nil, fmt.Errorf("cannot move %s — it blocks active task %s (%s)", id, dep.ID, dep.Status)
+		}
+	}
+
+	var warnings []string
+	for _, depID := range t.BlockedBy {
+		warnings = append(warnings, fmt.Sprintf("dropped dependency on %s (dependencies cannot cross projects)", depID))
+	}
+	for _, depID := range t.Blocks {
+		warnings = append(warnings, fmt.Sprintf("dropped dependency of %s (dependencies cannot cross projects)", depID))
+	}
+
+	newID, err := GetNextTaskID(dstDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	oldPath := t.FilePath
+	moved := *t
+	moved.ID = newID
+	moved.FilePath = filepath.Join(dstDir, newID+".md")
+	moved.BlockedBy = nil
+	moved.Blocks = nil
+	if err := WriteTask(moved.FilePath, &moved); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := os.Stat(LogPath(srcDir, id)); err == nil {
+		if err := os.MkdirAll(filepath.Join(dstDir, "logs"), 0755); err != nil {
+			return nil, nil, err
+		}
+		if err := os.Rename(LogPath(srcDir, id), LogPath(dstDir, newID)); err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not move log: %v", err))
+		}
+	}
+
+	for _, other := range tasks {
+		if other.ID == id {
+			continue
+		}
+		origBlockedBy, origBlocks := len(other.BlockedBy), len(other.Blocks)
+		other.BlockedBy = removeFromSlice(other.BlockedBy, id)
+		other.Blocks = removeFromSlice(other.Blocks, id)
+		if len(other.BlockedBy) != origBlockedBy || len(other.Blocks) != origBlocks {
+			if err := WriteTask(other.FilePath, other); err != nil {
+				warnings = append(warnings, fmt.Sprintf("could not update %s: %v", other.ID, err))
+			}
+		}
+	}
+
+	if err := os.Remove(oldPath); err != nil {
+		return &moved, warnings, fmt.Errorf("moved to %s but could not remove %s: %w", newID, oldPath, err)
+	}
+	return &moved, warnings, nil
+}
@@ -0,0 +1,67 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMoveTask(t *testing.T) {
+	storageDir, tasksDir, cleanup := setupTasksDir(t)
+	defer cleanup()
+	dstDir := TasksDirFor(storageDir, t.TempDir())
+
+	// The target already has tasks, so the moved one must take its next ID.
+	writeTestTask(t, dstDir, &Task{ID: "TSK-000001", Title: "Existing", Status: StatusTodo})
+	if err := os.WriteFile(filepath.Join(dstDir, ".task_sequence"), []byte("1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000001", Title: "Prereq", Status: StatusDone, Blocks: []string{"TSK-000002"}})
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000002", Title: "Mover", Status: StatusTodo, Priority: 3, BlockedBy: []string{"TSK-000001"}, Content: "Body"})
+	writeTestLog(t, tasksDir, "TSK-000002", `{"event":"created"}`+"\n")
+
+	moved, warnings, err := MoveTask(tasksDir, dstDir, "TSK-000002")
+	if err != nil {
+		t.Fatalf("MoveTask: %v", err)
+	}
+	if moved.ID != "TSK-000002" || moved.FilePath != filepath.Join(dstDir, "TSK-000002.md") {
+		t.Errorf("moved = %s at %s, want TSK-000002 in %s", moved.ID, moved.FilePath, dstDir)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "TSK-000001") {
+		t.Errorf("warnings = %v, want one about the dropped TSK-000001 dependency", warnings)
+	}
+
+	got := readTestTask(t, dstDir, moved.ID)
+	if got.Title != "Mover" || got.Priority != 3 || got.Content != "Body" || len(got.BlockedBy) != 0 {
+		t.Errorf("target task = %+v", got)
+	}
+	if _, err := os.Stat(filepath.Join(tasksDir, "TSK-000002.md")); !os.IsNotExist(err) {
+		t.Error("source task file should be removed")
+	}
+	if _, err := os.Stat(LogPath(dstDir, moved.ID)); err != nil {
+		t.Errorf("log should move with the task: %v", err)
+	}
+	if prereq := readTestTask(t, tasksDir, "TSK-000001"); len(prereq.Blocks) != 0 {
+		t.Errorf("source Blocks edge should be dropped, got %v", prereq.Blocks)
+	}
+}
+
+func TestMoveTaskRefusesInProgress(t *testing.T) {
+	storageDir, tasksDir, cleanup := setupTasksDir(t)
+	defer cleanup()
+	dstDir := TasksDirFor(storageDir, t.TempDir())
+
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000001", Title: "Busy", Status: StatusInProgress, OwnerPID: 1})
+
+	if _, _, err := MoveTask(tasksDir, dstDir, "TSK-000001"); err == nil || !strings.Contains(err.Error(), "in progress") {
+		t.Fatalf("MoveTask err = %v, want in-progress refusal", err)
+	}
+	if _, err := os.Stat(filepath.Join(tasksDir, "TSK-000001.md")); err != nil {
+		t.Errorf("task should stay in place: %v", err)
+	}
+	if tasks, _ := ListTasks(dstDir); len(tasks) != 0 {
+		t.Errorf("target should be untouched, got %d tasks", len(tasks))
+	}
+}
@@ -0,0 +1,67 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// notesHeading starts the section of a task's content that AddNote appends to.
+const notesHeading = "## Notes"
+
+// AddNote appends a timestamped line to the task's notes section, creating
+// the section at the end of the content when there is none.
+func (t *Task) AddNote(text string, at time.Time) {
+	line := fmt.Sprintf("- %s %s", at.Format(time.RFC3339), text)
+
+	if strings.HasSuffix(t.Content, "\n"+notesHeading) || t.Content == notesHeading {
+		t.Content += "\n"
+	}
+	start := -1
+	if strings.HasPrefix(t.Content, notesHeading+"\n") {
+		start = 0
+	} else if i := strings.Index(t.Content, "\n"+notesHeading+"\n"); i >= 0 {
+		start = i + 1
+	}
+	if start < 0 {
+		content := strings.TrimRight(t.Content, "\n")
+		if content != "" {
+			content += "\n\n"
+		}
+		t.Content = content + notesHeading + "\n" + line
+		return
+	}
+
+	// Insert before the next section, if any, so notes stay together.
+	body := start + len(notesHeading) + 1
+	end := len(t.Content)
+	if next := strings.Index(t.Content[body:], "\n## "); next >= 0 {
+		end = body + next
+	}
+	section := strings.TrimRight(t.Content[body:end], "\n")
+	gap := t.Content[body+len(section) : end]
+	if section != "" {
+		section += "\n"
+	}
+	t.Content = t.Content[:body] + section + line + gap + t.Content[end:]
+}
+
+// Reopen moves a done task back to todo, clearing its completion time and
+// recording reason as a note.
+func (t *Task) Reopen(reason string, now time.Time) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return fmt.Errorf("a reason is required to reopen a task")
+	}
+	if t.Status != StatusDone {
+		return fmt.Errorf("task %s is not done (current: %s)", t.ID, t.Status)
+	}
+	if err := ValidateStatusTransition(t.Status, StatusTodo); err != nil {
+		return err
+	}
+	t.Status = StatusTodo
+	t.CompletedAt = nil
+	t.ClearOwnership()
+	t.AddNote("Reopened: "+reason, now)
+	return nil
+}
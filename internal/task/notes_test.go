@@ -0,0 +1,69 @@
+package task
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddNote(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tk := &Task{Content: "Implement the thing."}
+	tk.AddNote("first", at)
+	tk.AddNote("second", at)
+	want := "Implement the thing.\n\n## Notes\n- 2026-03-01T12:00:00Z first\n- 2026-03-01T12:00:00Z second"
+	if tk.Content != want {
+		t.Errorf("Content = %q, want %q", tk.Content, want)
+	}
+
+	tk = &Task{Content: "## Notes\n- old\n\n## Acceptance\n- works"}
+	tk.AddNote("new", at)
+	want = "## Notes\n- old\n- 2026-03-01T12:00:00Z new\n\n## Acceptance\n- works"
+	if tk.Content != want {
+		t.Errorf("notes before another section: Content = %q, want %q", tk.Content, want)
+	}
+}
+
+func TestWorkReopen(t *testing.T) {
+	storageDir, tasksDir, cleanup := setupTasksDir(t)
+	defer cleanup()
+
+	started := time.Now().Add(-time.Hour).Truncate(time.Second)
+	completed := started.Add(30 * time.Minute)
+	writeTestTask(t, tasksDir, &Task{
+		ID: "TSK-000001", Title: "Flaky fix", Status: StatusDone,
+		StartedAt: &started, CompletedAt: &completed, Content: "Fix the flake.",
+	})
+
+	HandleWorkCommand(storageDir, Settings{}, []string{"reopen", "1", "--reason", "flake is back"})
+
+	tk := readTestTask(t, tasksDir, "TSK-000001")
+	if tk.Status != StatusTodo {
+		t.Errorf("Status = %q, want %q", tk.Status, StatusTodo)
+	}
+	if tk.CompletedAt != nil {
+		t.Errorf("CompletedAt = %v, want nil", tk.CompletedAt)
+	}
+	if tk.StartedAt == nil || !tk.StartedAt.Equal(started) {
+		t.Errorf("StartedAt = %v, want it kept", tk.StartedAt)
+	}
+	if !strings.Contains(tk.Content, "## Notes\n- ") || !strings.HasSuffix(tk.Content, " Reopened: flake is back") {
+		t.Errorf("expected a reopen note, got %q", tk.Content)
+	}
+}
+
+func TestReopenRejectsTasksNotDone(t *testing.T) {
+	for _, status := range []string{StatusTodo, StatusInProgress, StatusBlocked} {
+		tk := &Task{ID: "TSK-000001", Status: status}
+		if err := tk.Reopen("why not", time.Now()); err == nil || !strings.Contains(err.Error(), "is not done") {
+			t.Errorf("Reopen of a %s task: err = %v, want a not-done error", status, err)
+		}
+		if tk.Status != status || tk.Content != "" {
+			t.Errorf("a rejected reopen changed the task: %+v", tk)
+		}
+	}
+	tk := &Task{ID: "TSK-000001", Status: StatusDone}
+	if err := tk.Reopen("  ", time.Now()); err == nil || tk.Status != StatusDone {
+		t.Errorf("Reopen without a reason: err = %v, status %s", err, tk.Status)
+	}
+}
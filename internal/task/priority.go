@@ -0,0 +1,89 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PriorityLevel names every priority from Min up to the next level's Min.
+type PriorityLevel struct {
+	Min   int
+	Label string
+}
+
+// PriorityLevels is a priority → label mapping sorted by Min. A nil
+// PriorityLevels behaves as DefaultPriorityLevels.
+type PriorityLevels []PriorityLevel
+
+// DefaultPriorityLevels is the built-in mapping: 0=none, 1-2=low, 3=medium,
+// 4=high, 5 and above=urgent.
+var DefaultPriorityLevels = PriorityLevels{
+	{Min: 0, Label: "none"},
+	{Min: 1, Label: "low"},
+	{Min: 3, Label: "medium"},
+	{Min: 4, Label: "high"},
+	{Min: 5, Label: "urgent"},
+}
+
+// NewPriorityLevels builds the mapping from a label → minimum priority map
+// (the "priority_labels" config). An empty map gives the defaults.
+func NewPriorityLevels(labels map[string]int) PriorityLevels {
+	if len(labels) == 0 {
+		return DefaultPriorityLevels
+	}
+	levels := make(PriorityLevels, 0, len(labels))
+	for label, min := range labels {
+		levels = append(levels, PriorityLevel{Min: min, Label: strings.ToLower(label)})
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Min < levels[j].Min })
+	return levels
+}
+
+func (l PriorityLevels) orDefault() PriorityLevels {
+	if l == nil {
+		return DefaultPriorityLevels
+	}
+	return l
+}
+
+// label returns the label of the highest level whose Min is <= p, or "" when
+// p is below every level.
+func (l PriorityLevels) label(p int) string {
+	label := ""
+	for _, lvl := range l.orDefault() {
+		if p >= lvl.Min {
+			label = lvl.Label
+		}
+	}
+	return label
+}
+
+// format renders p as "4 (high)", or just the number when unlabeled.
+func (l PriorityLevels) format(p int) string {
+	if label := l.label(p); label != "" {
+		return fmt.Sprintf("%d (%s)", p, label)
+	}
+	return strconv.Itoa(p)
+}
+
+// parse accepts a non-negative integer or a level label, which maps to that
+// level's Min.
+func (l PriorityLevels) parse(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if p, err := strconv.Atoi(s); err == nil {
+		if p < 0 {
+			return 0, fmt.Errorf("priority must be non-negative")
+		}
+		return p, nil
+	}
+	var names []string
+	for _, lvl := range l.orDefault() {
+		if strings.EqualFold(s, lvl.Label) {
+			return lvl.Min, nil
+		}
+		names = append(names, lvl.Label)
+	}
+	return 0, fmt.Errorf("priority must be a non-negative integer or one of: %s", strings.Join(names, ", "))
+}
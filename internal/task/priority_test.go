@@ -0,0 +1,82 @@
+package task
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPriorityLabel(t *testing.T) {
+	tests := []struct {
+		p    int
+		want string
+	}{
+		{0, "none"},
+		{1, "low"},
+		{2, "low"},
+		{3, "medium"},
+		{4, "high"},
+		{5, "urgent"},
+		{9, "urgent"},
+	}
+	for _, tt := range tests {
+		if got := DefaultPriorityLevels.label(tt.p); got != tt.want {
+			t.Errorf("label(%d) = %q, want %q", tt.p, got, tt.want)
+		}
+	}
+	var unset PriorityLevels
+	if got := unset.format(4); got != "4 (high)" {
+		t.Errorf("format(4) = %q", got)
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"3", 3, false},
+		{"0", 0, false},
+		{"low", 1, false},
+		{"High", 4, false},
+		{"urgent", 5, false},
+		{"-1", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := DefaultPriorityLevels.parse(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parse(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parse(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPriorityRoundTrip(t *testing.T) {
+	for _, lvl := range DefaultPriorityLevels {
+		p, err := DefaultPriorityLevels.parse(lvl.Label)
+		if err != nil || DefaultPriorityLevels.label(p) != lvl.Label {
+			t.Errorf("label %q did not round-trip: p=%d err=%v", lvl.Label, p, err)
+		}
+	}
+}
+
+func TestNewPriorityLevels(t *testing.T) {
+	levels := NewPriorityLevels(map[string]int{"P3": 0, "P2": 5, "P1": 10})
+	if got := levels.label(7); got != "p2" {
+		t.Errorf("expected p2 for 7, got %q", got)
+	}
+	if p, err := levels.parse("P1"); err != nil || p != 10 {
+		t.Errorf("expected P1 → 10, got %d (%v)", p, err)
+	}
+	if _, err := levels.parse("high"); err == nil || !strings.Contains(err.Error(), "p1") {
+		t.Errorf("expected error listing configured labels, got %v", err)
+	}
+
+	if got := NewPriorityLevels(nil).label(4); got != "high" {
+		t.Errorf("expected defaults for an empty map, got %q", got)
+	}
+}
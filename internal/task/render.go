@@ -38,6 +38,19 @@ func FormatDuration(t *Task) string {
 	return "—"
 }
 
+// FormatTotalTime is the cumulative counterpart of FormatDuration: the time
+// t spent in progress over every cycle, marked while a span is running.
+func FormatTotalTime(t *Task, now time.Time) string {
+	d := t.ActiveTime(now)
+	if d <= 0 {
+		return "—"
+	}
+	if t.Status == StatusInProgress {
+		return formatHumanDuration(d) + " (running)"
+	}
+	return formatHumanDuration(d)
+}
+
 func formatHumanDuration(d time.Duration) string {
 	if d < 0 {
 		d = 0
@@ -70,30 +83,62 @@ func printStatusSummaryTo(w io.Writer, tasks []*Task) {
 		counts[StatusTodo], counts[StatusInProgress], counts[StatusDone], counts[StatusBlocked])
 }
 
-func RenderList(w io.Writer, tasks []*Task) {
+// Readiness markers for todo tasks in RenderList: ready to start (every
+// dependency done, so "work next" may pick it) or waiting on dependencies.
+const (
+	markerReady   = "▶"
+	markerWaiting = "⏳"
+)
+
+// listStatus renders the 13-column STATUS cell, marking todo tasks ready or
+// waiting. ⏳ takes two terminal columns, so its padding is one shorter.
+func listStatus(t *Task, taskMap map[string]*Task) string {
+	switch {
+	case t.Status != StatusTodo:
+		return fmt.Sprintf("%-13s", t.Status)
+	case t.IsReady(taskMap):
+		return fmt.Sprintf("%-13s", t.Status+" "+markerReady)
+	default:
+		return fmt.Sprintf("%-12s", t.Status+" "+markerWaiting)
+	}
+}
+
+func RenderList(w io.Writer, tasks []*Task, levels PriorityLevels) {
 	if len(tasks) == 0 {
 		fmt.Fprintln(w, "No tasks found. Use 'tenazas work add \"Title\" \"Description\"' to create one.")
 		return
 	}
+	renderListRows(w, tasks, buildTaskMap(tasks), levels)
+}
+
+// renderListRows writes the table and summary for tasks, judging readiness
+// against taskMap, which may hold tasks that aren't listed.
+func renderListRows(w io.Writer, tasks []*Task, taskMap map[string]*Task, levels PriorityLevels) {
 	sortTasksForList(tasks)
-	fmt.Fprintf(w, "%-12s %-13s %-4s %-30s %s\n", "ID", "STATUS", "PRI", "TITLE", "DURATION")
-	fmt.Fprintln(w, strings.Repeat("─", 72))
+	fmt.Fprintf(w, "%-12s %-13s %-12s %-30s %s\n", "ID", "STATUS", "PRI", "TITLE", "DURATION")
+	fmt.Fprintln(w, strings.Repeat("─", 80))
 	for _, t := range tasks {
 		title := truncateTitle(t.Title, 30)
 		dur := FormatDuration(t)
-		fmt.Fprintf(w, "%-12s %-13s %-4d %-30s %s\n", t.ID, t.Status, t.Priority, title, dur)
+		fmt.Fprintf(w, "%-12s %s %-12s %-30s %s\n", t.ID, listStatus(t, taskMap), levels.format(t.Priority), title, dur)
 	}
 	fmt.Fprintln(w)
 	printStatusSummaryTo(w, tasks)
 }
 
-func RenderShow(w io.Writer, task *Task, taskMap map[string]*Task) {
+func RenderShow(w io.Writer, task *Task, taskMap map[string]*Task, levels PriorityLevels) {
 	fmt.Fprintf(w, "═══ %s: %s ═══\n\n", task.ID, task.Title)
 	fmt.Fprintf(w, "  Status:      %s\n", task.Status)
-	fmt.Fprintf(w, "  Priority:    %d\n", task.Priority)
+	fmt.Fprintf(w, "  Priority:    %s\n", levels.format(task.Priority))
+	if task.Weight != 0 {
+		fmt.Fprintf(w, "  Weight:      %d\n", task.Weight)
+	}
 	fmt.Fprintf(w, "  Created:     %s\n", task.CreatedAt.Format(time.RFC3339))
 	fmt.Fprintf(w, "  Updated:     %s\n", task.UpdatedAt.Format(time.RFC3339))
 	fmt.Fprintf(w, "  Duration:    %s\n", FormatDuration(task))
+	if task.TotalActiveSeconds > 0 || task.Status == StatusInProgress {
+		fmt.Fprintf(w, "  Total Time:  %s\n", FormatTotalTime(task, time.Now()))
+	}
 
 	if task.OwnerPID != 0 || task.OwnerInstanceID != "" || task.OwnerSessionID != "" {
 		fmt.Fprintf(w, "\n  Owner:\n")
@@ -131,3 +176,88 @@ func renderDeps(w io.Writer, label string, ids []string, taskMap map[string]*Tas
 		}
 	}
 }
+
+// DepTreeDepth is how many levels of blockers "work show --deps" expands.
+const DepTreeDepth = 3
+
+// RenderDepTree writes the tasks blocking task and, under each one that is
+// not done, the tasks blocking it in turn, down to depth levels. Deeper
+// blockers are summarized as a count, and a task already on the path is
+// marked as a cycle instead of being expanded again.
+func RenderDepTree(w io.Writer, task *Task, taskMap map[string]*Task, depth int) {
+	if len(task.BlockedBy) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n  Dependency Tree:\n")
+	renderDepLevel(w, task.BlockedBy, taskMap, depth, 1, map[string]bool{task.ID: true})
+}
+
+func renderDepLevel(w io.Writer, ids []string, taskMap map[string]*Task, depth, level int, path map[string]bool) {
+	indent := strings.Repeat("  ", level+1)
+	if level > depth {
+		fmt.Fprintf(w, "%s… %d more not shown\n", indent, len(ids))
+		return
+	}
+	for _, id := range ids {
+		dep, ok := taskMap[id]
+		switch {
+		case !ok:
+			fmt.Fprintf(w, "%s%s (unknown)\n", indent, id)
+		case path[id]:
+			fmt.Fprintf(w, "%s%s (%s, cycle)\n", indent, id, dep.Status)
+		default:
+			fmt.Fprintf(w, "%s%s (%s)\n", indent, id, dep.Status)
+			if dep.Status != StatusDone && len(dep.BlockedBy) > 0 {
+				path[id] = true
+				renderDepLevel(w, dep.BlockedBy, taskMap, depth, level+1, path)
+				delete(path, id)
+			}
+		}
+	}
+}
+
+// DepStatus is a dependency of a shown task with its resolved status.
+type DepStatus struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// TaskDetail is the "work show --json" view of a task: the task itself, its
+// content, and its dependencies resolved like RenderShow prints them.
+type TaskDetail struct {
+	*Task
+	Content   string          `json:"content"`
+	Blocks    []DepStatus     `json:"blocks"`
+	BlockedBy []DepStatus     `json:"blocked_by"`
+	Log       []TaskLogDetail `json:"log,omitempty"`
+}
+
+// TaskLogDetail is a log entry in TaskDetail.
+type TaskLogDetail struct {
+	Time    time.Time `json:"time"`
+	Event   string    `json:"event"`
+	Message string    `json:"message"`
+}
+
+// NewTaskDetail resolves task's dependencies against taskMap; unknown ones
+// get the status "unknown".
+func NewTaskDetail(task *Task, taskMap map[string]*Task) *TaskDetail {
+	return &TaskDetail{
+		Task:      task,
+		Content:   task.Content,
+		Blocks:    resolveDeps(task.Blocks, taskMap),
+		BlockedBy: resolveDeps(task.BlockedBy, taskMap),
+	}
+}
+
+func resolveDeps(ids []string, taskMap map[string]*Task) []DepStatus {
+	deps := make([]DepStatus, 0, len(ids))
+	for _, id := range ids {
+		status := "unknown"
+		if dep, ok := taskMap[id]; ok {
+			status = dep.Status
+		}
+		deps = append(deps, DepStatus{ID: id, Status: status})
+	}
+	return deps
+}
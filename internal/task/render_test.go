@@ -2,6 +2,7 @@ package task
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -214,7 +215,7 @@ func TestRenderList(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	RenderList(&buf, tasks)
+	RenderList(&buf, tasks, nil)
 	out := buf.String()
 
 	// Header present.
@@ -248,7 +249,7 @@ func TestRenderList(t *testing.T) {
 
 func TestRenderListEmpty(t *testing.T) {
 	var buf bytes.Buffer
-	RenderList(&buf, []*Task{})
+	RenderList(&buf, []*Task{}, nil)
 	out := buf.String()
 
 	want := "No tasks found. Use 'tenazas work add \"Title\" \"Description\"' to create one.\n"
@@ -270,7 +271,7 @@ func TestRenderListSorting(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	RenderList(&buf, tasks)
+	RenderList(&buf, tasks, nil)
 	out := buf.String()
 
 	// Expected order: in-progress → blocked → todo(pri=5) → todo(pri=0) → done.
@@ -409,7 +410,7 @@ func TestRenderShow(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	RenderShow(&buf, task, taskMap)
+	RenderShow(&buf, task, taskMap, nil)
 	out := buf.String()
 
 	// Title banner.
@@ -479,7 +480,7 @@ func TestRenderShowNoOwner(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	RenderShow(&buf, task, map[string]*Task{"TSK-000001": task})
+	RenderShow(&buf, task, map[string]*Task{"TSK-000001": task}, nil)
 	out := buf.String()
 
 	// Metadata must still be rendered even without owner fields.
@@ -511,7 +512,7 @@ func TestRenderShowUnknownDependency(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	RenderShow(&buf, task, map[string]*Task{"TSK-000002": task})
+	RenderShow(&buf, task, map[string]*Task{"TSK-000002": task}, nil)
 	out := buf.String()
 
 	if !strings.Contains(out, "TSK-999999 (unknown)") {
@@ -531,7 +532,7 @@ func TestRenderShowNoContent(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	RenderShow(&buf, task, map[string]*Task{})
+	RenderShow(&buf, task, map[string]*Task{}, nil)
 	out := buf.String()
 
 	// Metadata must still be rendered even without content.
@@ -548,6 +549,56 @@ func TestRenderShowNoContent(t *testing.T) {
 	}
 }
 
+func TestRenderDepTreeTwoLevels(t *testing.T) {
+	tasks := []*Task{
+		{ID: "TSK-000001", Status: StatusBlocked, BlockedBy: []string{"TSK-000005", "TSK-000002"}},
+		{ID: "TSK-000002", Status: StatusDone, BlockedBy: []string{"TSK-000003"}},
+		{ID: "TSK-000003", Status: StatusTodo},
+		{ID: "TSK-000005", Status: StatusBlocked, BlockedBy: []string{"TSK-000009"}},
+		{ID: "TSK-000009", Status: StatusTodo},
+	}
+	taskMap := buildTaskMap(tasks)
+
+	var buf bytes.Buffer
+	RenderDepTree(&buf, tasks[0], taskMap, DepTreeDepth)
+	want := "\n  Dependency Tree:\n" +
+		"    TSK-000005 (blocked)\n" +
+		"      TSK-000009 (todo)\n" +
+		"    TSK-000002 (done)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("tree =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderDepTreeDepthAndCycles(t *testing.T) {
+	tasks := []*Task{
+		{ID: "TSK-000001", Status: StatusBlocked, BlockedBy: []string{"TSK-000002"}},
+		{ID: "TSK-000002", Status: StatusBlocked, BlockedBy: []string{"TSK-000003"}},
+		{ID: "TSK-000003", Status: StatusBlocked, BlockedBy: []string{"TSK-000001", "TSK-000004"}},
+		{ID: "TSK-000004", Status: StatusTodo},
+	}
+	taskMap := buildTaskMap(tasks)
+
+	var buf bytes.Buffer
+	RenderDepTree(&buf, tasks[0], taskMap, 2)
+	out := buf.String()
+	if !strings.Contains(out, "      TSK-000003 (blocked)\n        … 2 more not shown\n") {
+		t.Errorf("expected blockers past depth 2 to be summarized, got:\n%s", out)
+	}
+
+	buf.Reset()
+	RenderDepTree(&buf, tasks[0], taskMap, DepTreeDepth)
+	if out := buf.String(); !strings.Contains(out, "TSK-000001 (blocked, cycle)") || !strings.Contains(out, "        TSK-000004 (todo)") {
+		t.Errorf("expected the cycle marked and its sibling shown, got:\n%s", out)
+	}
+
+	buf.Reset()
+	RenderDepTree(&buf, tasks[3], taskMap, DepTreeDepth)
+	if buf.Len() != 0 {
+		t.Errorf("task without blockers should render nothing, got %q", buf.String())
+	}
+}
+
 // ---------------------------------------------------------------------------
 // normalizeTaskID (unexported, in work.go)
 // ---------------------------------------------------------------------------
@@ -592,7 +643,7 @@ func TestTitleTruncationInRenderList(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	RenderList(&buf, []*Task{task})
+	RenderList(&buf, []*Task{task}, nil)
 	out := buf.String()
 
 	// Full title must NOT appear.
@@ -633,3 +684,119 @@ func padNum(n int) string {
 	}
 	return result
 }
+
+func TestRenderListMarksReadiness(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []*Task{
+		{ID: "TSK-000001", Title: "Dep Done", Status: StatusDone, CreatedAt: base, UpdatedAt: base},
+		{ID: "TSK-000002", Title: "Dep Open", Status: StatusTodo, CreatedAt: base, UpdatedAt: base},
+		{ID: "TSK-000003", Title: "Ready", Status: StatusTodo, BlockedBy: []string{"TSK-000001"}, CreatedAt: base, UpdatedAt: base},
+		{ID: "TSK-000004", Title: "Waiting", Status: StatusTodo, BlockedBy: []string{"TSK-000001", "TSK-000002"}, CreatedAt: base, UpdatedAt: base},
+	}
+
+	var buf bytes.Buffer
+	RenderList(&buf, tasks, nil)
+
+	rows := map[string]string{}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, "TSK-") {
+			rows[strings.Fields(line)[0]] = line
+		}
+	}
+	for id, want := range map[string]string{"TSK-000002": markerReady, "TSK-000003": markerReady, "TSK-000004": markerWaiting} {
+		if !strings.Contains(rows[id], "todo "+want) {
+			t.Errorf("%s: expected %q, got %q", id, "todo "+want, rows[id])
+		}
+	}
+	if strings.Contains(rows["TSK-000001"], markerReady) || strings.Contains(rows["TSK-000001"], markerWaiting) {
+		t.Errorf("done tasks should not be marked, got %q", rows["TSK-000001"])
+	}
+	if next := SelectNextTask(tasks); next == nil || !strings.Contains(rows[next.ID], markerReady) {
+		t.Errorf("work next should pick a task marked ready, got %v", next)
+	}
+}
+
+func TestRenderFilteredList(t *testing.T) {
+	tasks := []*Task{
+		{ID: "TSK-000001", Title: "API auth", Status: StatusInProgress, Labels: []string{"backend", "auth"}},
+		{ID: "TSK-000002", Title: "API docs", Status: StatusDone, Labels: []string{"backend"}},
+		{ID: "TSK-000003", Title: "Login page", Status: StatusTodo, Labels: []string{"frontend", "auth"}, BlockedBy: []string{"TSK-000002"}},
+		{ID: "TSK-000004", Title: "Rate limits", Status: StatusBlocked, Labels: []string{"Backend"}},
+	}
+	listed := func(out string) string {
+		var ids []string
+		for _, line := range strings.Split(out, "\n") {
+			if strings.HasPrefix(line, "TSK-") {
+				ids = append(ids, strings.Fields(line)[0])
+			}
+		}
+		return strings.Join(ids, ",")
+	}
+
+	var buf bytes.Buffer
+	RenderFilteredList(&buf, tasks, ListFilter{Labels: []string{"backend"}, Statuses: []string{StatusInProgress, StatusBlocked}}, nil)
+	if got := listed(buf.String()); got != "TSK-000001,TSK-000004" {
+		t.Errorf("label and status filter listed %s", got)
+	}
+	if !strings.Contains(buf.String(), "Todo: 0 | In-Progress: 1 | Done: 0 | Blocked: 1") {
+		t.Errorf("summary should count only the filtered tasks:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	RenderFilteredList(&buf, tasks, ListFilter{Labels: []string{"backend", "auth"}}, nil)
+	if got := listed(buf.String()); got != "TSK-000001" {
+		t.Errorf("labels should AND together, listed %s", got)
+	}
+
+	buf.Reset()
+	RenderFilteredList(&buf, tasks, ListFilter{Statuses: []string{StatusTodo}}, nil)
+	if !strings.Contains(buf.String(), "todo "+markerReady) {
+		t.Errorf("a todo task whose done dependency is filtered out should still be ready:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	RenderFilteredList(&buf, tasks, ListFilter{Labels: []string{"mobile"}}, nil)
+	if buf.String() != "No tasks match the given filters.\n" {
+		t.Errorf("no matches = %q", buf.String())
+	}
+}
+
+func TestParseListFilter(t *testing.T) {
+	f, err := parseListFilter([]string{"--label", "Backend", "--status", "todo,blocked", "--label", "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(f.Labels, ",") != "backend,api" || strings.Join(f.Statuses, ",") != "todo,blocked" {
+		t.Errorf("parsed %+v", f)
+	}
+	if _, err := parseListFilter([]string{"--status", "todo,finished"}); err == nil {
+		t.Error("expected an unknown status to be rejected")
+	}
+}
+
+func TestTaskDetailJSON(t *testing.T) {
+	tk := &Task{ID: "TSK-000002", Title: "Wire API", Status: StatusTodo, Content: "## Notes\nbody",
+		BlockedBy: []string{"TSK-000001", "TSK-000009"}}
+	taskMap := map[string]*Task{
+		"TSK-000001": {ID: "TSK-000001", Status: StatusDone},
+		"TSK-000002": tk,
+	}
+	data, err := json.Marshal(NewTaskDetail(tk, taskMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["id"] != "TSK-000002" || got["content"] != "## Notes\nbody" {
+		t.Errorf("expected the task fields and content, got %s", data)
+	}
+	deps, _ := json.Marshal(got["blocked_by"])
+	if string(deps) != `[{"id":"TSK-000001","status":"done"},{"id":"TSK-000009","status":"unknown"}]` {
+		t.Errorf("blocked_by = %s", deps)
+	}
+	if blocks, _ := json.Marshal(got["blocks"]); string(blocks) != "[]" {
+		t.Errorf("blocks = %s, want []", blocks)
+	}
+}
@@ -0,0 +1,35 @@
+package task
+
+// LabelSkills maps a normalized task label to the skill that handles tasks
+// carrying it (the "label_skills" config), e.g. "bug" → "debug". A nil
+// LabelSkills turns label routing off.
+type LabelSkills map[string]string
+
+// NewLabelSkills builds a LabelSkills from the config mapping, normalizing
+// the labels and dropping empty entries.
+func NewLabelSkills(mapping map[string]string) LabelSkills {
+	if len(mapping) == 0 {
+		return nil
+	}
+	m := make(LabelSkills, len(mapping))
+	for label, skill := range mapping {
+		if norm := NormalizeLabel(label); norm != "" && skill != "" {
+			m[norm] = skill
+		}
+	}
+	return m
+}
+
+// SkillFor is the skill to run for t: its own Skill, else the skill its
+// first routed label maps to, else "" so the caller uses its default.
+func (m LabelSkills) SkillFor(t *Task) string {
+	if t.Skill != "" {
+		return t.Skill
+	}
+	for _, label := range t.Labels {
+		if skill := m[NormalizeLabel(label)]; skill != "" {
+			return skill
+		}
+	}
+	return ""
+}
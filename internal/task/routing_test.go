@@ -0,0 +1,41 @@
+package task
+
+import "testing"
+
+func TestSkillForPrecedence(t *testing.T) {
+	mapping := LabelSkills{"bug": "debug", "docs": "writer"}
+	tests := []struct {
+		name string
+		task *Task
+		want string
+	}{
+		{"explicit skill wins", &Task{Skill: "tdd", Labels: []string{"bug"}}, "tdd"},
+		{"label maps to a skill", &Task{Labels: []string{"backend", "bug"}}, "debug"},
+		{"first mapped label wins", &Task{Labels: []string{"docs", "bug"}}, "writer"},
+		{"no mapping falls back", &Task{Labels: []string{"backend"}}, ""},
+		{"no labels falls back", &Task{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mapping.SkillFor(tt.task); got != tt.want {
+				t.Errorf("SkillFor = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLabelSkillsNormalizes(t *testing.T) {
+	mapping := NewLabelSkills(map[string]string{"Bug Fix": "debug", "empty": ""})
+
+	task := &Task{Labels: []string{"bug-fix"}}
+	if got := mapping.SkillFor(task); got != "debug" {
+		t.Errorf("SkillFor = %q, want debug", got)
+	}
+	if _, ok := mapping["empty"]; ok {
+		t.Error("labels mapped to no skill should be dropped")
+	}
+
+	if got := NewLabelSkills(nil).SkillFor(task); got != "" {
+		t.Errorf("SkillFor = %q without a mapping", got)
+	}
+}
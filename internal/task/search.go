@@ -0,0 +1,154 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// snippetContext is how many characters of content "work search" shows on
+// each side of a match.
+const snippetContext = 30
+
+// SearchMatch is a task found by "work search". Snippet is the line of
+// content around the first match, empty when only the title matched; Start
+// and End delimit the match inside Snippet.
+type SearchMatch struct {
+	Task       *Task
+	Snippet    string
+	Start, End int
+}
+
+// SearchTasks returns the tasks whose title or content contains query,
+// optionally only those with the given status. The match ignores case
+// unless caseSensitive is set.
+func SearchTasks(tasks []*Task, query, status string, caseSensitive bool) []SearchMatch {
+	index := strings.Index
+	if !caseSensitive {
+		index = indexFold
+	}
+
+	var matches []SearchMatch
+	for _, t := range tasks {
+		if status != "" && t.Status != status {
+			continue
+		}
+		m := SearchMatch{Task: t}
+		inTitle := index(t.Title, query) >= 0
+		if i := index(t.Content, query); i >= 0 {
+			m.Snippet, m.Start, m.End = snippet(t.Content, i, len(query))
+		} else if !inTitle {
+			continue
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// indexFold is strings.Index ignoring case.
+func indexFold(s, substr string) int {
+	for i := range s {
+		if len(s)-i < len(substr) {
+			break
+		}
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// snippet cuts the line of content holding the match at [i, i+n) down to
+// snippetContext characters on either side, and returns the match bounds in
+// the result.
+func snippet(content string, i, n int) (string, int, int) {
+	lineStart := strings.LastIndexByte(content[:i], '\n') + 1
+	lineEnd := len(content)
+	if j := strings.IndexByte(content[i+n:], '\n'); j >= 0 {
+		lineEnd = i + n + j
+	}
+	before := []rune(content[lineStart:i])
+	match := content[i : i+n]
+	after := []rune(content[i+n : lineEnd])
+
+	var sb strings.Builder
+	if len(before) > snippetContext {
+		sb.WriteString("…")
+		before = before[len(before)-snippetContext:]
+	}
+	sb.WriteString(strings.TrimLeft(string(before), " \t"))
+	start := sb.Len()
+	sb.WriteString(match)
+	end := sb.Len()
+	if len(after) > snippetContext {
+		sb.WriteString(string(after[:snippetContext]) + "…")
+	} else {
+		sb.WriteString(strings.TrimRight(string(after), " \t\r"))
+	}
+	return sb.String(), start, end
+}
+
+// RenderSearch prints the matching tasks as RenderList does, judging their
+// readiness against all (the tasks searched), followed by the content
+// snippets. color highlights the match with ANSI escapes; otherwise it is
+// wrapped in ">>" and "<<".
+func RenderSearch(w io.Writer, matches []SearchMatch, all []*Task, color bool, levels PriorityLevels) {
+	if len(matches) == 0 {
+		fmt.Fprintln(w, "No matching tasks.")
+		return
+	}
+	tasks := make([]*Task, len(matches))
+	for i, m := range matches {
+		tasks[i] = m.Task
+	}
+	renderListRows(w, tasks, buildTaskMap(all), levels)
+
+	open, close := ">>", "<<"
+	if color {
+		open, close = "\x1b[1;33m", "\x1b[0m"
+	}
+	header := false
+	for _, m := range matches {
+		if m.Snippet == "" {
+			continue
+		}
+		if !header {
+			fmt.Fprintln(w, "\nContent matches:")
+			header = true
+		}
+		fmt.Fprintf(w, "  %-12s %s%s%s%s%s\n", m.Task.ID, m.Snippet[:m.Start], open, m.Snippet[m.Start:m.End], close, m.Snippet[m.End:])
+	}
+}
+
+func handleWorkSearch(tasksDir string, levels PriorityLevels, args []string) {
+	const usage = "Usage: tenazas work search <query> [--status <status>] [--case-sensitive]"
+	caseSensitive, args := extractBoolFlag(args, "--case-sensitive")
+	var status string
+	var terms []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--status" {
+			status = nextFlagValue(args, &i, "--status")
+			if _, ok := allowedTransitions[status]; !ok {
+				fmt.Fprintf(os.Stderr, "Error: unknown status: %s\n", status)
+				os.Exit(1)
+			}
+			continue
+		}
+		terms = append(terms, args[i])
+	}
+	query := strings.Join(terms, " ")
+	if query == "" {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	tasks := listTasksOrDie(tasksDir)
+	RenderSearch(os.Stdout, SearchTasks(tasks, query, status, caseSensitive), tasks, isTerminal(os.Stdout), levels)
+}
+
+// isTerminal reports whether f is a character device, i.e. worth coloring.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,88 @@
+package task
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSearchTasks(t *testing.T) {
+	_, tasksDir, cleanup := setupTasksDir(t)
+	defer cleanup()
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000001", Title: "Fix Login Timeout", Status: StatusTodo})
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000002", Title: "Session cleanup", Status: StatusTodo,
+		Content: "Notes\nUsers hit a login timeout after 30s on slow networks.\nMore notes"})
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000003", Title: "Old timeout work", Status: StatusDone})
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000004", Title: "Unrelated", Status: StatusTodo})
+	if _, err := ForceArchive(tasksDir); err != nil {
+		t.Fatal(err)
+	}
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000005", Title: "Done TIMEOUT", Status: StatusDone})
+
+	tasks, err := ListTasks(tasksDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := func(matches []SearchMatch) string {
+		var out []string
+		for _, m := range matches {
+			out = append(out, m.Task.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := ids(SearchTasks(tasks, "login timeout", "", false)); got != "TSK-000001,TSK-000002" {
+		t.Errorf("title and content search = %s", got)
+	}
+	if got := ids(SearchTasks(tasks, "timeout", "", false)); got != "TSK-000001,TSK-000002,TSK-000005" {
+		t.Errorf("archived tasks should be skipped, got %s", got)
+	}
+	if got := ids(SearchTasks(tasks, "timeout", StatusTodo, false)); got != "TSK-000001,TSK-000002" {
+		t.Errorf("--status todo = %s", got)
+	}
+	if got := ids(SearchTasks(tasks, "Timeout", "", true)); got != "TSK-000001" {
+		t.Errorf("case-sensitive search = %s", got)
+	}
+}
+
+func TestRenderSearchHighlightsSnippet(t *testing.T) {
+	content := "Intro\n" + strings.Repeat("x", 50) + " the login timeout fires " + strings.Repeat("y", 50) + "\nOutro"
+	tasks := []*Task{
+		{ID: "TSK-000001", Title: "Login timeout", Status: StatusTodo},
+		{ID: "TSK-000002", Title: "Cleanup", Status: StatusTodo, Content: content},
+	}
+	matches := SearchTasks(tasks, "LOGIN TIMEOUT", "", false)
+
+	var buf bytes.Buffer
+	RenderSearch(&buf, matches, tasks, false, nil)
+	out := buf.String()
+	if !strings.Contains(out, "TSK-000001") || !strings.Contains(out, "Content matches:") {
+		t.Fatalf("expected the task table and a snippet section, got:\n%s", out)
+	}
+	var line string
+	for _, l := range strings.Split(out, "\n") {
+		if strings.Contains(l, ">>") {
+			line = l
+		}
+	}
+	if !strings.Contains(line, "TSK-000002") || !strings.Contains(line, ">>login timeout<<") {
+		t.Errorf("snippet line = %q, want the match highlighted", line)
+	}
+	if strings.Contains(line, "Intro") || strings.Contains(line, "Outro") || !strings.Contains(line, "…") {
+		t.Errorf("snippet should be one trimmed line, got %q", line)
+	}
+}
+
+func TestRenderSearchReadinessUsesAllTasks(t *testing.T) {
+	tasks := []*Task{
+		{ID: "TSK-000001", Title: "Schema migration", Status: StatusDone},
+		{ID: "TSK-000002", Title: "Login timeout", Status: StatusTodo, BlockedBy: []string{"TSK-000001"}},
+	}
+	matches := SearchTasks(tasks, "login", "", false)
+
+	var buf bytes.Buffer
+	RenderSearch(&buf, matches, tasks, false, nil)
+	if out := buf.String(); strings.Contains(out, "⏳") {
+		t.Errorf("a task whose blocker is done should not show as waiting, got:\n%s", out)
+	}
+}
@@ -0,0 +1,75 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestGetNextTaskIDConcurrentCallsAreUnique(t *testing.T) {
+	dir := t.TempDir()
+	const workers, perWorker = 16, 25
+
+	var wg sync.WaitGroup
+	results := make([][]string, workers)
+	errs := make(chan error, workers*perWorker)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				id, err := GetNextTaskID(dir)
+				if err != nil {
+					errs <- err
+					return
+				}
+				results[w] = append(results[w], id)
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("GetNextTaskID: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for w, ids := range results {
+		for i, id := range ids {
+			if seen[id] {
+				t.Fatalf("duplicate ID %s", id)
+			}
+			seen[id] = true
+			// Each caller sees the sequence only move forward.
+			if i > 0 && id <= ids[i-1] {
+				t.Errorf("worker %d: %s did not increase after %s", w, id, ids[i-1])
+			}
+		}
+	}
+	total := workers * perWorker
+	for n := 1; n <= total; n++ {
+		if id := fmt.Sprintf("TSK-%06d", n); !seen[id] {
+			t.Errorf("expected %s to be handed out", id)
+		}
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, ".task_sequence"))
+	if string(data) != fmt.Sprint(total) {
+		t.Errorf("sequence file = %q, want %d", data, total)
+	}
+}
+
+func TestGetNextTaskIDRejectsCorruptSequence(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".task_sequence"), []byte("garbage"), 0644)
+
+	if id, err := GetNextTaskID(dir); err == nil {
+		t.Fatalf("expected an error for a corrupt sequence file, got %s", id)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ".task_sequence"))
+	if string(data) != "garbage" {
+		t.Errorf("corrupt sequence file was overwritten: %q", data)
+	}
+}
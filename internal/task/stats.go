@@ -0,0 +1,135 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// maxHotspots caps how many failure hotspots "work stats" lists.
+const maxHotspots = 5
+
+// Stats aggregates a project's tasks for "work stats".
+type Stats struct {
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"by_status"`
+	Blocked  int            `json:"blocked"`
+	// Durations cover done tasks with both start and completion times.
+	Timed             int     `json:"timed"`
+	AvgDurationSec    float64 `json:"avg_duration_sec"`
+	MedianDurationSec float64 `json:"median_duration_sec"`
+	// LongestWaiting is the oldest todo task, nil when there is none.
+	LongestWaiting *StatTask `json:"longest_waiting,omitempty"`
+	// Hotspots are the tasks that failed most, highest FailureCount first.
+	Hotspots []StatTask `json:"hotspots,omitempty"`
+}
+
+// StatTask identifies a task in Stats.
+type StatTask struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	CreatedAt    time.Time `json:"created_at"`
+	FailureCount int       `json:"failure_count,omitempty"`
+}
+
+func statTask(t *Task) StatTask {
+	return StatTask{ID: t.ID, Title: t.Title, CreatedAt: t.CreatedAt, FailureCount: t.FailureCount}
+}
+
+func computeStats(tasks []*Task) Stats {
+	s := Stats{
+		Total:    len(tasks),
+		ByStatus: map[string]int{StatusTodo: 0, StatusInProgress: 0, StatusDone: 0, StatusBlocked: 0},
+	}
+	var durations []time.Duration
+	var hot []*Task
+	for _, t := range tasks {
+		s.ByStatus[t.Status]++
+		if t.Status == StatusDone && t.StartedAt != nil && t.CompletedAt != nil {
+			durations = append(durations, t.CompletedAt.Sub(*t.StartedAt))
+		}
+		if t.Status == StatusTodo && (s.LongestWaiting == nil || t.CreatedAt.Before(s.LongestWaiting.CreatedAt)) {
+			st := statTask(t)
+			s.LongestWaiting = &st
+		}
+		if t.FailureCount > 0 {
+			hot = append(hot, t)
+		}
+	}
+	s.Blocked = s.ByStatus[StatusBlocked]
+
+	if len(durations) > 0 {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		var sum time.Duration
+		for _, d := range durations {
+			sum += d
+		}
+		s.Timed = len(durations)
+		s.AvgDurationSec = sum.Seconds() / float64(len(durations))
+		mid := len(durations) / 2
+		if len(durations)%2 == 1 {
+			s.MedianDurationSec = durations[mid].Seconds()
+		} else {
+			s.MedianDurationSec = (durations[mid-1] + durations[mid]).Seconds() / 2
+		}
+	}
+
+	sort.Slice(hot, func(i, j int) bool {
+		if hot[i].FailureCount != hot[j].FailureCount {
+			return hot[i].FailureCount > hot[j].FailureCount
+		}
+		return hot[i].ID < hot[j].ID
+	})
+	for i, t := range hot {
+		if i == maxHotspots {
+			break
+		}
+		s.Hotspots = append(s.Hotspots, statTask(t))
+	}
+	return s
+}
+
+// RenderStats writes the "work stats" report.
+func RenderStats(w io.Writer, tasks []*Task, now time.Time) {
+	s := computeStats(tasks)
+	fmt.Fprintf(w, "Tasks: %d\n", s.Total)
+	printStatusSummaryTo(w, tasks)
+	if s.Timed > 0 {
+		fmt.Fprintf(w, "Completion time: avg %s, median %s (%d timed)\n",
+			formatHumanDuration(secondsDuration(s.AvgDurationSec)), formatHumanDuration(secondsDuration(s.MedianDurationSec)), s.Timed)
+	} else {
+		fmt.Fprintln(w, "Completion time: —")
+	}
+	fmt.Fprintf(w, "Blocked: %d\n", s.Blocked)
+	if lw := s.LongestWaiting; lw != nil {
+		fmt.Fprintf(w, "Longest waiting: %s %s (%s)\n", lw.ID, lw.Title, formatHumanDuration(now.Sub(lw.CreatedAt)))
+	}
+	if len(s.Hotspots) > 0 {
+		fmt.Fprintln(w, "Failure hotspots:")
+		for _, h := range s.Hotspots {
+			fmt.Fprintf(w, "  %-12s %3d  %s\n", h.ID, h.FailureCount, h.Title)
+		}
+	}
+}
+
+func secondsDuration(sec float64) time.Duration {
+	return time.Duration(sec * float64(time.Second))
+}
+
+func handleWorkStats(tasksDir string, args []string) {
+	asJSON, _ := extractBoolFlag(args, "--json")
+	tasks := listTasksOrDie(tasksDir)
+	if !asJSON {
+		RenderStats(os.Stdout, tasks, time.Now())
+		return
+	}
+	data, err := json.MarshalIndent(computeStats(tasks), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
+}
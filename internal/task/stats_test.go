@@ -0,0 +1,84 @@
+package task
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeStats(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	done := func(id string, d time.Duration) *Task {
+		start := base
+		end := base.Add(d)
+		return &Task{ID: id, Status: StatusDone, CreatedAt: base, StartedAt: &start, CompletedAt: &end}
+	}
+	tasks := []*Task{
+		done("TSK-000001", 1*time.Minute),
+		done("TSK-000002", 3*time.Minute),
+		done("TSK-000003", 8*time.Minute),
+		{ID: "TSK-000004", Status: StatusDone, CreatedAt: base}, // untimed
+		{ID: "TSK-000005", Title: "newer", Status: StatusTodo, CreatedAt: base.Add(time.Hour)},
+		{ID: "TSK-000006", Title: "older", Status: StatusTodo, CreatedAt: base.Add(time.Minute)},
+		{ID: "TSK-000007", Status: StatusBlocked, CreatedAt: base, FailureCount: 4},
+		{ID: "TSK-000008", Status: StatusInProgress, CreatedAt: base, FailureCount: 1},
+	}
+
+	s := computeStats(tasks)
+	if s.Total != 8 {
+		t.Errorf("Total = %d, want 8", s.Total)
+	}
+	want := map[string]int{StatusTodo: 2, StatusInProgress: 1, StatusDone: 4, StatusBlocked: 1}
+	for status, n := range want {
+		if s.ByStatus[status] != n {
+			t.Errorf("ByStatus[%s] = %d, want %d", status, s.ByStatus[status], n)
+		}
+	}
+	if s.Blocked != 1 {
+		t.Errorf("Blocked = %d, want 1", s.Blocked)
+	}
+	if s.Timed != 3 {
+		t.Errorf("Timed = %d, want 3", s.Timed)
+	}
+	if s.AvgDurationSec != 240 {
+		t.Errorf("AvgDurationSec = %v, want 240", s.AvgDurationSec)
+	}
+	if s.MedianDurationSec != 180 {
+		t.Errorf("MedianDurationSec = %v, want 180", s.MedianDurationSec)
+	}
+	if s.LongestWaiting == nil || s.LongestWaiting.ID != "TSK-000006" {
+		t.Errorf("LongestWaiting = %+v, want TSK-000006", s.LongestWaiting)
+	}
+	if len(s.Hotspots) != 2 || s.Hotspots[0].ID != "TSK-000007" || s.Hotspots[1].ID != "TSK-000008" {
+		t.Errorf("Hotspots = %+v, want TSK-000007 then TSK-000008", s.Hotspots)
+	}
+}
+
+func TestComputeStatsEvenMedian(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var tasks []*Task
+	for _, d := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 10 * time.Minute} {
+		end := start.Add(d)
+		tasks = append(tasks, &Task{Status: StatusDone, StartedAt: &start, CompletedAt: &end})
+	}
+	s := computeStats(tasks)
+	if s.MedianDurationSec != 180 {
+		t.Errorf("MedianDurationSec = %v, want 180", s.MedianDurationSec)
+	}
+	if s.AvgDurationSec != 255 {
+		t.Errorf("AvgDurationSec = %v, want 255", s.AvgDurationSec)
+	}
+}
+
+func TestRenderStatsEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	RenderStats(&buf, nil, time.Now())
+	out := buf.String()
+	if !strings.Contains(out, "Tasks: 0") || !strings.Contains(out, "Completion time: —") {
+		t.Errorf("unexpected report:\n%s", out)
+	}
+	if strings.Contains(out, "Longest waiting") || strings.Contains(out, "hotspots") {
+		t.Errorf("empty project should not list waiting tasks or hotspots:\n%s", out)
+	}
+}
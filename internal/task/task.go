@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -28,6 +29,7 @@ type Task struct {
 	Title           string     `json:"title"`
 	Status          string     `json:"status"`
 	Priority        int        `json:"priority"`
+	Weight          int        `json:"weight,omitempty"` // pins ready tasks ahead of higher priorities; higher runs first
 	FailureCount    int        `json:"failure_count"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
@@ -38,13 +40,18 @@ type Task struct {
 	OwnerSessionID  string     `json:"owner_session_id,omitempty"`
 	StartedAt       *time.Time `json:"started_at,omitempty"`
 	CompletedAt     *time.Time `json:"completed_at,omitempty"`
-	Skill           string     `json:"skill,omitempty"`
-	Labels          []string   `json:"labels,omitempty"`
-	Content         string     `json:"-"`
-	FilePath        string     `json:"-"`
+	// TotalActiveSeconds sums the finished in-progress spans, so time from
+	// before a reopen is kept; ResumedAt starts the current span.
+	TotalActiveSeconds int64      `json:"total_active_seconds,omitempty"`
+	ResumedAt          *time.Time `json:"resumed_at,omitempty"`
+	Skill              string     `json:"skill,omitempty"`
+	Labels             []string   `json:"labels,omitempty"`
+	Content            string     `json:"-"`
+	FilePath           string     `json:"-"`
 }
 
-// NormalizeTaskID converts user input into canonical TSK-XXXXXX format.
+// NormalizeTaskID converts user input into canonical TSK-XXXXXX format at
+// DefaultIDWidth; NormalizeTaskIDIn honors a project's configured width.
 // Examples: "6" → "TSK-000006", "tsk-6" → "TSK-6", "TSK-000006" → "TSK-000006"
 func NormalizeTaskID(input string) string {
 	return normalizeTaskID(input)
@@ -57,6 +64,18 @@ func (t *Task) ClearOwnership() {
 	t.OwnerSessionID = ""
 }
 
+// Reset puts t back to todo as if it had never been started: no owner, no
+// failures, no start or completion time and no tracked time.
+func (t *Task) Reset() {
+	t.Status = StatusTodo
+	t.ClearOwnership()
+	t.FailureCount = 0
+	t.StartedAt = nil
+	t.CompletedAt = nil
+	t.TotalActiveSeconds = 0
+	t.ResumedAt = nil
+}
+
 // IsReady checks if a task is 'todo' and all its dependencies are 'done'.
 func (t *Task) IsReady(taskMap map[string]*Task) bool {
 	if t.Status != StatusTodo {
@@ -215,6 +234,11 @@ func AddDependency(tasksDir string, task *Task, depID string) error {
 	if err != nil {
 		return fmt.Errorf("dependency %s not found", depID)
 	}
+	// Either half of the opposite edge is enough: a task file edited by hand
+	// may carry only one side.
+	if sliceContains(dep.BlockedBy, task.ID) || sliceContains(task.Blocks, depID) {
+		return fmt.Errorf("reverse dependency already exists: %s is blocked by %s, so adding %s → %s would create a cycle", depID, task.ID, task.ID, depID)
+	}
 
 	// Tentatively add edges; rollback undoes both on failure.
 	task.BlockedBy = append(task.BlockedBy, depID)
@@ -299,7 +323,8 @@ func GetNextTaskID(tasksDir string) (string, error) {
 		return "", err
 	}
 
-	f, err := os.OpenFile(filepath.Join(tasksDir, ".task_sequence"), os.O_RDWR|os.O_CREATE, 0644)
+	path := filepath.Join(tasksDir, ".task_sequence")
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
 	if err != nil {
 		return "", err
 	}
@@ -310,15 +335,53 @@ func GetNextTaskID(tasksDir string) (string, error) {
 	}
 	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
 
-	var seq int
-	fmt.Fscanf(f, "%d", &seq)
+	seq, err := readSequence(f)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
 	seq++
 
-	f.Seek(0, 0)
-	f.Truncate(0)
-	fmt.Fprintf(f, "%d", seq)
+	// Sync before releasing the lock so the next holder (or a restart after
+	// a crash) never reads a stale value and hands out the same ID twice.
+	if _, err := f.Seek(0, 0); err != nil {
+		return "", err
+	}
+	if err := f.Truncate(0); err != nil {
+		return "", err
+	}
+	if _, err := fmt.Fprintf(f, "%d", seq); err != nil {
+		return "", err
+	}
+	if err := f.Sync(); err != nil {
+		return "", err
+	}
+	if got, err := readSequence(f); err != nil || got != seq {
+		return "", fmt.Errorf("%s: wrote %d but read back %d (%v)", path, seq, got, err)
+	}
+
+	return formatTaskID(seq, IDWidth(tasksDir)), nil
+}
 
-	return fmt.Sprintf("TSK-%06d", seq), nil
+// readSequence reads the counter from the start of the sequence file. An
+// empty file is 0; anything else that isn't a number is an error rather than
+// a silent restart from 1.
+func readSequence(f *os.File) (int, error) {
+	if _, err := f.Seek(0, 0); err != nil {
+		return 0, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return 0, err
+	}
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return 0, nil
+	}
+	seq, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("corrupt task sequence %q", text)
+	}
+	return seq, nil
 }
 
 func buildTaskMap(tasks []*Task) map[string]*Task {
@@ -329,7 +392,9 @@ func buildTaskMap(tasks []*Task) map[string]*Task {
 	return m
 }
 
-func SelectNextTask(tasks []*Task) *Task {
+// ReadyTasks returns the tasks that can start now, in the order work next
+// would pick them: heaviest first, then highest priority, then oldest.
+func ReadyTasks(tasks []*Task) []*Task {
 	taskMap := buildTaskMap(tasks)
 
 	var ready []*Task
@@ -339,17 +404,23 @@ func SelectNextTask(tasks []*Task) *Task {
 		}
 	}
 
-	if len(ready) == 0 {
-		return nil
-	}
-
-	sort.Slice(ready, func(i, j int) bool {
+	sort.SliceStable(ready, func(i, j int) bool {
+		if ready[i].Weight != ready[j].Weight {
+			return ready[i].Weight > ready[j].Weight
+		}
 		if ready[i].Priority != ready[j].Priority {
 			return ready[i].Priority > ready[j].Priority
 		}
 		return ready[i].CreatedAt.Before(ready[j].CreatedAt)
 	})
+	return ready
+}
 
+func SelectNextTask(tasks []*Task) *Task {
+	ready := ReadyTasks(tasks)
+	if len(ready) == 0 {
+		return nil
+	}
 	return ready[0]
 }
 
@@ -502,14 +573,34 @@ func ListTasks(dir string) ([]*Task, error) {
 		}
 		return nil
 	})
+	// File names sort by number only while every ID has the same width.
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, aok := taskNumber(tasks[i].ID)
+		b, bok := taskNumber(tasks[j].ID)
+		if aok != bok {
+			return aok
+		}
+		return a < b
+	})
 	return tasks, err
 }
 
+// FindTask loads task id from dir. A TSK-<n> ID also matches a task stored
+// with n at another width.
 func FindTask(dir string, id string) (*Task, error) {
-	path := filepath.Join(dir, id+".md")
-	t, err := ReadTask(path)
+	t, err := ReadTask(filepath.Join(dir, id+".md"))
 	if err != nil {
-		return nil, fmt.Errorf("task %s not found", id)
+		n, ok := taskNumber(id)
+		if !ok {
+			return nil, fmt.Errorf("task %s not found", id)
+		}
+		existing := findIDByNumber(dir, n)
+		if existing == "" || existing == id {
+			return nil, fmt.Errorf("task %s not found", id)
+		}
+		if t, err = ReadTask(filepath.Join(dir, existing+".md")); err != nil {
+			return nil, fmt.Errorf("task %s not found", id)
+		}
 	}
 	return t, nil
 }
@@ -542,7 +633,7 @@ func MigrateTasks(tasksDir string) error {
 		}
 
 		idNum, _ := strconv.Atoi(m[1])
-		t.ID = fmt.Sprintf("TSK-%06d", idNum)
+		t.ID = formatTaskID(idNum, IDWidth(tasksDir))
 		destPath := filepath.Join(filepath.Dir(f), t.ID+".md")
 
 		if _, err := os.Stat(destPath); err == nil {
@@ -130,6 +130,28 @@ func TestSelectNextTaskWithPriority(t *testing.T) {
 	}
 }
 
+func TestSelectNextTaskWithWeight(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []*Task{
+		{ID: "TSK-000001", Status: StatusTodo, Priority: 5, CreatedAt: base},
+		{ID: "TSK-000002", Status: StatusTodo, Priority: 0, Weight: 1, CreatedAt: base.Add(2 * time.Second)},
+		{ID: "TSK-000003", Status: StatusTodo, Priority: 0, Weight: 1, CreatedAt: base.Add(1 * time.Second)},
+	}
+
+	// Weight outranks priority; equal weights fall back to age.
+	if next := SelectNextTask(tasks); next == nil || next.ID != "TSK-000003" {
+		t.Errorf("Expected the older weighted TSK-000003 first, got %v", next)
+	}
+	tasks[1].Weight = 2
+	if next := SelectNextTask(tasks); next == nil || next.ID != "TSK-000002" {
+		t.Errorf("Expected TSK-000002 (weight 2) first, got %v", next)
+	}
+	tasks[1].Status, tasks[2].Status = StatusDone, StatusDone
+	if next := SelectNextTask(tasks); next == nil || next.ID != "TSK-000001" {
+		t.Errorf("Expected the unweighted TSK-000001 last, got %v", next)
+	}
+}
+
 func TestSelectNextTaskPriorityFIFO(t *testing.T) {
 	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
 	tasks := []*Task{
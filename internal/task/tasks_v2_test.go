@@ -126,7 +126,7 @@ func TestWorkAddWithPriority(t *testing.T) {
 	os.MkdirAll(tasksDir, 0755)
 
 	// Test with --priority flag
-	HandleWorkCommand(tmpStorage, []string{"add", "--priority", "3", "Urgent Fix", "Fix the production bug"})
+	HandleWorkCommand(tmpStorage, Settings{}, []string{"add", "--priority", "3", "Urgent Fix", "Fix the production bug"})
 
 	tasks, err := ListTasks(tasksDir)
 	if err != nil {
@@ -143,7 +143,7 @@ func TestWorkAddWithPriority(t *testing.T) {
 	}
 
 	// Test without --priority flag (default 0)
-	HandleWorkCommand(tmpStorage, []string{"add", "Normal Task", "Regular work"})
+	HandleWorkCommand(tmpStorage, Settings{}, []string{"add", "Normal Task", "Regular work"})
 
 	tasks, err = ListTasks(tasksDir)
 	if err != nil {
@@ -189,7 +189,7 @@ func TestWorkNextStrictness(t *testing.T) {
 	os.WriteFile(filepath.Join(tasksDir, "TSK-000002.md"), []byte(content2), 0644)
 
 	// Call HandleWorkCommand("next")
-	HandleWorkCommand(tmpStorage, []string{"next"})
+	HandleWorkCommand(tmpStorage, Settings{}, []string{"next"})
 
 	// Verify that the second task is STILL 'todo'
 	updatedTodo, _ := ReadTask(filepath.Join(tasksDir, "TSK-000002.md"))
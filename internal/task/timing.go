@@ -0,0 +1,39 @@
+package task
+
+import "time"
+
+// SetStatus moves t to status at now and keeps its time tracking: leaving
+// in-progress adds the span since ResumedAt to TotalActiveSeconds, entering
+// it starts a new span.
+func (t *Task) SetStatus(status string, now time.Time) {
+	if t.Status == StatusInProgress && status != StatusInProgress {
+		t.TotalActiveSeconds += int64(t.currentSpan(now) / time.Second)
+		t.ResumedAt = nil
+	}
+	if status == StatusInProgress && t.Status != StatusInProgress {
+		t.ResumedAt = &now
+	}
+	t.Status = status
+}
+
+// currentSpan is how long t has been in progress since it last entered that
+// status. Tasks started before ResumedAt was recorded count from StartedAt.
+func (t *Task) currentSpan(now time.Time) time.Duration {
+	if t.Status != StatusInProgress {
+		return 0
+	}
+	since := t.ResumedAt
+	if since == nil {
+		since = t.StartedAt
+	}
+	if since == nil || now.Before(*since) {
+		return 0
+	}
+	return now.Sub(*since)
+}
+
+// ActiveTime is the total time t has spent in progress at now, across
+// reopen cycles and including a span still running.
+func (t *Task) ActiveTime(now time.Time) time.Duration {
+	return time.Duration(t.TotalActiveSeconds)*time.Second + t.currentSpan(now)
+}
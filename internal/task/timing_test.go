@@ -0,0 +1,65 @@
+package task
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestActiveTimeAccumulatesAcrossReopen(t *testing.T) {
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	tk := &Task{ID: "TSK-000001", Status: StatusTodo}
+
+	tk.SetStatus(StatusInProgress, base)
+	tk.SetStatus(StatusDone, base.Add(30*time.Minute))
+	if tk.TotalActiveSeconds != 1800 || tk.ResumedAt != nil {
+		t.Fatalf("after the first span: total %d, resumed %v", tk.TotalActiveSeconds, tk.ResumedAt)
+	}
+
+	if err := tk.Reopen("flaky test", base.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	tk.SetStatus(StatusInProgress, base.Add(2*time.Hour))
+	if got := tk.ActiveTime(base.Add(2*time.Hour + 10*time.Minute)); got != 40*time.Minute {
+		t.Errorf("ActiveTime mid-span = %s, want 40m (idle time between cycles excluded)", got)
+	}
+	tk.SetStatus(StatusBlocked, base.Add(2*time.Hour+15*time.Minute))
+	if tk.TotalActiveSeconds != 45*60 {
+		t.Errorf("TotalActiveSeconds = %d, want 2700", tk.TotalActiveSeconds)
+	}
+
+	tk.Reset()
+	if tk.TotalActiveSeconds != 0 || tk.ActiveTime(base.Add(3*time.Hour)) != 0 {
+		t.Errorf("Reset should zero the tracked time, got %d", tk.TotalActiveSeconds)
+	}
+}
+
+func TestActiveTimeLegacyInProgress(t *testing.T) {
+	started := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	tk := &Task{Status: StatusInProgress, StartedAt: &started}
+	if got := tk.ActiveTime(started.Add(5 * time.Minute)); got != 5*time.Minute {
+		t.Errorf("a task started before ResumedAt existed should count from StartedAt, got %s", got)
+	}
+}
+
+func TestWorkEditTracksActiveTime(t *testing.T) {
+	_, tasksDir, cleanup := setupTasksDir(t)
+	defer cleanup()
+	resumed := time.Now().Add(-90 * time.Second).Truncate(time.Second)
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000001", Title: "Tracked", Status: StatusInProgress,
+		StartedAt: &resumed, ResumedAt: &resumed, TotalActiveSeconds: 600})
+
+	handleWorkEdit(tasksDir, nil, []string{"1", "--status", "todo"})
+
+	tk := readTestTask(t, tasksDir, "TSK-000001")
+	if tk.TotalActiveSeconds < 690 || tk.TotalActiveSeconds > 700 || tk.ResumedAt != nil {
+		t.Errorf("leaving in-progress should add the span: total %d, resumed %v", tk.TotalActiveSeconds, tk.ResumedAt)
+	}
+
+	var buf bytes.Buffer
+	RenderShow(&buf, tk, nil, nil)
+	if !strings.Contains(buf.String(), "Total Time:  11m") {
+		t.Errorf("RenderShow should show the cumulative time, got:\n%s", buf.String())
+	}
+}
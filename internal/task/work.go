@@ -1,7 +1,9 @@
 package task
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -13,9 +15,17 @@ import (
 
 const taskIDPrefix = "TSK-"
 
-func HandleWorkCommand(storageDir string, args []string) {
+// Settings carries the configured task behavior: priority labels (the
+// "priority_labels" config) and label routing (the "label_skills" config).
+// The zero value uses the default labels and routes by Skill only.
+type Settings struct {
+	Priorities  PriorityLevels
+	LabelSkills LabelSkills
+}
+
+func HandleWorkCommand(storageDir string, settings Settings, args []string) {
 	if len(args) < 1 {
-		fmt.Println("Usage: tenazas work [init|add|next|complete|status|list|show|archive]")
+		fmt.Println("Usage: tenazas work [init|add|next|complete|status|stats|list|search|show|log|graph|reopen|doctor|archive|move]")
 		os.Exit(1)
 	}
 
@@ -24,38 +34,68 @@ func HandleWorkCommand(storageDir string, args []string) {
 
 	switch cmd {
 	case "init":
-		handleWorkInit(tasksDir)
+		handleWorkInit(tasksDir, args[1:])
 	case "add":
-		handleWorkAdd(tasksDir, args[1:])
+		handleWorkAdd(tasksDir, settings.Priorities, args[1:])
 	case "next":
-		handleWorkNext(tasksDir)
+		handleWorkNext(tasksDir, settings.LabelSkills)
 	case "complete":
 		handleWorkComplete(tasksDir)
 	case "status":
 		handleWorkStatus(tasksDir)
+	case "stats":
+		handleWorkStats(tasksDir, args[1:])
 	case "list":
-		handleWorkList(tasksDir)
+		handleWorkList(tasksDir, settings.Priorities, args[1:])
+	case "search":
+		handleWorkSearch(tasksDir, settings.Priorities, args[1:])
 	case "show":
-		handleWorkShow(tasksDir, args[1:])
+		handleWorkShow(tasksDir, settings.Priorities, args[1:])
+	case "log":
+		handleWorkLog(tasksDir, args[1:])
+	case "graph":
+		handleWorkGraph(tasksDir, args[1:])
 	case "edit":
-		handleWorkEdit(tasksDir, args[1:])
+		handleWorkEdit(tasksDir, settings.Priorities, args[1:])
 	case "delete":
 		handleWorkDelete(tasksDir, args[1:])
 	case "dep":
 		handleWorkDep(tasksDir, args[1:])
 	case "unblock":
 		handleWorkUnblock(tasksDir, args[1:])
+	case "reopen":
+		handleWorkReopen(tasksDir, args[1:])
 	case "reset":
 		handleWorkReset(tasksDir, args[1:])
+	case "doctor":
+		handleWorkDoctor(tasksDir, args[1:])
 	case "archive":
 		handleWorkArchive(tasksDir, args[1:])
+	case "move":
+		handleWorkMove(storageDir, tasksDir, args[1:])
 	default:
 		fmt.Printf("Unknown command: %s\n", cmd)
 		os.Exit(1)
 	}
 }
 
-func handleWorkInit(tasksDir string) {
+func handleWorkInit(tasksDir string, args []string) {
+	if len(args) > 0 {
+		if len(args) != 2 || args[0] != "--id-width" {
+			fmt.Fprintln(os.Stderr, "Usage: tenazas work init [--id-width <digits>]")
+			os.Exit(1)
+		}
+		width, err := strconv.Atoi(args[1])
+		if err == nil {
+			err = SetIDWidth(tasksDir, width)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("New task IDs use %d digits (e.g. %s); existing tasks keep theirs.\n", width, formatTaskID(1, width))
+	}
+
 	if err := MigrateTasks(tasksDir); err != nil {
 		fmt.Fprintf(os.Stderr, "Migration error: %v\n", err)
 	}
@@ -71,8 +111,9 @@ func handleWorkInit(tasksDir string) {
 	printStatusSummaryTo(os.Stdout, tasks)
 }
 
-// extractPriorityFlag separates --priority <int> from positional args.
-func extractPriorityFlag(args []string) (int, []string, error) {
+// ExtractPriorityFlag separates --priority <int|label> from positional args,
+// resolving labels against levels.
+func ExtractPriorityFlag(args []string, levels PriorityLevels) (int, []string, error) {
 	var priority int
 	var positional []string
 	for i := 0; i < len(args); i++ {
@@ -80,9 +121,9 @@ func extractPriorityFlag(args []string) (int, []string, error) {
 			if i+1 >= len(args) {
 				return 0, nil, fmt.Errorf("--priority requires a value")
 			}
-			p, err := strconv.Atoi(args[i+1])
-			if err != nil || p < 0 {
-				return 0, nil, fmt.Errorf("--priority must be a non-negative integer")
+			p, err := levels.parse(args[i+1])
+			if err != nil {
+				return 0, nil, fmt.Errorf("--priority: %v", err)
 			}
 			priority = p
 			i++
@@ -93,15 +134,20 @@ func extractPriorityFlag(args []string) (int, []string, error) {
 	return priority, positional, nil
 }
 
-func handleWorkAdd(tasksDir string, args []string) {
-	priority, positional, err := extractPriorityFlag(args)
+func handleWorkAdd(tasksDir string, levels PriorityLevels, args []string) {
+	rawLabels, args, err := extractLabelsFlag(args)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	priority, positional, err := ExtractPriorityFlag(args, levels)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 
 	if len(positional) < 2 {
-		fmt.Println("Usage: tenazas work add [--priority <int>] \"Title\" \"Description\"")
+		fmt.Println("Usage: tenazas work add [--priority <int|label>] [--labels <csv>] \"Title\" \"Description\"")
 		os.Exit(1)
 	}
 
@@ -117,6 +163,7 @@ func handleWorkAdd(tasksDir string, args []string) {
 		Status:    StatusTodo,
 		Priority:  priority,
 		CreatedAt: time.Now().Truncate(time.Second),
+		Labels:    labelsFromCSV(rawLabels),
 		Content:   positional[1],
 	}
 
@@ -128,7 +175,7 @@ func handleWorkAdd(tasksDir string, args []string) {
 	fmt.Printf("Created task: %s\n", taskPath)
 }
 
-func handleWorkNext(tasksDir string) {
+func handleWorkNext(tasksDir string, labelSkills LabelSkills) {
 	tasks, err := ListTasks(tasksDir)
 	if err != nil {
 		fmt.Printf("Error searching for tasks: %v\n", err)
@@ -146,11 +193,14 @@ func handleWorkNext(tasksDir string) {
 		os.Exit(1)
 	}
 
-	next.Status = StatusInProgress
-	next.OwnerPID = os.Getpid()
 	now := time.Now().Truncate(time.Second)
+	next.SetStatus(StatusInProgress, now)
+	next.OwnerPID = os.Getpid()
 	next.StartedAt = &now
 	updateAndPrintTask(next)
+	if skill := labelSkills.SkillFor(next); skill != "" {
+		fmt.Printf("SKILL:%s\n", skill)
+	}
 }
 
 func handleWorkComplete(tasksDir string) {
@@ -161,8 +211,8 @@ func handleWorkComplete(tasksDir string) {
 		os.Exit(1)
 	}
 
-	active.Status = StatusDone
 	now := time.Now().Truncate(time.Second)
+	active.SetStatus(StatusDone, now)
 	active.CompletedAt = &now
 	active.ClearOwnership()
 	if err := WriteTask(active.FilePath, active); err != nil {
@@ -214,7 +264,7 @@ func GetTasksDir(storageDir string) string {
 		fmt.Fprintf(os.Stderr, "Error getting working directory: %v\n", err)
 		os.Exit(1)
 	}
-	tasksDir := filepath.Join(storageDir, "tasks", storage.Slugify(cwd))
+	tasksDir := TasksDirFor(storageDir, cwd)
 	if err := os.MkdirAll(tasksDir, 0755); err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating tasks directory: %v\n", err)
 		os.Exit(1)
@@ -222,17 +272,118 @@ func GetTasksDir(storageDir string) string {
 	return tasksDir
 }
 
-func handleWorkList(tasksDir string) {
-	tasks := listTasksOrDie(tasksDir)
-	RenderList(os.Stdout, tasks)
+// TasksDirFor returns the tasks dir of the project at projectDir.
+func TasksDirFor(storageDir, projectDir string) string {
+	return filepath.Join(storageDir, "tasks", storage.Slugify(projectDir))
+}
+
+// ListFilter narrows "work list": a task must carry every label and have one
+// of the statuses. Empty fields match everything.
+type ListFilter struct {
+	Labels   []string
+	Statuses []string
+}
+
+// Active reports whether f filters anything out.
+func (f ListFilter) Active() bool {
+	return len(f.Labels) > 0 || len(f.Statuses) > 0
+}
+
+// Match reports whether t passes the filter.
+func (f ListFilter) Match(t *Task) bool {
+	if len(f.Statuses) > 0 && !sliceContains(f.Statuses, t.Status) {
+		return false
+	}
+	for _, label := range f.Labels {
+		found := false
+		for _, have := range t.Labels {
+			if NormalizeLabel(have) == label {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterTasks returns the tasks matching f, in their original order.
+func FilterTasks(tasks []*Task, f ListFilter) []*Task {
+	if !f.Active() {
+		return tasks
+	}
+	var out []*Task
+	for _, t := range tasks {
+		if f.Match(t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// parseListFilter reads repeated --label flags and a comma-separated
+// --status list.
+func parseListFilter(args []string) (ListFilter, error) {
+	var f ListFilter
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--label":
+			label := NormalizeLabel(nextFlagValue(args, &i, "--label"))
+			if label == "" {
+				return f, fmt.Errorf("invalid label: %q", args[i])
+			}
+			f.Labels = append(f.Labels, label)
+		case "--status":
+			for _, status := range parseCSVLabels(nextFlagValue(args, &i, "--status")) {
+				if _, ok := allowedTransitions[status]; !ok {
+					return f, fmt.Errorf("unknown status: %s", status)
+				}
+				f.Statuses = append(f.Statuses, status)
+			}
+		default:
+			return f, fmt.Errorf("unknown flag: %s", args[i])
+		}
+	}
+	return f, nil
 }
 
-func handleWorkShow(tasksDir string, args []string) {
+// RenderFilteredList is RenderList over the tasks matching f, with its own
+// message when the filters leave nothing. Readiness markers still account
+// for dependencies that were filtered out.
+func RenderFilteredList(w io.Writer, tasks []*Task, f ListFilter, levels PriorityLevels) {
+	if len(tasks) == 0 || !f.Active() {
+		RenderList(w, tasks, levels)
+		return
+	}
+	filtered := FilterTasks(tasks, f)
+	if len(filtered) == 0 {
+		fmt.Fprintln(w, "No tasks match the given filters.")
+		return
+	}
+	renderListRows(w, filtered, buildTaskMap(tasks), levels)
+}
+
+func handleWorkList(tasksDir string, levels PriorityLevels, args []string) {
+	f, err := parseListFilter(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Usage: tenazas work list [--label <label>]... [--status <s>[,<s>...]]")
+		os.Exit(1)
+	}
+	RenderFilteredList(os.Stdout, listTasksOrDie(tasksDir), f, levels)
+}
+
+func handleWorkShow(tasksDir string, levels PriorityLevels, args []string) {
+	withLog, args := extractBoolFlag(args, "--log")
+	asJSON, args := extractBoolFlag(args, "--json")
+	withDeps, args := extractBoolFlag(args, "--deps")
 	if len(args) < 1 {
-		fmt.Fprintln(os.Stderr, "Usage: tenazas work show <task-id>")
+		fmt.Fprintln(os.Stderr, "Usage: tenazas work show <task-id> [--log] [--deps] [--json]")
 		os.Exit(1)
 	}
-	id := normalizeTaskID(args[0])
+	id := NormalizeTaskIDIn(tasksDir, args[0])
 	allTasks := listTasksOrDie(tasksDir)
 	taskMap := buildTaskMap(allTasks)
 	task, ok := taskMap[id]
@@ -240,24 +391,69 @@ func handleWorkShow(tasksDir string, args []string) {
 		fmt.Fprintf(os.Stderr, "Error: Task %s not found\n", id)
 		os.Exit(1)
 	}
-	RenderShow(os.Stdout, task, taskMap)
+	var entries []LogEntry
+	if withLog {
+		var err error
+		if entries, err = ReadLog(tasksDir, task.ID, DefaultLogTail); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not read task log: %v\n", err)
+		}
+	}
+	if asJSON {
+		detail := NewTaskDetail(task, taskMap)
+		for _, e := range entries {
+			detail.Log = append(detail.Log, TaskLogDetail{Time: e.Time, Event: e.Event, Message: e.Message})
+		}
+		data, err := json.MarshalIndent(detail, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+	RenderShow(os.Stdout, task, taskMap, levels)
+	if withDeps {
+		RenderDepTree(os.Stdout, task, taskMap, DepTreeDepth)
+	}
+	if withLog {
+		RenderLog(os.Stdout, entries)
+	}
+}
+
+// extractBoolFlag removes every occurrence of flag from args and reports
+// whether it was present.
+func extractBoolFlag(args []string, flag string) (bool, []string) {
+	var rest []string
+	found := false
+	for _, a := range args {
+		if a == flag {
+			found = true
+			continue
+		}
+		rest = append(rest, a)
+	}
+	return found, rest
 }
 
 func normalizeTaskID(input string) string {
+	return normalizeTaskIDWidth(input, DefaultIDWidth)
+}
+
+func normalizeTaskIDWidth(input string, width int) string {
 	s := strings.TrimSpace(input)
 	upper := strings.ToUpper(s)
 	if strings.HasPrefix(upper, taskIDPrefix) {
 		return upper
 	}
 	if n, err := strconv.Atoi(s); err == nil {
-		return fmt.Sprintf("%s%06d", taskIDPrefix, n)
+		return formatTaskID(n, width)
 	}
 	return s
 }
 
 // findTaskOrDie normalizes rawID, loads the task, and exits on error.
 func findTaskOrDie(tasksDir, rawID string) (string, *Task) {
-	id := normalizeTaskID(rawID)
+	id := NormalizeTaskIDIn(tasksDir, rawID)
 	task, err := FindTask(tasksDir, id)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -300,67 +496,199 @@ func parseCSVLabels(raw string) []string {
 	return labels
 }
 
-func handleWorkEdit(tasksDir string, args []string) {
-	const usage = "Usage: tenazas work edit <id> [--title <str>] [--status <str>] [--priority <int>] [--skill <str>] [--labels <csv>]"
-	if len(args) < 2 {
-		fmt.Fprintln(os.Stderr, usage)
-		os.Exit(1)
+// labelsFromCSV parses and normalizes a --labels value, reporting any
+// transformed or dropped labels on stderr.
+func labelsFromCSV(raw string) []string {
+	labels, warnings := NormalizeLabels(parseCSVLabels(raw))
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
 	}
+	return labels
+}
 
-	id, task := findTaskOrDie(tasksDir, args[0])
+// extractLabelsFlag separates --labels <csv> from the remaining args.
+func extractLabelsFlag(args []string) (string, []string, error) {
+	var raw string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--labels" {
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("--labels requires a value")
+			}
+			raw = args[i+1]
+			i++
+		} else {
+			rest = append(rest, args[i])
+		}
+	}
+	return raw, rest, nil
+}
+
+// taskEdit is the set of field changes requested by "work edit"; nil
+// fields are left alone.
+type taskEdit struct {
+	title    *string
+	status   *string
+	priority *int
+	weight   *int
+	skill    *string
+	labels   *[]string
+}
+
+// apply validates the edit against t and, if it is allowed, applies it,
+// setting timestamps and ownership the way a status change requires.
+func (ed taskEdit) apply(t *Task) error {
+	if ed.status != nil {
+		if err := ValidateStatusTransition(t.Status, *ed.status); err != nil {
+			return err
+		}
+	}
+	if ed.title != nil {
+		t.Title = *ed.title
+	}
+	if ed.status != nil {
+		now := time.Now().Truncate(time.Second)
+		t.SetStatus(*ed.status, now)
+		if t.Status == StatusDone {
+			t.CompletedAt = &now
+			t.ClearOwnership()
+		}
+		if t.Status == StatusInProgress {
+			if t.StartedAt == nil {
+				t.StartedAt = &now
+			}
+			t.OwnerPID = os.Getpid()
+		}
+	}
+	if ed.priority != nil {
+		t.Priority = *ed.priority
+	}
+	if ed.weight != nil {
+		t.Weight = *ed.weight
+	}
+	if ed.skill != nil {
+		t.Skill = *ed.skill
+	}
+	if ed.labels != nil {
+		t.Labels = *ed.labels
+	}
+	return nil
+}
 
-	flags := args[1:]
-	hasFlag := false
+// parseTaskEdit reads the leading task IDs and the field flags of
+// "work edit". ok is false when no flag was given.
+func parseTaskEdit(args []string, levels PriorityLevels) (ids []string, ed taskEdit, ok bool) {
+	i := 0
+	for ; i < len(args) && !strings.HasPrefix(args[i], "--"); i++ {
+		ids = append(ids, args[i])
+	}
+	flags := args[i:]
 	for i := 0; i < len(flags); i++ {
 		switch flags[i] {
 		case "--title":
-			task.Title = nextFlagValue(flags, &i, "--title")
-			hasFlag = true
+			v := nextFlagValue(flags, &i, "--title")
+			ed.title = &v
+			ok = true
 		case "--status":
-			newStatus := nextFlagValue(flags, &i, "--status")
-			if err := ValidateStatusTransition(task.Status, newStatus); err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
-			}
-			task.Status = newStatus
-			if newStatus == StatusDone {
-				now := time.Now().Truncate(time.Second)
-				task.CompletedAt = &now
-				task.ClearOwnership()
-			}
-			if newStatus == StatusInProgress {
-				if task.StartedAt == nil {
-					now := time.Now().Truncate(time.Second)
-					task.StartedAt = &now
-				}
-				task.OwnerPID = os.Getpid()
-			}
-			hasFlag = true
+			v := nextFlagValue(flags, &i, "--status")
+			ed.status = &v
+			ok = true
 		case "--priority":
 			raw := nextFlagValue(flags, &i, "--priority")
-			p, err := strconv.Atoi(raw)
-			if err != nil || p < 0 {
-				fmt.Fprintln(os.Stderr, "Error: --priority must be a non-negative integer")
+			p, err := levels.parse(raw)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --priority: %v\n", err)
+				os.Exit(1)
+			}
+			ed.priority = &p
+			ok = true
+		case "--weight":
+			raw := nextFlagValue(flags, &i, "--weight")
+			w, err := strconv.Atoi(raw)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --weight: invalid integer %q\n", raw)
 				os.Exit(1)
 			}
-			task.Priority = p
-			hasFlag = true
+			ed.weight = &w
+			ok = true
 		case "--skill":
-			task.Skill = nextFlagValue(flags, &i, "--skill")
-			hasFlag = true
+			v := nextFlagValue(flags, &i, "--skill")
+			ed.skill = &v
+			ok = true
 		case "--labels":
-			task.Labels = parseCSVLabels(nextFlagValue(flags, &i, "--labels"))
-			hasFlag = true
+			v := labelsFromCSV(nextFlagValue(flags, &i, "--labels"))
+			ed.labels = &v
+			ok = true
+		}
+	}
+	return ids, ed, ok
+}
+
+// editResult is the outcome of a "work edit" on one task.
+type editResult struct {
+	ID  string
+	Err error
+}
+
+// editTasks applies ed to each task in turn; a task that can't be found,
+// changed or saved doesn't stop the others.
+func editTasks(tasksDir string, rawIDs []string, ed taskEdit) []editResult {
+	results := make([]editResult, 0, len(rawIDs))
+	for _, raw := range rawIDs {
+		id := NormalizeTaskIDIn(tasksDir, raw)
+		t, err := FindTask(tasksDir, id)
+		if err == nil {
+			err = ed.apply(t)
+		}
+		if err == nil {
+			err = WriteTask(t.FilePath, t)
 		}
+		results = append(results, editResult{ID: id, Err: err})
 	}
+	return results
+}
 
-	if !hasFlag {
+// renderEditResults reports a batch edit, successes first, and returns how
+// many tasks failed.
+func renderEditResults(w io.Writer, results []editResult) int {
+	failed := 0
+	for _, r := range results {
+		if r.Err == nil {
+			fmt.Fprintf(w, "Updated: %s\n", r.ID)
+		}
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(w, "Failed:  %s: %v\n", r.ID, r.Err)
+			failed++
+		}
+	}
+	fmt.Fprintf(w, "%d of %d task(s) updated\n", len(results)-failed, len(results))
+	return failed
+}
+
+func handleWorkEdit(tasksDir string, levels PriorityLevels, args []string) {
+	const usage = "Usage: tenazas work edit <id>... [--title <str>] [--status <str>] [--priority <int|label>] [--weight <int>] [--skill <str>] [--labels <csv>]"
+	ids, ed, ok := parseTaskEdit(args, levels)
+	if len(ids) == 0 || !ok {
 		fmt.Fprintln(os.Stderr, usage)
 		os.Exit(1)
 	}
 
-	writeTaskOrDie(task)
-	fmt.Printf("Updated: %s\n", id)
+	if len(ids) == 1 {
+		id, task := findTaskOrDie(tasksDir, ids[0])
+		if err := ed.apply(task); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		writeTaskOrDie(task)
+		fmt.Printf("Updated: %s\n", id)
+		return
+	}
+
+	if renderEditResults(os.Stdout, editTasks(tasksDir, ids, ed)) > 0 {
+		os.Exit(1)
+	}
 }
 
 func handleWorkDelete(tasksDir string, args []string) {
@@ -369,7 +697,7 @@ func handleWorkDelete(tasksDir string, args []string) {
 		os.Exit(1)
 	}
 
-	id := normalizeTaskID(args[0])
+	id := NormalizeTaskIDIn(tasksDir, args[0])
 	tasks := listTasksOrDie(tasksDir)
 	taskMap := buildTaskMap(tasks)
 
@@ -415,7 +743,7 @@ func handleWorkDep(tasksDir string, args []string) {
 
 	action := args[0]
 	id, task := findTaskOrDie(tasksDir, args[1])
-	depID := normalizeTaskID(args[2])
+	depID := NormalizeTaskIDIn(tasksDir, args[2])
 
 	switch action {
 	case "add":
@@ -457,6 +785,32 @@ func handleWorkUnblock(tasksDir string, args []string) {
 	fmt.Printf("Unblocked: %s\n", id)
 }
 
+func handleWorkReopen(tasksDir string, args []string) {
+	const usage = "Usage: tenazas work reopen <id> --reason <text>"
+	var reason string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--reason" {
+			reason = nextFlagValue(args, &i, "--reason")
+			continue
+		}
+		rest = append(rest, args[i])
+	}
+	if len(rest) != 1 || strings.TrimSpace(reason) == "" {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	id, task := findTaskOrDie(tasksDir, rest[0])
+	if err := task.Reopen(reason, time.Now().Truncate(time.Second)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	writeTaskOrDie(task)
+	fmt.Printf("Reopened: %s\n", id)
+}
+
 func handleWorkReset(tasksDir string, args []string) {
 	if len(args) < 1 {
 		fmt.Fprintln(os.Stderr, "Usage: tenazas work reset <id>")
@@ -464,13 +818,7 @@ func handleWorkReset(tasksDir string, args []string) {
 	}
 
 	id, task := findTaskOrDie(tasksDir, args[0])
-
-	task.Status = StatusTodo
-	task.ClearOwnership()
-	task.FailureCount = 0
-	task.StartedAt = nil
-	task.CompletedAt = nil
-
+	task.Reset()
 	writeTaskOrDie(task)
 	fmt.Printf("Reset: %s\n", id)
 }
@@ -508,3 +856,33 @@ func handleWorkArchive(tasksDir string, args []string) {
 		fmt.Printf("Archived %d completed tasks\n", count)
 	}
 }
+
+func handleWorkMove(storageDir, tasksDir string, args []string) {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: tenazas work move <id> <target-dir>")
+		os.Exit(1)
+	}
+
+	id := NormalizeTaskIDIn(tasksDir, args[0])
+	target, err := filepath.Abs(args[1])
+	if err == nil {
+		var info os.FileInfo
+		if info, err = os.Stat(target); err == nil && !info.IsDir() {
+			err = fmt.Errorf("%s is not a directory", target)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	moved, warnings, err := MoveTask(tasksDir, TasksDirFor(storageDir, target), id)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Moved: %s → %s (%s)\n", id, moved.ID, target)
+}
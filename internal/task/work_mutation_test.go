@@ -1,6 +1,7 @@
 package task
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -118,7 +119,7 @@ func TestWorkEditTitle(t *testing.T) {
 		Status: StatusTodo,
 	})
 
-	HandleWorkCommand(storageDir, []string{"edit", "1", "--title", "Updated Title"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"edit", "1", "--title", "Updated Title"})
 
 	tk := readTestTask(t, tasksDir, "TSK-000001")
 	if tk.Title != "Updated Title" {
@@ -141,7 +142,7 @@ func TestWorkEditStatus(t *testing.T) {
 	})
 
 	before := time.Now().Truncate(time.Second)
-	HandleWorkCommand(storageDir, []string{"edit", "1", "--status", "in-progress"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"edit", "1", "--status", "in-progress"})
 
 	tk := readTestTask(t, tasksDir, "TSK-000001")
 	if tk.Status != StatusInProgress {
@@ -173,7 +174,7 @@ func TestWorkEditStatusToDone(t *testing.T) {
 		StartedAt:       &startedAt,
 	})
 
-	HandleWorkCommand(storageDir, []string{"edit", "1", "--status", "done"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"edit", "1", "--status", "done"})
 
 	tk := readTestTask(t, tasksDir, "TSK-000001")
 	if tk.Status != StatusDone {
@@ -205,7 +206,7 @@ func TestWorkEditPriority(t *testing.T) {
 		Priority: 0,
 	})
 
-	HandleWorkCommand(storageDir, []string{"edit", "1", "--priority", "5"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"edit", "1", "--priority", "5"})
 
 	tk := readTestTask(t, tasksDir, "TSK-000001")
 	if tk.Priority != 5 {
@@ -213,6 +214,25 @@ func TestWorkEditPriority(t *testing.T) {
 	}
 }
 
+func TestWorkEditWeight(t *testing.T) {
+	storageDir, tasksDir, cleanup := setupTasksDir(t)
+	defer cleanup()
+
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000001", Title: "Weight Test", Status: StatusTodo})
+
+	HandleWorkCommand(storageDir, Settings{}, []string{"edit", "1", "--weight", "10"})
+
+	tk := readTestTask(t, tasksDir, "TSK-000001")
+	if tk.Weight != 10 {
+		t.Errorf("Weight = %d, want 10", tk.Weight)
+	}
+	var buf bytes.Buffer
+	RenderShow(&buf, tk, buildTaskMap([]*Task{tk}), nil)
+	if !strings.Contains(buf.String(), "  Weight:      10\n") {
+		t.Errorf("expected RenderShow to print the weight, got:\n%s", buf.String())
+	}
+}
+
 func TestWorkEditSkillAndLabels(t *testing.T) {
 	storageDir, tasksDir, cleanup := setupTasksDir(t)
 	defer cleanup()
@@ -223,7 +243,7 @@ func TestWorkEditSkillAndLabels(t *testing.T) {
 		Status: StatusTodo,
 	})
 
-	HandleWorkCommand(storageDir, []string{"edit", "1", "--skill", "analyze", "--labels", "bug,urgent"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"edit", "1", "--skill", "analyze", "--labels", "bug,urgent"})
 
 	tk := readTestTask(t, tasksDir, "TSK-000001")
 	if tk.Skill != "analyze" {
@@ -246,7 +266,7 @@ func TestWorkEditMultipleFields(t *testing.T) {
 		Priority: 0,
 	})
 
-	HandleWorkCommand(storageDir, []string{
+	HandleWorkCommand(storageDir, Settings{}, []string{
 		"edit", "1",
 		"--title", "New Title",
 		"--priority", "3",
@@ -265,6 +285,50 @@ func TestWorkEditMultipleFields(t *testing.T) {
 	}
 }
 
+func TestWorkEditBatch(t *testing.T) {
+	storageDir, tasksDir, cleanup := setupTasksDir(t)
+	defer cleanup()
+
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000001", Title: "One", Status: StatusInProgress, OwnerPID: 4242, OwnerSessionID: "sess-1"})
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000002", Title: "Two", Status: StatusTodo})
+	writeTestTask(t, tasksDir, &Task{ID: "TSK-000003", Title: "Three", Status: StatusDone})
+
+	ids, ed, ok := parseTaskEdit([]string{"1", "2", "3", "9", "--status", "blocked", "--priority", "4"}, nil)
+	if !ok || strings.Join(ids, ",") != "1,2,3,9" {
+		t.Fatalf("parseTaskEdit = %v, %v", ids, ok)
+	}
+	var buf bytes.Buffer
+	failed := renderEditResults(&buf, editTasks(tasksDir, ids, ed))
+	if failed != 2 {
+		t.Errorf("failed = %d, want 2 (done → blocked and a missing task)", failed)
+	}
+	out := buf.String()
+	for _, want := range []string{"Updated: TSK-000001\nUpdated: TSK-000002\n", "Failed:  TSK-000003: invalid transition", "Failed:  TSK-000009:", "2 of 4 task(s) updated"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report lacks %q:\n%s", want, out)
+		}
+	}
+
+	one := readTestTask(t, tasksDir, "TSK-000001")
+	two := readTestTask(t, tasksDir, "TSK-000002")
+	if one.Status != StatusBlocked || two.Status != StatusBlocked || one.Priority != 4 || two.Priority != 4 {
+		t.Errorf("batch not applied: %+v / %+v", one, two)
+	}
+	if three := readTestTask(t, tasksDir, "TSK-000003"); three.Status != StatusDone || three.Priority != 0 {
+		t.Errorf("a rejected task must be left untouched, got %+v", three)
+	}
+
+	// Status side effects apply to every task in the batch.
+	HandleWorkCommand(storageDir, Settings{}, []string{"edit", "1", "2", "--status", "in-progress"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"edit", "1", "2", "--status", "done"})
+	for _, id := range []string{"TSK-000001", "TSK-000002"} {
+		tk := readTestTask(t, tasksDir, id)
+		if tk.Status != StatusDone || tk.StartedAt == nil || tk.CompletedAt == nil || tk.OwnerPID != 0 || tk.OwnerSessionID != "" {
+			t.Errorf("%s: expected done with timestamps and no owner, got %+v", id, tk)
+		}
+	}
+}
+
 func TestWorkEditInvalidStatus(t *testing.T) {
 	// Directly test the validator — done → blocked is forbidden.
 	err := ValidateStatusTransition(StatusDone, StatusBlocked)
@@ -286,7 +350,7 @@ func TestWorkEditLabelsWhitespaceTrimming(t *testing.T) {
 		Status: StatusTodo,
 	})
 
-	HandleWorkCommand(storageDir, []string{"edit", "1", "--labels", " bug , urgent , backend "})
+	HandleWorkCommand(storageDir, Settings{}, []string{"edit", "1", "--labels", " bug , urgent , backend "})
 
 	tk := readTestTask(t, tasksDir, "TSK-000001")
 	wantLabels := []string{"bug", "urgent", "backend"}
@@ -306,7 +370,7 @@ func TestWorkEditClearLabels(t *testing.T) {
 		Labels: []string{"old-label"},
 	})
 
-	HandleWorkCommand(storageDir, []string{"edit", "1", "--labels", ""})
+	HandleWorkCommand(storageDir, Settings{}, []string{"edit", "1", "--labels", ""})
 
 	tk := readTestTask(t, tasksDir, "TSK-000001")
 	if len(tk.Labels) != 0 {
@@ -334,7 +398,7 @@ func TestWorkDelete(t *testing.T) {
 		t.Fatal("Task file should exist before delete")
 	}
 
-	HandleWorkCommand(storageDir, []string{"delete", "1"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"delete", "1"})
 
 	// File should be gone
 	if _, err := os.Stat(taskPath); !os.IsNotExist(err) {
@@ -360,7 +424,7 @@ func TestWorkDeleteCleansReferences(t *testing.T) {
 		BlockedBy: []string{"TSK-000001"},
 	})
 
-	HandleWorkCommand(storageDir, []string{"delete", "1"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"delete", "1"})
 
 	// Task B's BlockedBy should no longer contain TSK-000001
 	tkB := readTestTask(t, tasksDir, "TSK-000002")
@@ -441,7 +505,7 @@ func TestWorkDeleteBlocksOnlyDoneDependents(t *testing.T) {
 		BlockedBy: []string{"TSK-000001"},
 	})
 
-	HandleWorkCommand(storageDir, []string{"delete", "1"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"delete", "1"})
 
 	// File should be gone
 	taskPath := filepath.Join(tasksDir, "TSK-000001.md")
@@ -477,7 +541,7 @@ func TestWorkDepAdd(t *testing.T) {
 		Status: StatusTodo,
 	})
 
-	HandleWorkCommand(storageDir, []string{"dep", "add", "1", "2"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"dep", "add", "1", "2"})
 
 	tkA := readTestTask(t, tasksDir, "TSK-000001")
 	tkB := readTestTask(t, tasksDir, "TSK-000002")
@@ -510,7 +574,7 @@ func TestWorkDepAddIdempotent(t *testing.T) {
 	})
 
 	// Adding the same dependency again should not create duplicates
-	HandleWorkCommand(storageDir, []string{"dep", "add", "1", "2"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"dep", "add", "1", "2"})
 
 	tkA := readTestTask(t, tasksDir, "TSK-000001")
 	tkB := readTestTask(t, tasksDir, "TSK-000002")
@@ -589,6 +653,38 @@ func TestWorkDepAddCycle(t *testing.T) {
 	_ = tkA
 }
 
+func TestAddDependencyReverseEdge(t *testing.T) {
+	_, tasksDir, cleanup := setupTasksDir(t)
+	defer cleanup()
+
+	// B depends on A, recorded only on B's side.
+	tkA := writeTestTask(t, tasksDir, &Task{
+		ID:     "TSK-000001",
+		Title:  "Task A",
+		Status: StatusTodo,
+	})
+	writeTestTask(t, tasksDir, &Task{
+		ID:        "TSK-000002",
+		Title:     "Task B",
+		Status:    StatusTodo,
+		BlockedBy: []string{"TSK-000001"},
+	})
+
+	err := AddDependency(tasksDir, tkA, "TSK-000002")
+	if err == nil {
+		t.Fatal("Expected reverse dependency error, got nil")
+	}
+	if !strings.Contains(err.Error(), "reverse dependency already exists: TSK-000002 is blocked by TSK-000001") {
+		t.Errorf("Error should name the existing reverse edge, got: %v", err)
+	}
+	if contains(tkA.BlockedBy, "TSK-000002") {
+		t.Error("Task A's BlockedBy should be unchanged")
+	}
+	if contains(readTestTask(t, tasksDir, "TSK-000001").BlockedBy, "TSK-000002") {
+		t.Error("Task A should not be rewritten")
+	}
+}
+
 func TestWorkDepRemove(t *testing.T) {
 	storageDir, tasksDir, cleanup := setupTasksDir(t)
 	defer cleanup()
@@ -607,7 +703,7 @@ func TestWorkDepRemove(t *testing.T) {
 		Blocks: []string{"TSK-000001"},
 	})
 
-	HandleWorkCommand(storageDir, []string{"dep", "remove", "1", "2"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"dep", "remove", "1", "2"})
 
 	tkA := readTestTask(t, tasksDir, "TSK-000001")
 	tkB := readTestTask(t, tasksDir, "TSK-000002")
@@ -703,7 +799,7 @@ func TestWorkUnblock(t *testing.T) {
 		OwnerSessionID:  "sess-xyz",
 	})
 
-	HandleWorkCommand(storageDir, []string{"unblock", "1"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"unblock", "1"})
 
 	tk := readTestTask(t, tasksDir, "TSK-000001")
 	if tk.Status != StatusTodo {
@@ -740,7 +836,7 @@ func TestWorkUnblockPreservesDependencies(t *testing.T) {
 		Blocks: []string{"TSK-000001"},
 	})
 
-	HandleWorkCommand(storageDir, []string{"unblock", "1"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"unblock", "1"})
 
 	tk := readTestTask(t, tasksDir, "TSK-000001")
 	// Dependencies should remain intact — unblock only changes status
@@ -792,7 +888,7 @@ func TestWorkReset(t *testing.T) {
 		StartedAt:       &startedAt,
 	})
 
-	HandleWorkCommand(storageDir, []string{"reset", "1"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"reset", "1"})
 
 	tk := readTestTask(t, tasksDir, "TSK-000001")
 	if tk.Status != StatusTodo {
@@ -832,7 +928,7 @@ func TestWorkResetFromDone(t *testing.T) {
 		CompletedAt: &completedAt,
 	})
 
-	HandleWorkCommand(storageDir, []string{"reset", "1"})
+	HandleWorkCommand(storageDir, Settings{}, []string{"reset", "1"})
 
 	tk := readTestTask(t, tasksDir, "TSK-000001")
 	if tk.Status != StatusTodo {
@@ -0,0 +1,121 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxCallbackData is Telegram's limit on a button's callback data, in bytes.
+const maxCallbackData = 64
+
+// sessionIDLen is the length of a session ID (a UUID), used to check
+// configured buttons before any session exists.
+const sessionIDLen = 36
+
+// skillActionPrefix marks a quick action that runs a named skill, e.g.
+// "skill:deploy".
+const skillActionPrefix = "skill:"
+
+// defaultActionKeyboard is the quick-action layout under each response, one
+// inner slice per row.
+var defaultActionKeyboard = [][]string{{"continue", "new_session"}, {"run"}, {"more"}}
+
+// actionKeywords are the quick actions a keyboard row can name, besides
+// "skill:<name>".
+var actionKeywords = map[string]bool{
+	"continue": true, "new_session": true, "run": true, "more": true, "last": true, "settings": true,
+}
+
+// getActionKeyboard builds the default quick-action keyboard.
+func getActionKeyboard(sessionID, content string) map[string]interface{} {
+	return buildActionKeyboard(defaultActionKeyboard, sessionID, content)
+}
+
+// actionKeyboard builds the configured quick-action keyboard, or the default
+// one when ActionKeyboard is empty.
+func (tg *Telegram) actionKeyboard(sessionID, content string) map[string]interface{} {
+	if len(tg.ActionKeyboard) == 0 {
+		return getActionKeyboard(sessionID, content)
+	}
+	return buildActionKeyboard(tg.ActionKeyboard, sessionID, content)
+}
+
+// buildActionKeyboard lays out rows of quick actions. Unknown actions, a
+// "run" with no shell command in content and buttons whose callback data
+// would exceed Telegram's limit are left out; empty rows are dropped.
+func buildActionKeyboard(rows [][]string, sessionID, content string) map[string]interface{} {
+	keyboard := [][]map[string]interface{}{}
+	for _, row := range rows {
+		var buttons []map[string]interface{}
+		for _, name := range row {
+			if btn, ok := actionButton(name, sessionID, content); ok {
+				buttons = append(buttons, btn)
+			}
+		}
+		if len(buttons) > 0 {
+			keyboard = append(keyboard, buttons)
+		}
+	}
+	return map[string]interface{}{"inline_keyboard": keyboard}
+}
+
+// actionButton returns the button for one quick action.
+func actionButton(name, sessionID, content string) (map[string]interface{}, bool) {
+	var text, data string
+	switch {
+	case name == "continue":
+		text, data = "➡️ Continue", "act:continue_prompt:"+sessionID
+	case name == "new_session":
+		text, data = "🆕 New Session", "act:new_session:"+sessionID
+	case name == "run":
+		cmd := ExtractShellCommand(content)
+		if cmd == "" {
+			return nil, false
+		}
+		if len(cmd) > 20 {
+			cmd = cmd[:17] + "..."
+		}
+		text, data = "▶️ Run: "+cmd, "act:run_command:"+sessionID
+	case name == "more":
+		text, data = "➕ More Actions...", "act:more_actions:"+sessionID
+	case name == "last":
+		text, data = "📜 Last Logs", "act:show_last:"+sessionID
+	case name == "settings":
+		text, data = "⚙️ Settings", settingsCallback("menu", "", sessionID)
+	case strings.HasPrefix(name, skillActionPrefix):
+		skillName := strings.TrimPrefix(name, skillActionPrefix)
+		if skillName == "" || strings.Contains(skillName, ":") {
+			return nil, false
+		}
+		text, data = "⚡ "+skillName, "act:skill:"+sessionID+":"+skillName
+	default:
+		return nil, false
+	}
+	if len(data) > maxCallbackData {
+		return nil, false
+	}
+	return tgBtn(text, data), true
+}
+
+// ValidateActionKeyboard reports configured quick actions that would never
+// show: unknown names and skill buttons whose callback data would exceed
+// Telegram's 64-byte limit.
+func ValidateActionKeyboard(rows [][]string) []string {
+	placeholder := strings.Repeat("0", sessionIDLen)
+	var problems []string
+	for _, row := range rows {
+		for _, name := range row {
+			if !actionKeywords[name] && !strings.HasPrefix(name, skillActionPrefix) {
+				problems = append(problems, fmt.Sprintf("unknown action %q", name))
+				continue
+			}
+			if name == "run" {
+				continue // only shown when a response has a command
+			}
+			if _, ok := actionButton(name, placeholder, ""); !ok {
+				problems = append(problems, fmt.Sprintf("action %q does not fit Telegram's %d-byte callback data limit (keep skill names short, without ':')", name, maxCallbackData))
+			}
+		}
+	}
+	return problems
+}
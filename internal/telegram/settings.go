@@ -0,0 +1,185 @@
+package telegram
+
+import (
+	"sort"
+	"strings"
+
+	"tenazas/internal/formatter"
+	"tenazas/internal/models"
+)
+
+// defaultTiers are offered when the session's client has no tier→model
+// mapping configured.
+var defaultTiers = []string{"high", "medium", "low"}
+
+// settingsModes are the approval modes offered, in display order.
+var settingsModes = []string{models.ApprovalModePlan, models.ApprovalModeAutoEdit, models.ApprovalModeYolo}
+
+// settingsCallback encodes a settings button as "set:<kind>:<value>:<sessionID>".
+// kind is "tier" or "mode"; the "menu" kind has no value.
+func settingsCallback(kind, value, sessionID string) string {
+	if kind == "menu" {
+		return "set:menu:" + sessionID
+	}
+	return "set:" + kind + ":" + value + ":" + sessionID
+}
+
+// parseSettingsCallback decodes the parts of a "set:" callback.
+func parseSettingsCallback(parts []string) (kind, value, sessionID string, ok bool) {
+	if len(parts) < 2 {
+		return "", "", "", false
+	}
+	switch kind = parts[1]; kind {
+	case "menu":
+		if len(parts) > 2 {
+			sessionID = parts[2]
+		}
+		return kind, "", sessionID, true
+	case "tier", "mode":
+		if len(parts) != 4 || parts[2] == "" {
+			return "", "", "", false
+		}
+		return kind, parts[2], parts[3], true
+	}
+	return "", "", "", false
+}
+
+// availableTiers lists the tiers configured for client, high/medium/low
+// first, falling back to defaultTiers.
+func (tg *Telegram) availableTiers(client string) []string {
+	if client == "" {
+		client = tg.DefaultClient
+	}
+	configured := tg.ClientModels[client]
+	if len(configured) == 0 {
+		return defaultTiers
+	}
+	var tiers, extra []string
+	for _, t := range defaultTiers {
+		if _, ok := configured[t]; ok {
+			tiers = append(tiers, t)
+		}
+	}
+	for t := range configured {
+		if t != "high" && t != "medium" && t != "low" {
+			extra = append(extra, t)
+		}
+	}
+	sort.Strings(extra)
+	return append(tiers, extra...)
+}
+
+func (tg *Telegram) handleSettingsCB(chatID int64, instanceID string, parts []string) {
+	kind, value, sessionID, ok := parseSettingsCallback(parts)
+	if !ok {
+		return
+	}
+	switch kind {
+	case "menu":
+		tg.showSettings(chatID, instanceID, sessionID)
+	case "tier":
+		tg.setTier(chatID, sessionID, value)
+	case "mode":
+		tg.setApprovalMode(chatID, sessionID, value)
+	}
+}
+
+// showSettings sends the tier and approval-mode buttons for sessionID, or for
+// the focused session when sessionID is empty.
+func (tg *Telegram) showSettings(chatID int64, instanceID, sessionID string) {
+	var sess *models.Session
+	var err error
+	if sessionID != "" {
+		sess, err = tg.Sm.Load(sessionID)
+	} else {
+		sess, err = tg.getOrFocusSession(instanceID)
+	}
+	if err != nil {
+		tg.send(chatID, "No active session.")
+		return
+	}
+
+	tier := sess.ModelTier
+	if tier == "" {
+		tier = "high"
+	}
+	mode := sess.ApprovalMode
+	if mode == "" {
+		mode = models.ApprovalModePlan
+	}
+
+	var tierRow, modeRow []map[string]interface{}
+	for _, t := range tg.availableTiers(sess.Client) {
+		label := t
+		if t == tier {
+			label = "✅ " + t
+		}
+		tierRow = append(tierRow, tgBtn(label, settingsCallback("tier", t, sess.ID)))
+	}
+	for _, m := range settingsModes {
+		label := strings.ToLower(m)
+		if m == mode {
+			label = "✅ " + label
+		}
+		modeRow = append(modeRow, tgBtn(label, settingsCallback("mode", m, sess.ID)))
+	}
+
+	text := "⚙️ <b>Settings</b> for <code>" + FormatHTML(tg.sessionLabel(sess)) + "</code>\n" +
+		"🎚 Model tier: <b>" + tier + "</b>\n" +
+		"🛡 Approval mode: <b>" + strings.ToLower(mode) + "</b>"
+	tg.send(chatID, text, map[string]interface{}{
+		"reply_markup": map[string]interface{}{
+			"inline_keyboard": [][]map[string]interface{}{tierRow, modeRow},
+		},
+	})
+}
+
+func (tg *Telegram) setTier(chatID int64, sessionID, tier string) {
+	sess, err := tg.Sm.Load(sessionID)
+	if err != nil {
+		tg.send(chatID, "Error: "+FormatHTML(err.Error()))
+		return
+	}
+	valid := false
+	for _, t := range tg.availableTiers(sess.Client) {
+		valid = valid || t == tier
+	}
+	if !valid {
+		tg.send(chatID, "Tier <b>"+FormatHTML(tier)+"</b> is not available for this session's client.")
+		return
+	}
+	if _, err := tg.Sm.UpdateSession(sessionID, func(s *models.Session) { s.ModelTier = tier }); err != nil {
+		tg.send(chatID, "❌ Error saving tier: "+FormatHTML(err.Error()))
+		return
+	}
+	tg.send(chatID, "🎚 Model tier set to <b>"+tier+"</b>")
+}
+
+func (tg *Telegram) setApprovalMode(chatID int64, sessionID, value string) {
+	mode, ok := models.ParseApprovalMode(value)
+	if !ok {
+		tg.send(chatID, "Unknown approval mode: "+FormatHTML(value))
+		return
+	}
+	sess, err := tg.Sm.UpdateSession(sessionID, func(s *models.Session) {
+		s.ApprovalMode = mode
+		s.Yolo = mode == models.ApprovalModeYolo
+	})
+	if err != nil {
+		tg.send(chatID, "❌ Error saving mode: "+FormatHTML(err.Error()))
+		return
+	}
+	tg.Sm.RememberApprovalMode(sess.CWD, mode)
+	msg := "🛡 Approval mode set to <b>" + strings.ToLower(mode) + "</b>"
+	if mode == models.ApprovalModeYolo {
+		msg = "⚠️ " + msg + " — the agent will edit files and run commands without asking."
+	}
+	tg.send(chatID, msg)
+}
+
+func (tg *Telegram) sessionLabel(sess *models.Session) string {
+	if sess.Title != "" {
+		return sess.Title
+	}
+	return formatter.ShortID(sess.ID, nil, tg.ShortIDLength)
+}
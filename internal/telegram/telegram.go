@@ -4,50 +4,91 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"net/http"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"tenazas/internal/events"
 	"tenazas/internal/formatter"
 	"tenazas/internal/models"
 	"tenazas/internal/registry"
 	"tenazas/internal/session"
+	"tenazas/internal/skill"
 )
 
+// Stopper halts and resumes all automated work (implemented by
+// heartbeat.Runner).
+type Stopper interface {
+	StopAll(reason string) ([]string, error)
+	ResumeAll() error
+}
+
 type Telegram struct {
-	Token          string
-	AllowedIDs     []int64
-	UpdateInterval int
-	Sm             *session.Manager
-	Reg            *registry.Registry
-	Engine         models.EngineInterface
-	DefaultClient  string
-	lastUpdateID   int64
-	activeMessages map[string]*tgLiveStream
-	mu             sync.RWMutex
-}
-
-// SendNotification implements heartbeat.Notifier.
+	Token               string
+	AllowedIDs          []int64
+	AllowedUsernames    []string // also allowed, resolved to IDs when their first update arrives
+	UpdateInterval      int
+	Sm                  *session.Manager
+	Reg                 *registry.Registry
+	Engine              models.EngineInterface
+	DefaultClient       string
+	ClientModels        map[string]map[string]string // clientName → tier → model name; keys are the tiers offered in Settings
+	DefaultApprovalMode string                       // models.ApprovalMode* for new sessions; empty means plan
+	TimeFormat          formatter.TimeFormat         // timestamp layout/timezone for audit output
+	ShortIDLength       int                          // session ID characters shown in buttons; 0 means formatter.DefaultShortIDLength
+	StatusDebounce      time.Duration                // min gap between task status edits per session; 0 disables
+	ActionKeyboard      [][]string                   // quick-action rows under responses; empty uses defaultActionKeyboard
+	EscalationChatIDs   []int64                      // also told about interventions left unanswered (see Engine.EscalateAfter)
+	Stopper             Stopper                      // backs /stopall and /resumeall; nil disables them
+	MaxMessageChars     int                          // longer messages are cut to this many bytes; 0 means defaultMaxMessageChars
+	TruncationMarker    string                       // ends a cut message or preview; empty means "..."
+	PreviewChars        int                          // characters of each /last entry; 0 means lastPreviewLen
+	StreamMinDelta      int                          // least new characters worth a live edit; 0 means minStreamEditDelta
+	StreamFinalOnly     bool                         // skip live edits and post only the final response
+	lastUpdateID        int64
+	activeMessages      map[string]*tgLiveStream
+	mu                  sync.RWMutex
+	statusMu            sync.Mutex
+	statusNotes         map[string]*statusNote // sessionID -> last task status sent
+	usersMu             sync.Mutex
+	resolved            map[string]int64 // normalized username -> user ID; nil until loaded from UsernamesFileName
+}
+
+// SendNotification implements heartbeat.Notifier. text is plain and is
+// escaped for the HTML parse mode.
 func (tg *Telegram) SendNotification(chatID int64, text string) {
-	tg.send(chatID, text)
+	tg.send(chatID, FormatHTML(text))
 }
 
 // AllowedChatIDs implements heartbeat.Notifier.
 func (tg *Telegram) AllowedChatIDs() []int64 {
-	return tg.AllowedIDs
+	return tg.allowedIDs()
 }
 
 type tgLiveStream struct {
-	msgID    int64
-	fullText string
-	lastEdit time.Time
+	msgID     int64 // 0 while no live message was posted (StreamFinalOnly)
+	fullText  string
+	lastEdit  time.Time
+	editedLen int       // len(fullText) at the last edit
+	holdUntil time.Time // no edits before this, after Telegram answered 429
 }
 
+// minStreamEditDelta is the least new text, in characters, worth an
+// intermediate edit of a live message; smaller deltas wait for more text or
+// the final flush.
+const minStreamEditDelta = 24
+
+// minStreamInterval is the shortest gap between edits of one live message,
+// whatever UpdateInterval says; Telegram throttles faster edits of a chat.
+const minStreamInterval = time.Second
+
 func (tg *Telegram) streamKey(chatID int64, sessionID string) string {
 	return fmt.Sprintf("%d:%s", chatID, sessionID)
 }
@@ -58,14 +99,16 @@ type TgUpdate struct {
 	Message  struct {
 		MessageID int64 `json:"message_id"`
 		From      struct {
-			ID int64 `json:"id"`
+			ID       int64  `json:"id"`
+			Username string `json:"username"`
 		} `json:"from"`
 		Text string `json:"text"`
 	} `json:"message"`
 	CallbackQuery struct {
 		ID   string `json:"id"`
 		From struct {
-			ID int64 `json:"id"`
+			ID       int64  `json:"id"`
+			Username string `json:"username"`
 		} `json:"from"`
 		Data string `json:"data"`
 	} `json:"callback_query"`
@@ -80,18 +123,18 @@ type TgMessageResponse struct {
 	OK          bool   `json:"ok"`
 	ErrorCode   int    `json:"error_code,omitempty"`
 	Description string `json:"description,omitempty"`
-	Result      struct {
+	Parameters  struct {
+		RetryAfter int `json:"retry_after,omitempty"` // seconds to wait after a 429
+	} `json:"parameters"`
+	Result struct {
 		MessageID int64 `json:"message_id"`
 	} `json:"result"`
 }
 
+// IsAllowed reports whether id is in AllowedIDs or was resolved from one of
+// AllowedUsernames.
 func (tg *Telegram) IsAllowed(id int64) bool {
-	for _, aid := range tg.AllowedIDs {
-		if aid == id {
-			return true
-		}
-	}
-	return false
+	return containsID(tg.AllowedIDs, id) || containsID(tg.resolvedIDs(), id)
 }
 
 var BaseURL = "https://api.telegram.org/bot"
@@ -179,12 +222,8 @@ func (tg *Telegram) Poll() {
 
 		for _, upd := range res.Result {
 			tg.lastUpdateID = upd.UpdateID
-			fromID := upd.Message.From.ID
-			if fromID == 0 {
-				fromID = upd.CallbackQuery.From.ID
-			}
-
-			if !tg.IsAllowed(fromID) {
+			fromID, ok := tg.admit(upd)
+			if !ok {
 				continue
 			}
 
@@ -198,7 +237,7 @@ func (tg *Telegram) Poll() {
 }
 
 func (tg *Telegram) listenEvents(ch chan events.Event) {
-	f := &formatter.HtmlFormatter{}
+	f := &formatter.HtmlFormatter{Time: tg.TimeFormat}
 	for e := range ch {
 		switch e.Type {
 		case events.EventAudit:
@@ -206,10 +245,38 @@ func (tg *Telegram) listenEvents(ch chan events.Event) {
 		case events.EventTaskStatus:
 			payload := e.Payload.(events.TaskStatusPayload)
 			tg.NotifyTaskState(e.SessionID, payload.State, payload.Details)
+		case events.EventStorageError:
+			msg := "⚠️ " + FormatHTML(e.Payload.(events.StorageErrorPayload).Message())
+			for _, id := range tg.allowedIDs() {
+				tg.send(id, msg)
+			}
+		case events.EventEscalation:
+			tg.sendEscalation(e.SessionID, e.Payload.(events.EscalationPayload))
 		}
 	}
 }
 
+// sendEscalation tells the escalation contacts that a session has waited on
+// an intervention past the escalation window. The allowed users already got
+// the intervention itself when it started.
+func (tg *Telegram) sendEscalation(sessionID string, p events.EscalationPayload) {
+	if len(tg.EscalationChatIDs) == 0 {
+		return
+	}
+	title := sessionID
+	if sess, err := tg.Sm.Load(sessionID); err == nil && sess.Title != "" {
+		title = sess.Title
+	}
+	msg := fmt.Sprintf("🚨 <b>Escalation</b>: <b>%s</b> has waited %s for an intervention at <code>%s</code>.",
+		FormatHTML(title), p.Waiting.Round(time.Second), FormatHTML(p.Node))
+	if p.Reason != "" {
+		msg += "\n\n" + FormatHTML(p.Reason)
+	}
+	for _, id := range tg.EscalationChatIDs {
+		tg.send(id, msg)
+	}
+}
+
 func (tg *Telegram) broadcastAudit(sessionID string, audit events.AuditEntry, f *formatter.HtmlFormatter) {
 	tg.mu.Lock()
 	if tg.activeMessages == nil {
@@ -217,7 +284,7 @@ func (tg *Telegram) broadcastAudit(sessionID string, audit events.AuditEntry, f
 	}
 	tg.mu.Unlock()
 
-	for _, id := range tg.AllowedIDs {
+	for _, id := range tg.allowedIDs() {
 		instanceID := tg.instanceID(id)
 		state, _ := tg.Reg.Get(instanceID)
 
@@ -248,7 +315,7 @@ func (tg *Telegram) shouldDisplay(verbosity, auditType string) bool {
 	case "LOW":
 		return auditType == events.AuditIntervention || auditType == events.AuditStatus
 	case "MEDIUM":
-		return auditType == events.AuditIntervention || auditType == events.AuditInfo || auditType == events.AuditStatus
+		return auditType == events.AuditIntervention || auditType == events.AuditInfo || auditType == events.AuditStatus || auditType == events.AuditResume
 	case "HIGH":
 		return true
 	}
@@ -269,8 +336,24 @@ func (tg *Telegram) sendAuditMessage(chatID int64, sessionID string, audit event
 	}
 }
 
+// handleStreamingChunk adds content to the live message of a response,
+// editing it once both the update interval has passed and enough new text
+// has arrived. With StreamFinalOnly the text is only collected for
+// handleStreamingEnd.
 func (tg *Telegram) handleStreamingChunk(id int64, sessionID, content string, f *formatter.HtmlFormatter) {
 	key := tg.streamKey(id, sessionID)
+	if tg.StreamFinalOnly {
+		tg.mu.Lock()
+		stream, ok := tg.activeMessages[key]
+		if !ok {
+			stream = &tgLiveStream{}
+			tg.activeMessages[key] = stream
+		}
+		stream.fullText += content
+		tg.mu.Unlock()
+		return
+	}
+
 	tg.mu.RLock()
 	stream, ok := tg.activeMessages[key]
 	tg.mu.RUnlock()
@@ -302,40 +385,62 @@ func (tg *Telegram) handleStreamingChunk(id int64, sessionID, content string, f
 	defer tg.mu.Unlock()
 
 	stream.fullText += content
-	interval := time.Duration(tg.UpdateInterval) * time.Millisecond
-	if interval < 1000*time.Millisecond {
-		interval = 1000 * time.Millisecond
+	if !tg.streamEditDue(stream, time.Now()) {
+		return
+	}
+	text := tg.truncateMessage(f.Escape(stream.fullText))
+	resp, err := tg.Call("editMessageText", map[string]interface{}{
+		"chat_id":    id,
+		"message_id": stream.msgID,
+		"text":       text,
+		"parse_mode": "HTML",
+	})
+	stream.lastEdit = time.Now()
+	stream.editedLen = len(stream.fullText)
+	var res TgMessageResponse
+	if err == nil && json.Unmarshal(resp, &res) == nil && res.Parameters.RetryAfter > 0 {
+		stream.holdUntil = stream.lastEdit.Add(time.Duration(res.Parameters.RetryAfter) * time.Second)
 	}
+}
 
-	if time.Since(stream.lastEdit) > interval {
-		text := f.Escape(stream.fullText)
-		if len(text) > 4000 {
-			text = text[:3997] + "..."
-		}
-		_, _ = tg.Call("editMessageText", map[string]interface{}{
-			"chat_id":    id,
-			"message_id": stream.msgID,
-			"text":       text,
-			"parse_mode": "HTML",
-		})
-		stream.lastEdit = time.Now()
+// streamEditDue reports whether stream's live message should be edited now:
+// the update interval has passed, Telegram isn't holding edits back, and at
+// least StreamMinDelta characters arrived since the last edit.
+func (tg *Telegram) streamEditDue(stream *tgLiveStream, now time.Time) bool {
+	interval := time.Duration(tg.UpdateInterval) * time.Millisecond
+	if interval < minStreamInterval {
+		interval = minStreamInterval
+	}
+	if now.Sub(stream.lastEdit) <= interval || now.Before(stream.holdUntil) {
+		return false
+	}
+	minDelta := tg.StreamMinDelta
+	if minDelta <= 0 {
+		minDelta = minStreamEditDelta
 	}
+	return utf8.RuneCountInString(stream.fullText[stream.editedLen:]) >= minDelta
 }
 
+// handleStreamingEnd always replaces the live message (placeholder or last
+// intermediate edit) with the complete response, whatever was throttled.
 func (tg *Telegram) handleStreamingEnd(id int64, sessionID, content string, f *formatter.HtmlFormatter) {
-	markup := getActionKeyboard(sessionID, content)
-	text := "🟢 <b>RESPONSE:</b>\n" + f.Escape(content)
-
 	key := tg.streamKey(id, sessionID)
 	tg.mu.Lock()
 	stream, ok := tg.activeMessages[key]
 	var msgID int64
 	if ok {
 		msgID = stream.msgID
+		// Clients that only stream leave the response empty; the chunks
+		// are then the whole text.
+		if strings.TrimSpace(content) == "" {
+			content = stream.fullText
+		}
 		delete(tg.activeMessages, key)
 	}
 	tg.mu.Unlock()
 
+	markup := tg.actionKeyboard(sessionID, content)
+	text := "🟢 <b>RESPONSE:</b>\n" + f.Escape(content)
 	_, _ = tg.upsertMonitoringMessage(id, msgID, text, markup)
 }
 
@@ -350,15 +455,93 @@ func (tg *Telegram) sendIntervention(id int64, sessionID string, text string) {
 	})
 }
 
+// statusNote remembers the last task status sent for a session, so repeated
+// identical notifications are dropped and bursts are coalesced.
+type statusNote struct {
+	key     string    // statusKey of the last notification sent
+	sentAt  time.Time // when it was sent
+	pending *pendingStatus
+}
+
+// pendingStatus is the latest notification waiting out the debounce window.
+type pendingStatus struct {
+	state   string
+	details map[string]string
+}
+
+// statusKey fingerprints a notification by state and details.
+func statusKey(state string, details map[string]string) string {
+	keys := make([]string, 0, len(details))
+	for k := range details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := fnv.New64a()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s\x00", k, details[k])
+	}
+	return fmt.Sprintf("%s:%x", state, h.Sum64())
+}
+
+// NotifyTaskState updates the session's monitoring message. A notification
+// identical to the last one sent is skipped; within StatusDebounce of the
+// last edit only the newest notification is kept and sent when it elapses.
 func (tg *Telegram) NotifyTaskState(sessionID string, state string, details map[string]string) {
+	key := statusKey(state, details)
+
+	tg.statusMu.Lock()
+	if tg.statusNotes == nil {
+		tg.statusNotes = make(map[string]*statusNote)
+	}
+	note := tg.statusNotes[sessionID]
+	if note == nil {
+		note = &statusNote{}
+		tg.statusNotes[sessionID] = note
+	}
+	if note.pending == nil && note.key == key {
+		tg.statusMu.Unlock()
+		return
+	}
+	if wait := tg.StatusDebounce - time.Since(note.sentAt); tg.StatusDebounce > 0 && wait > 0 {
+		if note.pending == nil {
+			time.AfterFunc(wait, func() { tg.flushTaskState(sessionID) })
+		}
+		note.pending = &pendingStatus{state: state, details: details}
+		tg.statusMu.Unlock()
+		return
+	}
+	note.key, note.sentAt = key, time.Now()
+	tg.statusMu.Unlock()
+
+	tg.sendTaskState(sessionID, state, details)
+}
+
+// flushTaskState sends the notification held back by the debounce window,
+// unless it matches what was last sent.
+func (tg *Telegram) flushTaskState(sessionID string) {
+	tg.statusMu.Lock()
+	note := tg.statusNotes[sessionID]
+	p := note.pending
+	note.pending = nil
+	if p == nil || statusKey(p.state, p.details) == note.key {
+		tg.statusMu.Unlock()
+		return
+	}
+	note.key, note.sentAt = statusKey(p.state, p.details), time.Now()
+	tg.statusMu.Unlock()
+
+	tg.sendTaskState(sessionID, p.state, p.details)
+}
+
+func (tg *Telegram) sendTaskState(sessionID string, state string, details map[string]string) {
 	sess, err := tg.Sm.Load(sessionID)
 	if err != nil {
 		return
 	}
 
 	chatID := sess.MonitoringChatID
-	if chatID == 0 && len(tg.AllowedIDs) > 0 {
-		chatID = tg.AllowedIDs[0]
+	if ids := tg.allowedIDs(); chatID == 0 && len(ids) > 0 {
+		chatID = ids[0]
 	}
 
 	if chatID == 0 {
@@ -379,9 +562,7 @@ func (tg *Telegram) NotifyTaskState(sessionID string, state string, details map[
 }
 
 func (tg *Telegram) upsertMonitoringMessage(chatID, msgID int64, text string, keyboard map[string]interface{}) (int64, error) {
-	if len(text) > 4000 {
-		text = text[:3997] + "..."
-	}
+	text = tg.truncateMessage(text)
 
 	payload := map[string]interface{}{
 		"chat_id":      chatID,
@@ -424,6 +605,7 @@ var stateMeta = map[string]struct {
 	events.TaskStateBlocked:   {"⏸️", "BLOCKED", "💬 Respond & Unblock", "task_respond"},
 	events.TaskStateCompleted: {"✅", "COMPLETED", "🔍 Review Output", "task_review"},
 	events.TaskStateFailed:    {"❌", "FAILED", "🔍 Review Output", "task_review"},
+	events.TaskStateStopped:   {"⏹️", "STOPPED", "🔍 Review Output", "task_review"},
 }
 
 func (tg *Telegram) formatTaskStatusText(sess *models.Session, state string, details map[string]string) string {
@@ -443,11 +625,14 @@ func (tg *Telegram) formatTaskStatusText(sess *models.Session, state string, det
 
 	var buf strings.Builder
 	_, _ = fmt.Fprintf(&buf, "%s <b>TASK %s</b>\n\n", icon, label)
-	_, _ = fmt.Fprintf(&buf, "<b>Task:</b> %s\n", title)
-	_, _ = fmt.Fprintf(&buf, "<b>Path:</b> <code>%s</code>\n", filepath.Base(sess.CWD))
+	_, _ = fmt.Fprintf(&buf, "<b>Task:</b> %s\n", FormatHTML(title))
+	_, _ = fmt.Fprintf(&buf, "<b>Path:</b> <code>%s</code>\n", FormatHTML(filepath.Base(sess.CWD)))
 
+	if category := details["category"]; category != "" {
+		_, _ = fmt.Fprintf(&buf, "<b>Category:</b> <code>%s</code>\n", FormatHTML(category))
+	}
 	if reason, ok := details["reason"]; ok && reason != "" {
-		_, _ = fmt.Fprintf(&buf, "\n<b>Details:</b> %s\n", reason)
+		_, _ = fmt.Fprintf(&buf, "\n<b>Details:</b> %s\n", FormatHTML(reason))
 	}
 
 	return buf.String()
@@ -528,23 +713,6 @@ func ExtractShellCommand(content string) string {
 	return blocks[0].cmd
 }
 
-func getActionKeyboard(sessionID, content string) map[string]interface{} {
-	keyboard := [][]map[string]interface{}{
-		{tgBtn("➡️ Continue", "act:continue_prompt:"+sessionID), tgBtn("🆕 New Session", "act:new_session:"+sessionID)},
-	}
-
-	if cmd := ExtractShellCommand(content); cmd != "" {
-		shortCmd := cmd
-		if len(shortCmd) > 20 {
-			shortCmd = shortCmd[:17] + "..."
-		}
-		keyboard = append(keyboard, []map[string]interface{}{tgBtn("▶️ Run: "+shortCmd, "act:run_command:"+sessionID)})
-	}
-
-	keyboard = append(keyboard, []map[string]interface{}{tgBtn("➕ More Actions...", "act:more_actions:"+sessionID)})
-	return map[string]interface{}{"inline_keyboard": keyboard}
-}
-
 func tgBtn(text, data string) map[string]interface{} {
 	return map[string]interface{}{"text": text, "callback_data": data}
 }
@@ -572,13 +740,17 @@ func (tg *Telegram) HandleMessage(chatID int64, text string) {
 		tg.handleCommand(chatID, instanceID, text)
 		return
 	}
+	if strings.TrimSpace(text) == "" {
+		tg.send(chatID, "✏️ That message was empty. Send some text to prompt the agent.")
+		return
+	}
 
 	state, err := tg.Reg.Get(instanceID)
 	if err == nil && state.PendingAction == "rename" {
 		if err := tg.Sm.Rename(state.PendingData, text); err != nil {
-			tg.send(chatID, "❌ Error renaming session: "+err.Error())
+			tg.send(chatID, "❌ Error renaming session: "+FormatHTML(err.Error()))
 		} else {
-			tg.send(chatID, "✅ Session renamed to: <b>"+text+"</b>")
+			tg.send(chatID, "✅ Session renamed to: <b>"+FormatHTML(text)+"</b>")
 		}
 		_ = tg.Reg.ClearPending(instanceID)
 		return
@@ -612,8 +784,14 @@ func (tg *Telegram) handleCommand(chatID int64, instanceID, text string) {
 		}
 	case "/yolo":
 		tg.toggleYolo(chatID, instanceID)
+	case "/budget":
+		tg.setBudget(chatID, instanceID, parts[1:])
 	case "/sessions":
 		tg.showSessionsMenu(chatID, 0)
+	case "/settings":
+		tg.showSettings(chatID, instanceID, "")
+	case "/nudge":
+		tg.nudge(chatID, instanceID)
 	case "/start":
 		tg.handleStartCommand(chatID)
 	case "/run":
@@ -621,15 +799,20 @@ func (tg *Telegram) handleCommand(chatID int64, instanceID, text string) {
 			tg.startSkill(chatID, instanceID, parts[1])
 		}
 	case "/last":
-		n := 5
-		if len(parts) > 1 {
-			_, _ = fmt.Sscanf(parts[1], "%d", &n)
+		n, filter, err := parseLastArgs(parts[1:])
+		if err != nil {
+			tg.send(chatID, "❌ "+FormatHTML(err.Error())+"\nUsage: /last [n] [type]")
+			return
 		}
-		tg.showLastLogs(chatID, instanceID, n)
+		tg.showLastPage(chatID, instanceID, n, 0, filter)
+	case "/stopall":
+		tg.stopAll(chatID)
+	case "/resumeall":
+		tg.resumeAll(chatID)
 	case "/help":
 		tg.showHelp(chatID)
 	default:
-		tg.send(chatID, "Unknown command: "+cmd)
+		tg.send(chatID, "Unknown command: "+FormatHTML(cmd))
 	}
 }
 
@@ -638,19 +821,52 @@ func (tg *Telegram) showHelp(chatID int64) {
 /help - Show this help message
 /sessions - List and resume previous sessions
 /yolo - Toggle YOLO mode (autonomous mode)
+/budget [amount] - Show or set the session budget cap in USD (0 = unlimited)
+/settings - Switch the session's model tier and approval mode
+/nudge - Show the pending intervention question again
 /verbosity [LOW|MEDIUM|HIGH] - Set event verbosity
 /run [skill] - Run a skill from your skills folder
-/last [n] - Show the last N audit log entries for the session
+/last [n] [type] - Page through the session's audit log, N entries per page, optionally only one type (e.g. cmd_result)
+/stopall - Stop every running session and pause heartbeats
+/resumeall - Let heartbeats run again
 `
 	tg.send(chatID, helpText)
 }
 
+// stopAll implements /stopall: stop everything the daemon is running and
+// keep heartbeats from starting more until /resumeall.
+func (tg *Telegram) stopAll(chatID int64) {
+	if tg.Stopper == nil {
+		tg.send(chatID, "Stop-all is only available in the daemon.")
+		return
+	}
+	ids, err := tg.Stopper.StopAll(fmt.Sprintf("/stopall from Telegram (%d)", chatID))
+	if err != nil {
+		tg.send(chatID, "❌ Error pausing heartbeats: "+FormatHTML(err.Error()))
+		return
+	}
+	tg.send(chatID, fmt.Sprintf("⏹️ Stopped %d running session(s). Heartbeats are paused; /resumeall to resume.", len(ids)))
+}
+
+func (tg *Telegram) resumeAll(chatID int64) {
+	if tg.Stopper == nil {
+		tg.send(chatID, "Stop-all is only available in the daemon.")
+		return
+	}
+	if err := tg.Stopper.ResumeAll(); err != nil {
+		tg.send(chatID, "❌ Error resuming heartbeats: "+FormatHTML(err.Error()))
+		return
+	}
+	tg.send(chatID, "▶️ Heartbeats resumed.")
+}
+
 func (tg *Telegram) handleStartCommand(chatID int64) {
 	tg.send(chatID, "👋 <b>Welcome to Tenazas!</b>\n\nI am your terminal-to-Telegram gateway. What would you like to do?", map[string]interface{}{
 		"reply_markup": map[string]interface{}{
 			"inline_keyboard": [][]map[string]interface{}{
 				{tgBtn("📂 My Sessions", "show_sessions:0")},
 				{tgBtn("🛠 Run Skill", "show_skills")},
+				{tgBtn("⚙️ Settings", settingsCallback("menu", "", ""))},
 				{tgBtn("❓ Help", "help")},
 			},
 		},
@@ -680,9 +896,9 @@ func (tg *Telegram) toggleYolo(chatID int64, instanceID string) {
 		tg.send(chatID, "No active session.")
 		return
 	}
-	sess.Yolo = !sess.Yolo
-	if err := tg.Sm.Save(sess); err != nil {
-		tg.send(chatID, "❌ Error saving mode: "+err.Error())
+	sess, err = tg.Sm.UpdateSession(sess.ID, func(s *models.Session) { s.Yolo = !s.Yolo })
+	if err != nil {
+		tg.send(chatID, "❌ Error saving mode: "+FormatHTML(err.Error()))
 		return
 	}
 	status := "OFF"
@@ -692,59 +908,281 @@ func (tg *Telegram) toggleYolo(chatID int64, instanceID string) {
 	tg.send(chatID, "⚠️ YOLO Mode is now <b>"+status+"</b>")
 }
 
-func (tg *Telegram) startSkill(chatID int64, instanceID, skillName string) {
-	skill, err := tg.Sm.LoadSkill(skillName)
+// setBudget implements "/budget [amount]" with the same semantics as the CLI:
+// no argument shows the current cap, 0 removes it.
+func (tg *Telegram) setBudget(chatID int64, instanceID string, args []string) {
+	sess, err := tg.getOrFocusSession(instanceID)
 	if err != nil {
-		tg.send(chatID, "Skill not found: "+err.Error())
+		tg.send(chatID, "No active session.")
+		return
+	}
+	if len(args) == 0 {
+		tg.send(chatID, "💰 Budget: <b>"+budgetLabel(sess.MaxBudgetUSD)+"</b>")
+		return
+	}
+	var amount float64
+	if _, err := fmt.Sscanf(args[0], "%f", &amount); err != nil || amount < 0 {
+		tg.send(chatID, "Invalid budget. Use: /budget &lt;amount&gt; (e.g. /budget 5.00, /budget 0 for unlimited)")
+		return
+	}
+	if _, err := tg.Sm.UpdateSession(sess.ID, func(s *models.Session) { s.MaxBudgetUSD = amount }); err != nil {
+		tg.send(chatID, "❌ Error saving budget: "+FormatHTML(err.Error()))
 		return
 	}
+	tg.send(chatID, "💰 Budget set to <b>"+budgetLabel(amount)+"</b>")
+}
+
+func budgetLabel(amount float64) string {
+	if amount <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("$%.2f", amount)
+}
 
+func (tg *Telegram) startSkill(chatID int64, instanceID, skillName string) {
 	sess, err := tg.getOrFocusSession(instanceID)
 	if err != nil {
 		tg.send(chatID, "No session found.")
 		return
 	}
 
-	sess.Title = "Task: " + skill.Name
+	sk, err := tg.Sm.LoadSkillFor(sess.CWD, skillName)
+	if err != nil {
+		msg := "Skill not found: " + FormatHTML(err.Error())
+		if hint := skill.NotFoundHint(tg.Sm.StoragePath, sess.CWD, skillName); hint != "" {
+			msg += "\n💡 " + FormatHTML(hint)
+		}
+		tg.send(chatID, msg)
+		return
+	}
+
+	sess.Title = "Task: " + sk.Name
 	sess.SkillName = skillName
 	if err := tg.Sm.Save(sess); err != nil {
-		tg.send(chatID, "❌ Error saving session: "+err.Error())
+		tg.send(chatID, "❌ Error saving session: "+FormatHTML(err.Error()))
 		return
 	}
 
-	tg.send(chatID, "Running skill: <b>"+skill.Name+"</b>")
-	tg.dispatch(func() { tg.Engine.Run(skill, sess) })
+	tg.send(chatID, "Running skill: <b>"+FormatHTML(sk.Name)+"</b>")
+	tg.dispatch(func() { tg.Engine.Run(sk, sess) })
+}
+
+// nudge asks the focused session's pending intervention question again, with
+// its buttons, without touching the run.
+func (tg *Telegram) nudge(chatID int64, instanceID string) {
+	sess, err := tg.getOrFocusSession(instanceID)
+	if err != nil {
+		tg.send(chatID, "No active session.")
+		return
+	}
+	details, err := tg.Engine.Nudge(sess.ID)
+	if err != nil {
+		tg.send(chatID, "Nothing to nudge: "+FormatHTML(err.Error()))
+		return
+	}
+	tg.sendIntervention(chatID, sess.ID, nudgeText(details))
+}
+
+// nudgeText renders blocked-task details for the nudge message.
+func nudgeText(details map[string]string) string {
+	var buf strings.Builder
+	buf.WriteString("⚠️ <b>Intervention Required</b>")
+	if node := details["node"]; node != "" {
+		fmt.Fprintf(&buf, " at <code>%s</code>", FormatHTML(node))
+	}
+	buf.WriteString("\n")
+	if category := details["category"]; category != "" {
+		fmt.Fprintf(&buf, "<b>Category:</b> <code>%s</code>\n", FormatHTML(category))
+	}
+	if instruction := details["instruction"]; instruction != "" {
+		fmt.Fprintf(&buf, "<b>Instruction:</b> %s\n", FormatHTML(instruction))
+	}
+	if reason := details["reason"]; reason != "" {
+		fmt.Fprintf(&buf, "<b>Reason:</b> %s\n", FormatHTML(reason))
+	}
+	if out := details["last_output"]; out != "" {
+		fmt.Fprintf(&buf, "<b>Last output:</b>\n<pre>%s</pre>", FormatHTML(out))
+	}
+	return buf.String()
 }
 
 func (tg *Telegram) showLastLogs(chatID int64, instanceID string, n int) {
+	tg.showLastPage(chatID, instanceID, n, 0, "")
+}
+
+const (
+	tgMaxMessageLen    = 4096
+	lastPreviewLen     = 300
+	lastDefaultPerPage = 5
+	lastMaxPerPage     = 50
+	lastMaxFilterLen   = 32 // keeps "last:<page>:<n>:<filter>" within maxCallbackData
+)
+
+// parseLastArgs reads the optional page size and audit type filter of /last.
+// Either argument may come first. The page size must be a positive number,
+// and the filter short enough to fit the paging buttons' callback data.
+func parseLastArgs(args []string) (int, string, error) {
+	n, filter := lastDefaultPerPage, ""
+	for _, a := range args {
+		if v, err := strconv.Atoi(a); err == nil {
+			if v <= 0 {
+				return 0, "", fmt.Errorf("page size must be a positive number, got %d", v)
+			}
+			n = v
+			continue
+		}
+		filter = strings.ToLower(a)
+	}
+	if len(filter) > lastMaxFilterLen {
+		return 0, "", fmt.Errorf("type filter is longer than %d characters", lastMaxFilterLen)
+	}
+	if n > lastMaxPerPage {
+		n = lastMaxPerPage
+	}
+	return n, filter, nil
+}
+
+// pageAuditEntries returns the window of entries shown on the given page.
+// Page 0 holds the most recent entries; higher pages go back in time. Entries
+// inside a window stay in chronological order. The page is clamped to the
+// valid range and returned alongside the total number of pages.
+func pageAuditEntries(entries []events.AuditEntry, page, pageSize int) ([]events.AuditEntry, int, int) {
+	if pageSize <= 0 {
+		pageSize = lastDefaultPerPage
+	}
+	total := (len(entries) + pageSize - 1) / pageSize
+	if total == 0 {
+		return nil, 0, 0
+	}
+	if page < 0 {
+		page = 0
+	}
+	if page >= total {
+		page = total - 1
+	}
+	end := len(entries) - page*pageSize
+	start := end - pageSize
+	if start < 0 {
+		start = 0
+	}
+	return entries[start:end], page, total
+}
+
+// splitMessage breaks Telegram HTML text into chunks no longer than limit,
+// preferring line boundaries. Lines longer than limit are cut with cutHTML,
+// so no chunk splits a rune, tag or entity or leaves a tag unclosed.
+func splitMessage(text string, limit int) []string {
+	if len(text) <= limit {
+		return []string{text}
+	}
+	var chunks []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+		}
+	}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for len(line) > limit {
+			flush()
+			var head string
+			head, line = cutHTML(line, limit)
+			chunks = append(chunks, head)
+		}
+		if cur.Len()+len(line) > limit {
+			flush()
+		}
+		cur.WriteString(line)
+	}
+	flush()
+	return chunks
+}
+
+func lastCallbackData(page, n int, filter string) string {
+	return fmt.Sprintf("last:%d:%d:%s", page, n, filter)
+}
+
+func (tg *Telegram) formatLastEntry(e events.AuditEntry) string {
+	content := tg.preview(strings.Join(strings.Fields(e.Content), " "))
+	return fmt.Sprintf("[%s] <b>%s</b>: %s\n", tg.TimeFormat.Format(e.Timestamp, "15:04"), FormatHTML(e.Type), FormatHTML(content))
+}
+
+func (tg *Telegram) showLastPage(chatID int64, instanceID string, n, page int, filter string) {
 	sess, err := tg.getOrFocusSession(instanceID)
 	if err != nil {
 		tg.send(chatID, "No active session.")
 		return
 	}
-	logs, _ := tg.Sm.GetLastAudit(sess, n)
+	entries, err := tg.Sm.FilterAudit(sess, func(e events.AuditEntry) bool {
+		if filter == "" {
+			// Streaming chunks are repeated by the final llm_response.
+			return e.Type != events.AuditLLMChunk
+		}
+		return e.Type == filter
+	})
+	if err != nil {
+		tg.send(chatID, "❌ Error reading audit log: "+FormatHTML(err.Error()))
+		return
+	}
+
+	window, page, totalPages := pageAuditEntries(entries, page, n)
+	if totalPages == 0 {
+		if filter != "" {
+			tg.send(chatID, "No <b>"+FormatHTML(filter)+"</b> entries in this session.")
+		} else {
+			tg.send(chatID, "No entries in this session yet.")
+		}
+		return
+	}
 
 	var buf strings.Builder
-	buf.WriteString("<b>Last entries:</b>\n")
-	for _, l := range logs {
-		_, _ = fmt.Fprintf(&buf, "[%s] %s: %s\n", l.Timestamp.Format("15:04"), l.Type, l.Content)
+	_, _ = fmt.Fprintf(&buf, "<b>Last entries</b> (page %d/%d", page+1, totalPages)
+	if filter != "" {
+		_, _ = fmt.Fprintf(&buf, ", type: %s", FormatHTML(filter))
+	}
+	buf.WriteString(")\n")
+	for _, e := range window {
+		buf.WriteString(tg.formatLastEntry(e))
+	}
+
+	var nav []map[string]interface{}
+	if page+1 < totalPages {
+		nav = append(nav, tgBtn("⬅️ Older", lastCallbackData(page+1, n, filter)))
+	}
+	if page > 0 {
+		nav = append(nav, tgBtn("Newer ➡️", lastCallbackData(page-1, n, filter)))
+	}
+
+	chunks := splitMessage(buf.String(), tgMaxMessageLen)
+	for i, chunk := range chunks {
+		if i == len(chunks)-1 && len(nav) > 0 {
+			tg.send(chatID, chunk, map[string]interface{}{
+				"reply_markup": map[string]interface{}{"inline_keyboard": [][]map[string]interface{}{nav}},
+			})
+			continue
+		}
+		tg.send(chatID, chunk)
 	}
-	tg.send(chatID, FormatHTML(buf.String()))
 }
 
 func (tg *Telegram) showSessionsMenu(chatID int64, page int) {
 	pageSize := 8
 	sessions, total, err := tg.Sm.ListActive(page, pageSize)
 	if err != nil {
-		tg.send(chatID, "Error: "+err.Error())
+		tg.send(chatID, "Error: "+FormatHTML(err.Error()))
 		return
 	}
 
+	ids := make([]string, len(sessions))
+	for i, s := range sessions {
+		ids[i] = s.ID
+	}
 	var buttons [][]map[string]interface{}
 	for _, s := range sessions {
 		title := s.Title
 		if title == "" {
-			title = s.ID[:8]
+			title = formatter.ShortID(s.ID, ids, tg.ShortIDLength)
 		}
 		label := fmt.Sprintf("%s (%s)", title, filepath.Base(s.CWD))
 		buttons = append(buttons, []map[string]interface{}{tgBtn(label, "view_session:"+s.ID)})
@@ -790,6 +1228,8 @@ func (tg *Telegram) HandleCallback(chatID int64, data string) {
 		"intv":              tg.handleInterventionCB,
 		"act":               tg.handleActionCB,
 		"start_new_session": tg.handleStartNewSession,
+		"last":              tg.handleLastCB,
+		"set":               tg.handleSettingsCB,
 	}
 
 	if h, ok := handlers[cmd]; ok {
@@ -805,6 +1245,20 @@ func (tg *Telegram) handleShowSessions(chatID int64, _ string, parts []string) {
 	tg.showSessionsMenu(chatID, page)
 }
 
+func (tg *Telegram) handleLastCB(chatID int64, instanceID string, parts []string) {
+	page, n, filter := 0, lastDefaultPerPage, ""
+	if len(parts) > 1 {
+		_, _ = fmt.Sscanf(parts[1], "%d", &page)
+	}
+	if len(parts) > 2 {
+		_, _ = fmt.Sscanf(parts[2], "%d", &n)
+	}
+	if len(parts) > 3 {
+		filter = parts[3]
+	}
+	tg.showLastPage(chatID, instanceID, n, page, filter)
+}
+
 func (tg *Telegram) handleFocusSession(chatID int64, instanceID string, parts []string) {
 	if len(parts) > 1 {
 		tg.focusSession(chatID, instanceID, parts[1])
@@ -848,7 +1302,7 @@ func (tg *Telegram) handleStartNewSession(chatID int64, _ string, _ []string) {
 
 func (tg *Telegram) archiveSession(chatID int64, sessionID string) {
 	if err := tg.Sm.Archive(sessionID); err != nil {
-		tg.send(chatID, "❌ Error archiving: "+err.Error())
+		tg.send(chatID, "❌ Error archiving: "+FormatHTML(err.Error()))
 		return
 	}
 	tg.send(chatID, "📦 Session archived.")
@@ -862,7 +1316,7 @@ func (tg *Telegram) handleActionCallback(chatID int64, instanceID string, parts
 
 	sess, err := tg.Sm.Load(sessionID)
 	if err != nil {
-		tg.send(chatID, "Error: "+err.Error())
+		tg.send(chatID, "Error: "+FormatHTML(err.Error()))
 		return
 	}
 
@@ -870,7 +1324,7 @@ func (tg *Telegram) handleActionCallback(chatID int64, instanceID string, parts
 		"task_pause": func(s *models.Session) {
 			s.Status = models.StatusIdle
 			if err := tg.Sm.Save(s); err != nil {
-				tg.send(chatID, "❌ Error pausing task: "+err.Error())
+				tg.send(chatID, "❌ Error pausing task: "+FormatHTML(err.Error()))
 			} else {
 				tg.send(chatID, "⏸️ Task paused.")
 			}
@@ -889,9 +1343,13 @@ func (tg *Telegram) handleActionCallback(chatID int64, instanceID string, parts
 		"new_session": func(s *models.Session) {
 			newSess, _ := tg.Sm.Create(s.CWD, "New Session")
 			newSess.Client = tg.DefaultClient
+			if tg.DefaultApprovalMode != "" {
+				newSess.ApprovalMode = tg.DefaultApprovalMode
+				newSess.Yolo = tg.DefaultApprovalMode == models.ApprovalModeYolo
+			}
 			tg.Sm.Save(newSess)
 			tg.Reg.Set(instanceID, newSess.ID)
-			tg.send(chatID, "🆕 Started new session in <code>"+filepath.Base(s.CWD)+"</code>")
+			tg.send(chatID, "🆕 Started new session in <code>"+FormatHTML(filepath.Base(s.CWD))+"</code>")
 		},
 		"resume": func(s *models.Session) { tg.focusSession(chatID, instanceID, s.ID) },
 		"rename": func(s *models.Session) {
@@ -903,6 +1361,12 @@ func (tg *Telegram) handleActionCallback(chatID int64, instanceID string, parts
 		"toggle_yolo":  func(s *models.Session) { tg.toggleYolo(chatID, instanceID) },
 		"show_last":    func(s *models.Session) { tg.showLastLogs(chatID, instanceID, 5) },
 		"help":         func(_ *models.Session) { tg.showHelp(chatID) },
+		"skill": func(s *models.Session) {
+			if len(parts) > 3 {
+				tg.Reg.Set(instanceID, s.ID)
+				tg.startSkill(chatID, instanceID, parts[3])
+			}
+		},
 	}
 
 	if h, ok := actionHandlers[action]; ok {
@@ -915,7 +1379,7 @@ func (tg *Telegram) showMoreActions(chatID int64, sessionID string) {
 		"reply_markup": map[string]interface{}{
 			"inline_keyboard": [][]map[string]interface{}{
 				{tgBtn("📝 Rename", "act:rename:"+sessionID), tgBtn("📦 Archive", "act:archive:"+sessionID)},
-				{tgBtn("⚠️ Toggle YOLO", "act:toggle_yolo:"+sessionID)},
+				{tgBtn("⚠️ Toggle YOLO", "act:toggle_yolo:"+sessionID), tgBtn("⚙️ Settings", settingsCallback("menu", "", sessionID))},
 				{tgBtn("📜 Show Last Logs", "act:show_last:"+sessionID)},
 			},
 		},
@@ -937,9 +1401,9 @@ func (tg *Telegram) handleRunCommandAction(chatID int64, sess *models.Session) {
 
 func (tg *Telegram) focusSession(chatID int64, instanceID, sessID string) {
 	tg.Reg.Set(instanceID, sessID)
-	info := "✅ Focused on session <code>" + sessID + "</code>"
+	info := "✅ Focused on session <code>" + FormatHTML(sessID) + "</code>"
 	if sess, err := tg.Sm.Load(sessID); err == nil && sess.Client != "" {
-		info += "\n🔧 Client: <b>" + sess.Client + "</b>"
+		info += "\n🔧 Client: <b>" + FormatHTML(sess.Client) + "</b>"
 	}
 	tg.send(chatID, info)
 }
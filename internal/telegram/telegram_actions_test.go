@@ -16,6 +16,7 @@ type mockEngine struct {
 	lastPrompt           string
 	executeCommandCalled bool
 	lastCommand          string
+	nudgeDetails         map[string]string // returned by Nudge; nil means not in intervention
 }
 
 func (m *mockEngine) ExecutePrompt(sess *models.Session, prompt string) {
@@ -33,6 +34,13 @@ func (m *mockEngine) ResolveIntervention(id, action string)             {}
 func (m *mockEngine) IsRunning(sessionID string) bool                   { return false }
 func (m *mockEngine) CancelSession(sessionID string)                    {}
 
+func (m *mockEngine) Nudge(sessionID string) (map[string]string, error) {
+	if m.nudgeDetails == nil {
+		return nil, fmt.Errorf("session is not waiting for an intervention")
+	}
+	return m.nudgeDetails, nil
+}
+
 func TestHandleActionCallback(t *testing.T) {
 	storageDir, _ := os.MkdirTemp("", "tenazas-tg-act-test-*")
 	defer os.RemoveAll(storageDir)
@@ -0,0 +1,61 @@
+package telegram
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tenazas/internal/models"
+	"tenazas/internal/registry"
+	"tenazas/internal/session"
+)
+
+func TestBudgetCommandUpdatesAndPersists(t *testing.T) {
+	storageDir := t.TempDir()
+
+	mock := &mockTgServer{}
+	ts := httptest.NewServer(mock)
+	defer ts.Close()
+	originalURL := BaseURL
+	BaseURL = ts.URL + "/bot"
+	defer func() { BaseURL = originalURL }()
+
+	sm := session.NewManager(storageDir)
+	reg, _ := registry.NewRegistry(storageDir)
+	tg := &Telegram{Sm: sm, Reg: reg}
+
+	sess := &models.Session{ID: "budget-sess", CWD: storageDir}
+	sm.Save(sess)
+	chatID := int64(7)
+	reg.Set(tg.instanceID(chatID), sess.ID)
+
+	tg.HandleMessage(chatID, "/budget")
+	tg.HandleMessage(chatID, "/budget 2.5")
+	tg.HandleMessage(chatID, "/budget -1")
+
+	loaded, err := sm.Load(sess.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.MaxBudgetUSD != 2.5 {
+		t.Errorf("expected persisted budget 2.5, got %v", loaded.MaxBudgetUSD)
+	}
+
+	tg.HandleMessage(chatID, "/budget 0")
+	loaded, _ = sm.Load(sess.ID)
+	if loaded.MaxBudgetUSD != 0 {
+		t.Errorf("expected budget reset to unlimited, got %v", loaded.MaxBudgetUSD)
+	}
+
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if len(mock.calls) != 4 {
+		t.Fatalf("expected 4 replies, got %d", len(mock.calls))
+	}
+	want := []string{"Budget: <b>unlimited", "Budget set to <b>$2.50", "Invalid budget", "Budget set to <b>unlimited"}
+	for i, w := range want {
+		if text, _ := mock.calls[i].Payload["text"].(string); !strings.Contains(text, w) {
+			t.Errorf("reply %d: expected %q in %q", i, w, text)
+		}
+	}
+}
@@ -37,6 +37,9 @@ func (m *mockEngineForCallback) ResolveIntervention(id, action string) {
 }
 func (m *mockEngineForCallback) IsRunning(sessionID string) bool { return false }
 func (m *mockEngineForCallback) CancelSession(sessionID string)  {}
+func (m *mockEngineForCallback) Nudge(sessionID string) (map[string]string, error) {
+	return nil, nil
+}
 
 func TestHandleCallback_Tokenization(t *testing.T) {
 	tmpDir, _ := os.MkdirTemp("", "tenazas-test-*")
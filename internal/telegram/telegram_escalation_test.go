@@ -0,0 +1,42 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+)
+
+func TestSendEscalationGoesToEscalationChats(t *testing.T) {
+	tg, mock, id := setupStatusDedupe(t, 0)
+	tg.EscalationChatIDs = []int64{999}
+
+	tg.sendEscalation(id, events.EscalationPayload{Node: "build", Reason: "tests <failed>", Waiting: 15 * time.Minute})
+
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if len(mock.calls) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(mock.calls))
+	}
+	call := mock.calls[0]
+	if chat, _ := call.Payload["chat_id"].(float64); int64(chat) != 999 {
+		t.Errorf("escalation sent to chat %v, want 999", call.Payload["chat_id"])
+	}
+	text, _ := call.Payload["text"].(string)
+	for _, want := range []string{"Escalation", "Dedupe", "15m0s", "<code>build</code>", "tests &lt;failed&gt;"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("escalation text %q missing %q", text, want)
+		}
+	}
+}
+
+func TestSendEscalationWithoutContactsIsSilent(t *testing.T) {
+	tg, mock, id := setupStatusDedupe(t, 0)
+
+	tg.sendEscalation(id, events.EscalationPayload{Node: "build", Waiting: time.Minute})
+
+	if n := countEdits(mock); n != 0 {
+		t.Errorf("expected no messages without escalation contacts, got %d", n)
+	}
+}
@@ -0,0 +1,113 @@
+package telegram
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+)
+
+const hostileTitle = `Fix <script> & "quotes" 🚀`
+
+var (
+	htmlTag    = regexp.MustCompile(`^<(/?)(b|i|code|pre)>`)
+	htmlEntity = regexp.MustCompile(`^&(amp|lt|gt|quot);`)
+)
+
+// assertTelegramHTML fails unless s only uses the tags Tenazas emits,
+// properly nested, and every & starts an entity.
+func assertTelegramHTML(t *testing.T, s string) {
+	t.Helper()
+	var open []string
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '<':
+			m := htmlTag.FindStringSubmatch(s[i:])
+			if m == nil {
+				t.Fatalf("unescaped '<' at %d in %q", i, s)
+			}
+			if m[1] == "" {
+				open = append(open, m[2])
+			} else if len(open) == 0 || open[len(open)-1] != m[2] {
+				t.Fatalf("unbalanced </%s> at %d in %q", m[2], i, s)
+			} else {
+				open = open[:len(open)-1]
+			}
+			i += len(m[0]) - 1
+		case '&':
+			if !htmlEntity.MatchString(s[i:]) {
+				t.Fatalf("unescaped '&' at %d in %q", i, s)
+			}
+		}
+	}
+	if len(open) > 0 {
+		t.Fatalf("unclosed tags %v in %q", open, s)
+	}
+}
+
+func lastSentText(t *testing.T, m *mockTgServer) string {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.calls) - 1; i >= 0; i-- {
+		if text, ok := m.calls[i].Payload["text"].(string); ok {
+			return text
+		}
+	}
+	t.Fatal("no message was sent")
+	return ""
+}
+
+func TestTaskStatusTextEscapesTitle(t *testing.T) {
+	tg := &Telegram{}
+	sess := &models.Session{ID: "s1", Title: hostileTitle, CWD: "/tmp/a&b"}
+	text := tg.formatTaskStatusText(sess, events.TaskStateBlocked, map[string]string{
+		"category": "no<progress>",
+		"reason":   "expected 1 < 2 && 3 > 2",
+	})
+
+	assertTelegramHTML(t, text)
+	if !strings.Contains(text, "Fix &lt;script&gt; &amp; \"quotes\" 🚀") {
+		t.Errorf("title not escaped in %q", text)
+	}
+	if !strings.Contains(text, "<code>a&amp;b</code>") {
+		t.Errorf("path not escaped in %q", text)
+	}
+}
+
+func TestRenameAndFocusEscapeUserText(t *testing.T) {
+	tg, mock, sessID := setupStatusDedupe(t, 0)
+	instanceID := tg.instanceID(123)
+
+	tg.Reg.SetPending(instanceID, "rename", sessID)
+	tg.HandleMessage(123, hostileTitle)
+	text := lastSentText(t, mock)
+	assertTelegramHTML(t, text)
+	if !strings.Contains(text, "&lt;script&gt;") || !strings.Contains(text, "🚀") {
+		t.Errorf("rename confirmation not escaped: %q", text)
+	}
+
+	sess, _ := tg.Sm.Load(sessID)
+	sess.Client = "<odd>&client"
+	tg.Sm.Save(sess)
+	tg.focusSession(123, instanceID, sessID)
+	text = lastSentText(t, mock)
+	assertTelegramHTML(t, text)
+	if !strings.Contains(text, "&lt;odd&gt;&amp;client") {
+		t.Errorf("client name not escaped: %q", text)
+	}
+}
+
+func TestUnknownCommandIsEscaped(t *testing.T) {
+	tg, mock, _ := setupStatusDedupe(t, 0)
+
+	tg.handleCommand(123, tg.instanceID(123), "/<b>oops&")
+
+	text := lastSentText(t, mock)
+	assertTelegramHTML(t, text)
+	if !strings.Contains(text, "/&lt;b&gt;oops&amp;") {
+		t.Errorf("command not escaped: %q", text)
+	}
+}
@@ -131,3 +131,64 @@ func TestGetActionKeyboard(t *testing.T) {
 		}
 	})
 }
+
+// keyboardData flattens a keyboard into rows of callback data.
+func keyboardData(markup map[string]interface{}) [][]string {
+	var rows [][]string
+	for _, row := range markup["inline_keyboard"].([][]map[string]interface{}) {
+		var data []string
+		for _, btn := range row {
+			data = append(data, btn["callback_data"].(string))
+		}
+		rows = append(rows, data)
+	}
+	return rows
+}
+
+func TestConfiguredActionKeyboard(t *testing.T) {
+	sid := "11111111-2222-3333-4444-555555555555"
+	tg := &Telegram{ActionKeyboard: [][]string{
+		{"skill:deploy", "continue"},
+		{"run"}, // no command in the response: row dropped
+		{"last"},
+	}}
+
+	got := keyboardData(tg.actionKeyboard(sid, "All done."))
+	want := [][]string{
+		{"act:skill:" + sid + ":deploy", "act:continue_prompt:" + sid},
+		{"act:show_last:" + sid},
+	}
+	gotJS, _ := json.Marshal(got)
+	wantJS, _ := json.Marshal(want)
+	if string(gotJS) != string(wantJS) {
+		t.Errorf("keyboard = %s, want %s", gotJS, wantJS)
+	}
+
+	// Without configuration the default layout applies.
+	tg.ActionKeyboard = nil
+	if rows := keyboardData(tg.actionKeyboard(sid, "")); len(rows) != 2 || rows[0][0] != "act:continue_prompt:"+sid {
+		t.Errorf("expected the default keyboard, got %v", rows)
+	}
+}
+
+func TestActionKeyboardRejectsLongCallbackData(t *testing.T) {
+	sid := "11111111-2222-3333-4444-555555555555"
+	rows := [][]string{{"skill:a-very-long-skill-name", "skill:ok"}, {"bogus"}}
+
+	got := keyboardData(buildActionKeyboard(rows, sid, ""))
+	if len(got) != 1 || len(got[0]) != 1 || got[0][0] != "act:skill:"+sid+":ok" {
+		t.Fatalf("expected only the short skill button, got %v", got)
+	}
+	for _, row := range got {
+		for _, data := range row {
+			if len(data) > maxCallbackData {
+				t.Errorf("callback data %q exceeds %d bytes", data, maxCallbackData)
+			}
+		}
+	}
+
+	problems := ValidateActionKeyboard(rows)
+	if len(problems) != 2 || !strings.Contains(problems[0], "skill:a-very-long-skill-name") || !strings.Contains(problems[1], `unknown action "bogus"`) {
+		t.Errorf("unexpected validation problems %q", problems)
+	}
+}
@@ -0,0 +1,224 @@
+package telegram
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"tenazas/internal/events"
+	"tenazas/internal/formatter"
+	"tenazas/internal/models"
+	"tenazas/internal/registry"
+	"tenazas/internal/session"
+)
+
+func makeAuditEntries(n int) []events.AuditEntry {
+	entries := make([]events.AuditEntry, n)
+	for i := range entries {
+		entries[i] = events.AuditEntry{Type: events.AuditInfo, Content: fmt.Sprintf("entry-%d", i)}
+	}
+	return entries
+}
+
+func TestPageAuditEntries(t *testing.T) {
+	entries := makeAuditEntries(12)
+
+	tests := []struct {
+		name      string
+		page      int
+		wantFirst string
+		wantLast  string
+		wantPage  int
+	}{
+		{"newest page", 0, "entry-7", "entry-11", 0},
+		{"middle page", 1, "entry-2", "entry-6", 1},
+		{"oldest partial page", 2, "entry-0", "entry-1", 2},
+		{"page past end clamps", 9, "entry-0", "entry-1", 2},
+		{"negative page clamps", -3, "entry-7", "entry-11", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, page, total := pageAuditEntries(entries, tt.page, 5)
+			if total != 3 {
+				t.Errorf("expected 3 pages, got %d", total)
+			}
+			if page != tt.wantPage {
+				t.Errorf("expected page %d, got %d", tt.wantPage, page)
+			}
+			if len(window) == 0 {
+				t.Fatal("expected a non-empty window")
+			}
+			if window[0].Content != tt.wantFirst || window[len(window)-1].Content != tt.wantLast {
+				t.Errorf("expected window %s..%s, got %s..%s", tt.wantFirst, tt.wantLast, window[0].Content, window[len(window)-1].Content)
+			}
+		})
+	}
+
+	if window, _, total := pageAuditEntries(nil, 0, 5); window != nil || total != 0 {
+		t.Errorf("expected empty result for no entries, got %v (%d pages)", window, total)
+	}
+}
+
+func TestParseLastArgs(t *testing.T) {
+	tests := []struct {
+		args       []string
+		wantN      int
+		wantFilter string
+		wantErr    bool
+	}{
+		{nil, 5, "", false},
+		{[]string{"10"}, 10, "", false},
+		{[]string{"cmd_result"}, 5, "cmd_result", false},
+		{[]string{"CMD_RESULT", "3"}, 3, "cmd_result", false},
+		{[]string{"500"}, lastMaxPerPage, "", false},
+		{[]string{"0"}, 0, "", true},
+		{[]string{"-3"}, 0, "", true},
+		{[]string{"10abc"}, 5, "10abc", false},
+		{[]string{strings.Repeat("x", lastMaxFilterLen+1)}, 0, "", true},
+	}
+	for _, tt := range tests {
+		n, filter, err := parseLastArgs(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseLastArgs(%v) err = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (n != tt.wantN || filter != tt.wantFilter) {
+			t.Errorf("parseLastArgs(%v) = (%d, %q), want (%d, %q)", tt.args, n, filter, tt.wantN, tt.wantFilter)
+		}
+	}
+	if data := lastCallbackData(99999, lastMaxPerPage, strings.Repeat("x", lastMaxFilterLen)); len(data) > maxCallbackData {
+		t.Errorf("callback data for the longest filter is %d bytes, over %d", len(data), maxCallbackData)
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	if chunks := splitMessage("short", 100); len(chunks) != 1 || chunks[0] != "short" {
+		t.Errorf("expected a single chunk, got %v", chunks)
+	}
+
+	line := strings.Repeat("a", 30) + "\n"
+	text := strings.Repeat(line, 10)
+	chunks := splitMessage(text, 100)
+	if strings.Join(chunks, "") != text {
+		t.Error("expected chunks to reassemble to the original text")
+	}
+	for _, c := range chunks {
+		if len(c) > 100 {
+			t.Errorf("chunk exceeds limit: %d", len(c))
+		}
+		if !strings.HasSuffix(c, "\n") {
+			t.Errorf("expected chunk to end on a line boundary, got %q", c)
+		}
+	}
+
+	long := strings.Repeat("b", 250)
+	chunks = splitMessage(long, 100)
+	if len(chunks) != 3 || strings.Join(chunks, "") != long {
+		t.Errorf("expected long line to be hard-cut into 3 chunks, got %d", len(chunks))
+	}
+}
+
+func TestSplitMessageKeepsHTMLIntact(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"runes", strings.Repeat("é", 120)},
+		{"entities", strings.Repeat("a &amp; b ", 40)},
+		{"tags", "<b>" + strings.Repeat("bold ", 60) + "</b> and <code>" + strings.Repeat("x", 80) + "</code>"},
+	}
+	for _, tt := range tests {
+		chunks := splitMessage(tt.text, 101)
+		if len(chunks) < 2 {
+			t.Fatalf("%s: expected several chunks, got %d", tt.name, len(chunks))
+		}
+		for _, c := range chunks {
+			if len(c) > 101 {
+				t.Errorf("%s: chunk exceeds limit: %d", tt.name, len(c))
+			}
+			if !utf8.ValidString(c) {
+				t.Errorf("%s: chunk splits a rune: %q", tt.name, c)
+			}
+			if strings.Count(c, "&") != strings.Count(c, ";") {
+				t.Errorf("%s: chunk splits an entity: %q", tt.name, c)
+			}
+			if strings.Count(c, "<b>") != strings.Count(c, "</b>") || strings.Count(c, "<code>") != strings.Count(c, "</code>") {
+				t.Errorf("%s: chunk leaves a tag open: %q", tt.name, c)
+			}
+		}
+	}
+}
+
+func TestShowLastPageFilterAndNavigation(t *testing.T) {
+	storageDir, _ := os.MkdirTemp("", "tenazas-tg-last-test-*")
+	defer os.RemoveAll(storageDir)
+
+	mock := &mockTgServer{}
+	ts := httptest.NewServer(mock)
+	defer ts.Close()
+	originalURL := BaseURL
+	BaseURL = ts.URL + "/bot"
+	defer func() { BaseURL = originalURL }()
+
+	sm := session.NewManager(storageDir)
+	reg, _ := registry.NewRegistry(storageDir)
+	tg := &Telegram{Sm: sm, Reg: reg}
+
+	sess := &models.Session{ID: "last-sess", CWD: storageDir}
+	sm.Save(sess)
+	for i := 0; i < 7; i++ {
+		sm.AppendAudit(sess, events.AuditEntry{Type: events.AuditCmdResult, Content: fmt.Sprintf("cmd-%d", i)})
+		sm.AppendAudit(sess, events.AuditEntry{Type: events.AuditInfo, Content: fmt.Sprintf("info-%d", i)})
+	}
+
+	chatID := int64(42)
+	instanceID := tg.instanceID(chatID)
+	reg.Set(instanceID, sess.ID)
+
+	tg.HandleMessage(chatID, "/last 3 cmd_result")
+
+	mock.mu.Lock()
+	if len(mock.calls) != 1 {
+		mock.mu.Unlock()
+		t.Fatalf("expected 1 call, got %d", len(mock.calls))
+	}
+	payload := mock.calls[0].Payload
+	mock.mu.Unlock()
+
+	text := payload["text"].(string)
+	if !strings.Contains(text, "page 1/3") || !strings.Contains(text, "cmd-6") || strings.Contains(text, "info-") {
+		t.Errorf("unexpected first page text: %s", text)
+	}
+	markup, ok := payload["reply_markup"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected navigation keyboard on first page")
+	}
+	row := markup["inline_keyboard"].([]interface{})[0].([]interface{})
+	if len(row) != 1 || row[0].(map[string]interface{})["callback_data"] != "last:1:3:cmd_result" {
+		t.Errorf("expected only an Older button, got %v", row)
+	}
+
+	tg.HandleCallback(chatID, "last:2:3:cmd_result")
+
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	text = mock.calls[1].Payload["text"].(string)
+	if !strings.Contains(text, "page 3/3") || !strings.Contains(text, "cmd-0") || strings.Contains(text, "cmd-1") {
+		t.Errorf("unexpected last page text: %s", text)
+	}
+}
+
+func TestFormatLastEntryUsesConfiguredTimeFormat(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 9, 30, 0, 0, time.FixedZone("X", -3*3600))
+	tg := &Telegram{TimeFormat: formatter.TimeFormat{Layout: "15:04Z07:00", UTC: true}}
+
+	out := tg.formatLastEntry(events.AuditEntry{Timestamp: ts, Type: events.AuditInfo, Content: "hi"})
+	if !strings.HasPrefix(out, "[12:30Z]") {
+		t.Errorf("expected UTC timestamp prefix, got %q", out)
+	}
+}
@@ -153,3 +153,21 @@ func TestHandleTaskCallbacks(t *testing.T) {
 		}
 	})
 }
+
+func TestFormatTaskStatusTextShowsFailureCategory(t *testing.T) {
+	tg := &Telegram{}
+	sess := &models.Session{ID: "s1", Title: "Fix build", CWD: "/tmp/proj"}
+
+	text := tg.formatTaskStatusText(sess, events.TaskStateBlocked, map[string]string{
+		"reason":   "exit 1",
+		"category": "test-failure",
+	})
+	if !strings.Contains(text, "<b>Category:</b> <code>test-failure</code>") {
+		t.Errorf("expected category line, got:\n%s", text)
+	}
+
+	text = tg.formatTaskStatusText(sess, events.TaskStateBlocked, map[string]string{"reason": "x"})
+	if strings.Contains(text, "Category") {
+		t.Errorf("expected no category line without a category, got:\n%s", text)
+	}
+}
@@ -0,0 +1,48 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"tenazas/internal/registry"
+	"tenazas/internal/session"
+)
+
+func TestNudgeCommand(t *testing.T) {
+	tg, mock := setupStreamingTest(t)
+	storageDir := t.TempDir()
+	tg.Sm = session.NewManager(storageDir)
+	tg.Reg, _ = registry.NewRegistry(storageDir)
+	eng := &mockEngine{}
+	tg.Engine = eng
+
+	sess, _ := tg.Sm.Create("/tmp", "Blocked")
+	tg.Sm.Save(sess)
+	chatID := int64(42)
+	tg.Reg.Set(fmt.Sprintf("tg-%d", chatID), sess.ID)
+
+	tg.handleCommand(chatID, tg.instanceID(chatID), "/nudge")
+	calls := streamCalls(mock)
+	if len(calls) != 1 || !strings.Contains(calls[0].Payload["text"].(string), "Nothing to nudge") {
+		t.Fatalf("expected a no-op notice, got %+v", calls)
+	}
+	if calls[0].Payload["reply_markup"] != nil {
+		t.Error("expected no intervention buttons for a session that is not blocked")
+	}
+
+	eng.nudgeDetails = map[string]string{"node": "verify", "reason": "tests <failed>", "last_output": "FAIL"}
+	tg.handleCommand(chatID, tg.instanceID(chatID), "/nudge")
+	calls = streamCalls(mock)
+	last := calls[len(calls)-1]
+	text := last.Payload["text"].(string)
+	for _, want := range []string{"Intervention Required", "<code>verify</code>", "tests &lt;failed&gt;", "<pre>FAIL</pre>"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("nudge message missing %q: %s", want, text)
+		}
+	}
+	markup := fmt.Sprint(last.Payload["reply_markup"])
+	if !strings.Contains(markup, "intv:retry:"+sess.ID) || !strings.Contains(markup, "intv:abort:"+sess.ID) {
+		t.Errorf("expected intervention buttons, got %s", markup)
+	}
+}
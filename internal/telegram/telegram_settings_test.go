@@ -0,0 +1,119 @@
+package telegram
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"tenazas/internal/models"
+	"tenazas/internal/registry"
+	"tenazas/internal/session"
+)
+
+func TestSettingsCallbackRoundTrip(t *testing.T) {
+	const sid = "0f8fad5b-d9cb-469f-a165-70867728950e"
+	tests := []struct {
+		kind, value, sessionID string
+		data                   string
+	}{
+		{"menu", "", "", "set:menu:"},
+		{"menu", "", sid, "set:menu:" + sid},
+		{"tier", "medium", sid, "set:tier:medium:" + sid},
+		{"mode", models.ApprovalModeAutoEdit, sid, "set:mode:AUTO_EDIT:" + sid},
+	}
+	for _, tt := range tests {
+		data := settingsCallback(tt.kind, tt.value, tt.sessionID)
+		if data != tt.data {
+			t.Errorf("settingsCallback(%q, %q, %q) = %q, want %q", tt.kind, tt.value, tt.sessionID, data, tt.data)
+		}
+		if len(data) > 64 {
+			t.Errorf("%q exceeds Telegram's 64-byte callback_data limit", data)
+		}
+		kind, value, sessionID, ok := parseSettingsCallback(strings.Split(data, ":"))
+		if !ok || kind != tt.kind || value != tt.value || sessionID != tt.sessionID {
+			t.Errorf("parseSettingsCallback(%q) = %q, %q, %q, %v", data, kind, value, sessionID, ok)
+		}
+	}
+
+	for _, bad := range []string{"set", "set:tier::" + sid, "set:tier:high", "set:bogus:x:" + sid} {
+		if _, _, _, ok := parseSettingsCallback(strings.Split(bad, ":")); ok {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestAvailableTiersFollowClientModels(t *testing.T) {
+	tg := &Telegram{
+		DefaultClient: "gemini",
+		ClientModels: map[string]map[string]string{
+			"gemini": {"low": "flash", "high": "pro", "max": "ultra"},
+		},
+	}
+	if got, want := tg.availableTiers(""), []string{"high", "low", "max"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("availableTiers(default) = %v, want %v", got, want)
+	}
+	if got := tg.availableTiers("claude-code"); !reflect.DeepEqual(got, defaultTiers) {
+		t.Errorf("availableTiers(unconfigured) = %v, want %v", got, defaultTiers)
+	}
+}
+
+func TestSettingsButtonsUpdateSession(t *testing.T) {
+	storageDir := t.TempDir()
+	mock := &mockTgServer{}
+	ts := httptest.NewServer(mock)
+	defer ts.Close()
+	originalURL := BaseURL
+	BaseURL = ts.URL + "/bot"
+	defer func() { BaseURL = originalURL }()
+
+	sm := session.NewManager(storageDir)
+	reg, _ := registry.NewRegistry(storageDir)
+	tg := &Telegram{Sm: sm, Reg: reg, DefaultClient: "gemini"}
+
+	sess := &models.Session{ID: "settings-sess", CWD: storageDir, Client: "gemini", ModelTier: "high", ApprovalMode: models.ApprovalModePlan}
+	sm.Save(sess)
+	chatID := int64(11)
+	reg.Set(tg.instanceID(chatID), sess.ID)
+
+	tg.HandleCallback(chatID, "set:menu:")
+	tg.HandleCallback(chatID, settingsCallback("tier", "low", sess.ID))
+	tg.HandleCallback(chatID, settingsCallback("mode", models.ApprovalModeYolo, sess.ID))
+	tg.HandleCallback(chatID, settingsCallback("tier", "turbo", sess.ID))
+
+	loaded, err := sm.Load(sess.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ModelTier != "low" {
+		t.Errorf("ModelTier = %q, want low", loaded.ModelTier)
+	}
+	if loaded.ApprovalMode != models.ApprovalModeYolo || !loaded.Yolo {
+		t.Errorf("expected yolo mode, got %q (yolo=%v)", loaded.ApprovalMode, loaded.Yolo)
+	}
+
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if len(mock.calls) != 4 {
+		t.Fatalf("expected 4 replies, got %d", len(mock.calls))
+	}
+	menu, _ := mock.calls[0].Payload["text"].(string)
+	if !strings.Contains(menu, "Model tier: <b>high") || !strings.Contains(menu, "Approval mode: <b>plan") {
+		t.Errorf("unexpected settings menu: %q", menu)
+	}
+	markup, _ := mock.calls[0].Payload["reply_markup"].(map[string]interface{})
+	rows, _ := markup["inline_keyboard"].([]interface{})
+	if len(rows) != 2 {
+		t.Fatalf("expected tier and mode rows, got %v", markup)
+	}
+	first, _ := rows[0].([]interface{})[0].(map[string]interface{})
+	if first["text"] != "✅ high" || first["callback_data"] != "set:tier:high:"+sess.ID {
+		t.Errorf("unexpected first tier button: %v", first)
+	}
+	want := []string{"Model tier set to <b>low", "Approval mode set to <b>yolo", "not available"}
+	for i, w := range want {
+		if text, _ := mock.calls[i+1].Payload["text"].(string); !strings.Contains(text, w) {
+			t.Errorf("reply %d: expected %q in %q", i+1, w, text)
+		}
+	}
+}
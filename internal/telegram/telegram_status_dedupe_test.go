@@ -0,0 +1,85 @@
+package telegram
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tenazas/internal/events"
+	"tenazas/internal/registry"
+	"tenazas/internal/session"
+)
+
+func setupStatusDedupe(t *testing.T, debounce time.Duration) (*Telegram, *mockTgServer, string) {
+	t.Helper()
+	mock := &mockTgServer{}
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+	oldBaseURL := BaseURL
+	BaseURL = server.URL + "/bot"
+	t.Cleanup(func() { BaseURL = oldBaseURL })
+
+	storageDir := t.TempDir()
+	sm := session.NewManager(storageDir)
+	reg, _ := registry.NewRegistry(storageDir)
+	tg := &Telegram{Token: "mock-token", Sm: sm, Reg: reg, AllowedIDs: []int64{123}, StatusDebounce: debounce}
+
+	sess, _ := sm.Create(storageDir, "Dedupe")
+	sess.MonitoringChatID = 123
+	sess.MonitoringMessageID = 777
+	sm.Save(sess)
+	return tg, mock, sess.ID
+}
+
+func countEdits(m *mockTgServer) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, c := range m.calls {
+		if c.Method == "editMessageText" || c.Method == "sendMessage" {
+			n++
+		}
+	}
+	return n
+}
+
+func TestNotifyTaskStateSkipsIdenticalNotifications(t *testing.T) {
+	tg, mock, id := setupStatusDedupe(t, 0)
+
+	tg.NotifyTaskState(id, events.TaskStateBlocked, map[string]string{"reason": "tests failed"})
+	tg.NotifyTaskState(id, events.TaskStateBlocked, map[string]string{"reason": "tests failed"})
+	if n := countEdits(mock); n != 1 {
+		t.Fatalf("expected 1 edit for identical notifications, got %d", n)
+	}
+
+	tg.NotifyTaskState(id, events.TaskStateBlocked, map[string]string{"reason": "build failed"})
+	if n := countEdits(mock); n != 2 {
+		t.Fatalf("expected a new edit when details change, got %d", n)
+	}
+	tg.NotifyTaskState(id, events.TaskStateStarted, map[string]string{"reason": "build failed"})
+	if n := countEdits(mock); n != 3 {
+		t.Fatalf("expected a new edit when the state changes, got %d", n)
+	}
+}
+
+func TestNotifyTaskStateDebouncesBursts(t *testing.T) {
+	tg, mock, id := setupStatusDedupe(t, 100*time.Millisecond)
+
+	tg.NotifyTaskState(id, events.TaskStateStarted, nil)
+	tg.NotifyTaskState(id, events.TaskStateBlocked, nil)
+	tg.NotifyTaskState(id, events.TaskStateStarted, map[string]string{"node": "build"})
+	if n := countEdits(mock); n != 1 {
+		t.Fatalf("expected the burst to be held back, got %d edits", n)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if n := countEdits(mock); n != 2 {
+		t.Fatalf("expected one trailing edit after the window, got %d", n)
+	}
+	mock.mu.Lock()
+	last := mock.calls[len(mock.calls)-1]
+	mock.mu.Unlock()
+	if text, _ := last.Payload["text"].(string); text == "" {
+		t.Errorf("expected the trailing edit to carry text")
+	}
+}
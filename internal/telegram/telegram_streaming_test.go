@@ -0,0 +1,187 @@
+package telegram
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tenazas/internal/formatter"
+)
+
+func setupStreamingTest(t *testing.T) (*Telegram, *mockTgServer) {
+	t.Helper()
+	mock := &mockTgServer{}
+	ts := httptest.NewServer(mock)
+	t.Cleanup(ts.Close)
+	originalURL := BaseURL
+	BaseURL = ts.URL + "/bot"
+	t.Cleanup(func() { BaseURL = originalURL })
+	return &Telegram{activeMessages: make(map[string]*tgLiveStream)}, mock
+}
+
+func streamCalls(mock *mockTgServer) []mockCall {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	return append([]mockCall(nil), mock.calls...)
+}
+
+func TestStreamingShortResponseReplacesPlaceholder(t *testing.T) {
+	tg, mock := setupStreamingTest(t)
+	f := &formatter.HtmlFormatter{}
+
+	// Well within the update interval: no intermediate edit happens.
+	tg.handleStreamingChunk(1, "s", "Hi", f)
+	tg.handleStreamingChunk(1, "s", " there", f)
+	tg.handleStreamingEnd(1, "s", "Hi there", f)
+
+	calls := streamCalls(mock)
+	if len(calls) != 2 {
+		t.Fatalf("expected placeholder + final edit, got %d calls", len(calls))
+	}
+	if calls[0].Method != "sendMessage" || calls[0].Payload["text"] != "<i>...</i>" {
+		t.Errorf("expected the placeholder first, got %s %v", calls[0].Method, calls[0].Payload["text"])
+	}
+	if calls[1].Method != "editMessageText" || calls[1].Payload["text"] != "🟢 <b>RESPONSE:</b>\nHi there" {
+		t.Errorf("expected the placeholder to be edited to the full text, got %s %v", calls[1].Method, calls[1].Payload["text"])
+	}
+}
+
+func TestStreamingEndFallsBackToStreamedText(t *testing.T) {
+	tg, mock := setupStreamingTest(t)
+	f := &formatter.HtmlFormatter{}
+
+	tg.handleStreamingChunk(1, "s", "only streamed", f)
+	tg.handleStreamingEnd(1, "s", "", f)
+
+	calls := streamCalls(mock)
+	if last := calls[len(calls)-1]; last.Payload["text"] != "🟢 <b>RESPONSE:</b>\nonly streamed" {
+		t.Errorf("expected the streamed text as the final message, got %v", last.Payload["text"])
+	}
+}
+
+func TestStreamingLongResponseCoalescesEdits(t *testing.T) {
+	tg, mock := setupStreamingTest(t)
+	f := &formatter.HtmlFormatter{}
+	key := tg.streamKey(1, "s")
+	expire := func() {
+		tg.mu.Lock()
+		tg.activeMessages[key].lastEdit = time.Now().Add(-time.Hour)
+		tg.mu.Unlock()
+	}
+
+	tg.handleStreamingChunk(1, "s", strings.Repeat("a", 40), f)
+	expire()
+	tg.handleStreamingChunk(1, "s", strings.Repeat("b", 40), f) // interval passed, big delta: edit
+	expire()
+	tg.handleStreamingChunk(1, "s", "c", f) // interval passed, tiny delta: no edit
+
+	edits := 0
+	for _, c := range streamCalls(mock) {
+		if c.Method == "editMessageText" {
+			edits++
+			if c.Payload["text"] != strings.Repeat("a", 40)+strings.Repeat("b", 40) {
+				t.Errorf("unexpected intermediate edit %v", c.Payload["text"])
+			}
+		}
+	}
+	if edits != 1 {
+		t.Fatalf("expected exactly one intermediate edit, got %d", edits)
+	}
+
+	full := strings.Repeat("a", 40) + strings.Repeat("b", 40) + "c"
+	tg.handleStreamingEnd(1, "s", full, f)
+	calls := streamCalls(mock)
+	if last := calls[len(calls)-1]; last.Method != "editMessageText" || last.Payload["text"] != "🟢 <b>RESPONSE:</b>\n"+full {
+		t.Errorf("expected the final edit to carry the full text, got %s %v", last.Method, last.Payload["text"])
+	}
+	if _, ok := tg.activeMessages[key]; ok {
+		t.Error("expected the live stream to be cleared")
+	}
+}
+
+func countStreamEdits(mock *mockTgServer) int {
+	edits := 0
+	for _, c := range streamCalls(mock) {
+		if c.Method == "editMessageText" {
+			edits++
+		}
+	}
+	return edits
+}
+
+func TestStreamingDeltaGate(t *testing.T) {
+	tg, mock := setupStreamingTest(t)
+	tg.StreamMinDelta = 5
+	f := &formatter.HtmlFormatter{}
+	key := tg.streamKey(1, "s")
+	expire := func() {
+		tg.mu.Lock()
+		tg.activeMessages[key].lastEdit = time.Now().Add(-time.Hour)
+		tg.mu.Unlock()
+	}
+
+	tg.handleStreamingChunk(1, "s", "ab", f)
+	expire()
+	tg.handleStreamingChunk(1, "s", "çd", f) // 4 characters (5 bytes) since the last edit: skipped
+	if n := countStreamEdits(mock); n != 0 {
+		t.Fatalf("a delta below stream_min_delta should not edit, got %d edits", n)
+	}
+	expire()
+	tg.handleStreamingChunk(1, "s", "e", f)
+	if n := countStreamEdits(mock); n != 1 {
+		t.Fatalf("expected an edit once the delta reached 5 characters, got %d", n)
+	}
+}
+
+func TestStreamingTimeGate(t *testing.T) {
+	tg, mock := setupStreamingTest(t)
+	tg.UpdateInterval = 5000
+	f := &formatter.HtmlFormatter{}
+	key := tg.streamKey(1, "s")
+	setLastEdit := func(ago time.Duration) {
+		tg.mu.Lock()
+		tg.activeMessages[key].lastEdit = time.Now().Add(-ago)
+		tg.mu.Unlock()
+	}
+
+	tg.handleStreamingChunk(1, "s", strings.Repeat("a", 100), f)
+	setLastEdit(2 * time.Second) // past the 1s floor but within update_interval
+	tg.handleStreamingChunk(1, "s", strings.Repeat("b", 100), f)
+	if n := countStreamEdits(mock); n != 0 {
+		t.Fatalf("an edit inside update_interval should wait, got %d edits", n)
+	}
+	setLastEdit(6 * time.Second)
+	tg.handleStreamingChunk(1, "s", "c", f)
+	if n := countStreamEdits(mock); n != 1 {
+		t.Fatalf("expected one edit after update_interval, got %d", n)
+	}
+
+	// A 429 holds edits back for retry_after, whatever the interval.
+	stream := &tgLiveStream{fullText: strings.Repeat("x", 100), holdUntil: time.Now().Add(time.Minute)}
+	if tg.streamEditDue(stream, time.Now()) {
+		t.Error("edits should wait out Telegram's retry_after")
+	}
+}
+
+func TestStreamingFinalOnly(t *testing.T) {
+	tg, mock := setupStreamingTest(t)
+	tg.StreamFinalOnly = true
+	f := &formatter.HtmlFormatter{}
+
+	tg.handleStreamingChunk(1, "s", strings.Repeat("a", 100), f)
+	tg.mu.Lock()
+	tg.activeMessages[tg.streamKey(1, "s")].lastEdit = time.Now().Add(-time.Hour)
+	tg.mu.Unlock()
+	tg.handleStreamingChunk(1, "s", strings.Repeat("b", 100), f)
+	if calls := streamCalls(mock); len(calls) != 0 {
+		t.Fatalf("final-only mode should not post while streaming, got %d calls", len(calls))
+	}
+
+	tg.handleStreamingEnd(1, "s", "", f)
+	calls := streamCalls(mock)
+	want := "🟢 <b>RESPONSE:</b>\n" + strings.Repeat("a", 100) + strings.Repeat("b", 100)
+	if len(calls) != 1 || calls[0].Method != "sendMessage" || calls[0].Payload["text"] != want {
+		t.Fatalf("expected one final message with the whole response, got %+v", calls)
+	}
+}
@@ -0,0 +1,50 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSafeTruncateHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		limit int
+		want  string
+	}{
+		{"short text is kept", "<b>hi</b>", 20, "<b>hi</b>"},
+		{"cut mid-tag backs off before it", "abcdef<b>bold</b>", 10, "abcdef…"},
+		{"open tags are closed", "<pre>0123456789</pre>", 20, "<pre>012345…</pre>"},
+		{"nested tags close in order", "<b><i>0123456789</i></b>", 20, "<b><i>012…</i></b>"},
+		{"cut mid-entity backs off", "ab&amp;cdef", 6, "ab…"},
+		{"cut mid-rune backs off", "añbcdef", 5, "a…"},
+		{"attributes are not kept in the closer", `<a href="x">link text here</a>`, 26, `<a href="x">link te…</a>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := safeTruncateHTML(tt.in, tt.limit, "…")
+			if got != tt.want {
+				t.Errorf("safeTruncateHTML(%q, %d) = %q, want %q", tt.in, tt.limit, got, tt.want)
+			}
+			if len(got) > tt.limit {
+				t.Errorf("result is %d bytes, over the %d limit", len(got), tt.limit)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("result %q is not valid UTF-8", got)
+			}
+		})
+	}
+}
+
+func TestTruncateMessageUsesConfig(t *testing.T) {
+	tg := &Telegram{MaxMessageChars: 14, TruncationMarker: "[cut]"}
+	if got := tg.truncateMessage("<i>" + strings.Repeat("x", 20) + "</i>"); got != "<i>xx[cut]</i>" {
+		t.Errorf("truncateMessage = %q", got)
+	}
+
+	tg = &Telegram{PreviewChars: 3}
+	if got := tg.preview("ñandú"); got != "ñan..." {
+		t.Errorf("preview = %q, want rune-based cut with the default marker", got)
+	}
+}
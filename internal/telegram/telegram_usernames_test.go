@@ -0,0 +1,89 @@
+package telegram
+
+import (
+	"encoding/json"
+	"testing"
+
+	"tenazas/internal/session"
+)
+
+func updateFrom(t *testing.T, id int64, username string) TgUpdate {
+	t.Helper()
+	raw, _ := json.Marshal(map[string]interface{}{
+		"update_id": 1,
+		"message": map[string]interface{}{
+			"from": map[string]interface{}{"id": id, "username": username},
+			"text": "hi",
+		},
+	})
+	var upd TgUpdate
+	if err := json.Unmarshal(raw, &upd); err != nil {
+		t.Fatal(err)
+	}
+	return upd
+}
+
+func TestAllowByUsername(t *testing.T) {
+	storageDir := t.TempDir()
+	tg := &Telegram{Sm: session.NewManager(storageDir), AllowedIDs: []int64{123}, AllowedUsernames: []string{"@Alice"}}
+
+	if tg.IsAllowed(555) {
+		t.Fatal("an unresolved username must not allow anyone yet")
+	}
+	if id, ok := tg.admit(updateFrom(t, 555, "alice")); !ok || id != 555 {
+		t.Fatalf("admit(alice) = %d, %v; want 555 allowed", id, ok)
+	}
+	if !tg.IsAllowed(555) {
+		t.Error("IsAllowed should accept the ID resolved from @alice")
+	}
+	if !tg.IsAllowed(123) {
+		t.Error("numeric IDs must keep working")
+	}
+	if ids := tg.AllowedChatIDs(); len(ids) != 2 || ids[0] != 123 || ids[1] != 555 {
+		t.Errorf("AllowedChatIDs = %v, want [123 555]", ids)
+	}
+
+	restarted := &Telegram{Sm: session.NewManager(storageDir), AllowedUsernames: []string{"alice"}}
+	if !restarted.IsAllowed(555) {
+		t.Error("the resolved ID should be read back from the stored mapping")
+	}
+}
+
+func TestRenameRevokesUsernameAccess(t *testing.T) {
+	storageDir := t.TempDir()
+	tg := &Telegram{Sm: session.NewManager(storageDir), AllowedIDs: []int64{123}, AllowedUsernames: []string{"alice"}}
+
+	if _, ok := tg.admit(updateFrom(t, 555, "alice")); !ok {
+		t.Fatal("admit(alice) should allow 555")
+	}
+	if id, ok := tg.admit(updateFrom(t, 555, "mallory")); ok {
+		t.Fatalf("admit allowed %d after it was renamed away from alice", id)
+	}
+	if tg.IsAllowed(555) {
+		t.Error("a renamed account must lose access it only had by username")
+	}
+	if ids := tg.AllowedChatIDs(); len(ids) != 1 || ids[0] != 123 {
+		t.Errorf("AllowedChatIDs = %v, want only the numeric ID", ids)
+	}
+
+	restarted := &Telegram{Sm: session.NewManager(storageDir), AllowedUsernames: []string{"alice"}}
+	if restarted.IsAllowed(555) {
+		t.Error("the revocation should be stored")
+	}
+}
+
+func TestRejectUnknownUsername(t *testing.T) {
+	tg := &Telegram{Sm: session.NewManager(t.TempDir()), AllowedIDs: []int64{123}, AllowedUsernames: []string{"alice"}}
+
+	for _, upd := range []TgUpdate{updateFrom(t, 666, "mallory"), updateFrom(t, 777, "")} {
+		if id, ok := tg.admit(upd); ok {
+			t.Errorf("admit allowed %d", id)
+		}
+	}
+	if tg.IsAllowed(666) || tg.IsAllowed(777) {
+		t.Error("unknown usernames must stay rejected")
+	}
+	if ids := tg.AllowedChatIDs(); len(ids) != 1 {
+		t.Errorf("AllowedChatIDs = %v, want only the numeric ID", ids)
+	}
+}
@@ -0,0 +1,159 @@
+package telegram
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// defaultMaxMessageChars keeps cut messages a little under
+	// tgMaxMessageLen.
+	defaultMaxMessageChars  = 4000
+	defaultTruncationMarker = "..."
+)
+
+// safeTruncateHTML shortens Telegram HTML s to at most limit bytes, ending
+// with marker. The cut never splits a rune, a tag or an entity, and tags
+// left open at the cut are closed after the marker, so Telegram can still
+// parse the result.
+func safeTruncateHTML(s string, limit int, marker string) string {
+	if len(s) <= limit {
+		return s
+	}
+	budget := limit - len(marker)
+	var open []string
+	cut, closers := 0, ""
+	for i := 0; i < len(s) && i <= budget; {
+		if c := closingTags(open); i+len(c) <= budget {
+			cut, closers = i, c
+		}
+		switch s[i] {
+		case '<':
+			end := strings.IndexByte(s[i:], '>')
+			if end < 0 {
+				i = len(s)
+				continue
+			}
+			open = trackTag(open, s[i+1:i+end])
+			i += end + 1
+		case '&':
+			if end := strings.IndexByte(s[i:], ';'); end > 0 && end <= 10 {
+				i += end + 1
+			} else {
+				i++
+			}
+		default:
+			_, size := utf8.DecodeRuneInString(s[i:])
+			i += size
+		}
+	}
+	return s[:cut] + marker + closers
+}
+
+// cutHTML splits Telegram HTML s, which is longer than limit bytes, at the
+// last point where the head, with the tags still open there closed, fits in
+// limit. Like safeTruncateHTML it never cuts inside a rune, tag or entity.
+// The tail starts by reopening the closed tags. When nothing fits, s is
+// returned whole.
+func cutHTML(s string, limit int) (head, tail string) {
+	var open, raw []string
+	cut, closers, reopen := 0, "", ""
+	for i := 0; i < len(s) && i <= limit; {
+		// Cutting where only reopened tags precede would make no progress.
+		if c, r := closingTags(open), strings.Join(raw, ""); i > len(r) && i+len(c) <= limit {
+			cut, closers, reopen = i, c, r
+		}
+		switch s[i] {
+		case '<':
+			end := strings.IndexByte(s[i:], '>')
+			if end < 0 {
+				i = len(s)
+				continue
+			}
+			open = trackTag(open, s[i+1:i+end])
+			if len(open) > len(raw) {
+				raw = append(raw, s[i:i+end+1])
+			} else {
+				raw = raw[:len(open)]
+			}
+			i += end + 1
+		case '&':
+			if end := strings.IndexByte(s[i:], ';'); end > 0 && end <= 10 {
+				i += end + 1
+			} else {
+				i++
+			}
+		default:
+			_, size := utf8.DecodeRuneInString(s[i:])
+			i += size
+		}
+	}
+	if cut == 0 {
+		return s, ""
+	}
+	return s[:cut] + closers, reopen + s[cut:]
+}
+
+// trackTag updates the stack of open tag names with the tag whose inside
+// (between "<" and ">") is tag.
+func trackTag(open []string, tag string) []string {
+	if strings.HasPrefix(tag, "/") {
+		name := tagName(tag[1:])
+		for i := len(open) - 1; i >= 0; i-- {
+			if open[i] == name {
+				return open[:i]
+			}
+		}
+		return open
+	}
+	if strings.HasSuffix(tag, "/") {
+		return open
+	}
+	return append(open, tagName(tag))
+}
+
+func tagName(tag string) string {
+	if i := strings.IndexAny(tag, " \t\n"); i >= 0 {
+		tag = tag[:i]
+	}
+	return strings.ToLower(tag)
+}
+
+func closingTags(open []string) string {
+	var sb strings.Builder
+	for i := len(open) - 1; i >= 0; i-- {
+		sb.WriteString("</" + open[i] + ">")
+	}
+	return sb.String()
+}
+
+// truncateMessage applies safeTruncateHTML with the configured message limit
+// and marker.
+func (tg *Telegram) truncateMessage(text string) string {
+	limit := tg.MaxMessageChars
+	if limit <= 0 || limit > tgMaxMessageLen {
+		limit = defaultMaxMessageChars
+	}
+	return safeTruncateHTML(text, limit, html.EscapeString(tg.truncationMarker()))
+}
+
+func (tg *Telegram) truncationMarker() string {
+	if tg.TruncationMarker == "" {
+		return defaultTruncationMarker
+	}
+	return tg.TruncationMarker
+}
+
+// preview shortens plain text to the configured preview length in runes,
+// ending with the truncation marker.
+func (tg *Telegram) preview(text string) string {
+	n := tg.PreviewChars
+	if n <= 0 {
+		n = lastPreviewLen
+	}
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	return string([]rune(text)[:n]) + tg.truncationMarker()
+}
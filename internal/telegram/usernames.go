@@ -0,0 +1,126 @@
+package telegram
+
+import (
+	"strings"
+
+	"tenazas/internal/storage"
+)
+
+// UsernamesFileName stores the user IDs learned for AllowedUsernames, so
+// broadcasts reach those users after a restart before they write again.
+const UsernamesFileName = "telegram_users.json"
+
+// normalizeUsername lowercases name and drops a leading "@"; Telegram
+// usernames are case-insensitive.
+func normalizeUsername(name string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "@"))
+}
+
+// usernameAllowed reports whether username is one of AllowedUsernames.
+func (tg *Telegram) usernameAllowed(username string) bool {
+	username = normalizeUsername(username)
+	if username == "" {
+		return false
+	}
+	for _, name := range tg.AllowedUsernames {
+		if normalizeUsername(name) == username {
+			return true
+		}
+	}
+	return false
+}
+
+// loadUsernamesLocked reads the stored username mapping once. Callers hold
+// tg.usersMu.
+func (tg *Telegram) loadUsernamesLocked() {
+	if tg.resolved != nil {
+		return
+	}
+	tg.resolved = make(map[string]int64)
+	if tg.Sm != nil {
+		storage.NewStorage(tg.Sm.StoragePath).ReadJSON(UsernamesFileName, &tg.resolved)
+	}
+}
+
+// resolveSender checks the sender of every update against the stored
+// mapping: it records the ID behind an allowed username the first time an
+// update from it arrives (or when the username moves to another account),
+// and forgets usernames id was resolved from but no longer carries, so an
+// account admitted only by name loses access once it is renamed.
+func (tg *Telegram) resolveSender(id int64, username string) {
+	if id == 0 || len(tg.AllowedUsernames) == 0 {
+		return
+	}
+	username = normalizeUsername(username)
+	tg.usersMu.Lock()
+	defer tg.usersMu.Unlock()
+	tg.loadUsernamesLocked()
+	changed := false
+	for name, known := range tg.resolved {
+		if known == id && name != username {
+			delete(tg.resolved, name)
+			changed = true
+		}
+	}
+	if tg.usernameAllowed(username) && tg.resolved[username] != id {
+		tg.resolved[username] = id
+		changed = true
+	}
+	if changed && tg.Sm != nil {
+		storage.NewStorage(tg.Sm.StoragePath).WriteJSON(UsernamesFileName, tg.resolved)
+	}
+}
+
+// admit returns the sender of upd and whether they may use the bot,
+// resolving an allowed username on the way.
+func (tg *Telegram) admit(upd TgUpdate) (int64, bool) {
+	fromID, username := upd.Message.From.ID, upd.Message.From.Username
+	if fromID == 0 {
+		fromID, username = upd.CallbackQuery.From.ID, upd.CallbackQuery.From.Username
+	}
+	tg.resolveSender(fromID, username)
+	return fromID, tg.IsAllowed(fromID)
+}
+
+// resolvedIDs returns the IDs learned for AllowedUsernames that are still
+// allowed by name.
+func (tg *Telegram) resolvedIDs() []int64 {
+	if len(tg.AllowedUsernames) == 0 {
+		return nil
+	}
+	tg.usersMu.Lock()
+	defer tg.usersMu.Unlock()
+	tg.loadUsernamesLocked()
+	var ids []int64
+	for name, id := range tg.resolved {
+		if tg.usernameAllowed(name) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// allowedIDs returns AllowedIDs plus the IDs resolved from AllowedUsernames,
+// without duplicates: everyone who receives broadcasts.
+func (tg *Telegram) allowedIDs() []int64 {
+	resolved := tg.resolvedIDs()
+	if len(resolved) == 0 {
+		return tg.AllowedIDs
+	}
+	ids := append([]int64(nil), tg.AllowedIDs...)
+	for _, id := range resolved {
+		if !containsID(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
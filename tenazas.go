@@ -0,0 +1,177 @@
+// Package tenazas is the public API for embedding the Tenazas engine in other
+// Go programs. It wraps the wiring cmd/tenazas does for the CLI and daemon —
+// session storage, client construction and engine settings — behind a
+// Runtime that runs skills synchronously and streams their audit events.
+//
+// The types below are aliases of the internal ones, so values move freely
+// between this package and a Runtime's Sessions and Engine.
+package tenazas
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tenazas/internal/client"
+	"tenazas/internal/config"
+	"tenazas/internal/engine"
+	"tenazas/internal/events"
+	"tenazas/internal/models"
+	"tenazas/internal/session"
+)
+
+type (
+	Config         = config.Config
+	ClientConfig   = config.ClientConfig
+	Client         = client.Client
+	Session        = models.Session
+	SkillGraph     = models.SkillGraph
+	SessionManager = session.Manager
+	Engine         = engine.Engine
+	Event          = events.Event
+	AuditEntry     = events.AuditEntry
+)
+
+// Final session statuses reported in Result.Status.
+const (
+	StatusCompleted    = models.StatusCompleted
+	StatusFailed       = models.StatusFailed
+	StatusIntervention = models.StatusIntervention
+)
+
+// abortPoll is how often RunSkill re-sends the cancellation of a run whose
+// context is done, until the run returns.
+const abortPoll = 50 * time.Millisecond
+
+// LoadConfig reads the config file and environment overrides, the same way
+// the tenazas binary does.
+func LoadConfig() (*Config, error) {
+	return config.Load()
+}
+
+// RegisterClient makes a client implementation available by name to
+// NewClient and to Config.Clients.
+func RegisterClient(name string, ctor func(binPath, logPath string) Client) {
+	client.Register(name, ctor)
+}
+
+// NewClient builds a registered client; logPath receives its debug log.
+func NewClient(name, binPath, logPath string) (Client, error) {
+	return client.NewClient(name, binPath, logPath)
+}
+
+// NewSessionManager returns a session store rooted at storageDir.
+func NewSessionManager(storageDir string) *SessionManager {
+	return session.NewManager(storageDir)
+}
+
+// Runtime is an engine wired to its session store and clients.
+type Runtime struct {
+	Sessions *SessionManager
+	Engine   *Engine
+	cfg      *Config
+}
+
+// Result describes a finished skill run. A run that fails or is left
+// waiting for intervention is reported here, not as an error.
+type Result struct {
+	SessionID string
+	Status    string // StatusCompleted, StatusFailed or StatusIntervention
+	Response  string // the last LLM response of the run, if any
+	Session   *Session
+}
+
+// New builds a Runtime from cfg: one client per cfg.Clients entry (logging
+// to <storage_dir>/tenazas.log) and an engine with cfg's limits.
+func New(cfg *Config) (*Runtime, error) {
+	sm := session.NewManager(cfg.StorageDir)
+	sm.Storage.IncludePaths = cfg.InstructionPaths
+	eng, err := engine.NewFromConfig(sm, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Runtime{Sessions: sm, Engine: eng, cfg: cfg}, nil
+}
+
+// RunSkill runs skillName (a project skill in cwd, or a global one) in a new
+// session anchored to cwd and blocks until it finishes. Like `tenazas run`,
+// the session is in yolo mode since nobody is there to approve tool calls.
+// When ctx is done the run is cancelled and ctx.Err() is returned with the
+// partial result.
+func (r *Runtime) RunSkill(ctx context.Context, skillName, cwd string) (Result, error) {
+	sk, err := r.Sessions.LoadSkillFor(cwd, skillName)
+	if err != nil {
+		return Result{}, fmt.Errorf("load skill %q: %w", skillName, err)
+	}
+	if err := r.Engine.CheckCWD(r.cfg.DefaultClient, cwd); err != nil {
+		return Result{}, err
+	}
+	sess, err := r.Sessions.Create(cwd, "run: "+skillName)
+	if err != nil {
+		return Result{}, fmt.Errorf("create session: %w", err)
+	}
+	r.Sessions.Update(sess, func(s *models.Session) {
+		s.Client = r.cfg.DefaultClient
+		s.SkillName = skillName
+		s.Yolo = true
+		if r.cfg.DefaultModelTier != "" {
+			s.ModelTier = r.cfg.DefaultModelTier
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Engine.Run(sk, sess)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		r.abort(sess.ID, done)
+	}
+
+	return r.result(sess), ctx.Err()
+}
+
+// abort cancels a run and unblocks a pending intervention, retrying until
+// the run returns since the engine may not have registered either yet.
+func (r *Runtime) abort(sessionID string, done chan struct{}) {
+	ticker := time.NewTicker(abortPoll)
+	defer ticker.Stop()
+	for {
+		r.Engine.CancelSession(sessionID)
+		r.Engine.ResolveIntervention(sessionID, "abort")
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runtime) result(sess *models.Session) Result {
+	if loaded, err := r.Sessions.Load(sess.ID); err == nil {
+		sess = loaded
+	}
+	res := Result{SessionID: sess.ID, Status: sess.Status, Session: sess}
+	responses, _ := r.Sessions.FilterAudit(sess, func(e events.AuditEntry) bool { return e.Type == events.AuditLLMResponse })
+	if len(responses) > 0 {
+		res.Response = responses[len(responses)-1].Content
+	}
+	return res
+}
+
+// Subscribe streams every event the engine publishes, for all sessions. Call
+// the returned function to stop; the channel is then closed.
+func (r *Runtime) Subscribe() (<-chan Event, func()) {
+	ch := events.GlobalBus.Subscribe()
+	return ch, func() { events.GlobalBus.Unsubscribe(ch) }
+}
+
+// SubscribeSession streams the audit entries of one session. Keep reading
+// until the channel is closed after calling the returned stop function.
+func (r *Runtime) SubscribeSession(sessionID string) (<-chan AuditEntry, func()) {
+	ch := events.GlobalBus.Subscribe()
+	return events.FilterForSession(ch, sessionID), func() { events.GlobalBus.Unsubscribe(ch) }
+}
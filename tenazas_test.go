@@ -0,0 +1,107 @@
+package tenazas
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSkill(t *testing.T, storageDir, name, body string) {
+	t.Helper()
+	dir := filepath.Join(storageDir, "skills", name)
+	os.MkdirAll(dir, 0755)
+	if err := os.WriteFile(filepath.Join(dir, "skill.json"), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestRuntime(t *testing.T) (*Runtime, string) {
+	t.Helper()
+	storageDir := t.TempDir()
+	script := filepath.Join(storageDir, "client.sh")
+	os.WriteFile(script, []byte("#!/bin/sh\necho '{\"type\": \"message\", \"content\": \"all done\"}'\n"), 0755)
+
+	rt, err := New(&Config{
+		StorageDir:    storageDir,
+		MaxLoops:      5,
+		DefaultClient: "gemini",
+		Clients:       map[string]ClientConfig{"gemini": {BinPath: script}},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return rt, storageDir
+}
+
+func TestRunSkillDrivesSkillToCompletion(t *testing.T) {
+	rt, storageDir := newTestRuntime(t)
+	writeSkill(t, storageDir, "demo", `{
+		"name": "demo",
+		"initial_state": "work",
+		"states": {
+			"work": {"type": "action_loop", "instruction": "do the thing", "next": "done"},
+			"done": {"type": "end"}
+		}
+	}`)
+
+	events, stop := rt.Subscribe()
+	res, err := rt.RunSkill(context.Background(), "demo", t.TempDir())
+	stop()
+	if err != nil {
+		t.Fatalf("RunSkill: %v", err)
+	}
+	if res.Status != StatusCompleted {
+		t.Fatalf("Status = %q, want %q", res.Status, StatusCompleted)
+	}
+	if res.Response != "all done" {
+		t.Errorf("Response = %q, want %q", res.Response, "all done")
+	}
+	if res.Session == nil || res.Session.SkillName != "demo" {
+		t.Errorf("expected the final session for skill demo, got %+v", res.Session)
+	}
+
+	seen := 0
+	for e := range events {
+		if e.SessionID == res.SessionID {
+			seen++
+		}
+	}
+	if seen == 0 {
+		t.Error("expected the subscription to receive the run's events")
+	}
+}
+
+func TestRunSkillStopsWhenContextIsDone(t *testing.T) {
+	rt, storageDir := newTestRuntime(t)
+	writeSkill(t, storageDir, "waiter", `{
+		"name": "waiter",
+		"initial_state": "wait",
+		"states": {
+			"wait": {"type": "wait", "wait_file": "never-created", "next": "done"},
+			"done": {"type": "end"}
+		}
+	}`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	res, err := rt.RunSkill(ctx, "waiter", t.TempDir())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if res.Status == StatusCompleted {
+		t.Error("a cancelled run must not report completion")
+	}
+	if rt.Engine.IsRunning(res.SessionID) {
+		t.Error("expected the run to have stopped")
+	}
+}
+
+func TestRunSkillUnknownSkill(t *testing.T) {
+	rt, _ := newTestRuntime(t)
+	if _, err := rt.RunSkill(context.Background(), "missing", t.TempDir()); err == nil {
+		t.Fatal("expected an error for an unknown skill")
+	}
+}